@@ -15,9 +15,15 @@ func init() {
 		"Performance Emulation": "性能エミュレーション",
 		"Layout and Style":      "レイアウトとスタイル",
 		"Banner":                "バナー",
+		"Overlay":               "オーバーレイ",
 		"Video and Quality":     "動画と品質",
 		"Debug":                 "デバッグ",
 		"Logging":               "ログ",
+		"Alignment":             "タイミング合わせ",
+		"Edit":                  "編集",
+		"Budgets":               "パフォーマンスバジェット",
+		"Thresholds":            "しきい値",
+		"History":               "履歴",
 
 		// Root command
 		"Create page load videos for web performance visualization":            "Webページの読み込みパフォーマンスを可視化する動画を作成",
@@ -28,26 +34,46 @@ func init() {
 		"Record the loading process of a web page and save it as an MP4 video.": "Webページの読み込み過程を記録し、MP4動画として保存します。",
 
 		// Juxtapose command
-		"Create a side-by-side comparison video":                        "2つの動画を並べた比較動画を作成",
+		"Create a side-by-side comparison video":                        "複数の動画を並べた比較動画を作成",
 		"Create a side-by-side comparison video from two input videos.": "2つの入力動画から並列比較動画を作成します。",
 
+		// Diff command
+		"Create a video of the pixel differences between two recordings": "2つの記録のピクセル差分を示す動画を作成",
+
+		// Filmstrip command
+		"Create a filmstrip contact sheet from a recorded video": "記録済み動画からフィルムストリップ（コンタクトシート）を作成",
+
+		// Inspect command
+		"Show container, frame and metadata information of a video": "動画のコンテナ・フレーム・メタデータ情報を表示",
+
+		// Edit command
+		"Trim, concatenate or change the speed of recorded videos": "記録済み動画のトリミング・連結・速度変更",
+		"Keep a time range of a video":                             "動画の指定範囲を切り出す",
+		"Play videos one after another":                            "動画を順番につなげる",
+		"Change the playback speed of a video":                     "動画の再生速度を変更",
+
 		// Version command
 		"Show version information":         "バージョン情報を表示",
 		"Display the version of loadshow.": "loadshowのバージョンを表示します。",
 		"loadshow (Go) version %s":         "loadshow (Go版) バージョン %s",
 
 		// Required flags
-		"Output MP4 file path (required)": "出力MP4ファイルパス（必須）",
+		"Output MP4 file path (required)":                                                 "出力MP4ファイルパス（必須）",
+		"Output file path (required; .mp4, .webm, .gif, .webp or .apng)":                  "出力ファイルパス（必須、.mp4 / .webm / .gif / .webp / .apng）",
+		"Output format (mp4, webm, gif, webp, apng; default: from output file extension)": "出力形式（mp4, webm, gif, webp, apng、デフォルト: 出力ファイルの拡張子から判定）",
 
 		// Preset flags
 		"Device preset (desktop, mobile)":    "デバイスプリセット（desktop, mobile）",
 		"Quality preset (low, medium, high)": "品質プリセット（low, medium, high）",
 
 		// Video output flags
-		"Output video width (default: 512)":                                 "出力動画の幅（デフォルト: 512）",
-		"Output video height (default: 640)":                                "出力動画の高さ（デフォルト: 640）",
-		"Video CRF value (0-63, lower is better, overrides quality preset)": "動画のCRF値（0-63、低いほど高品質、品質プリセットを上書き）",
-		"Duration to hold final frame in milliseconds":                      "最終フレームの保持時間（ミリ秒）",
+		"Output video width (default: 512)":                                             "出力動画の幅（デフォルト: 512）",
+		"Output video height (default: 640)":                                            "出力動画の高さ（デフォルト: 640）",
+		"Video CRF value (0-63, lower is better, overrides quality preset)":             "動画のCRF値（0-63、低いほど高品質、品質プリセットを上書き）",
+		"Maximum output file size, e.g. 5MB or 800KB (raises CRF until the video fits)": "出力ファイルの最大サイズ（例: 5MB、800KB。収まるまでCRFを上げます）",
+		"Duration to hold final frame in milliseconds":                                  "最終フレームの保持時間（ミリ秒）",
+		"Video codec (h264, av1, vp9; WebM uses av1 unless vp9 is given)":               "動画コーデック（h264, av1, vp9、WebMではvp9指定時以外はav1）",
+		"Path to ffmpeg executable (for H.264 on Linux and VP9)":                        "ffmpeg実行ファイルのパス（LinuxでのH.264およびVP9用）",
 
 		// Recording flags
 		"Screencast JPEG quality (0-100, overrides quality preset)": "スクリーンキャストのJPEG品質（0-100、品質プリセットを上書き）",
@@ -61,9 +87,12 @@ func init() {
 		"Additional bottom margin for column 1": "1列目の追加下部余白",
 
 		// Style flags
-		"Background color (hex, e.g., #dcdcdc)": "背景色（16進数、例: #dcdcdc）",
-		"Border color (hex, e.g., #b4b4b4)":     "枠線の色（16進数、例: #b4b4b4）",
-		"Border width in pixels":                "枠線の幅（ピクセル）",
+		"Background color (hex, e.g., #dcdcdc)":                                      "背景色（16進数、例: #dcdcdc）",
+		"Border color (hex, e.g., #b4b4b4)":                                          "枠線の色（16進数、例: #b4b4b4）",
+		"Border width in pixels":                                                     "枠線の幅（ピクセル）",
+		"Device frame around each column (phone, browser)":                           "各カラムを囲むデバイスフレーム（phone, browser）",
+		"9-slice PNG used as the device frame":                                       "デバイスフレームとして使用する9スライスPNG",
+		"9-slice insets of the frame image in pixels (all or top,right,bottom,left)": "フレーム画像の9スライス境界（ピクセル、全辺共通または top,right,bottom,left）",
 
 		// Network throttling flags
 		"Download speed in Mbps (0 = unlimited)": "ダウンロード速度（Mbps、0 = 無制限）",
@@ -78,6 +107,13 @@ func init() {
 		// Banner flags
 		"Custom text shown in banner (default: loadshow)": "バナーに表示するカスタムテキスト（デフォルト: loadshow）",
 
+		// Overlay flags
+		"Highlight regions that changed between frames":                                     "フレーム間で変化した領域をハイライト",
+		"Fade duration for change highlights in milliseconds (default: 500)":                "変化ハイライトのフェード時間（ミリ秒、デフォルト: 500）",
+		"Outline elements moved by layout shifts and show the running CLS score":            "レイアウトシフトで移動した要素を枠で囲み、CLSスコアを表示",
+		"Outline the Largest Contentful Paint element and show an LCP badge":                "Largest Contentful Paint要素を枠で囲み、LCPバッジを表示",
+		"Show a user-timing mark or measure as a badge (name[:label[:#color]], repeatable)": "User Timingのマークまたはメジャーをバッジとして表示（name[:label[:#color]]、複数指定可）",
+
 		// Browser flags
 		"Run browser in non-headless mode":            "ブラウザを非ヘッドレスモードで実行",
 		"Path to Chrome executable":                   "Chrome実行ファイルのパス",
@@ -98,24 +134,108 @@ func init() {
 		"Output saved to %s":            "出力を %s に保存しました",
 		"Interrupted, shutting down...": "中断されました。シャットダウン中...",
 
+		// Filmstrip flags
+		"Also write a filmstrip contact sheet image (.png or .jpg)": "フィルムストリップ画像も出力（.png または .jpg）",
+		"Output image file path, .png or .jpg (required)":           "出力画像ファイルパス、.png または .jpg（必須）",
+		"Filmstrip sampling (interval, changes)":                    "フィルムストリップのサンプリング方法（interval, changes）",
+		"Filmstrip sampling interval in milliseconds":               "フィルムストリップのサンプリング間隔（ミリ秒）",
+		"Filmstrip frames per row (0 = single row)":                 "フィルムストリップの1行あたりのフレーム数（0 = 1行）",
+		"Thumbnail width in pixels":                                 "サムネイルの幅（ピクセル）",
+
+		// Subtitle flags
+		"Add a subtitle track announcing DOMContentLoaded, Load and LCP (MP4 only)": "DOMContentLoaded・Load・LCPを通知する字幕トラックを追加（MP4のみ）",
+		"Also write the milestone subtitles as a WebVTT file (.vtt)":                "マイルストーンの字幕をWebVTTファイル（.vtt）にも出力",
+
+		// Poster flags
+		"Also write a poster image (.png or .jpg)":                               "ポスター画像（.png または .jpg）も出力",
+		"Poster frame (final, lcp, load, or a time in milliseconds)":             "ポスターに使うフレーム（final、lcp、load、またはミリ秒）",
+		"Also write poster thumbnails at these widths in pixels (e.g., 320,160)": "指定した幅（ピクセル）のポスターサムネイルも出力（例: 320,160）",
+
+		// Filmstrip messages
+		"Creating filmstrip: %s → %s":                                         "フィルムストリップを作成中: %s → %s",
+		"No screen area in the metadata of %s; overlays may count as changes": "%s のメタデータに画面領域がありません。オーバーレイが変化として扱われる場合があります",
+
 		// Juxtapose flags
-		"Gap between videos in pixels": "動画間の隙間（ピクセル）",
+		"Gap between videos in pixels":                                                                            "動画間の隙間（ピクセル）",
+		"Arrangement of the videos (horizontal, vertical, grid)":                                                  "動画の配置（horizontal、vertical、grid）",
+		"Videos per row for the grid layout (0 = auto)":                                                           "グリッド配置の1行あたりの動画数（0 = 自動）",
+		"Caption for each video, in input order (repeatable; default: file name)":                                 "各動画のキャプション（入力順、複数指定可、デフォルト: ファイル名）",
+		"Caption position (top, bottom)":                                                                          "キャプションの位置（top、bottom）",
+		"Caption text color (hex, e.g., #ffffff)":                                                                 "キャプションの文字色（16進数、例: #ffffff）",
+		"Caption font size in pixels":                                                                             "キャプションのフォントサイズ（ピクセル）",
+		"Show each video's DOMContentLoaded and Load times from its embedded metadata, and mark when it finishes": "埋め込みメタデータから各動画のDOMContentLoadedとLoadの時間を表示し、読み込み完了を示す",
+		"Start alignment (start, first-change, milestone, offset)":                                                "開始位置の揃え方（start、first-change、milestone、offset）",
+		"Milestone to align at with --align milestone (e.g., DOMContentLoaded, Load, LCP or a custom mark)":       "--align milestone で揃えるマイルストーン（例: DOMContentLoaded、Load、LCP、カスタムマーク）",
+		"Start time of each video in milliseconds, in input order (implies --align offset)":                       "各動画の開始時刻（ミリ秒、入力順、--align offset を含意）",
 
 		// Juxtapose messages
-		"Creating comparison video: %s + %s → %s": "比較動画を作成中: %s + %s → %s",
-		"Frames: %d, Duration: %dms":              "フレーム数: %d, 再生時間: %dms",
+		"Creating comparison video: %s → %s": "比較動画を作成中: %s → %s",
+		"Frames: %d, Duration: %dms":         "フレーム数: %d, 再生時間: %dms",
+		"%s: %s recorded at %s by %s":        "%s: %s（%s に %s で記録）",
+		"No timing metadata in %s: %s":       "%s にタイミングのメタデータがありません: %s",
+		"%s: aligned at %dms":                "%s: %dms の位置で揃えました",
+
+		// Diff flags
+		"Also write per-frame difference scores (.csv or .json)":                 "フレームごとの差分スコアも出力（.csv または .json）",
+		"Difference rendering (heatmap, overlay)":                                "差分の描画方法（heatmap、overlay）",
+		"Per-channel difference (0-255) a pixel must exceed to count as changed": "ピクセルを変化ありと判定するチャンネルごとの差分のしきい値（0-255）",
+		"Overlay color for changed pixels (hex, e.g., #ff1744)":                  "変化したピクセルのオーバーレイ色（16進数、例: #ff1744）",
+
+		// Diff messages
+		"Creating difference video: %s → %s":                  "差分動画を作成中: %s → %s",
+		"Differing frames: %d, peak %.1f%% of pixels at %dms": "差分のあるフレーム数: %d, 最大 %.1f%% のピクセル（%dms）",
+		"Scores saved to %s":                                  "スコアを %s に保存しました",
+
+		// Inspect flags
+		"Report format (text, json)":                                            "レポート形式（text、json）",
+		"List every frame with its timestamp, size and keyframe flag":           "全フレームのタイムスタンプ・サイズ・キーフレームかどうかを一覧表示",
+		"Save the frame shown at this time in milliseconds as PNG (repeatable)": "指定時刻（ミリ秒）に表示されるフレームをPNGで保存（複数指定可）",
+		"Directory for extracted frames":                                        "抽出したフレームの保存先ディレクトリ",
+
+		// Inspect messages
+		"Frame at %dms saved to %s": "%dms のフレームを %s に保存しました",
+
+		// Edit flags
+		"Start of the kept range in milliseconds":                         "切り出す範囲の開始（ミリ秒）",
+		"End of the kept range in milliseconds (0 = end of video)":        "切り出す範囲の終了（ミリ秒、0 = 動画の最後）",
+		"Solid frames between videos in milliseconds":                     "動画の間に挟む単色フレームの長さ（ミリ秒）",
+		"Color of gap frames and letterboxing (hex)":                      "間のフレームと余白の色（16進数）",
+		"Transition between videos (cut, fade)":                           "動画の切り替え方（cut、fade）",
+		"Fade length in milliseconds":                                     "フェードの長さ（ミリ秒）",
+		"Playback speed (2 = twice as fast, 0.5 = half speed) (required)": "再生速度（2 = 2倍速、0.5 = 半分の速度）（必須）",
+
+		// Edit messages
+		"Trimming %s → %s":                 "トリミング中: %s → %s",
+		"Concatenating %s → %s":            "連結中: %s → %s",
+		"Changing speed of %s by %vx → %s": "%s の速度を %v 倍に変更中 → %s",
+		"%s: starts at %dms":               "%s: %dms から開始",
 
 		// Orchestrator messages
 		"Encoding video with CRF %d": "CRF %d で動画をエンコード中",
 
 		// Error messages
-		"URL argument is required":         "URL引数が必要です",
-		"Two video arguments are required": "2つの動画引数が必要です",
+		"URL argument is required":                  "URL引数が必要です",
+		"At least two video arguments are required": "2つ以上の動画引数が必要です",
+		"Two video arguments are required":          "2つの動画引数が必要です",
+		"Video argument is required":                "動画引数が必要です",
 
 		// Summary output flag
-		"Output execution summary to file (Markdown format)": "実行サマリーをファイルに出力（Markdown形式）",
-		"Summary saved to %s":                                "サマリーを %s に保存しました",
-		"Failed to write summary: %s":                        "サマリーの書き込みに失敗しました: %s",
+		"Output execution summary to file (Markdown, JSON for a .json path, HTML for .html)": "実行サマリーをファイルに出力（Markdown形式、.jsonの場合はJSON形式、.htmlの場合はHTML形式）",
+		"Summary format (markdown, json, html; default: from the file extension)":            "サマリーの形式（markdown、json、html、デフォルト: ファイルの拡張子から判定）",
+		"Link the video and images from an HTML summary instead of embedding them":           "HTMLサマリーに動画と画像を埋め込まずリンクする",
+		"Print the run result as JSON to stdout and nothing else":                            "実行結果のJSONだけを標準出力に出力",
+		"Summary saved to %s":         "サマリーを %s に保存しました",
+		"Failed to write summary: %s": "サマリーの書き込みに失敗しました: %s",
+
+		// Budgets
+		"Check the result against a budgets file (YAML); exits with status 3 when a budget is exceeded": "バジェットファイル（YAML）で結果をチェック（超過時は終了コード3）",
+		"Also write the budget results as JUnit XML":                                                    "バジェットの結果をJUnit XMLでも出力",
+		"No budget matches %s":          "%s に一致するバジェットがありません",
+		"Budget passed: %s":             "バジェット達成: %s",
+		"Budget exceeded: %s":           "バジェット超過: %s",
+		"Budget skipped: %s":            "バジェット未確認: %s",
+		"JUnit report saved to %s":      "JUnitレポートを %s に保存しました",
+		"%d of %d budget checks failed": "%d / %d 件のバジェットチェックが失敗しました",
 
 		// Summary content
 		"Recording Summary": "記録サマリー",
@@ -153,6 +273,65 @@ func init() {
 		"Canvas Size":     "キャンバスサイズ",
 		"CRF":             "CRF値",
 		"Outro Duration":  "アウトロ時間",
+		"Max Size":        "最大サイズ",
+		"passes":          "パス",
 		"Generated by":    "生成:",
+
+		// Poster section
+		"Poster":       "ポスター",
+		"Poster Image": "ポスター画像",
+		"Thumbnail":    "サムネイル",
+
+		// HTML report
+		"Video":                    "動画",
+		"Filmstrip":                "フィルムストリップ",
+		"Timing":                   "タイミング",
+		"Loading Progress":         "読み込み進捗",
+		"Largest Contentful Paint": "最大コンテンツの描画",
+		"Requests":                 "リクエスト",
+		"Type":                     "種類",
+		"Start":                    "開始",
+		"Duration":                 "所要時間",
+		"Size":                     "サイズ",
+
+		// Compare-results command
+		"Compare two JSON run summaries and report the deltas": "2つのJSON実行サマリーを比較して差分を出力",
+		"Report format (markdown, json)":                       "レポート形式（markdown, json）",
+		"Write the report to a file instead of stdout":         "標準出力の代わりにファイルへレポートを出力",
+		"Minimum change in percent to flag a metric":           "変化として示す最小の変化率（%）",
+		"Minimum change in milliseconds to flag a timing":      "タイミングの変化として示す最小の変化量（ミリ秒）",
+		"Minimum change to flag a size (e.g. 10KB, 1MB)":       "サイズの変化として示す最小の変化量（例: 10KB, 1MB）",
+		"Minimum change to flag the layout shift score":        "レイアウトシフトのスコアの変化として示す最小の変化量",
+		"Two summary files are required":                       "サマリーファイルを2つ指定してください",
+		"Thresholds must not be negative":                      "しきい値に負の値は指定できません",
+		"Run Comparison":                                       "実行結果の比較",
+		"Traffic":                                              "トラフィック",
+		"Metric":                                               "指標",
+		"Change":                                               "変化",
+		"Regression":                                           "悪化",
+		"Improvement":                                          "改善",
+		"Changed":                                              "変更",
+		"Significant changes":                                  "有意な変化",
+		"thresholds":                                           "しきい値",
+		"Canvas Width":                                         "キャンバス幅",
+		"Canvas Height":                                        "キャンバス高さ",
+		"Encode Passes":                                        "エンコード回数",
+
+		// History
+		"Append the result to a history file (JSON lines) for loadshow history":                        "結果を履歴ファイル（JSON Lines）に追記（loadshow historyで参照）",
+		"Profile name the run is stored under in the history (default: the preset)":                    "履歴に記録するプロファイル名（デフォルト: プリセット名）",
+		"Failed to update history: %s":                                                                 "履歴の更新に失敗しました: %s",
+		"Run added to history %s":                                                                      "履歴 %s に実行結果を追加しました",
+		"List recorded runs, show trends and detect regressions":                                       "記録した実行結果の一覧、推移、悪化の検出",
+		"List the runs in the history":                                                                 "履歴の実行結果を一覧表示",
+		"Show sparklines and medians of the recent runs":                                               "直近の実行結果のスパークラインと中央値を表示",
+		"Compare the latest run with the median of previous runs; exits with status 3 on a regression": "最新の実行結果を過去の中央値と比較（悪化時は終了コード3）",
+		"History file written by record --history":                                                     "record --historyで書き出した履歴ファイル",
+		"Only include runs of this URL":                                                                "このURLの実行結果のみ対象にする",
+		"Only include runs of this profile":                                                            "このプロファイルの実行結果のみ対象にする",
+		"Number of recent runs to include (0 = all)":                                                   "対象とする直近の実行回数（0 = すべて）",
+		"Number of previous runs in the baseline (0 = all)":                                            "基準とする過去の実行回数（0 = すべて）",
+		"No runs match the filters":                                                                    "条件に一致する実行結果がありません",
+		"%d regressions against the baseline":                                                          "基準に対して%d件の悪化があります",
 	})
 }
@@ -2,19 +2,26 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"image/color"
 	"io"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"runtime"
+	"slices"
+	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/ideamans/go-l10n"
 	"github.com/urfave/cli/v2"
 
+	"github.com/user/loadshow/pkg/adapters/animencoder"
 	"github.com/user/loadshow/pkg/adapters/capturehtml"
 	"github.com/user/loadshow/pkg/adapters/chromebrowser"
 	"github.com/user/loadshow/pkg/adapters/filesink"
@@ -24,17 +31,26 @@ import (
 	"github.com/user/loadshow/pkg/adapters/osfilesystem"
 	"github.com/user/loadshow/pkg/adapters/smartdecoder"
 	"github.com/user/loadshow/pkg/adapters/smartencoder"
+	"github.com/user/loadshow/pkg/budget"
 	"github.com/user/loadshow/pkg/config"
+	"github.com/user/loadshow/pkg/history"
+	"github.com/user/loadshow/pkg/inspect"
 	"github.com/user/loadshow/pkg/juxtapose"
 	"github.com/user/loadshow/pkg/loadshow"
+	"github.com/user/loadshow/pkg/mp4meta"
 	"github.com/user/loadshow/pkg/orchestrator"
+	"github.com/user/loadshow/pkg/pipeline"
 	"github.com/user/loadshow/pkg/ports"
 	"github.com/user/loadshow/pkg/stages/banner"
 	"github.com/user/loadshow/pkg/stages/composite"
 	"github.com/user/loadshow/pkg/stages/encode"
+	"github.com/user/loadshow/pkg/stages/filmstrip"
 	"github.com/user/loadshow/pkg/stages/layout"
+	"github.com/user/loadshow/pkg/stages/poster"
 	"github.com/user/loadshow/pkg/stages/record"
 	"github.com/user/loadshow/pkg/summarizer"
+	"github.com/user/loadshow/pkg/videodiff"
+	"github.com/user/loadshow/pkg/videoedit"
 )
 
 var version = "dev"
@@ -48,9 +64,15 @@ const (
 	catPerformance  = "Performance Emulation"
 	catLayoutStyle  = "Layout and Style"
 	catBanner       = "Banner"
+	catOverlay      = "Overlay"
 	catVideoQuality = "Video and Quality"
 	catDebug        = "Debug"
 	catLogging      = "Logging"
+	catAlignment    = "Alignment"
+	catEdit         = "Edit"
+	catBudgets      = "Budgets"
+	catThresholds   = "Thresholds"
+	catHistory      = "History"
 )
 
 // categoryOrder defines the display order of flag categories
@@ -61,7 +83,13 @@ var categoryOrder = []string{
 	"Performance Emulation",
 	"Layout and Style",
 	"Banner",
+	"Overlay",
+	"Alignment",
+	"Edit",
 	"Video and Quality",
+	"Budgets",
+	"Thresholds",
+	"History",
 	"Debug",
 	"Logging",
 }
@@ -141,6 +169,12 @@ func main() {
 		Commands: []*cli.Command{
 			recordCommand(),
 			juxtaposeCommand(),
+			diffCommand(),
+			filmstripCommand(),
+			inspectCommand(),
+			editCommand(),
+			compareResultsCommand(),
+			historyCommand(),
 		},
 	}
 
@@ -160,10 +194,63 @@ func recordCommand() *cli.Command {
 			&cli.StringFlag{
 				Name:     "output",
 				Aliases:  []string{"o"},
-				Usage:    l10n.T("Output MP4 file path (required)"),
+				Usage:    l10n.T("Output file path (required; .mp4, .webm, .gif, .webp or .apng)"),
 				Required: true,
 				Category: l10n.T(catOutput),
 			},
+			&cli.StringFlag{
+				Name:     "format",
+				Usage:    l10n.T("Output format (mp4, webm, gif, webp, apng; default: from output file extension)"),
+				Category: l10n.T(catOutput),
+			},
+			&cli.StringFlag{
+				Name:     "output-filmstrip",
+				Usage:    l10n.T("Also write a filmstrip contact sheet image (.png or .jpg)"),
+				Category: l10n.T(catOutput),
+			},
+			&cli.StringFlag{
+				Name:     "filmstrip-mode",
+				Value:    "interval",
+				Usage:    l10n.T("Filmstrip sampling (interval, changes)"),
+				Category: l10n.T(catOutput),
+			},
+			&cli.IntFlag{
+				Name:     "filmstrip-interval",
+				Value:    100,
+				Usage:    l10n.T("Filmstrip sampling interval in milliseconds"),
+				Category: l10n.T(catOutput),
+			},
+			&cli.IntFlag{
+				Name:     "filmstrip-columns",
+				Usage:    l10n.T("Filmstrip frames per row (0 = single row)"),
+				Category: l10n.T(catOutput),
+			},
+			&cli.BoolFlag{
+				Name:     "subtitles",
+				Usage:    l10n.T("Add a subtitle track announcing DOMContentLoaded, Load and LCP (MP4 only)"),
+				Category: l10n.T(catOutput),
+			},
+			&cli.StringFlag{
+				Name:     "output-subtitles",
+				Usage:    l10n.T("Also write the milestone subtitles as a WebVTT file (.vtt)"),
+				Category: l10n.T(catOutput),
+			},
+			&cli.StringFlag{
+				Name:     "output-poster",
+				Usage:    l10n.T("Also write a poster image (.png or .jpg)"),
+				Category: l10n.T(catOutput),
+			},
+			&cli.StringFlag{
+				Name:     "poster-frame",
+				Value:    "final",
+				Usage:    l10n.T("Poster frame (final, lcp, load, or a time in milliseconds)"),
+				Category: l10n.T(catOutput),
+			},
+			&cli.IntSliceFlag{
+				Name:     "poster-thumbnails",
+				Usage:    l10n.T("Also write poster thumbnails at these widths in pixels (e.g., 320,160)"),
+				Category: l10n.T(catOutput),
+			},
 
 			// ===== 2. Preset =====
 			&cli.StringFlag{
@@ -278,6 +365,22 @@ func recordCommand() *cli.Command {
 				Usage:    l10n.T("Border width in pixels"),
 				Category: l10n.T(catLayoutStyle),
 			},
+			&cli.StringFlag{
+				Name:     "device-frame",
+				Usage:    l10n.T("Device frame around each column (phone, browser)"),
+				Category: l10n.T(catLayoutStyle),
+			},
+			&cli.StringFlag{
+				Name:     "frame-image",
+				Usage:    l10n.T("9-slice PNG used as the device frame"),
+				Category: l10n.T(catLayoutStyle),
+			},
+			&cli.IntSliceFlag{
+				Name:     "frame-slice",
+				Usage:    l10n.T("9-slice insets of the frame image in pixels (all or top,right,bottom,left)"),
+				Value:    cli.NewIntSlice(16),
+				Category: l10n.T(catLayoutStyle),
+			},
 
 			// ===== 6. Banner =====
 			&cli.StringFlag{
@@ -286,16 +389,43 @@ func recordCommand() *cli.Command {
 				Category: l10n.T(catBanner),
 			},
 
-			// ===== 7. Video and Quality =====
+			// ===== 7. Overlay =====
+			&cli.BoolFlag{
+				Name:     "highlight-changes",
+				Usage:    l10n.T("Highlight regions that changed between frames"),
+				Category: l10n.T(catOverlay),
+			},
+			&cli.IntFlag{
+				Name:     "highlight-fade-ms",
+				Usage:    l10n.T("Fade duration for change highlights in milliseconds (default: 500)"),
+				Category: l10n.T(catOverlay),
+			},
+			&cli.BoolFlag{
+				Name:     "show-layout-shifts",
+				Usage:    l10n.T("Outline elements moved by layout shifts and show the running CLS score"),
+				Category: l10n.T(catOverlay),
+			},
+			&cli.BoolFlag{
+				Name:     "show-lcp",
+				Usage:    l10n.T("Outline the Largest Contentful Paint element and show an LCP badge"),
+				Category: l10n.T(catOverlay),
+			},
+			&cli.StringSliceFlag{
+				Name:     "timing-mark",
+				Usage:    l10n.T("Show a user-timing mark or measure as a badge (name[:label[:#color]], repeatable)"),
+				Category: l10n.T(catOverlay),
+			},
+
+			// ===== 8. Video and Quality =====
 			&cli.StringFlag{
 				Name:     "codec",
 				Value:    "h264",
-				Usage:    l10n.T("Video codec (h264, av1)"),
+				Usage:    l10n.T("Video codec (h264, av1, vp9; WebM uses av1 unless vp9 is given)"),
 				Category: l10n.T(catVideoQuality),
 			},
 			&cli.StringFlag{
 				Name:     "ffmpeg-path",
-				Usage:    l10n.T("Path to ffmpeg executable (Linux only, for H.264)"),
+				Usage:    l10n.T("Path to ffmpeg executable (for H.264 on Linux and VP9)"),
 				Category: l10n.T(catVideoQuality),
 			},
 			&cli.IntFlag{
@@ -325,8 +455,13 @@ func recordCommand() *cli.Command {
 				Usage:    l10n.T("Duration to hold final frame in milliseconds"),
 				Category: l10n.T(catVideoQuality),
 			},
+			&cli.StringFlag{
+				Name:     "max-size",
+				Usage:    l10n.T("Maximum output file size, e.g. 5MB or 800KB (raises CRF until the video fits)"),
+				Category: l10n.T(catVideoQuality),
+			},
 
-			// ===== 8. Debug =====
+			// ===== 9. Debug =====
 			&cli.BoolFlag{
 				Name:     "debug",
 				Aliases:  []string{"d"},
@@ -341,11 +476,50 @@ func recordCommand() *cli.Command {
 			},
 			&cli.StringFlag{
 				Name:     "output-summary",
-				Usage:    l10n.T("Output execution summary to file (Markdown format)"),
+				Usage:    l10n.T("Output execution summary to file (Markdown, JSON for a .json path, HTML for .html)"),
+				Category: l10n.T(catOutput),
+			},
+			&cli.StringFlag{
+				Name:     "summary-format",
+				Usage:    l10n.T("Summary format (markdown, json, html; default: from the file extension)"),
 				Category: l10n.T(catOutput),
 			},
+			&cli.BoolFlag{
+				Name:     "summary-link-media",
+				Usage:    l10n.T("Link the video and images from an HTML summary instead of embedding them"),
+				Category: l10n.T(catOutput),
+			},
+			&cli.BoolFlag{
+				Name:     "json",
+				Usage:    l10n.T("Print the run result as JSON to stdout and nothing else"),
+				Category: l10n.T(catOutput),
+			},
+
+			// ===== 10. Budgets =====
+			&cli.StringFlag{
+				Name:     "budgets",
+				Usage:    l10n.T("Check the result against a budgets file (YAML); exits with status 3 when a budget is exceeded"),
+				Category: l10n.T(catBudgets),
+			},
+			&cli.StringFlag{
+				Name:     "output-junit",
+				Usage:    l10n.T("Also write the budget results as JUnit XML"),
+				Category: l10n.T(catBudgets),
+			},
+
+			// ===== 11. History =====
+			&cli.StringFlag{
+				Name:     "history",
+				Usage:    l10n.T("Append the result to a history file (JSON lines) for loadshow history"),
+				Category: l10n.T(catHistory),
+			},
+			&cli.StringFlag{
+				Name:     "history-profile",
+				Usage:    l10n.T("Profile name the run is stored under in the history (default: the preset)"),
+				Category: l10n.T(catHistory),
+			},
 
-			// ===== 9. Logging =====
+			// ===== 12. Logging =====
 			&cli.StringFlag{
 				Name:     "log-level",
 				Aliases:  []string{"l"},
@@ -367,7 +541,7 @@ func juxtaposeCommand() *cli.Command {
 	return &cli.Command{
 		Name:      "juxtapose",
 		Usage:     l10n.T("Create a side-by-side comparison video"),
-		ArgsUsage: "<left> <right>",
+		ArgsUsage: "<video> <video> [<video>...]",
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:     "output",
@@ -405,28 +579,299 @@ func juxtaposeCommand() *cli.Command {
 				Usage:    l10n.T("Gap between videos in pixels"),
 				Category: l10n.T(catLayoutStyle),
 			},
+			&cli.StringFlag{
+				Name:     "layout",
+				Value:    "horizontal",
+				Usage:    l10n.T("Arrangement of the videos (horizontal, vertical, grid)"),
+				Category: l10n.T(catLayoutStyle),
+			},
+			&cli.IntFlag{
+				Name:     "columns",
+				Usage:    l10n.T("Videos per row for the grid layout (0 = auto)"),
+				Category: l10n.T(catLayoutStyle),
+			},
+			&cli.StringSliceFlag{
+				Name:     "label",
+				Usage:    l10n.T("Caption for each video, in input order (repeatable; default: file name)"),
+				Category: l10n.T(catLayoutStyle),
+			},
+			&cli.StringFlag{
+				Name:     "label-position",
+				Value:    "top",
+				Usage:    l10n.T("Caption position (top, bottom)"),
+				Category: l10n.T(catLayoutStyle),
+			},
+			&cli.StringFlag{
+				Name:     "label-color",
+				Usage:    l10n.T("Caption text color (hex, e.g., #ffffff)"),
+				Category: l10n.T(catLayoutStyle),
+			},
+			&cli.Float64Flag{
+				Name:     "label-font-size",
+				Value:    14,
+				Usage:    l10n.T("Caption font size in pixels"),
+				Category: l10n.T(catLayoutStyle),
+			},
+			&cli.BoolFlag{
+				Name:     "show-timings",
+				Usage:    l10n.T("Show each video's DOMContentLoaded and Load times from its embedded metadata, and mark when it finishes"),
+				Category: l10n.T(catLayoutStyle),
+			},
 			&cli.StringFlag{
 				Name:     "border-color",
 				Usage:    l10n.T("Border color between videos (hex, e.g., #505050)"),
 				Category: l10n.T(catLayoutStyle),
 			},
+			&cli.StringFlag{
+				Name:     "align",
+				Value:    "start",
+				Usage:    l10n.T("Start alignment (start, first-change, milestone, offset)"),
+				Category: l10n.T(catAlignment),
+			},
+			&cli.StringFlag{
+				Name:     "milestone",
+				Value:    "DOMContentLoaded",
+				Usage:    l10n.T("Milestone to align at with --align milestone (e.g., DOMContentLoaded, Load, LCP or a custom mark)"),
+				Category: l10n.T(catAlignment),
+			},
+			&cli.IntSliceFlag{
+				Name:     "offset",
+				Usage:    l10n.T("Start time of each video in milliseconds, in input order (implies --align offset)"),
+				Category: l10n.T(catAlignment),
+			},
 		},
 		Action: runJuxtapose,
 	}
 }
 
+func diffCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "diff",
+		Usage:     l10n.T("Create a video of the pixel differences between two recordings"),
+		ArgsUsage: "<before> <after>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "output",
+				Aliases:  []string{"o"},
+				Usage:    l10n.T("Output MP4 file path (required)"),
+				Required: true,
+				Category: l10n.T(catOutput),
+			},
+			&cli.StringFlag{
+				Name:     "scores",
+				Usage:    l10n.T("Also write per-frame difference scores (.csv or .json)"),
+				Category: l10n.T(catOutput),
+			},
+			&cli.StringFlag{
+				Name:     "quality",
+				Aliases:  []string{"q"},
+				Value:    "medium",
+				Usage:    l10n.T("Quality preset (low, medium, high)"),
+				Category: l10n.T(catPreset),
+			},
+			&cli.StringFlag{
+				Name:     "mode",
+				Value:    "heatmap",
+				Usage:    l10n.T("Difference rendering (heatmap, overlay)"),
+				Category: l10n.T(catLayoutStyle),
+			},
+			&cli.IntFlag{
+				Name:     "threshold",
+				Value:    videodiff.DefaultThreshold,
+				Usage:    l10n.T("Per-channel difference (0-255) a pixel must exceed to count as changed"),
+				Category: l10n.T(catLayoutStyle),
+			},
+			&cli.StringFlag{
+				Name:     "color",
+				Usage:    l10n.T("Overlay color for changed pixels (hex, e.g., #ff1744)"),
+				Category: l10n.T(catLayoutStyle),
+			},
+			&cli.StringFlag{
+				Name:     "codec",
+				Value:    "h264",
+				Usage:    l10n.T("Video codec (h264, av1)"),
+				Category: l10n.T(catVideoQuality),
+			},
+			&cli.StringFlag{
+				Name:     "ffmpeg-path",
+				Usage:    l10n.T("Path to ffmpeg executable (Linux only, for H.264)"),
+				Category: l10n.T(catVideoQuality),
+			},
+			&cli.IntFlag{
+				Name:     "video-crf",
+				Usage:    l10n.T("Video CRF value (0-63, lower is better, overrides quality preset)"),
+				Category: l10n.T(catVideoQuality),
+			},
+		},
+		Action: runDiff,
+	}
+}
+
+func filmstripCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "filmstrip",
+		Usage:     l10n.T("Create a filmstrip contact sheet from a recorded video"),
+		ArgsUsage: "<video>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "output",
+				Aliases:  []string{"o"},
+				Usage:    l10n.T("Output image file path, .png or .jpg (required)"),
+				Required: true,
+				Category: l10n.T(catOutput),
+			},
+			&cli.StringFlag{
+				Name:     "mode",
+				Value:    "interval",
+				Usage:    l10n.T("Filmstrip sampling (interval, changes)"),
+				Category: l10n.T(catLayoutStyle),
+			},
+			&cli.IntFlag{
+				Name:     "interval",
+				Value:    100,
+				Usage:    l10n.T("Filmstrip sampling interval in milliseconds"),
+				Category: l10n.T(catLayoutStyle),
+			},
+			&cli.IntFlag{
+				Name:     "columns",
+				Usage:    l10n.T("Filmstrip frames per row (0 = single row)"),
+				Category: l10n.T(catLayoutStyle),
+			},
+			&cli.IntFlag{
+				Name:     "thumb-width",
+				Value:    160,
+				Usage:    l10n.T("Thumbnail width in pixels"),
+				Category: l10n.T(catLayoutStyle),
+			},
+			&cli.StringFlag{
+				Name:     "ffmpeg-path",
+				Usage:    l10n.T("Path to ffmpeg executable (Linux only, for H.264)"),
+				Category: l10n.T(catVideoQuality),
+			},
+		},
+		Action: runFilmstrip,
+	}
+}
+
 func runRecord(c *cli.Context) error {
 	if c.NArg() < 1 {
 		return errors.New(l10n.T("URL argument is required"))
 	}
 	url := c.Args().Get(0)
 
+	// Validate device frame options
+	switch c.String("device-frame") {
+	case "", "phone", "browser":
+	default:
+		return fmt.Errorf("unknown device frame: %s (supported: phone, browser)", c.String("device-frame"))
+	}
+	if n := len(c.IntSlice("frame-slice")); n != 1 && n != 4 {
+		return fmt.Errorf("invalid frame slice: expected 1 or 4 values, got %d", n)
+	}
+
+	filmstripMode, err := parseFilmstripMode(c.String("filmstrip-mode"))
+	if err != nil {
+		return err
+	}
+
+	posterFrame, posterTimeMs, err := parsePosterFrame(c.String("poster-frame"))
+	if err != nil {
+		return err
+	}
+	if len(c.IntSlice("poster-thumbnails")) > 0 && c.String("output-poster") == "" {
+		return fmt.Errorf("--poster-thumbnails requires --output-poster")
+	}
+	// Each width is written once, even if given repeatedly
+	var thumbnailWidths []int
+	for _, w := range c.IntSlice("poster-thumbnails") {
+		if w <= 0 {
+			return fmt.Errorf("invalid thumbnail width: %d", w)
+		}
+		if !slices.Contains(thumbnailWidths, w) {
+			thumbnailWidths = append(thumbnailWidths, w)
+		}
+	}
+
+	// Resolve output format from --format or the output file extension
+	format := loadshow.FormatFromPath(c.String("output"))
+	if c.String("format") != "" {
+		f, err := loadshow.ParseOutputFormat(c.String("format"))
+		if err != nil {
+			return err
+		}
+		format = f
+	}
+
+	if c.Bool("subtitles") && (format.IsAnimatedImage() || format.Container() != ports.ContainerMP4) {
+		return fmt.Errorf("--subtitles requires MP4 output (use --output-subtitles for a WebVTT file)")
+	}
+
+	summaryFormat := summarizer.FormatForPath(c.String("output-summary"))
+	if c.String("summary-format") != "" {
+		f, err := summarizer.ParseFormat(c.String("summary-format"))
+		if err != nil {
+			return err
+		}
+		summaryFormat = f
+	}
+
+	var budgets *budget.File
+	if path := c.String("budgets"); path != "" {
+		f, err := budget.Load(path)
+		if err != nil {
+			return err
+		}
+		budgets = &f
+	} else if c.String("output-junit") != "" {
+		return fmt.Errorf("--output-junit requires --budgets")
+	}
+
+	var maxSize int64
+	if c.String("max-size") != "" {
+		if maxSize, err = loadshow.ParseSize(c.String("max-size")); err != nil {
+			return err
+		}
+	}
+
 	// Build config from preset and overrides
-	cfg := buildRecordConfig(c)
+	cfg := buildRecordConfig(c, format)
+	cfg.MaxSize = maxSize
+	if path := c.String("output-filmstrip"); path != "" {
+		cfg.FilmstripPath = path
+		cfg.FilmstripMode = filmstripMode
+		cfg.FilmstripIntervalMs = c.Int("filmstrip-interval")
+		cfg.FilmstripColumns = c.Int("filmstrip-columns")
+	}
+	summaryPath := c.String("output-summary")
+	tempFilmstrip := false
+	if summaryFormat == summarizer.FormatHTML && summaryPath != "" && cfg.FilmstripPath == "" {
+		// The HTML report shows a filmstrip of key frames. Embedded, it only
+		// needs to exist until the report is written.
+		path := strings.TrimSuffix(summaryPath, filepath.Ext(summaryPath)) + "-filmstrip.png"
+		if !c.Bool("summary-link-media") {
+			tmp, err := os.CreateTemp("", "loadshow-filmstrip-*.png")
+			if err != nil {
+				return fmt.Errorf("create filmstrip file: %w", err)
+			}
+			tmp.Close()
+			defer os.Remove(tmp.Name())
+			path = tmp.Name()
+			tempFilmstrip = true
+		}
+		cfg.FilmstripPath = path
+		cfg.FilmstripMode = pipeline.FilmstripChanges
+		cfg.FilmstripColumns = c.Int("filmstrip-columns")
+	}
+	if path := c.String("output-poster"); path != "" {
+		cfg.PosterPath = path
+		cfg.PosterFrame = posterFrame
+		cfg.PosterTimeMs = posterTimeMs
+		cfg.ThumbnailWidths = thumbnailWidths
+	}
 
-	// Create logger
+	// Create logger. With --json, stdout carries only the result.
 	var log ports.Logger
-	if c.Bool("quiet") {
+	if c.Bool("quiet") || c.Bool("json") {
 		log = logger.NewNoop()
 	} else {
 		log = logger.NewConsole(ports.ParseLogLevel(c.String("log-level")))
@@ -451,38 +896,11 @@ func runRecord(c *cli.Context) error {
 	browser := chromebrowser.New()
 	htmlCapturer := capturehtml.New()
 
-	// Select encoder based on --codec option using smart encoder
-	requestedCodec := c.String("codec")
-	var preferred smartencoder.Codec
-	switch requestedCodec {
-	case "av1":
-		preferred = smartencoder.CodecAV1
-	case "h264":
-		preferred = smartencoder.CodecH264
-	default:
-		return fmt.Errorf("unknown codec: %s (supported: h264, av1)", requestedCodec)
-	}
-
-	encoder, encoderInfo, err := smartencoder.New(preferred, smartencoder.Options{
-		FFmpegPath:    c.String("ffmpeg-path"),
-		AllowFallback: true,
-		Logger:        log,
-	})
+	// Select encoder: animated image formats have their own encoders,
+	// MP4 and WebM use the smart encoder based on --codec
+	encoder, codecName, err := newRecordEncoder(c, format, log)
 	if err != nil {
-		return fmt.Errorf("create encoder: %w", err)
-	}
-
-	// Build codec name for logging
-	var codecName string
-	switch {
-	case encoderInfo.Codec == smartencoder.CodecAV1:
-		codecName = "AV1"
-	case encoderInfo.Backend == smartencoder.BackendOS:
-		codecName = "H.264 (native)"
-	case encoderInfo.Backend == smartencoder.BackendFFmpeg:
-		codecName = "H.264 (ffmpeg)"
-	default:
-		codecName = string(encoderInfo.Codec)
+		return err
 	}
 
 	// Create debug sink
@@ -512,6 +930,8 @@ func runRecord(c *cli.Context) error {
 	bannerStage := banner.NewStage(htmlCapturer, sink, log)
 	compositeStage := composite.NewStage(renderer, sink, log, workers)
 	encodeStage := encode.NewStage(encoder, log)
+	filmstripStage := filmstrip.NewStage(renderer, log)
+	posterStage := poster.NewStage(renderer, log)
 
 	// Create orchestrator
 	orch := orchestrator.New(
@@ -520,6 +940,8 @@ func runRecord(c *cli.Context) error {
 		bannerStage,
 		compositeStage,
 		encodeStage,
+		filmstripStage,
+		posterStage,
 		fs,
 		sink,
 		log,
@@ -540,13 +962,9 @@ func runRecord(c *cli.Context) error {
 	log.Info(l10n.F("Output saved to %s", c.String("output")))
 
 	// Write summary if requested
-	if summaryPath := c.String("output-summary"); summaryPath != "" {
-		summary := buildSummary(c, cfg, result, codecName)
-		formatter := summarizer.NewMarkdownFormatter(
-			summarizer.WithTranslator(l10n.T),
-			summarizer.WithVersion(version),
-		)
-		writer := summarizer.NewWriter(formatter)
+	if summaryPath != "" {
+		summary := buildSummary(c, cfg, result, codecName, summaryPath)
+		writer := summarizer.NewWriter(newSummaryFormatter(c, summaryFormat, summaryPath))
 		if err := writer.Write(summaryPath, summary); err != nil {
 			log.Warn(l10n.F("Failed to write summary: %s", err))
 		} else {
@@ -554,16 +972,114 @@ func runRecord(c *cli.Context) error {
 		}
 	}
 
+	// Print the result for scripts; paths stay relative to the working directory
+	if c.Bool("json") {
+		summary := buildSummary(c, cfg, result, codecName, ".")
+		if tempFilmstrip {
+			summary.Filmstrip = nil
+		}
+		fmt.Print(summarizer.NewJSONFormatter(version).Format(summary))
+	}
+
+	if path := c.String("history"); path != "" {
+		profile := c.String("history-profile")
+		if profile == "" {
+			profile = c.String("preset")
+		}
+		entry := history.NewEntry(url, profile, time.Now(), result)
+		entry.Video = c.String("output")
+		if err := history.Append(path, entry); err != nil {
+			log.Warn(l10n.F("Failed to update history: %s", err))
+		} else {
+			log.Info(l10n.F("Run added to history %s", path))
+		}
+	}
+
+	// Budgets are checked last, so the video and summary are written either way
+	if budgets != nil {
+		return checkBudgets(c, log, *budgets, url, result)
+	}
+
 	return nil
 }
 
-// buildSummary creates a Summary from recording results.
-func buildSummary(c *cli.Context, cfg loadshow.Config, result orchestrator.RunResult, codecName string) *summarizer.Summary {
-	return summarizer.NewBuilder().
-		WithPage(result.PageTitle, result.PageURL).
-		WithTiming(result.DOMContentLoadedMs, result.LoadCompleteMs, result.TotalDurationMs).
-		WithTimeout(result.TimedOut, result.TimeoutSec).
-		WithTraffic(result.TotalBytes).
+// exitBudgetExceeded is the exit status of record when a budget is exceeded.
+const exitBudgetExceeded = 3
+
+// checkBudgets logs the budget results for a recording, writes them as
+// JUnit XML if requested and fails with exitBudgetExceeded on a violation.
+func checkBudgets(c *cli.Context, log ports.Logger, budgets budget.File, url string, result orchestrator.RunResult) error {
+	report := budgets.Evaluate(url, result)
+	if len(report.Results) == 0 {
+		log.Warn(l10n.F("No budget matches %s", url))
+	}
+	for _, r := range report.Results {
+		switch {
+		case r.Skipped:
+			log.Warn(l10n.F("Budget skipped: %s", r))
+		case r.Passed:
+			log.Info(l10n.F("Budget passed: %s", r))
+		default:
+			log.Warn(l10n.F("Budget exceeded: %s", r))
+		}
+	}
+
+	if path := c.String("output-junit"); path != "" {
+		var buf bytes.Buffer
+		if err := budget.WriteJUnit(&buf, report); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			return fmt.Errorf("write JUnit report: %w", err)
+		}
+		log.Info(l10n.F("JUnit report saved to %s", path))
+	}
+
+	if n := report.Failures(); n > 0 {
+		return cli.Exit(l10n.F("%d of %d budget checks failed", n, len(report.Results)), exitBudgetExceeded)
+	}
+	return nil
+}
+
+// newSummaryFormatter creates the formatter for a summary format.
+func newSummaryFormatter(c *cli.Context, format summarizer.Format, summaryPath string) summarizer.Formatter {
+	switch format {
+	case summarizer.FormatJSON:
+		return summarizer.NewJSONFormatter(version)
+	case summarizer.FormatHTML:
+		opts := []summarizer.HTMLOption{
+			summarizer.WithHTMLTranslator(l10n.T),
+			summarizer.WithHTMLVersion(version),
+		}
+		if !c.Bool("summary-link-media") {
+			// Summary paths are relative to the summary file
+			dir := filepath.Dir(summaryPath)
+			opts = append(opts, summarizer.WithEmbeddedMedia(func(path string) ([]byte, error) {
+				if !filepath.IsAbs(path) {
+					path = filepath.Join(dir, path)
+				}
+				return os.ReadFile(path)
+			}))
+		}
+		return summarizer.NewHTMLFormatter(opts...)
+	}
+	return summarizer.NewMarkdownFormatter(
+		summarizer.WithTranslator(l10n.T),
+		summarizer.WithVersion(version),
+	)
+}
+
+// buildSummary creates a Summary from recording results. File paths are
+// made relative to summaryPath so links in the summary resolve.
+func buildSummary(c *cli.Context, cfg loadshow.Config, result orchestrator.RunResult, codecName, summaryPath string) *summarizer.Summary {
+	b := summarizer.NewBuilder().
+		WithPage(result.PageTitle, result.PageURL).
+		WithTiming(result.DOMContentLoadedMs, result.LoadCompleteMs, result.TotalDurationMs).
+		WithTimeout(result.TimedOut, result.TimeoutSec).
+		WithLargestContentfulPaint(result.LargestContentfulPaintMs).
+		WithMarks(summaryMarks(result.TimingMarks)).
+		WithTraffic(result.TotalBytes).
+		WithRequestCount(result.RequestCount).
 		WithSettings(summarizer.Settings{
 			Preset:        c.String("preset"),
 			Quality:       c.String("quality"),
@@ -575,19 +1091,186 @@ func buildSummary(c *cli.Context, cfg loadshow.Config, result orchestrator.RunRe
 			CPUThrottling: cfg.CPUThrottling,
 		}).
 		WithVideo(summarizer.VideoInfo{
+			Path:          summaryRelPath(summaryPath, c.String("output")),
 			FrameCount:    result.FrameCount,
 			DurationMs:    result.VideoDuration,
 			FileSize:      result.VideoFileSize,
 			CanvasWidth:   result.CanvasWidth,
 			CanvasHeight:  result.CanvasHeight,
-			CRF:           cfg.VideoCRF,
+			CRF:           result.VideoCRF,
 			OutroDuration: cfg.OutroMs,
+			MaxSize:       cfg.MaxSize,
+			EncodePasses:  result.EncodePasses,
 		}).
-		Build()
+		WithFrames(summaryFrames(result.Frames)).
+		WithRequests(summaryRequests(result.Requests))
+	if result.FilmstripFrames > 0 {
+		b.WithFilmstrip(summarizer.FilmstripInfo{
+			Path:       summaryRelPath(summaryPath, cfg.FilmstripPath),
+			FrameCount: result.FilmstripFrames,
+		})
+	}
+	if result.Poster != nil {
+		b.WithPoster(summaryPoster(summaryPath, result.Poster))
+	}
+	return b.Build()
+}
+
+// summaryRequests converts the recorded network requests for the summary.
+func summaryRequests(requests []orchestrator.RequestResult) []summarizer.RequestInfo {
+	result := make([]summarizer.RequestInfo, 0, len(requests))
+	for _, r := range requests {
+		result = append(result, summarizer.RequestInfo(r))
+	}
+	return result
+}
+
+// summaryFrames converts the recorded frames for the summary.
+func summaryFrames(frames []orchestrator.FrameResult) []summarizer.FrameInfo {
+	result := make([]summarizer.FrameInfo, 0, len(frames))
+	for _, f := range frames {
+		result = append(result, summarizer.FrameInfo{
+			TimestampMs:     f.TimestampMs,
+			Progress:        f.Progress,
+			LoadedResources: f.LoadedResources,
+			TotalResources:  f.TotalResources,
+			TotalBytes:      f.TotalBytes,
+		})
+	}
+	return result
+}
+
+// summaryMarks converts selected timing marks for the summary.
+func summaryMarks(marks []orchestrator.TimingMarkResult) []summarizer.MarkTiming {
+	result := make([]summarizer.MarkTiming, 0, len(marks))
+	for _, m := range marks {
+		result = append(result, summarizer.MarkTiming{
+			Name:     m.Name,
+			Label:    m.Label,
+			TimeMs:   m.TimeMs,
+			Recorded: m.Recorded,
+		})
+	}
+	return result
+}
+
+// summaryRelPath makes a written file's path relative to the summary file.
+func summaryRelPath(summaryPath, path string) string {
+	r, err := filepath.Rel(filepath.Dir(summaryPath), path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(r)
+}
+
+// summaryPoster converts the written poster for the summary.
+// Paths are made relative to the summary file so image links resolve.
+func summaryPoster(summaryPath string, poster *orchestrator.PosterResult) summarizer.PosterInfo {
+	info := summarizer.PosterInfo{
+		Path:        summaryRelPath(summaryPath, poster.Path),
+		TimestampMs: poster.TimestampMs,
+		Width:       poster.Width,
+		Height:      poster.Height,
+	}
+	for _, thumb := range poster.Thumbnails {
+		info.Thumbnails = append(info.Thumbnails, summarizer.ThumbnailInfo{
+			Path:   summaryRelPath(summaryPath, thumb.Path),
+			Width:  thumb.Width,
+			Height: thumb.Height,
+		})
+	}
+	return info
+}
+
+// parseTimingMark parses a --timing-mark value of the form name[:label[:#color]].
+// The color is nil when omitted.
+func parseTimingMark(spec string) (name, label string, c color.Color) {
+	parts := strings.SplitN(spec, ":", 3)
+	name = strings.TrimSpace(parts[0])
+	if len(parts) > 1 {
+		label = strings.TrimSpace(parts[1])
+	}
+	if len(parts) > 2 && strings.TrimSpace(parts[2]) != "" {
+		c = config.ParseColor(strings.TrimSpace(parts[2]))
+	}
+	return name, label, c
+}
+
+// newRecordEncoder creates the encoder for the output format and returns it
+// with a codec name for logging.
+func newRecordEncoder(c *cli.Context, format loadshow.OutputFormat, log ports.Logger) (ports.VideoEncoder, string, error) {
+	switch format {
+	case loadshow.FormatGIF:
+		return animencoder.NewGIF(), "GIF", nil
+	case loadshow.FormatWebP:
+		return animencoder.NewWebP(), "WebP", nil
+	case loadshow.FormatAPNG:
+		return animencoder.NewAPNG(), "APNG", nil
+	}
+
+	// WebM carries AV1 or VP9, so the H.264 default becomes AV1 there
+	requestedCodec := c.String("codec")
+	if format == loadshow.FormatWebM {
+		if requestedCodec == "h264" {
+			if c.IsSet("codec") {
+				return nil, "", fmt.Errorf("codec h264 cannot be written to WebM (use av1 or vp9)")
+			}
+			requestedCodec = "av1"
+		}
+	} else if requestedCodec == "vp9" {
+		return nil, "", fmt.Errorf("codec vp9 requires WebM output (.webm)")
+	}
+
+	var preferred smartencoder.Codec
+	switch requestedCodec {
+	case "av1":
+		preferred = smartencoder.CodecAV1
+	case "h264":
+		preferred = smartencoder.CodecH264
+	case "vp9":
+		preferred = smartencoder.CodecVP9
+	default:
+		return nil, "", fmt.Errorf("unknown codec: %s (supported: h264, av1, vp9)", requestedCodec)
+	}
+
+	container := ports.ContainerMP4
+	if format == loadshow.FormatWebM {
+		container = ports.ContainerWebM
+	}
+	encoder, encoderInfo, err := smartencoder.New(preferred, smartencoder.Options{
+		FFmpegPath:    c.String("ffmpeg-path"),
+		AllowFallback: true,
+		Logger:        log,
+		Container:     container,
+	})
+	if errors.Is(err, smartencoder.ErrWebMNotAvailable) {
+		return nil, "", fmt.Errorf("WebM output needs libaom or ffmpeg with libvpx-vp9; install ffmpeg with libvpx or write .mp4 instead")
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("create encoder: %w", err)
+	}
+
+	// Build codec name for logging
+	var codecName string
+	switch {
+	case encoderInfo.Codec == smartencoder.CodecAV1:
+		codecName = "AV1"
+	case encoderInfo.Codec == smartencoder.CodecMJPEG:
+		codecName = "Motion JPEG"
+	case encoderInfo.Codec == smartencoder.CodecVP9:
+		codecName = "VP9 (ffmpeg)"
+	case encoderInfo.Backend == smartencoder.BackendOS:
+		codecName = "H.264 (native)"
+	case encoderInfo.Backend == smartencoder.BackendFFmpeg:
+		codecName = "H.264 (ffmpeg)"
+	default:
+		codecName = string(encoderInfo.Codec)
+	}
+	return encoder, codecName, nil
 }
 
 // buildRecordConfig creates a Config from preset and CLI overrides.
-func buildRecordConfig(c *cli.Context) loadshow.Config {
+func buildRecordConfig(c *cli.Context, format loadshow.OutputFormat) loadshow.Config {
 	// Start with device preset
 	var builder *loadshow.ConfigBuilder
 	switch c.String("preset") {
@@ -600,6 +1283,9 @@ func buildRecordConfig(c *cli.Context) loadshow.Config {
 	// Apply quality preset
 	builder.WithQualityPreset(loadshow.QualityPreset(c.String("quality")))
 
+	// Apply output format (sets the frame rate default)
+	builder.WithFormat(format)
+
 	// Apply video dimensions
 	if c.Int("width") > 0 {
 		builder.WithWidth(c.Int("width"))
@@ -652,6 +1338,18 @@ func buildRecordConfig(c *cli.Context) loadshow.Config {
 		builder.WithBorderWidth(c.Int("border-width"))
 	}
 
+	// Apply device frame
+	if c.String("device-frame") != "" {
+		builder.WithDeviceFrame(c.String("device-frame"))
+	}
+	if c.String("frame-image") != "" {
+		slice := c.IntSlice("frame-slice")
+		if len(slice) == 1 {
+			slice = []int{slice[0], slice[0], slice[0], slice[0]}
+		}
+		builder.WithFrameImage(c.String("frame-image"), slice[0], slice[1], slice[2], slice[3])
+	}
+
 	// Apply network throttling (convert Mbps to bytes/sec)
 	if c.Float64("download-mbps") > 0 {
 		builder.WithDownloadSpeed(loadshow.MbpsToBytes(c.Float64("download-mbps")))
@@ -670,6 +1368,25 @@ func buildRecordConfig(c *cli.Context) loadshow.Config {
 		builder.WithCredit(c.String("credit"))
 	}
 
+	// Apply overlay options
+	if c.Bool("highlight-changes") {
+		builder.WithHighlightChanges(true)
+	}
+	if c.Int("highlight-fade-ms") > 0 {
+		builder.WithHighlightFadeMs(c.Int("highlight-fade-ms"))
+	}
+	if c.Bool("show-layout-shifts") {
+		builder.WithShowLayoutShifts(true)
+	}
+	if c.Bool("show-lcp") {
+		builder.WithShowLCP(true)
+	}
+	for _, spec := range c.StringSlice("timing-mark") {
+		if name, label, markColor := parseTimingMark(spec); name != "" {
+			builder.WithTimingMark(name, label, markColor)
+		}
+	}
+
 	// Apply browser options
 	if c.Bool("ignore-https-errors") {
 		builder.WithIgnoreHTTPSErrors(true)
@@ -683,17 +1400,50 @@ func buildRecordConfig(c *cli.Context) loadshow.Config {
 		builder.WithTimeoutSec(c.Int("timeout-sec"))
 	}
 
+	builder.WithVersion(version)
+
+	// Apply subtitles
+	builder.WithSubtitles(c.Bool("subtitles"))
+	builder.WithSubtitlesFile(c.String("output-subtitles"))
+
 	return builder.Build()
 }
 
 func runJuxtapose(c *cli.Context) error {
 	if c.NArg() < 2 {
-		return errors.New(l10n.T("Two video arguments are required"))
+		return errors.New(l10n.T("At least two video arguments are required"))
 	}
-	left := c.Args().Get(0)
-	right := c.Args().Get(1)
+	inputs := c.Args().Slice()
 	output := c.String("output")
 
+	layout, err := parseJuxtaposeLayout(c.String("layout"))
+	if err != nil {
+		return err
+	}
+
+	align, err := parseJuxtaposeAlign(c)
+	if err != nil {
+		return err
+	}
+	offsets := c.IntSlice("offset")
+	if len(offsets) > len(inputs) {
+		return fmt.Errorf("too many offsets: %d offsets for %d videos", len(offsets), len(inputs))
+	}
+
+	labels := c.StringSlice("label")
+	if len(labels) > len(inputs) {
+		return fmt.Errorf("too many labels: %d labels for %d videos", len(labels), len(inputs))
+	}
+	var labelPosition juxtapose.LabelPosition
+	if len(labels) > 0 || c.Bool("show-timings") {
+		switch p := juxtapose.LabelPosition(c.String("label-position")); p {
+		case juxtapose.LabelTop, juxtapose.LabelBottom:
+			labelPosition = p
+		default:
+			return fmt.Errorf("unknown label position: %s (supported: top, bottom)", c.String("label-position"))
+		}
+	}
+
 	// Create logger
 	log := logger.NewConsole(ports.LevelInfo)
 
@@ -724,35 +1474,116 @@ func runJuxtapose(c *cli.Context) error {
 	ffmpegPath := c.String("ffmpeg-path")
 
 	// Auto-detect input video codecs using smart decoder
-	leftCodec, err := smartdecoder.DetectCodec(left)
+	decoder, inputCodec, err := newInputDecoder(log, inputs, ffmpegPath)
+	if err != nil {
+		return err
+	}
+	defer decoder.Close()
+
+	// Select encoder based on --codec option using smart encoder
+	encoder, codecName, err := newOutputEncoder(log, c.String("codec"), ffmpegPath)
 	if err != nil {
-		return fmt.Errorf("failed to detect codec for %s: %w", left, err)
+		return err
+	}
+
+	// Create juxtapose options
+	opts := juxtapose.Options{
+		Layout:      layout,
+		Columns:     c.Int("columns"),
+		Gap:         c.Int("gap"),
+		BorderColor: juxtapose.DefaultBorderColor,
+		FPS:         30.0,
+		Quality:     videoCRF,
+		Bitrate:     0,
+	}
+
+	// Apply border color if specified
+	if c.String("border-color") != "" {
+		opts.BorderColor = config.ParseColor(c.String("border-color"))
 	}
-	rightCodec, err := smartdecoder.DetectCodec(right)
+
+	// Captions
+	opts.LabelPosition = labelPosition
+	opts.LabelFontSize = c.Float64("label-font-size")
+	if c.String("label-color") != "" {
+		opts.LabelColor = config.ParseColor(c.String("label-color"))
+	}
+	captions := juxtaposeCaptions(log, inputs, labels, c.Bool("show-timings"))
+
+	// Alignment
+	opts.Align = align
+	opts.Milestone = c.String("milestone")
+	opts.Offsets = offsets
+	var metadata []ports.VideoMetadata
+	if align == juxtapose.AlignMilestone {
+		metadata = juxtaposeMetadata(inputs)
+	}
+
+	// Create and run juxtapose stage
+	stage := juxtapose.New(decoder, encoder, fs, log, opts)
+
+	log.Info(l10n.F("Creating comparison video: %s → %s", strings.Join(inputs, " + "), output))
+	log.Info(l10n.F("Input codec: %s, Output codec: %s (CRF %d)", inputCodec, codecName, videoCRF))
+
+	result, err := stage.Execute(ctx, juxtapose.Input{
+		Paths:      inputs,
+		OutputPath: output,
+		Captions:   captions,
+		Metadata:   metadata,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to detect codec for %s: %w", right, err)
+		return fmt.Errorf("juxtapose: %w", err)
 	}
 
-	log.Debug(l10n.F("Left video codec: %s, Right video codec: %s", leftCodec, rightCodec))
+	if align != juxtapose.AlignStart {
+		for i, offset := range result.StartOffsetsMs {
+			log.Info(l10n.F("%s: aligned at %dms", inputs[i], offset))
+		}
+	}
+
+	log.Info(l10n.F("Output saved to %s", result.OutputPath))
+	log.Info(l10n.F("Frames: %d, Duration: %dms", result.FrameCount, result.DurationMs))
+
+	return nil
+}
+
+// newInputDecoder detects the codec of the input videos and creates a decoder
+// for it. All inputs must use the same codec.
+func newInputDecoder(log ports.Logger, inputs []string, ffmpegPath string) (*smartdecoder.Decoder, smartdecoder.Codec, error) {
+	var inputCodec smartdecoder.Codec
+	for i, input := range inputs {
+		codec, err := smartdecoder.DetectCodec(input)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to detect codec for %s: %w", input, err)
+		}
+		log.Debug(l10n.F("%s: codec %s", input, codec))
+
+		// Currently all inputs should have the same codec
+		if i == 0 {
+			inputCodec = codec
+		} else if codec != inputCodec {
+			return nil, "", fmt.Errorf("mixed codec inputs not supported: %s=%s, %s=%s", inputs[0], inputCodec, input, codec)
+		}
 
-	// Currently both inputs should have the same codec
-	if leftCodec != rightCodec {
-		return fmt.Errorf("mixed codec inputs not supported: left=%s, right=%s", leftCodec, rightCodec)
+		// Show what each input recorded, when it carries loadshow metadata
+		logVideoMetadata(log, input)
 	}
 
 	// Create decoder using smart decoder (auto-selects based on codec)
-	decoder, decoderInfo, err := smartdecoder.NewForCodec(leftCodec, smartdecoder.Options{
+	decoder, decoderInfo, err := smartdecoder.NewForCodec(inputCodec, smartdecoder.Options{
 		FFmpegPath: ffmpegPath,
 	})
 	if err != nil {
-		return fmt.Errorf("create decoder: %w", err)
+		return nil, "", fmt.Errorf("create decoder: %w", err)
 	}
-	defer decoder.Close()
 
 	log.Debug(l10n.F("Using decoder: codec=%s, backend=%s", decoderInfo.Codec, decoderInfo.Backend))
+	return decoder, inputCodec, nil
+}
 
-	// Select encoder based on --codec option using smart encoder
-	requestedCodec := c.String("codec")
+// newOutputEncoder creates the encoder for --codec and returns it with
+// a codec name for logging.
+func newOutputEncoder(log ports.Logger, requestedCodec, ffmpegPath string) (ports.VideoEncoder, string, error) {
 	var preferred smartencoder.Codec
 	switch requestedCodec {
 	case "av1":
@@ -760,7 +1591,7 @@ func runJuxtapose(c *cli.Context) error {
 	case "h264":
 		preferred = smartencoder.CodecH264
 	default:
-		return fmt.Errorf("unknown codec: %s (supported: h264, av1)", requestedCodec)
+		return nil, "", fmt.Errorf("unknown codec: %s (supported: h264, av1)", requestedCodec)
 	}
 
 	encoder, encoderInfo, err := smartencoder.New(preferred, smartencoder.Options{
@@ -769,7 +1600,7 @@ func runJuxtapose(c *cli.Context) error {
 		Logger:        log,
 	})
 	if err != nil {
-		return fmt.Errorf("create encoder: %w", err)
+		return nil, "", fmt.Errorf("create encoder: %w", err)
 	}
 
 	// Build codec name for logging
@@ -777,6 +1608,8 @@ func runJuxtapose(c *cli.Context) error {
 	switch {
 	case encoderInfo.Codec == smartencoder.CodecAV1:
 		codecName = "AV1"
+	case encoderInfo.Codec == smartencoder.CodecMJPEG:
+		codecName = "Motion JPEG"
 	case encoderInfo.Backend == smartencoder.BackendOS:
 		codecName = "H.264 (native)"
 	case encoderInfo.Backend == smartencoder.BackendFFmpeg:
@@ -784,38 +1617,870 @@ func runJuxtapose(c *cli.Context) error {
 	default:
 		codecName = string(encoderInfo.Codec)
 	}
+	return encoder, codecName, nil
+}
 
-	// Create juxtapose options
-	opts := juxtapose.Options{
-		Gap:         c.Int("gap"),
-		BorderColor: juxtapose.DefaultBorderColor,
-		FPS:         30.0,
-		Quality:     videoCRF,
-		Bitrate:     0,
+// juxtaposeCaptions builds the caption of each input from --label values.
+// With showTimings, DOMContentLoaded and Load times are read from the
+// metadata embedded by loadshow record.
+func juxtaposeCaptions(log ports.Logger, inputs, labels []string, showTimings bool) []juxtapose.Caption {
+	captions := make([]juxtapose.Caption, len(inputs))
+	for i, input := range inputs {
+		if i < len(labels) {
+			captions[i].Text = labels[i]
+		}
+		if !showTimings {
+			continue
+		}
+		md, err := mp4meta.ReadFile(input)
+		if err != nil {
+			log.Warn(l10n.F("No timing metadata in %s: %s", input, err))
+			continue
+		}
+		captions[i] = juxtapose.CaptionFromMetadata(captions[i].Text, md)
 	}
+	return captions
+}
 
-	// Apply border color if specified
-	if c.String("border-color") != "" {
-		opts.BorderColor = config.ParseColor(c.String("border-color"))
+// juxtaposeMetadata reads the embedded metadata of each input.
+// Inputs without metadata get an empty entry.
+func juxtaposeMetadata(inputs []string) []ports.VideoMetadata {
+	metadata := make([]ports.VideoMetadata, len(inputs))
+	for i, input := range inputs {
+		if md, err := mp4meta.ReadFile(input); err == nil {
+			metadata[i] = md
+		}
 	}
+	return metadata
+}
 
-	// Create and run juxtapose stage
-	stage := juxtapose.New(decoder, encoder, fs, log, opts)
+// parseJuxtaposeAlign validates --align. Per-input offsets imply
+// offset alignment unless another alignment is requested.
+func parseJuxtaposeAlign(c *cli.Context) (juxtapose.Align, error) {
+	if !c.IsSet("align") && len(c.IntSlice("offset")) > 0 {
+		return juxtapose.AlignOffset, nil
+	}
+	switch a := juxtapose.Align(c.String("align")); a {
+	case juxtapose.AlignStart, juxtapose.AlignFirstChange, juxtapose.AlignMilestone, juxtapose.AlignOffset:
+		return a, nil
+	default:
+		return "", fmt.Errorf("unknown alignment: %s (supported: start, first-change, milestone, offset)", c.String("align"))
+	}
+}
 
-	log.Info(l10n.F("Creating comparison video: %s + %s → %s", left, right, output))
-	log.Info(l10n.F("Input codec: %s, Output codec: %s (CRF %d)", leftCodec, codecName, videoCRF))
+// parseJuxtaposeLayout validates a juxtapose layout.
+func parseJuxtaposeLayout(layout string) (juxtapose.Layout, error) {
+	switch l := juxtapose.Layout(layout); l {
+	case juxtapose.LayoutHorizontal, juxtapose.LayoutVertical, juxtapose.LayoutGrid:
+		return l, nil
+	default:
+		return "", fmt.Errorf("unknown layout: %s (supported: horizontal, vertical, grid)", layout)
+	}
+}
 
-	result, err := stage.Execute(ctx, juxtapose.Input{
-		LeftPath:   left,
-		RightPath:  right,
+// logVideoMetadata logs the URL and recording time embedded in a video, if any.
+func logVideoMetadata(log ports.Logger, path string) {
+	md, err := mp4meta.ReadFile(path)
+	if err != nil {
+		return
+	}
+	log.Info(l10n.F("%s: %s recorded at %s by %s", path, md.URL, md.RecordedAt.Local().Format("2006-01-02 15:04:05"), md.Generator))
+}
+
+// parseFilmstripMode validates a filmstrip sampling mode.
+func parseFilmstripMode(mode string) (pipeline.FilmstripMode, error) {
+	switch m := pipeline.FilmstripMode(mode); m {
+	case pipeline.FilmstripInterval, pipeline.FilmstripChanges:
+		return m, nil
+	default:
+		return "", fmt.Errorf("unknown filmstrip mode: %s (supported: interval, changes)", mode)
+	}
+}
+
+// parsePosterFrame parses a --poster-frame value: final, lcp, load, or a time in milliseconds.
+func parsePosterFrame(value string) (pipeline.PosterFrame, int, error) {
+	switch f := pipeline.PosterFrame(value); f {
+	case pipeline.PosterFinal, pipeline.PosterLCP, pipeline.PosterLoad:
+		return f, 0, nil
+	}
+	ms, err := strconv.Atoi(value)
+	if err != nil || ms < 0 {
+		return "", 0, fmt.Errorf("unknown poster frame: %s (supported: final, lcp, load, or milliseconds)", value)
+	}
+	return pipeline.PosterTime, ms, nil
+}
+
+func runDiff(c *cli.Context) error {
+	if c.NArg() != 2 {
+		return errors.New(l10n.T("Two video arguments are required"))
+	}
+	inputs := c.Args().Slice()
+	output := c.String("output")
+
+	mode := videodiff.Mode(c.String("mode"))
+	switch mode {
+	case videodiff.ModeHeatmap, videodiff.ModeOverlay:
+	default:
+		return fmt.Errorf("unknown diff mode: %s (supported: heatmap, overlay)", c.String("mode"))
+	}
+	threshold := c.Int("threshold")
+	if threshold < 0 || threshold > 255 {
+		return fmt.Errorf("invalid threshold: %d (must be 0-255)", threshold)
+	}
+
+	// Create logger
+	log := logger.NewConsole(ports.LevelInfo)
+
+	// Setup context with cancellation
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Handle signals
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		log.Warn(l10n.T("Interrupted, shutting down..."))
+		cancel()
+	}()
+
+	// Determine CRF from quality preset or explicit value
+	videoCRF := c.Int("video-crf")
+	if videoCRF == 0 {
+		settings := loadshow.GetQualitySettings(loadshow.QualityPreset(c.String("quality")))
+		videoCRF = settings.VideoCRF
+	}
+
+	ffmpegPath := c.String("ffmpeg-path")
+	decoder, inputCodec, err := newInputDecoder(log, inputs, ffmpegPath)
+	if err != nil {
+		return err
+	}
+	defer decoder.Close()
+
+	encoder, codecName, err := newOutputEncoder(log, c.String("codec"), ffmpegPath)
+	if err != nil {
+		return err
+	}
+
+	opts := videodiff.DefaultOptions()
+	opts.Mode = mode
+	opts.Threshold = threshold
+	opts.Quality = videoCRF
+	if c.String("color") != "" {
+		opts.Color = config.ParseColor(c.String("color"))
+	}
+
+	stage := videodiff.New(decoder, encoder, osfilesystem.New(), log, opts)
+
+	log.Info(l10n.F("Creating difference video: %s → %s", strings.Join(inputs, " vs "), output))
+	log.Info(l10n.F("Input codec: %s, Output codec: %s (CRF %d)", inputCodec, codecName, videoCRF))
+
+	result, err := stage.Execute(ctx, videodiff.Input{
+		BeforePath: inputs[0],
+		AfterPath:  inputs[1],
 		OutputPath: output,
+		ScoresPath: c.String("scores"),
 	})
 	if err != nil {
-		return fmt.Errorf("juxtapose: %w", err)
+		return fmt.Errorf("diff: %w", err)
+	}
+
+	// Summarize how much and how long the recordings differ
+	var peak videodiff.FrameScore
+	differing := 0
+	for _, score := range result.Scores {
+		if score.ChangedRatio > peak.ChangedRatio {
+			peak = score
+		}
+		if score.ChangedRatio > 0 {
+			differing++
+		}
 	}
 
 	log.Info(l10n.F("Output saved to %s", result.OutputPath))
 	log.Info(l10n.F("Frames: %d, Duration: %dms", result.FrameCount, result.DurationMs))
+	log.Info(l10n.F("Differing frames: %d, peak %.1f%% of pixels at %dms", differing, peak.ChangedRatio*100, peak.TimestampMs))
+	if c.String("scores") != "" {
+		log.Info(l10n.F("Scores saved to %s", c.String("scores")))
+	}
 
 	return nil
 }
+
+func runFilmstrip(c *cli.Context) error {
+	if c.NArg() < 1 {
+		return errors.New(l10n.T("Video argument is required"))
+	}
+	input := c.Args().Get(0)
+	output := c.String("output")
+
+	mode, err := parseFilmstripMode(c.String("mode"))
+	if err != nil {
+		return err
+	}
+
+	// Create logger
+	log := logger.NewConsole(ports.LevelInfo)
+
+	// Setup context with cancellation
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Handle signals
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		log.Warn(l10n.T("Interrupted, shutting down..."))
+		cancel()
+	}()
+
+	// Create adapters
+	fs := osfilesystem.New()
+	renderer := ggrenderer.New()
+
+	// Auto-detect input video codec using smart decoder
+	codec, err := smartdecoder.DetectCodec(input)
+	if err != nil {
+		return fmt.Errorf("failed to detect codec for %s: %w", input, err)
+	}
+	decoder, decoderInfo, err := smartdecoder.NewForCodec(codec, smartdecoder.Options{
+		FFmpegPath: c.String("ffmpeg-path"),
+	})
+	if err != nil {
+		return fmt.Errorf("create decoder: %w", err)
+	}
+	defer decoder.Close()
+
+	log.Debug(l10n.F("Using decoder: codec=%s, backend=%s", decoderInfo.Codec, decoderInfo.Backend))
+	log.Info(l10n.F("Creating filmstrip: %s → %s", input, output))
+
+	frames, err := decoder.OpenFrames(input)
+	if err != nil {
+		return fmt.Errorf("open video: %w", err)
+	}
+	defer frames.Close()
+
+	stageInput := pipeline.DefaultFilmstripInput()
+	// Compare only the page, not the banner, progress bar and badges
+	if md, err := mp4meta.ReadFile(input); err == nil {
+		stageInput.CompareArea, _ = orchestrator.ScreenArea(md)
+	}
+	if stageInput.CompareArea == (pipeline.Rectangle{}) {
+		log.Warn(l10n.F("No screen area in the metadata of %s; overlays may count as changes", input))
+	}
+	stageInput.Mode = mode
+	stageInput.IntervalMs = c.Int("interval")
+	stageInput.Columns = c.Int("columns")
+	stageInput.ThumbWidth = c.Int("thumb-width")
+	stageInput.Format = orchestrator.FilmstripFormat(output)
+
+	stage := filmstrip.NewStage(renderer, log)
+	result, err := stage.ExecuteVideo(ctx, frames, stageInput)
+	if err != nil {
+		return fmt.Errorf("filmstrip: %w", err)
+	}
+
+	if err := fs.WriteFile(output, result.ImageData); err != nil {
+		return fmt.Errorf("write filmstrip: %w", err)
+	}
+
+	log.Info(l10n.F("Filmstrip saved to %s (%d frames)", output, result.FrameCount))
+	return nil
+}
+
+func inspectCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "inspect",
+		Usage:     l10n.T("Show container, frame and metadata information of a video"),
+		ArgsUsage: "<video>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "format",
+				Aliases:  []string{"f"},
+				Value:    "text",
+				Usage:    l10n.T("Report format (text, json)"),
+				Category: l10n.T(catOutput),
+			},
+			&cli.BoolFlag{
+				Name:     "frames",
+				Usage:    l10n.T("List every frame with its timestamp, size and keyframe flag"),
+				Category: l10n.T(catOutput),
+			},
+			&cli.IntSliceFlag{
+				Name:     "extract",
+				Usage:    l10n.T("Save the frame shown at this time in milliseconds as PNG (repeatable)"),
+				Category: l10n.T(catOutput),
+			},
+			&cli.StringFlag{
+				Name:     "extract-dir",
+				Value:    ".",
+				Usage:    l10n.T("Directory for extracted frames"),
+				Category: l10n.T(catOutput),
+			},
+			&cli.StringFlag{
+				Name:     "ffmpeg-path",
+				Usage:    l10n.T("Path to ffmpeg executable (Linux only, for H.264)"),
+				Category: l10n.T(catVideoQuality),
+			},
+		},
+		Action: runInspect,
+	}
+}
+
+func runInspect(c *cli.Context) error {
+	if c.NArg() < 1 {
+		return errors.New(l10n.T("Video argument is required"))
+	}
+	input := c.Args().Get(0)
+
+	format := c.String("format")
+	if format != "text" && format != "json" {
+		return fmt.Errorf("unknown report format: %s (supported: text, json)", format)
+	}
+
+	report, err := inspect.InspectFile(input)
+	if err != nil {
+		return fmt.Errorf("inspect: %w", err)
+	}
+
+	if format == "json" {
+		err = inspect.WriteJSON(os.Stdout, report, c.Bool("frames"))
+	} else {
+		err = inspect.WriteText(os.Stdout, report, c.Bool("frames"))
+	}
+	if err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+
+	timestamps := c.IntSlice("extract")
+	if len(timestamps) == 0 {
+		return nil
+	}
+
+	// Keep a JSON report on stdout valid
+	var log ports.Logger = logger.NewConsole(ports.LevelInfo)
+	if format == "json" {
+		log = logger.NewNoop()
+	}
+
+	decoder, decoderInfo, err := smartdecoder.NewForCodec(smartdecoder.Codec(report.Codec), smartdecoder.Options{
+		FFmpegPath: c.String("ffmpeg-path"),
+	})
+	if err != nil {
+		return fmt.Errorf("create decoder: %w", err)
+	}
+	defer decoder.Close()
+	log.Debug(l10n.F("Using decoder: codec=%s, backend=%s", decoderInfo.Codec, decoderInfo.Backend))
+
+	frames, err := inspect.ExtractFrames(decoder, input, timestamps)
+	if err != nil {
+		return fmt.Errorf("extract frames: %w", err)
+	}
+
+	fs := osfilesystem.New()
+	renderer := ggrenderer.New()
+	dir := c.String("extract-dir")
+	if err := fs.MkdirAll(dir); err != nil {
+		return fmt.Errorf("create extract directory: %w", err)
+	}
+	base := strings.TrimSuffix(filepath.Base(input), filepath.Ext(input))
+	for i, frame := range frames {
+		data, err := renderer.EncodeImage(frame.Image, ports.FormatPNG, 0)
+		if err != nil {
+			return fmt.Errorf("encode frame: %w", err)
+		}
+		path := filepath.Join(dir, fmt.Sprintf("%s-%dms.png", base, timestamps[i]))
+		if err := fs.WriteFile(path, data); err != nil {
+			return fmt.Errorf("write frame: %w", err)
+		}
+		log.Info(l10n.F("Frame at %dms saved to %s", timestamps[i], path))
+	}
+
+	return nil
+}
+
+func editCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "edit",
+		Usage: l10n.T("Trim, concatenate or change the speed of recorded videos"),
+		Subcommands: []*cli.Command{
+			{
+				Name:      "trim",
+				Usage:     l10n.T("Keep a time range of a video"),
+				ArgsUsage: "<video>",
+				Flags: append(editOutputFlags(),
+					&cli.IntFlag{
+						Name:     "start",
+						Usage:    l10n.T("Start of the kept range in milliseconds"),
+						Category: l10n.T(catEdit),
+					},
+					&cli.IntFlag{
+						Name:     "end",
+						Usage:    l10n.T("End of the kept range in milliseconds (0 = end of video)"),
+						Category: l10n.T(catEdit),
+					},
+				),
+				Action: runEditTrim,
+			},
+			{
+				Name:      "concat",
+				Usage:     l10n.T("Play videos one after another"),
+				ArgsUsage: "<video> <video>...",
+				Flags: append(editOutputFlags(),
+					&cli.IntFlag{
+						Name:     "gap",
+						Usage:    l10n.T("Solid frames between videos in milliseconds"),
+						Category: l10n.T(catEdit),
+					},
+					&cli.StringFlag{
+						Name:     "gap-color",
+						Value:    "#000000",
+						Usage:    l10n.T("Color of gap frames and letterboxing (hex)"),
+						Category: l10n.T(catEdit),
+					},
+					&cli.StringFlag{
+						Name:     "transition",
+						Value:    "cut",
+						Usage:    l10n.T("Transition between videos (cut, fade)"),
+						Category: l10n.T(catEdit),
+					},
+					&cli.IntFlag{
+						Name:     "transition-duration",
+						Value:    500,
+						Usage:    l10n.T("Fade length in milliseconds"),
+						Category: l10n.T(catEdit),
+					},
+				),
+				Action: runEditConcat,
+			},
+			{
+				Name:      "speed",
+				Usage:     l10n.T("Change the playback speed of a video"),
+				ArgsUsage: "<video>",
+				Flags: append(editOutputFlags(),
+					&cli.Float64Flag{
+						Name:     "factor",
+						Usage:    l10n.T("Playback speed (2 = twice as fast, 0.5 = half speed) (required)"),
+						Required: true,
+						Category: l10n.T(catEdit),
+					},
+				),
+				Action: runEditSpeed,
+			},
+		},
+	}
+}
+
+// editOutputFlags returns the output flags shared by the edit subcommands.
+func editOutputFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:     "output",
+			Aliases:  []string{"o"},
+			Usage:    l10n.T("Output MP4 file path (required)"),
+			Required: true,
+			Category: l10n.T(catOutput),
+		},
+		&cli.StringFlag{
+			Name:     "quality",
+			Aliases:  []string{"q"},
+			Value:    "medium",
+			Usage:    l10n.T("Quality preset (low, medium, high)"),
+			Category: l10n.T(catPreset),
+		},
+		&cli.StringFlag{
+			Name:     "codec",
+			Value:    "h264",
+			Usage:    l10n.T("Video codec (h264, av1)"),
+			Category: l10n.T(catVideoQuality),
+		},
+		&cli.StringFlag{
+			Name:     "ffmpeg-path",
+			Usage:    l10n.T("Path to ffmpeg executable (Linux only, for H.264)"),
+			Category: l10n.T(catVideoQuality),
+		},
+		&cli.IntFlag{
+			Name:     "video-crf",
+			Usage:    l10n.T("Video CRF value (0-63, lower is better, overrides quality preset)"),
+			Category: l10n.T(catVideoQuality),
+		},
+	}
+}
+
+func runEditTrim(c *cli.Context) error {
+	if c.NArg() < 1 {
+		return errors.New(l10n.T("Video argument is required"))
+	}
+	input := c.Args().Get(0)
+
+	return runEdit(c, []string{input}, func(ctx context.Context, stage *videoedit.Stage, log ports.Logger) (videoedit.Result, error) {
+		log.Info(l10n.F("Trimming %s → %s", input, c.String("output")))
+		return stage.Trim(ctx, videoedit.TrimInput{
+			Path:       input,
+			OutputPath: c.String("output"),
+			StartMs:    c.Int("start"),
+			EndMs:      c.Int("end"),
+		})
+	})
+}
+
+func runEditConcat(c *cli.Context) error {
+	if c.NArg() < 2 {
+		return errors.New(l10n.T("At least two video arguments are required"))
+	}
+	inputs := c.Args().Slice()
+
+	transition := videoedit.Transition(c.String("transition"))
+	switch transition {
+	case videoedit.TransitionCut, videoedit.TransitionFade:
+	default:
+		return fmt.Errorf("unknown transition: %s (supported: cut, fade)", c.String("transition"))
+	}
+
+	return runEdit(c, inputs, func(ctx context.Context, stage *videoedit.Stage, log ports.Logger) (videoedit.Result, error) {
+		log.Info(l10n.F("Concatenating %s → %s", strings.Join(inputs, " + "), c.String("output")))
+		result, err := stage.Concat(ctx, videoedit.ConcatInput{
+			Paths:        inputs,
+			OutputPath:   c.String("output"),
+			GapMs:        c.Int("gap"),
+			GapColor:     config.ParseColor(c.String("gap-color")),
+			Transition:   transition,
+			TransitionMs: c.Int("transition-duration"),
+		})
+		for i, start := range result.ClipStartsMs {
+			log.Info(l10n.F("%s: starts at %dms", inputs[i], start))
+		}
+		return result, err
+	})
+}
+
+func runEditSpeed(c *cli.Context) error {
+	if c.NArg() < 1 {
+		return errors.New(l10n.T("Video argument is required"))
+	}
+	input := c.Args().Get(0)
+
+	return runEdit(c, []string{input}, func(ctx context.Context, stage *videoedit.Stage, log ports.Logger) (videoedit.Result, error) {
+		log.Info(l10n.F("Changing speed of %s by %vx → %s", input, c.Float64("factor"), c.String("output")))
+		return stage.Speed(ctx, videoedit.SpeedInput{
+			Path:       input,
+			OutputPath: c.String("output"),
+			Factor:     c.Float64("factor"),
+		})
+	})
+}
+
+// runEdit creates the decoder, encoder and edit stage shared by the edit
+// subcommands and runs one edit.
+func runEdit(c *cli.Context, inputs []string, edit func(context.Context, *videoedit.Stage, ports.Logger) (videoedit.Result, error)) error {
+	// Create logger
+	log := logger.NewConsole(ports.LevelInfo)
+
+	// Setup context with cancellation
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Handle signals
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		log.Warn(l10n.T("Interrupted, shutting down..."))
+		cancel()
+	}()
+
+	// Determine CRF from quality preset or explicit value
+	videoCRF := c.Int("video-crf")
+	if videoCRF == 0 {
+		settings := loadshow.GetQualitySettings(loadshow.QualityPreset(c.String("quality")))
+		videoCRF = settings.VideoCRF
+	}
+
+	ffmpegPath := c.String("ffmpeg-path")
+	decoder, inputCodec, err := newInputDecoder(log, inputs, ffmpegPath)
+	if err != nil {
+		return err
+	}
+	defer decoder.Close()
+
+	encoder, codecName, err := newOutputEncoder(log, c.String("codec"), ffmpegPath)
+	if err != nil {
+		return err
+	}
+
+	opts := videoedit.DefaultOptions()
+	opts.Quality = videoCRF
+	stage := videoedit.New(decoder, encoder, osfilesystem.New(), log, opts)
+
+	log.Info(l10n.F("Input codec: %s, Output codec: %s (CRF %d)", inputCodec, codecName, videoCRF))
+
+	result, err := edit(ctx, stage, log)
+	if err != nil {
+		return fmt.Errorf("edit: %w", err)
+	}
+
+	log.Info(l10n.F("Output saved to %s", result.OutputPath))
+	log.Info(l10n.F("Frames: %d, Duration: %dms", result.FrameCount, result.DurationMs))
+
+	return nil
+}
+
+func compareResultsCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "compare-results",
+		Usage:     l10n.T("Compare two JSON run summaries and report the deltas"),
+		ArgsUsage: "<a.json> <b.json>",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:     "format",
+				Aliases:  []string{"f"},
+				Value:    string(summarizer.FormatMarkdown),
+				Usage:    l10n.T("Report format (markdown, json)"),
+				Category: l10n.T(catOutput),
+			},
+			&cli.StringFlag{
+				Name:     "output",
+				Aliases:  []string{"o"},
+				Usage:    l10n.T("Write the report to a file instead of stdout"),
+				Category: l10n.T(catOutput),
+			},
+		}, thresholdFlags()...),
+		Action: runCompareResults,
+	}
+}
+
+// thresholdFlags returns the flags read by thresholdsFromFlags.
+func thresholdFlags() []cli.Flag {
+	defaults := summarizer.DefaultThresholds()
+	return []cli.Flag{
+		&cli.Float64Flag{
+			Name:     "threshold",
+			Value:    defaults.Percent,
+			Usage:    l10n.T("Minimum change in percent to flag a metric"),
+			Category: l10n.T(catThresholds),
+		},
+		&cli.IntFlag{
+			Name:     "threshold-ms",
+			Value:    defaults.TimeMs,
+			Usage:    l10n.T("Minimum change in milliseconds to flag a timing"),
+			Category: l10n.T(catThresholds),
+		},
+		&cli.StringFlag{
+			Name:     "threshold-bytes",
+			Value:    "10KB",
+			Usage:    l10n.T("Minimum change to flag a size (e.g. 10KB, 1MB)"),
+			Category: l10n.T(catThresholds),
+		},
+	}
+}
+
+func thresholdsFromFlags(c *cli.Context) (summarizer.Thresholds, error) {
+	th := summarizer.Thresholds{
+		Percent: c.Float64("threshold"),
+		TimeMs:  c.Int("threshold-ms"),
+	}
+	var err error
+	if th.Bytes, err = loadshow.ParseSize(c.String("threshold-bytes")); err != nil {
+		return th, err
+	}
+	if th.Percent < 0 || th.TimeMs < 0 {
+		return th, errors.New(l10n.T("Thresholds must not be negative"))
+	}
+	return th, nil
+}
+
+func runCompareResults(c *cli.Context) error {
+	if c.NArg() < 2 {
+		return errors.New(l10n.T("Two summary files are required"))
+	}
+
+	format, err := summarizer.ParseFormat(c.String("format"))
+	if err != nil || format == summarizer.FormatHTML {
+		return fmt.Errorf("unknown report format: %s (supported: markdown, json)", c.String("format"))
+	}
+
+	thresholds, err := thresholdsFromFlags(c)
+	if err != nil {
+		return err
+	}
+
+	a, err := summarizer.LoadJSON(c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	b, err := summarizer.LoadJSON(c.Args().Get(1))
+	if err != nil {
+		return err
+	}
+
+	comparison := summarizer.Compare(a, b, thresholds)
+	var report string
+	if format == summarizer.FormatJSON {
+		report = summarizer.ComparisonJSON(comparison)
+	} else {
+		report = summarizer.ComparisonMarkdown(comparison, l10n.T)
+	}
+
+	if path := c.String("output"); path != "" {
+		if err := osfilesystem.New().WriteFile(path, []byte(report)); err != nil {
+			return fmt.Errorf("write report: %w", err)
+		}
+		return nil
+	}
+	_, err = io.WriteString(os.Stdout, report)
+	return err
+}
+
+// exitRegression is the exit status of history check when the latest run regressed.
+const exitRegression = 3
+
+func historyCommand() *cli.Command {
+	filterFlags := func(extra ...cli.Flag) []cli.Flag {
+		return append([]cli.Flag{
+			&cli.StringFlag{
+				Name:     "history",
+				Value:    "loadshow-history.jsonl",
+				Usage:    l10n.T("History file written by record --history"),
+				Category: l10n.T(catHistory),
+			},
+			&cli.StringFlag{
+				Name:     "url",
+				Usage:    l10n.T("Only include runs of this URL"),
+				Category: l10n.T(catHistory),
+			},
+			&cli.StringFlag{
+				Name:     "profile",
+				Usage:    l10n.T("Only include runs of this profile"),
+				Category: l10n.T(catHistory),
+			},
+			&cli.StringFlag{
+				Name:     "format",
+				Aliases:  []string{"f"},
+				Value:    "text",
+				Usage:    l10n.T("Report format (text, json)"),
+				Category: l10n.T(catOutput),
+			},
+		}, extra...)
+	}
+
+	return &cli.Command{
+		Name:  "history",
+		Usage: l10n.T("List recorded runs, show trends and detect regressions"),
+		Subcommands: []*cli.Command{
+			{
+				Name:   "list",
+				Usage:  l10n.T("List the runs in the history"),
+				Flags:  filterFlags(),
+				Action: runHistoryList,
+			},
+			{
+				Name:  "trend",
+				Usage: l10n.T("Show sparklines and medians of the recent runs"),
+				Flags: filterFlags(
+					&cli.IntFlag{
+						Name:     "last",
+						Aliases:  []string{"n"},
+						Value:    10,
+						Usage:    l10n.T("Number of recent runs to include (0 = all)"),
+						Category: l10n.T(catHistory),
+					},
+				),
+				Action: runHistoryTrend,
+			},
+			{
+				Name:  "check",
+				Usage: l10n.T("Compare the latest run with the median of previous runs; exits with status 3 on a regression"),
+				Flags: filterFlags(append([]cli.Flag{
+					&cli.IntFlag{
+						Name:     "window",
+						Value:    5,
+						Usage:    l10n.T("Number of previous runs in the baseline (0 = all)"),
+						Category: l10n.T(catHistory),
+					},
+				}, append(thresholdFlags(),
+					&cli.Float64Flag{
+						Name:     "threshold-cls",
+						Value:    summarizer.DefaultThresholds().CLS,
+						Usage:    l10n.T("Minimum change to flag the layout shift score"),
+						Category: l10n.T(catThresholds),
+					},
+				)...)...),
+				Action: runHistoryCheck,
+			},
+		},
+	}
+}
+
+// loadHistory reads the history file and groups the runs matching the
+// --url and --profile filters.
+func loadHistory(c *cli.Context) ([]history.Series, error) {
+	if format := c.String("format"); format != "text" && format != "json" {
+		return nil, fmt.Errorf("unknown report format: %s (supported: text, json)", format)
+	}
+	entries, err := history.Load(c.String("history"))
+	if err != nil {
+		return nil, err
+	}
+	series := history.Group(entries, c.String("url"), c.String("profile"))
+	if len(series) == 0 {
+		return nil, errors.New(l10n.T("No runs match the filters"))
+	}
+	return series, nil
+}
+
+func runHistoryList(c *cli.Context) error {
+	series, err := loadHistory(c)
+	if err != nil {
+		return err
+	}
+	if c.String("format") == "json" {
+		return history.WriteRunsJSON(os.Stdout, series)
+	}
+	return history.WriteRunsText(os.Stdout, series)
+}
+
+func runHistoryTrend(c *cli.Context) error {
+	series, err := loadHistory(c)
+	if err != nil {
+		return err
+	}
+	if c.String("format") == "json" {
+		return history.WriteTrendsJSON(os.Stdout, series, c.Int("last"))
+	}
+	return history.WriteTrendsText(os.Stdout, series, c.Int("last"))
+}
+
+func runHistoryCheck(c *cli.Context) error {
+	series, err := loadHistory(c)
+	if err != nil {
+		return err
+	}
+	thresholds, err := thresholdsFromFlags(c)
+	if err != nil {
+		return err
+	}
+	if thresholds.CLS = c.Float64("threshold-cls"); thresholds.CLS < 0 {
+		return errors.New(l10n.T("Thresholds must not be negative"))
+	}
+
+	window := c.Int("window")
+	if c.String("format") == "json" {
+		err = history.WriteChecksJSON(os.Stdout, series, window, thresholds)
+	} else {
+		err = history.WriteChecksText(os.Stdout, series, window, thresholds)
+	}
+	if err != nil {
+		return err
+	}
+
+	regressions := 0
+	for _, s := range series {
+		regressions += len(history.Regressions(s.Check(window, thresholds)))
+	}
+	if regressions > 0 {
+		return cli.Exit(l10n.F("%d regressions against the baseline", regressions), exitRegression)
+	}
+	return nil
+}
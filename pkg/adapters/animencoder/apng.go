@@ -0,0 +1,167 @@
+package animencoder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/png"
+
+	"github.com/user/loadshow/pkg/ports"
+)
+
+// pngSignature is the 8-byte PNG file signature.
+var pngSignature = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}
+
+// APNGEncoder implements ports.VideoEncoder producing an animated PNG.
+// Frames are encoded with image/png and reassembled into APNG chunks.
+type APNGEncoder struct {
+	timeline
+}
+
+// NewAPNG creates a new APNG encoder.
+func NewAPNG() *APNGEncoder {
+	return &APNGEncoder{}
+}
+
+// Begin initializes the encoder.
+func (e *APNGEncoder) Begin(width, height int, fps float64, opts ports.EncoderOptions) error {
+	return e.begin(width, height, fps, opts)
+}
+
+// EncodeFrame adds a frame at the specified timestamp.
+func (e *APNGEncoder) EncodeFrame(img image.Image, timestampMs int) error {
+	return e.add(img, timestampMs)
+}
+
+// End encodes the collected frames and returns the APNG data.
+func (e *APNGEncoder) End() ([]byte, error) {
+	frames, err := e.finish()
+	if err != nil {
+		return nil, err
+	}
+
+	rects := frameRects(frames, 1)
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+
+	var buf bytes.Buffer
+	buf.Write(pngSignature)
+
+	seq := uint32(0)
+	for i, f := range frames {
+		var frameBuf bytes.Buffer
+		if err := encoder.Encode(&frameBuf, f.img.SubImage(rects[i])); err != nil {
+			return nil, fmt.Errorf("encode frame %d: %w", i, err)
+		}
+		chunks, err := readPNGChunks(frameBuf.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("read frame %d: %w", i, err)
+		}
+
+		if i == 0 {
+			// The first frame's IHDR describes the whole animation
+			for _, c := range chunks {
+				if c.typ == "IHDR" {
+					writePNGChunk(&buf, "IHDR", c.data)
+				}
+			}
+			actl := make([]byte, 8)
+			binary.BigEndian.PutUint32(actl[0:], uint32(len(frames)))
+			binary.BigEndian.PutUint32(actl[4:], 0) // loop forever
+			writePNGChunk(&buf, "acTL", actl)
+		}
+
+		writePNGChunk(&buf, "fcTL", frameControl(seq, rects[i], f.durationMs))
+		seq++
+
+		for _, c := range chunks {
+			if c.typ != "IDAT" {
+				continue
+			}
+			if i == 0 {
+				writePNGChunk(&buf, "IDAT", c.data)
+				continue
+			}
+			fdat := make([]byte, 4+len(c.data))
+			binary.BigEndian.PutUint32(fdat, seq)
+			copy(fdat[4:], c.data)
+			writePNGChunk(&buf, "fdAT", fdat)
+			seq++
+		}
+	}
+
+	writePNGChunk(&buf, "IEND", nil)
+	return buf.Bytes(), nil
+}
+
+// frameControl builds an fcTL chunk body (no disposal, source blending).
+func frameControl(seq uint32, r image.Rectangle, durationMs int) []byte {
+	// The delay fraction uses 16-bit fields; fall back to centiseconds for long frames
+	num, den := durationMs, 1000
+	if num > 0xffff {
+		num, den = durationMs/10, 100
+		if num > 0xffff {
+			num = 0xffff
+		}
+	}
+
+	b := make([]byte, 26)
+	binary.BigEndian.PutUint32(b[0:], seq)
+	binary.BigEndian.PutUint32(b[4:], uint32(r.Dx()))
+	binary.BigEndian.PutUint32(b[8:], uint32(r.Dy()))
+	binary.BigEndian.PutUint32(b[12:], uint32(r.Min.X))
+	binary.BigEndian.PutUint32(b[16:], uint32(r.Min.Y))
+	binary.BigEndian.PutUint16(b[20:], uint16(num))
+	binary.BigEndian.PutUint16(b[22:], uint16(den))
+	b[24] = 0 // APNG_DISPOSE_OP_NONE
+	b[25] = 0 // APNG_BLEND_OP_SOURCE
+	return b
+}
+
+// pngChunk is a raw PNG chunk.
+type pngChunk struct {
+	typ  string
+	data []byte
+}
+
+// readPNGChunks splits PNG data into chunks.
+func readPNGChunks(data []byte) ([]pngChunk, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, fmt.Errorf("invalid PNG signature")
+	}
+	data = data[len(pngSignature):]
+
+	var chunks []pngChunk
+	for len(data) >= 12 {
+		length := int(binary.BigEndian.Uint32(data))
+		if len(data) < 12+length {
+			return nil, fmt.Errorf("truncated PNG chunk")
+		}
+		chunks = append(chunks, pngChunk{
+			typ:  string(data[4:8]),
+			data: data[8 : 8+length],
+		})
+		data = data[12+length:]
+	}
+	return chunks, nil
+}
+
+// writePNGChunk writes a chunk with its length and CRC.
+func writePNGChunk(buf *bytes.Buffer, typ string, data []byte) {
+	var header [8]byte
+	binary.BigEndian.PutUint32(header[:4], uint32(len(data)))
+	copy(header[4:], typ)
+	buf.Write(header[:])
+	buf.Write(data)
+
+	crc := crc32.NewIEEE()
+	crc.Write(header[4:])
+	crc.Write(data)
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], crc.Sum32())
+	buf.Write(sum[:])
+}
+
+// Ensure APNGEncoder implements ports.VideoEncoder
+var _ ports.VideoEncoder = (*APNGEncoder)(nil)
@@ -0,0 +1,206 @@
+// Package animencoder provides animated image encoders (GIF, WebP, APNG)
+// implementing ports.VideoEncoder, for places where MP4 does not autoplay.
+//
+// All encoders share the same frame handling:
+//   - frames arriving faster than the frame rate replace the pending frame
+//   - frames identical to the previous frame are dropped (its duration is extended)
+//   - frames after the first only store the region that changed
+//   - the animation loops forever, holding the last frame for lastFrameHoldMs
+package animencoder
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"sync"
+
+	"github.com/user/loadshow/pkg/ports"
+)
+
+// lastFrameHoldMs is how long the final frame stays on screen before the animation loops.
+const lastFrameHoldMs = 2000
+
+var (
+	// ErrNotInitialized is returned when EncodeFrame or End is called before Begin.
+	ErrNotInitialized = errors.New("encoder not initialized")
+	// ErrNoFrames is returned when End is called without any frames.
+	ErrNoFrames = errors.New("no frames to encode")
+)
+
+// frame is a deduplicated animation frame.
+type frame struct {
+	img         *image.RGBA
+	timestampMs int
+	durationMs  int
+}
+
+// timeline collects frames for an animation.
+type timeline struct {
+	mu sync.Mutex
+
+	width      int
+	height     int
+	intervalMs int
+	options    ports.EncoderOptions
+	started    bool
+
+	frames []frame
+}
+
+// begin resets the timeline for a new animation.
+func (t *timeline) begin(width, height int, fps float64, opts ports.EncoderOptions) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("invalid dimensions: %dx%d", width, height)
+	}
+
+	t.width = width
+	t.height = height
+	t.intervalMs = 0
+	if fps > 0 {
+		t.intervalMs = int(1000 / fps)
+	}
+	t.options = opts
+	t.frames = nil
+	t.started = true
+	return nil
+}
+
+// add appends a frame, merging it into the previous frame when it arrives
+// within the frame interval or is identical to it.
+func (t *timeline) add(img image.Image, timestampMs int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.started {
+		return ErrNotInitialized
+	}
+
+	rgba := toOpaqueRGBA(img, t.width, t.height)
+
+	if n := len(t.frames); n > 0 {
+		last := &t.frames[n-1]
+		if timestampMs-last.timestampMs < t.intervalMs {
+			// Keep the latest state within the frame slot
+			last.img = rgba
+			return nil
+		}
+		if bytes.Equal(last.img.Pix, rgba.Pix) {
+			return nil
+		}
+	}
+
+	t.frames = append(t.frames, frame{img: rgba, timestampMs: timestampMs})
+	return nil
+}
+
+// finish returns the collected frames with durations, and resets the timeline.
+func (t *timeline) finish() ([]frame, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.started {
+		return nil, ErrNotInitialized
+	}
+	t.started = false
+
+	frames := t.frames
+	t.frames = nil
+	if len(frames) == 0 {
+		return nil, ErrNoFrames
+	}
+
+	// Replacing frames within a slot can leave consecutive duplicates
+	deduped := frames[:1]
+	for _, f := range frames[1:] {
+		if !bytes.Equal(deduped[len(deduped)-1].img.Pix, f.img.Pix) {
+			deduped = append(deduped, f)
+		}
+	}
+
+	for i := range deduped {
+		if i+1 < len(deduped) {
+			deduped[i].durationMs = deduped[i+1].timestampMs - deduped[i].timestampMs
+		} else {
+			deduped[i].durationMs = lastFrameHoldMs
+		}
+	}
+
+	return deduped, nil
+}
+
+// toOpaqueRGBA copies an image onto a white canvas of the given size.
+// Flattening alpha keeps every frame in the same pixel format.
+func toOpaqueRGBA(img image.Image, width, height int) *image.RGBA {
+	rgba := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(rgba, rgba.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(rgba, rgba.Bounds(), img, img.Bounds().Min, draw.Over)
+	return rgba
+}
+
+// changedRect returns the bounding box of pixels that differ between two frames.
+// It returns an empty rectangle if the frames are identical.
+func changedRect(prev, cur *image.RGBA) image.Rectangle {
+	b := cur.Bounds()
+	minX, minY, maxX, maxY := b.Max.X, b.Max.Y, b.Min.X-1, b.Min.Y-1
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		prevRow := prev.Pix[prev.PixOffset(b.Min.X, y):prev.PixOffset(b.Max.X, y)]
+		curRow := cur.Pix[cur.PixOffset(b.Min.X, y):cur.PixOffset(b.Max.X, y)]
+		if bytes.Equal(prevRow, curRow) {
+			continue
+		}
+		if y < minY {
+			minY = y
+		}
+		maxY = y
+
+		for x := 0; x < len(curRow)/4; x++ {
+			if !bytes.Equal(prevRow[x*4:x*4+4], curRow[x*4:x*4+4]) {
+				if b.Min.X+x < minX {
+					minX = b.Min.X + x
+				}
+				break
+			}
+		}
+		for x := len(curRow)/4 - 1; x >= 0; x-- {
+			if !bytes.Equal(prevRow[x*4:x*4+4], curRow[x*4:x*4+4]) {
+				if b.Min.X+x > maxX {
+					maxX = b.Min.X + x
+				}
+				break
+			}
+		}
+	}
+
+	if maxY < minY {
+		return image.Rectangle{}
+	}
+	return image.Rect(minX, minY, maxX+1, maxY+1)
+}
+
+// frameRects returns the region stored for each frame: the full canvas for
+// the first frame and the changed region for the rest.
+// align rounds region origins down to a multiple of align (1 = no alignment).
+func frameRects(frames []frame, align int) []image.Rectangle {
+	rects := make([]image.Rectangle, len(frames))
+	for i, f := range frames {
+		if i == 0 {
+			rects[i] = f.img.Bounds()
+			continue
+		}
+		r := changedRect(frames[i-1].img, f.img)
+		if r.Empty() {
+			// Deduplicated frames always differ, but keep a valid 1x1 region just in case
+			r = image.Rect(0, 0, 1, 1)
+		}
+		r.Min.X -= r.Min.X % align
+		r.Min.Y -= r.Min.Y % align
+		rects[i] = r
+	}
+	return rects
+}
@@ -0,0 +1,299 @@
+package animencoder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"math/rand"
+	"testing"
+
+	"golang.org/x/image/webp"
+
+	"github.com/user/loadshow/pkg/ports"
+)
+
+// testFrame returns a frame with a colored bar whose width grows with step.
+func testFrame(width, height, step int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.RGBA{240, 240, 240, 255}), image.Point{}, draw.Src)
+	bar := image.Rect(0, height/4, width*step/10, height/2)
+	draw.Draw(img, bar, image.NewUniform(color.RGBA{30, 90, 200, 255}), image.Point{}, draw.Src)
+	return img
+}
+
+func TestTimelineDeduplicatesFrames(t *testing.T) {
+	var tl timeline
+	if err := tl.begin(20, 10, 10, ports.EncoderOptions{}); err != nil {
+		t.Fatalf("begin failed: %v", err)
+	}
+
+	// Identical frames are dropped, frames within 100ms replace the previous one
+	tl.add(testFrame(20, 10, 1), 0)
+	tl.add(testFrame(20, 10, 1), 100)
+	tl.add(testFrame(20, 10, 2), 200)
+	tl.add(testFrame(20, 10, 3), 250)
+	tl.add(testFrame(20, 10, 4), 400)
+
+	frames, err := tl.finish()
+	if err != nil {
+		t.Fatalf("finish failed: %v", err)
+	}
+	if len(frames) != 3 {
+		t.Fatalf("expected 3 frames, got %d", len(frames))
+	}
+
+	wantDurations := []int{200, 200, lastFrameHoldMs}
+	for i, f := range frames {
+		if f.durationMs != wantDurations[i] {
+			t.Errorf("frame %d: expected duration %d, got %d", i, wantDurations[i], f.durationMs)
+		}
+	}
+	if !bytes.Equal(frames[1].img.Pix, testFrame(20, 10, 3).Pix) {
+		t.Error("expected the latest frame within an interval to be kept")
+	}
+}
+
+func TestTimelineErrors(t *testing.T) {
+	var tl timeline
+	if err := tl.add(testFrame(4, 4, 1), 0); err != ErrNotInitialized {
+		t.Errorf("expected ErrNotInitialized, got %v", err)
+	}
+	if err := tl.begin(0, 4, 10, ports.EncoderOptions{}); err == nil {
+		t.Error("expected error for invalid dimensions")
+	}
+	tl.begin(4, 4, 10, ports.EncoderOptions{})
+	if _, err := tl.finish(); err != ErrNoFrames {
+		t.Errorf("expected ErrNoFrames, got %v", err)
+	}
+}
+
+func TestChangedRect(t *testing.T) {
+	prev := testFrame(40, 20, 2)
+	cur := testFrame(40, 20, 5)
+
+	got := changedRect(prev, cur)
+	want := image.Rect(8, 5, 20, 10)
+	if got != want {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if r := changedRect(prev, prev); !r.Empty() {
+		t.Errorf("expected empty rect for identical frames, got %v", r)
+	}
+}
+
+// encodeTestAnimation feeds four frames to an encoder.
+func encodeTestAnimation(t *testing.T, enc ports.VideoEncoder, width, height int) []byte {
+	t.Helper()
+	if err := enc.Begin(width, height, 10, ports.EncoderOptions{Quality: 30}); err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		if err := enc.EncodeFrame(testFrame(width, height, i*3+1), i*100); err != nil {
+			t.Fatalf("EncodeFrame failed: %v", err)
+		}
+	}
+	data, err := enc.End()
+	if err != nil {
+		t.Fatalf("End failed: %v", err)
+	}
+	return data
+}
+
+func TestGIFEncoder(t *testing.T) {
+	data := encodeTestAnimation(t, NewGIF(), 40, 20)
+
+	anim, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to decode GIF: %v", err)
+	}
+	if len(anim.Image) != 4 {
+		t.Fatalf("expected 4 frames, got %d", len(anim.Image))
+	}
+	if anim.Config.Width != 40 || anim.Config.Height != 20 {
+		t.Errorf("expected 40x20, got %dx%d", anim.Config.Width, anim.Config.Height)
+	}
+	if anim.Delay[0] != 10 || anim.Delay[3] != lastFrameHoldMs/10 {
+		t.Errorf("unexpected delays: %v", anim.Delay)
+	}
+	if b := anim.Image[1].Bounds(); b.Dx() >= 40 {
+		t.Errorf("expected later frames to store only the changed region, got %v", b)
+	}
+
+	r, g, b, _ := anim.Image[3].At(25, 7).RGBA()
+	if r>>8 != 30 || g>>8 != 90 || b>>8 != 200 {
+		t.Errorf("unexpected bar color: %d,%d,%d", r>>8, g>>8, b>>8)
+	}
+}
+
+func TestGIFPaletteSize(t *testing.T) {
+	tests := []struct {
+		quality int
+		want    int
+	}{
+		{0, 256},
+		{20, 256},
+		{30, 128},
+		{40, 64},
+	}
+	for _, tt := range tests {
+		if got := gifPaletteSize(tt.quality); got != tt.want {
+			t.Errorf("gifPaletteSize(%d) = %d, want %d", tt.quality, got, tt.want)
+		}
+	}
+}
+
+func TestAPNGEncoder(t *testing.T) {
+	data := encodeTestAnimation(t, NewAPNG(), 40, 20)
+
+	chunks, err := readPNGChunks(data)
+	if err != nil {
+		t.Fatalf("failed to read chunks: %v", err)
+	}
+	counts := map[string]int{}
+	var types []string
+	for _, c := range chunks {
+		counts[c.typ]++
+		types = append(types, c.typ)
+	}
+	if types[0] != "IHDR" || types[1] != "acTL" || types[len(types)-1] != "IEND" {
+		t.Errorf("unexpected chunk order: %v", types)
+	}
+	if counts["fcTL"] != 4 {
+		t.Errorf("expected 4 fcTL chunks, got %d", counts["fcTL"])
+	}
+	if counts["fdAT"] == 0 {
+		t.Error("expected fdAT chunks for later frames")
+	}
+
+	// Sequence numbers across fcTL and fdAT must be consecutive
+	seq := uint32(0)
+	for _, c := range chunks {
+		if c.typ == "fcTL" || c.typ == "fdAT" {
+			if got := binary.BigEndian.Uint32(c.data); got != seq {
+				t.Fatalf("expected sequence %d, got %d", seq, got)
+			}
+			seq++
+		}
+	}
+
+	// Decoders without APNG support show the first frame
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to decode first frame: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 40 || b.Dy() != 20 {
+		t.Errorf("expected 40x20, got %v", b)
+	}
+}
+
+func TestWebPEncoder(t *testing.T) {
+	width, height := 40, 20
+	data := encodeTestAnimation(t, NewWebP(), width, height)
+
+	if string(data[0:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		t.Fatal("missing RIFF/WEBP header")
+	}
+	if int(binary.LittleEndian.Uint32(data[4:])) != len(data)-8 {
+		t.Error("RIFF size does not match data length")
+	}
+
+	// Rebuild the canvas by decoding each ANMF frame as a still image
+	canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+	frames := 0
+	for p := 12; p < len(data); {
+		fourCC := string(data[p : p+4])
+		size := int(binary.LittleEndian.Uint32(data[p+4:]))
+		payload := data[p+8 : p+8+size]
+		p += 8 + size + size%2
+
+		if fourCC != "ANMF" {
+			continue
+		}
+		frames++
+
+		x := 2 * int(uint32(payload[0])|uint32(payload[1])<<8|uint32(payload[2])<<16)
+		y := 2 * int(uint32(payload[3])|uint32(payload[4])<<8|uint32(payload[5])<<16)
+
+		var still bytes.Buffer
+		var body bytes.Buffer
+		body.WriteString("WEBP")
+		body.Write(payload[16:])
+		writeRIFFChunk(&still, "RIFF", body.Bytes())
+
+		img, err := webp.Decode(&still)
+		if err != nil {
+			t.Fatalf("failed to decode frame %d: %v", frames, err)
+		}
+		draw.Draw(canvas, img.Bounds().Add(image.Pt(x, y)), img, img.Bounds().Min, draw.Src)
+	}
+
+	if frames != 4 {
+		t.Fatalf("expected 4 frames, got %d", frames)
+	}
+	if want := testFrame(width, height, 10); !bytes.Equal(canvas.Pix, want.Pix) {
+		t.Error("reconstructed last frame does not match input")
+	}
+}
+
+func TestEncodeVP8LRoundTrip(t *testing.T) {
+	// Noise exercises literals and long codes, the flat band exercises copies
+	rng := rand.New(rand.NewSource(1))
+	img := image.NewRGBA(image.Rect(0, 0, 97, 61))
+	for i := 0; i < len(img.Pix); i += 4 {
+		y := i / img.Stride
+		if y < 30 {
+			img.Pix[i] = uint8(rng.Intn(256))
+			img.Pix[i+1] = uint8(rng.Intn(8) * 30)
+			img.Pix[i+2] = uint8(rng.Intn(256))
+		} else {
+			img.Pix[i], img.Pix[i+1], img.Pix[i+2] = 12, 34, 56
+		}
+		img.Pix[i+3] = 255
+	}
+
+	bitstream, err := encodeVP8L(img, img.Bounds())
+	if err != nil {
+		t.Fatalf("encodeVP8L failed: %v", err)
+	}
+
+	var body, still bytes.Buffer
+	body.WriteString("WEBP")
+	writeRIFFChunk(&body, "VP8L", bitstream)
+	writeRIFFChunk(&still, "RIFF", body.Bytes())
+
+	decoded, err := webp.Decode(&still)
+	if err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	got := image.NewRGBA(decoded.Bounds())
+	draw.Draw(got, got.Bounds(), decoded, image.Point{}, draw.Src)
+	if !bytes.Equal(got.Pix, img.Pix) {
+		t.Error("decoded image does not match input")
+	}
+}
+
+func TestHuffmanLengthsLimited(t *testing.T) {
+	// Fibonacci frequencies produce a maximally deep tree
+	freqs := make([]int, 30)
+	a, b := 1, 1
+	for i := range freqs {
+		freqs[i] = a
+		a, b = b, a+b
+	}
+
+	lengths := huffmanLengths(freqs, 15)
+	kraft := 0.0
+	for _, l := range lengths {
+		if l == 0 || l > 15 {
+			t.Fatalf("invalid code length %d", l)
+		}
+		kraft += 1 / float64(uint(1)<<l)
+	}
+	if kraft > 1 {
+		t.Errorf("code lengths violate the Kraft inequality: %f", kraft)
+	}
+}
@@ -0,0 +1,84 @@
+package animencoder
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/gif"
+
+	"github.com/user/loadshow/pkg/ports"
+)
+
+// GIFEncoder implements ports.VideoEncoder producing an animated GIF.
+// Each frame gets its own palette built by median cut.
+type GIFEncoder struct {
+	timeline
+}
+
+// NewGIF creates a new animated GIF encoder.
+func NewGIF() *GIFEncoder {
+	return &GIFEncoder{}
+}
+
+// Begin initializes the encoder.
+func (e *GIFEncoder) Begin(width, height int, fps float64, opts ports.EncoderOptions) error {
+	return e.begin(width, height, fps, opts)
+}
+
+// EncodeFrame adds a frame at the specified timestamp.
+func (e *GIFEncoder) EncodeFrame(img image.Image, timestampMs int) error {
+	return e.add(img, timestampMs)
+}
+
+// End encodes the collected frames and returns the GIF data.
+func (e *GIFEncoder) End() ([]byte, error) {
+	frames, err := e.finish()
+	if err != nil {
+		return nil, err
+	}
+
+	colors := gifPaletteSize(e.options.Quality)
+	rects := frameRects(frames, 1)
+
+	anim := &gif.GIF{
+		Config:    image.Config{Width: e.width, Height: e.height},
+		LoopCount: 0, // loop forever
+	}
+
+	// GIF delays are in centiseconds; round cumulative times to avoid drift
+	for i, f := range frames {
+		start := (f.timestampMs - frames[0].timestampMs + 5) / 10
+		end := (f.timestampMs - frames[0].timestampMs + f.durationMs + 5) / 10
+		delay := end - start
+		if delay < 2 {
+			// Browsers treat delays below 2cs as 10cs
+			delay = 2
+		}
+
+		anim.Image = append(anim.Image, quantize(f.img, rects[i], colors))
+		anim.Delay = append(anim.Delay, delay)
+		anim.Disposal = append(anim.Disposal, gif.DisposalNone)
+	}
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, anim); err != nil {
+		return nil, fmt.Errorf("encode GIF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// gifPaletteSize returns the palette size for a CRF-style quality value (lower is better).
+// Smaller palettes keep GIFs small at the low and medium quality presets.
+func gifPaletteSize(quality int) int {
+	switch {
+	case quality <= 20:
+		return 256
+	case quality <= 30:
+		return 128
+	default:
+		return 64
+	}
+}
+
+// Ensure GIFEncoder implements ports.VideoEncoder
+var _ ports.VideoEncoder = (*GIFEncoder)(nil)
@@ -0,0 +1,190 @@
+package animencoder
+
+import (
+	"image"
+	"image/color"
+	"sort"
+)
+
+// histBits is the number of bits per channel used for the color histogram.
+const histBits = 5
+
+// histBucket accumulates the pixels that fall into one histogram cell.
+type histBucket struct {
+	r, g, b int64
+	count   int64
+}
+
+// colorBox is a set of histogram cells split by median cut.
+type colorBox struct {
+	keys  []int
+	count int64
+}
+
+// quantize maps a region of an image to a palette of up to n colors,
+// built with median cut over a 5-bit-per-channel histogram.
+func quantize(img *image.RGBA, r image.Rectangle, n int) *image.Paletted {
+	hist := make([]histBucket, 1<<(histBits*3))
+	var keys []int
+
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			i := img.PixOffset(x, y)
+			pr, pg, pb := img.Pix[i], img.Pix[i+1], img.Pix[i+2]
+			key := histKey(pr, pg, pb)
+			bucket := &hist[key]
+			if bucket.count == 0 {
+				keys = append(keys, key)
+			}
+			bucket.r += int64(pr)
+			bucket.g += int64(pg)
+			bucket.b += int64(pb)
+			bucket.count++
+		}
+	}
+
+	palette := medianCut(hist, keys, n)
+
+	// Map each histogram cell to its nearest palette entry on first use
+	lut := make([]int16, len(hist))
+	for i := range lut {
+		lut[i] = -1
+	}
+
+	out := image.NewPaletted(r, palette)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			i := img.PixOffset(x, y)
+			pr, pg, pb := img.Pix[i], img.Pix[i+1], img.Pix[i+2]
+			key := histKey(pr, pg, pb)
+			if lut[key] < 0 {
+				lut[key] = int16(nearestColor(palette, pr, pg, pb))
+			}
+			out.Pix[out.PixOffset(x, y)] = uint8(lut[key])
+		}
+	}
+
+	return out
+}
+
+// histKey returns the histogram cell for a color.
+func histKey(r, g, b uint8) int {
+	const shift = 8 - histBits
+	return int(r>>shift)<<(histBits*2) | int(g>>shift)<<histBits | int(b>>shift)
+}
+
+// medianCut splits the histogram cells into at most n boxes and returns their average colors.
+func medianCut(hist []histBucket, keys []int, n int) color.Palette {
+	var total int64
+	for _, k := range keys {
+		total += hist[k].count
+	}
+	boxes := []colorBox{{keys: keys, count: total}}
+
+	for len(boxes) < n {
+		// Split the box with the largest spread weighted by pixel count
+		best, bestScore, bestAxis := -1, int64(0), 0
+		for i, box := range boxes {
+			if len(box.keys) < 2 {
+				continue
+			}
+			axis, spread := widestAxis(box.keys)
+			if score := int64(spread) * box.count; score > bestScore {
+				best, bestScore, bestAxis = i, score, axis
+			}
+		}
+		if best < 0 {
+			break
+		}
+
+		left, right := splitBox(hist, boxes[best], bestAxis)
+		boxes[best] = left
+		boxes = append(boxes, right)
+	}
+
+	palette := make(color.Palette, 0, len(boxes))
+	for _, box := range boxes {
+		var r, g, b, count int64
+		for _, k := range box.keys {
+			r += hist[k].r
+			g += hist[k].g
+			b += hist[k].b
+			count += hist[k].count
+		}
+		if count == 0 {
+			continue
+		}
+		palette = append(palette, color.RGBA{
+			R: uint8(r / count),
+			G: uint8(g / count),
+			B: uint8(b / count),
+			A: 255,
+		})
+	}
+	if len(palette) == 0 {
+		palette = append(palette, color.RGBA{A: 255})
+	}
+	return palette
+}
+
+// keyChannel returns one channel (0 = red, 1 = green, 2 = blue) of a histogram key.
+func keyChannel(key, axis int) int {
+	return key >> (histBits * (2 - axis)) & (1<<histBits - 1)
+}
+
+// widestAxis returns the channel with the largest range among the keys.
+func widestAxis(keys []int) (axis, spread int) {
+	for a := 0; a < 3; a++ {
+		lo, hi := 1<<histBits, -1
+		for _, k := range keys {
+			v := keyChannel(k, a)
+			if v < lo {
+				lo = v
+			}
+			if v > hi {
+				hi = v
+			}
+		}
+		if hi-lo > spread || a == 0 {
+			axis, spread = a, hi-lo
+		}
+	}
+	return axis, spread
+}
+
+// splitBox splits a box at the pixel-weighted median along the given axis.
+func splitBox(hist []histBucket, box colorBox, axis int) (colorBox, colorBox) {
+	keys := box.keys
+	sort.Slice(keys, func(i, j int) bool {
+		return keyChannel(keys[i], axis) < keyChannel(keys[j], axis)
+	})
+
+	var acc int64
+	mid := 1
+	for i, k := range keys[:len(keys)-1] {
+		acc += hist[k].count
+		mid = i + 1
+		if acc*2 >= box.count {
+			break
+		}
+	}
+
+	left := colorBox{keys: keys[:mid:mid], count: acc}
+	right := colorBox{keys: keys[mid:], count: box.count - acc}
+	return left, right
+}
+
+// nearestColor returns the index of the palette entry closest to the color.
+func nearestColor(palette color.Palette, r, g, b uint8) int {
+	best, bestDist := 0, -1
+	for i, c := range palette {
+		pc := c.(color.RGBA)
+		dr := int(pc.R) - int(r)
+		dg := int(pc.G) - int(g)
+		db := int(pc.B) - int(b)
+		if d := dr*dr + dg*dg + db*db; bestDist < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
@@ -0,0 +1,481 @@
+package animencoder
+
+import (
+	"fmt"
+	"image"
+	"sort"
+)
+
+// VP8L (WebP lossless) bitstream constants.
+const (
+	vp8lSignature  = 0x2f
+	vp8lMaxSize    = 1 << 14
+	vp8lCacheBits  = 10
+	vp8lCacheMult  = 0x1e35a7bd
+	vp8lLiterals   = 256
+	vp8lLengthSyms = 24
+	vp8lDistSyms   = 40
+	vp8lMaxLength  = 4096
+	vp8lMinMatch   = 3
+	vp8lHashBits   = 16
+
+	// vp8lMaxDistance keeps plain distance codes within the 40 distance prefixes.
+	vp8lMaxDistance = 1<<20 - 120
+
+	// transformSubtractGreen is the transform type for the subtract-green transform.
+	transformSubtractGreen = 2
+)
+
+// codeLengthOrder is the order in which code-length code lengths are written.
+var codeLengthOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// bitWriter writes bits least-significant first, as VP8L expects.
+type bitWriter struct {
+	buf  []byte
+	acc  uint64
+	nacc uint
+}
+
+func (w *bitWriter) write(v uint32, n uint) {
+	w.acc |= uint64(v) << w.nacc
+	w.nacc += n
+	for w.nacc >= 8 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc >>= 8
+		w.nacc -= 8
+	}
+}
+
+func (w *bitWriter) bytes() []byte {
+	if w.nacc > 0 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc, w.nacc = 0, 0
+	}
+	return w.buf
+}
+
+// vp8lToken is one entropy-coded element of the pixel stream.
+type vp8lToken struct {
+	kind  uint8  // tokenLiteral, tokenCache or tokenCopy
+	value uint32 // ARGB, cache index or copy length
+	dist  uint32 // distance code (copies only)
+}
+
+const (
+	tokenLiteral = iota
+	tokenCache
+	tokenCopy
+)
+
+// encodeVP8L encodes a region of an opaque image as a VP8L bitstream
+// using the subtract-green transform, a color cache and LZ77 references.
+func encodeVP8L(img *image.RGBA, r image.Rectangle) ([]byte, error) {
+	width, height := r.Dx(), r.Dy()
+	if width <= 0 || height <= 0 || width > vp8lMaxSize || height > vp8lMaxSize {
+		return nil, fmt.Errorf("invalid WebP frame size: %dx%d", width, height)
+	}
+
+	// Apply the subtract-green transform while converting to ARGB
+	argb := make([]uint32, 0, width*height)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			i := img.PixOffset(x, y)
+			pr, pg, pb := img.Pix[i], img.Pix[i+1], img.Pix[i+2]
+			argb = append(argb, 0xff000000|
+				uint32(pr-pg)<<16|uint32(pg)<<8|uint32(pb-pg))
+		}
+	}
+
+	tokens := vp8lTokenize(argb, width)
+
+	// Collect symbol frequencies for the five prefix codes
+	green := make([]int, vp8lLiterals+vp8lLengthSyms+1<<vp8lCacheBits)
+	red := make([]int, 256)
+	blue := make([]int, 256)
+	alpha := make([]int, 256)
+	dist := make([]int, vp8lDistSyms)
+	for _, t := range tokens {
+		switch t.kind {
+		case tokenLiteral:
+			alpha[t.value>>24]++
+			red[t.value>>16&0xff]++
+			green[t.value>>8&0xff]++
+			blue[t.value&0xff]++
+		case tokenCache:
+			green[vp8lLiterals+vp8lLengthSyms+int(t.value)]++
+		case tokenCopy:
+			lp, _, _ := prefixEncode(t.value)
+			green[vp8lLiterals+lp]++
+			dp, _, _ := prefixEncode(t.dist)
+			dist[dp]++
+		}
+	}
+
+	w := &bitWriter{}
+	w.write(vp8lSignature, 8)
+	w.write(uint32(width-1), 14)
+	w.write(uint32(height-1), 14)
+	w.write(0, 1) // alpha is not used
+	w.write(0, 3) // version
+
+	w.write(1, 1) // transform present
+	w.write(transformSubtractGreen, 2)
+	w.write(0, 1) // no more transforms
+
+	w.write(1, 1) // color cache
+	w.write(vp8lCacheBits, 4)
+	w.write(0, 1) // no meta prefix codes
+
+	codes := make([]*prefixCode, 5)
+	for i, freqs := range [][]int{green, red, blue, alpha, dist} {
+		codes[i] = newPrefixCode(freqs, 15)
+		codes[i].writeTo(w)
+	}
+	gc, rc, bc, ac, dc := codes[0], codes[1], codes[2], codes[3], codes[4]
+
+	for _, t := range tokens {
+		switch t.kind {
+		case tokenLiteral:
+			gc.emit(w, int(t.value>>8&0xff))
+			rc.emit(w, int(t.value>>16&0xff))
+			bc.emit(w, int(t.value&0xff))
+			ac.emit(w, int(t.value>>24))
+		case tokenCache:
+			gc.emit(w, vp8lLiterals+vp8lLengthSyms+int(t.value))
+		case tokenCopy:
+			lp, lbits, lextra := prefixEncode(t.value)
+			gc.emit(w, vp8lLiterals+lp)
+			w.write(lextra, lbits)
+			dp, dbits, dextra := prefixEncode(t.dist)
+			dc.emit(w, dp)
+			w.write(dextra, dbits)
+		}
+	}
+
+	return w.bytes(), nil
+}
+
+// vp8lTokenize turns ARGB pixels into literals, color cache hits and
+// backward references. References to the pixel above or to the left use
+// the short distance codes; other matches come from a hash of pixel pairs.
+func vp8lTokenize(argb []uint32, width int) []vp8lToken {
+	n := len(argb)
+	var cache [1 << vp8lCacheBits]uint32
+	head := make([]int32, 1<<vp8lHashBits)
+	for i := range head {
+		head[i] = -1
+	}
+
+	hashAt := func(i int) int {
+		if i+1 >= n {
+			return -1
+		}
+		return int((argb[i]*vp8lCacheMult ^ argb[i+1]*0x9e3779b1) >> (32 - vp8lHashBits))
+	}
+	matchLen := func(i, j int) int {
+		limit := n - i
+		if limit > vp8lMaxLength {
+			limit = vp8lMaxLength
+		}
+		l := 0
+		for l < limit && argb[i+l] == argb[j+l] {
+			l++
+		}
+		return l
+	}
+	insert := func(i int) {
+		p := argb[i]
+		cache[(p*vp8lCacheMult)>>(32-vp8lCacheBits)] = p
+		if h := hashAt(i); h >= 0 {
+			head[h] = int32(i)
+		}
+	}
+
+	tokens := make([]vp8lToken, 0, n/4)
+	for i := 0; i < n; {
+		bestLen, bestDist := 0, 0
+		if i >= width {
+			if l := matchLen(i, i-width); l > bestLen {
+				bestLen, bestDist = l, width
+			}
+		}
+		if i >= 1 {
+			if l := matchLen(i, i-1); l > bestLen {
+				bestLen, bestDist = l, 1
+			}
+		}
+		if h := hashAt(i); h >= 0 {
+			if j := int(head[h]); j >= 0 && i-j <= vp8lMaxDistance {
+				if l := matchLen(i, j); l > bestLen {
+					bestLen, bestDist = l, i-j
+				}
+			}
+		}
+
+		if bestLen >= vp8lMinMatch {
+			tokens = append(tokens, vp8lToken{
+				kind:  tokenCopy,
+				value: uint32(bestLen),
+				dist:  distanceCode(bestDist, width),
+			})
+			for k := 0; k < bestLen; k++ {
+				insert(i + k)
+			}
+			i += bestLen
+			continue
+		}
+
+		p := argb[i]
+		idx := (p * vp8lCacheMult) >> (32 - vp8lCacheBits)
+		if cache[idx] == p {
+			tokens = append(tokens, vp8lToken{kind: tokenCache, value: idx})
+		} else {
+			tokens = append(tokens, vp8lToken{kind: tokenLiteral, value: p})
+		}
+		insert(i)
+		i++
+	}
+	return tokens
+}
+
+// distanceCode maps a linear backward distance to a VP8L distance code.
+func distanceCode(dist, width int) uint32 {
+	switch dist {
+	case width:
+		return 1 // (0, 1): the pixel above
+	case 1:
+		return 2 // (1, 0): the pixel to the left
+	default:
+		return uint32(dist + 120)
+	}
+}
+
+// prefixEncode splits a length or distance value (>= 1) into its prefix
+// symbol and extra bits.
+func prefixEncode(v uint32) (prefix int, nbits uint, extra uint32) {
+	d := v - 1
+	if d < 4 {
+		return int(d), 0, 0
+	}
+	h := uint(31)
+	for d>>h == 0 {
+		h--
+	}
+	s := (d >> (h - 1)) & 1
+	return int(2*h + uint(s)), h - 1, d & (1<<(h-1) - 1)
+}
+
+// prefixCode is a canonical Huffman code.
+type prefixCode struct {
+	lengths []uint8  // code lengths as written to the header
+	codes   []uint32 // bit-reversed codes, ready for the LSB-first writer
+	single  bool     // only one symbol is used; it takes zero bits
+	used    int
+}
+
+// newPrefixCode builds a length-limited canonical Huffman code from symbol frequencies.
+func newPrefixCode(freqs []int, maxBits int) *prefixCode {
+	pc := &prefixCode{lengths: huffmanLengths(freqs, maxBits)}
+	for _, l := range pc.lengths {
+		if l > 0 {
+			pc.used++
+		}
+	}
+	pc.single = pc.used == 1
+	pc.codes = canonicalCodes(pc.lengths)
+	return pc
+}
+
+// emit writes the code for a symbol.
+func (pc *prefixCode) emit(w *bitWriter, symbol int) {
+	if pc.single {
+		return
+	}
+	w.write(pc.codes[symbol], uint(pc.lengths[symbol]))
+}
+
+// writeTo writes the code lengths in the VP8L prefix code format.
+func (pc *prefixCode) writeTo(w *bitWriter) {
+	if pc.used == 0 {
+		// Simple code with one 1-bit symbol (0); never referenced
+		w.write(1, 1)
+		w.write(0, 1)
+		w.write(0, 1)
+		w.write(0, 1)
+		return
+	}
+
+	w.write(0, 1) // normal code
+
+	// Run-length encode the code lengths with symbols 16-18
+	type rle struct {
+		symbol int
+		extra  uint32
+	}
+	var runs []rle
+	lengths := pc.lengths
+	for i := 0; i < len(lengths); {
+		v := lengths[i]
+		run := 1
+		for i+run < len(lengths) && lengths[i+run] == v {
+			run++
+		}
+		i += run
+
+		if v == 0 {
+			for run >= 11 {
+				k := min(run, 138)
+				runs = append(runs, rle{18, uint32(k - 11)})
+				run -= k
+			}
+			if run >= 3 {
+				runs = append(runs, rle{17, uint32(run - 3)})
+				run = 0
+			}
+		} else {
+			runs = append(runs, rle{int(v), 0})
+			run--
+			for run >= 3 {
+				k := min(run, 6)
+				runs = append(runs, rle{16, uint32(k - 3)})
+				run -= k
+			}
+		}
+		for ; run > 0; run-- {
+			runs = append(runs, rle{int(v), 0})
+		}
+	}
+
+	freqs := make([]int, len(codeLengthOrder))
+	for _, r := range runs {
+		freqs[r.symbol]++
+	}
+	clc := newPrefixCode(freqs, 7)
+
+	count := len(codeLengthOrder)
+	for count > 4 && clc.lengths[codeLengthOrder[count-1]] == 0 {
+		count--
+	}
+	w.write(uint32(count-4), 4)
+	for _, sym := range codeLengthOrder[:count] {
+		w.write(uint32(clc.lengths[sym]), 3)
+	}
+	w.write(0, 1) // max_symbol not used
+
+	for _, r := range runs {
+		clc.emit(w, r.symbol)
+		switch r.symbol {
+		case 16:
+			w.write(r.extra, 2)
+		case 17:
+			w.write(r.extra, 3)
+		case 18:
+			w.write(r.extra, 7)
+		}
+	}
+}
+
+// huffmanLengths computes Huffman code lengths no longer than maxBits.
+// When the tree is too deep, rare symbols are given a higher frequency
+// floor and the tree is rebuilt.
+func huffmanLengths(freqs []int, maxBits int) []uint8 {
+	lengths := make([]uint8, len(freqs))
+	var symbols []int
+	for s, f := range freqs {
+		if f > 0 {
+			symbols = append(symbols, s)
+		}
+	}
+	switch len(symbols) {
+	case 0:
+		return lengths
+	case 1:
+		lengths[symbols[0]] = 1
+		return lengths
+	}
+
+	for floor := 1; ; floor *= 2 {
+		weight := func(s int) int { return max(freqs[s], floor) }
+
+		sorted := append([]int(nil), symbols...)
+		sort.Slice(sorted, func(i, j int) bool {
+			wi, wj := weight(sorted[i]), weight(sorted[j])
+			if wi != wj {
+				return wi < wj
+			}
+			return sorted[i] < sorted[j]
+		})
+
+		// Two-queue Huffman construction: leaves, then internal nodes in creation order
+		n := len(sorted)
+		weights := make([]int, 2*n-1)
+		parent := make([]int, 2*n-1)
+		for i, s := range sorted {
+			weights[i] = weight(s)
+		}
+		leaf, inner, next := 0, n, n
+		pick := func() int {
+			if leaf < n && (inner >= next || weights[leaf] <= weights[inner]) {
+				leaf++
+				return leaf - 1
+			}
+			inner++
+			return inner - 1
+		}
+		for next < 2*n-1 {
+			a, b := pick(), pick()
+			weights[next] = weights[a] + weights[b]
+			parent[a], parent[b] = next, next
+			next++
+		}
+
+		depth := make([]int, 2*n-1)
+		maxDepth := 0
+		for i := 2*n - 3; i >= 0; i-- {
+			depth[i] = depth[parent[i]] + 1
+			if i < n && depth[i] > maxDepth {
+				maxDepth = depth[i]
+			}
+		}
+		if maxDepth > maxBits {
+			continue
+		}
+		for i, s := range sorted {
+			lengths[s] = uint8(depth[i])
+		}
+		return lengths
+	}
+}
+
+// canonicalCodes assigns canonical codes to code lengths, bit-reversed
+// so they can be written least-significant bit first.
+func canonicalCodes(lengths []uint8) []uint32 {
+	var count [16]uint32
+	for _, l := range lengths {
+		count[l]++
+	}
+	count[0] = 0
+
+	var next [16]uint32
+	code := uint32(0)
+	for bits := 1; bits < 16; bits++ {
+		code = (code + count[bits-1]) << 1
+		next[bits] = code
+	}
+
+	codes := make([]uint32, len(lengths))
+	for s, l := range lengths {
+		if l == 0 {
+			continue
+		}
+		c := next[l]
+		next[l]++
+
+		var rev uint32
+		for i := uint8(0); i < l; i++ {
+			rev = rev<<1 | (c>>i)&1
+		}
+		codes[s] = rev
+	}
+	return codes
+}
@@ -0,0 +1,112 @@
+package animencoder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+
+	"github.com/user/loadshow/pkg/ports"
+)
+
+// WebP extended-format flags and frame options.
+const (
+	webpFlagAnimation = 0x02
+	anmfNoBlend       = 0x02
+)
+
+// WebPEncoder implements ports.VideoEncoder producing an animated WebP.
+// Frames are stored losslessly (VP8L), which suits flat page content.
+type WebPEncoder struct {
+	timeline
+}
+
+// NewWebP creates a new animated WebP encoder.
+func NewWebP() *WebPEncoder {
+	return &WebPEncoder{}
+}
+
+// Begin initializes the encoder.
+func (e *WebPEncoder) Begin(width, height int, fps float64, opts ports.EncoderOptions) error {
+	if width > vp8lMaxSize || height > vp8lMaxSize {
+		return fmt.Errorf("invalid dimensions: %dx%d", width, height)
+	}
+	return e.begin(width, height, fps, opts)
+}
+
+// EncodeFrame adds a frame at the specified timestamp.
+func (e *WebPEncoder) EncodeFrame(img image.Image, timestampMs int) error {
+	return e.add(img, timestampMs)
+}
+
+// End encodes the collected frames and returns the WebP data.
+func (e *WebPEncoder) End() ([]byte, error) {
+	frames, err := e.finish()
+	if err != nil {
+		return nil, err
+	}
+
+	// ANMF offsets are stored divided by two
+	rects := frameRects(frames, 2)
+
+	var body bytes.Buffer
+	body.WriteString("WEBP")
+
+	vp8x := make([]byte, 10)
+	vp8x[0] = webpFlagAnimation
+	putUint24(vp8x[4:], e.width-1)
+	putUint24(vp8x[7:], e.height-1)
+	writeRIFFChunk(&body, "VP8X", vp8x)
+
+	anim := make([]byte, 6)
+	binary.LittleEndian.PutUint32(anim, 0xffffffff) // white background (BGRA)
+	binary.LittleEndian.PutUint16(anim[4:], 0)      // loop forever
+	writeRIFFChunk(&body, "ANIM", anim)
+
+	for i, f := range frames {
+		r := rects[i]
+		bitstream, err := encodeVP8L(f.img, r)
+		if err != nil {
+			return nil, fmt.Errorf("encode frame %d: %w", i, err)
+		}
+
+		var anmf bytes.Buffer
+		header := make([]byte, 16)
+		putUint24(header[0:], r.Min.X/2)
+		putUint24(header[3:], r.Min.Y/2)
+		putUint24(header[6:], r.Dx()-1)
+		putUint24(header[9:], r.Dy()-1)
+		putUint24(header[12:], min(f.durationMs, 1<<24-1))
+		header[15] = anmfNoBlend
+		anmf.Write(header)
+		writeRIFFChunk(&anmf, "VP8L", bitstream)
+
+		writeRIFFChunk(&body, "ANMF", anmf.Bytes())
+	}
+
+	var buf bytes.Buffer
+	writeRIFFChunk(&buf, "RIFF", body.Bytes())
+	return buf.Bytes(), nil
+}
+
+// writeRIFFChunk writes a chunk with its little-endian size, padded to an even length.
+func writeRIFFChunk(buf *bytes.Buffer, fourCC string, data []byte) {
+	var header [8]byte
+	copy(header[:4], fourCC)
+	binary.LittleEndian.PutUint32(header[4:], uint32(len(data)))
+	buf.Write(header[:])
+	buf.Write(data)
+	if len(data)%2 == 1 {
+		buf.WriteByte(0)
+	}
+}
+
+// putUint24 writes a little-endian 24-bit value.
+func putUint24(b []byte, v int) {
+	b[0] = byte(v)
+	b[1] = byte(v >> 8)
+	b[2] = byte(v >> 16)
+}
+
+// Ensure WebPEncoder implements ports.VideoEncoder
+var _ ports.VideoEncoder = (*WebPEncoder)(nil)
@@ -62,6 +62,11 @@ type Decoder struct {
 	codec *C.aom_codec_ctx_t
 }
 
+// IsAvailable reports whether libaom is linked into this binary.
+func IsAvailable() bool {
+	return true
+}
+
 // New creates a new AV1 decoder.
 func New() *Decoder {
 	return &Decoder{}
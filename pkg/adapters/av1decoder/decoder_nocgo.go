@@ -0,0 +1,38 @@
+//go:build !cgo
+
+package av1decoder
+
+import (
+	"errors"
+	"image"
+)
+
+// ErrNotAvailable is returned when the binary was built without cgo,
+// so libaom is not linked.
+var ErrNotAvailable = errors.New("av1decoder: built without libaom (cgo disabled)")
+
+// Decoder is a stub used when libaom is not linked.
+type Decoder struct{}
+
+// IsAvailable reports whether libaom is linked into this binary.
+func IsAvailable() bool {
+	return false
+}
+
+// New creates a new AV1 decoder.
+func New() *Decoder {
+	return &Decoder{}
+}
+
+// Init always fails without libaom.
+func (d *Decoder) Init() error {
+	return ErrNotAvailable
+}
+
+// DecodeFrame always fails without libaom.
+func (d *Decoder) DecodeFrame(data []byte) (image.Image, error) {
+	return nil, ErrNotAvailable
+}
+
+// Close is a no-op.
+func (d *Decoder) Close() {}
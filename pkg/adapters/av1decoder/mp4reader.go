@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/Eyevinn/mp4ff/mp4"
+	"github.com/user/loadshow/pkg/frameiter"
 	"github.com/user/loadshow/pkg/ports"
 )
 
@@ -161,6 +162,27 @@ func (r *MP4Reader) readProgressiveMP4(mp4File *mp4.File, reader io.ReadSeeker)
 	return nil, fmt.Errorf("progressive MP4 not supported, use fragmented MP4")
 }
 
+// OpenFrames opens an MP4 file for incremental decoding.
+// Each iterator has its own decoder, so several can be open at once.
+func (r *MP4Reader) OpenFrames(path string) (ports.FrameIterator, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open file: %w", err)
+	}
+	defer f.Close()
+
+	raw, err := extractFrames(f)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]frameiter.Sample, len(raw))
+	for i, frame := range raw {
+		samples[i] = frameiter.Sample(frame)
+	}
+	return frameiter.New(samples, New()), nil
+}
+
 // Close releases resources.
 func (r *MP4Reader) Close() {
 	if r.decoder != nil {
@@ -178,8 +200,10 @@ type RawFrame struct {
 
 // ExtractFrames extracts raw AV1 frames from MP4 data without decoding.
 func ExtractFrames(mp4Data []byte) ([]RawFrame, error) {
-	reader := &bytesReadSeeker{data: mp4Data}
+	return extractFrames(&bytesReadSeeker{data: mp4Data})
+}
 
+func extractFrames(reader io.ReadSeeker) ([]RawFrame, error) {
 	mp4File, err := mp4.DecodeFile(reader)
 	if err != nil {
 		return nil, fmt.Errorf("decode mp4: %w", err)
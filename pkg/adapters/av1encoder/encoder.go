@@ -103,6 +103,11 @@ type encodedFrame struct {
 	isKeyframe  bool
 }
 
+// IsAvailable reports whether libaom is linked into this binary.
+func IsAvailable() bool {
+	return true
+}
+
 // New creates a new AV1 encoder.
 func New() *Encoder {
 	return &Encoder{}
@@ -113,6 +118,10 @@ func (e *Encoder) Begin(width, height int, fps float64, opts ports.EncoderOption
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
+	if fps <= 0 {
+		return fmt.Errorf("invalid frame rate: %v", fps)
+	}
+
 	e.width = width
 	e.height = height
 	e.fps = fps
@@ -260,7 +269,7 @@ func (e *Encoder) EncodeFrame(img image.Image, timestampMs int) error {
 	return nil
 }
 
-// End finalizes encoding and returns the MP4 data.
+// End finalizes encoding and returns the MP4 or WebM data.
 func (e *Encoder) End() ([]byte, error) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
@@ -300,16 +309,25 @@ func (e *Encoder) End() ([]byte, error) {
 		}
 	}
 
-	// Build MP4 container
-	mp4Data, err := e.buildMP4()
-	if err != nil {
-		return nil, fmt.Errorf("build mp4: %w", err)
+	// Build the output container
+	var data []byte
+	var err error
+	if e.options.Container == ports.ContainerWebM {
+		data, err = e.buildWebM()
+		if err != nil {
+			return nil, fmt.Errorf("build webm: %w", err)
+		}
+	} else {
+		data, err = e.buildMP4()
+		if err != nil {
+			return nil, fmt.Errorf("build mp4: %w", err)
+		}
 	}
 
 	// Cleanup
 	e.cleanup()
 
-	return mp4Data, nil
+	return data, nil
 }
 
 func (e *Encoder) cleanup() {
@@ -0,0 +1,55 @@
+//go:build !cgo
+
+package av1encoder
+
+import (
+	"errors"
+	"image"
+
+	"github.com/user/loadshow/pkg/ports"
+)
+
+// ErrNotAvailable is returned when the binary was built without cgo,
+// so libaom is not linked.
+var ErrNotAvailable = errors.New("av1encoder: built without libaom (cgo disabled)")
+
+// Encoder is a stub used when libaom is not linked.
+type Encoder struct {
+	width   int
+	height  int
+	fps     float64
+	options ports.EncoderOptions
+
+	frames []encodedFrame
+}
+
+type encodedFrame struct {
+	data        []byte
+	timestampUs int64
+	isKeyframe  bool
+}
+
+// IsAvailable reports whether libaom is linked into this binary.
+func IsAvailable() bool {
+	return false
+}
+
+// New creates a new AV1 encoder.
+func New() *Encoder {
+	return &Encoder{}
+}
+
+// Begin always fails without libaom.
+func (e *Encoder) Begin(width, height int, fps float64, opts ports.EncoderOptions) error {
+	return ErrNotAvailable
+}
+
+// EncodeFrame always fails without libaom.
+func (e *Encoder) EncodeFrame(img image.Image, timestampMs int) error {
+	return ErrNotAvailable
+}
+
+// End always fails without libaom.
+func (e *Encoder) End() ([]byte, error) {
+	return nil, ErrNotAvailable
+}
@@ -1,6 +1,9 @@
+//go:build cgo
+
 package av1encoder
 
 import (
+	"bytes"
 	"image"
 	"image/color"
 	"testing"
@@ -269,3 +272,32 @@ func BenchmarkEncoder_EncodeFrame(b *testing.B) {
 		}
 	}
 }
+
+func TestEncoder_BuildWebM(t *testing.T) {
+	// Sequence header OBU (type 1, has_size) followed by a frame OBU
+	keyframe := []byte{0x0A, 0x02, 0x00, 0x00, 0x32, 0x01, 0x00}
+	enc := &Encoder{
+		width:  64,
+		height: 48,
+		fps:    30,
+		frames: []encodedFrame{
+			{data: keyframe, timestampUs: 0, isKeyframe: true},
+			{data: []byte{0x32, 0x01, 0x00}, timestampUs: 250000},
+		},
+	}
+
+	data, err := enc.buildWebM()
+	if err != nil {
+		t.Fatalf("buildWebM failed: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte{0x1A, 0x45, 0xDF, 0xA3}) {
+		t.Error("output does not start with an EBML header")
+	}
+	if !bytes.Contains(data, []byte("V_AV1")) {
+		t.Error("expected V_AV1 codec id")
+	}
+	// CodecPrivate must carry the sequence header from the keyframe
+	if !bytes.Contains(data, keyframe[:4]) {
+		t.Error("expected sequence header in codec private data")
+	}
+}
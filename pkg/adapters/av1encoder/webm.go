@@ -0,0 +1,44 @@
+package av1encoder
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/user/loadshow/pkg/adapters/webm"
+)
+
+// buildWebM creates a WebM container from encoded AV1 frames.
+func (e *Encoder) buildWebM() ([]byte, error) {
+	if len(e.frames) == 0 {
+		return nil, fmt.Errorf("no frames to encode")
+	}
+
+	// CodecPrivate carries the same AV1CodecConfigurationRecord as av1C
+	var codecPrivate bytes.Buffer
+	av1C := createAV1ConfigRecord(e.frames)
+	if err := av1C.CodecConfRec.Encode(&codecPrivate); err != nil {
+		return nil, fmt.Errorf("encode av1 config: %w", err)
+	}
+
+	frames := make([]webm.Frame, len(e.frames))
+	for i, f := range e.frames {
+		frames[i] = webm.Frame{
+			Data:        f.data,
+			TimestampMs: f.timestampUs / 1000,
+			Keyframe:    f.isKeyframe,
+		}
+	}
+
+	// Hold the last frame for one nominal frame interval, as in buildMP4
+	durationMs := frames[len(frames)-1].TimestampMs
+	if e.fps > 0 {
+		durationMs += int64(1000 / e.fps)
+	}
+
+	return webm.Mux(webm.Track{
+		CodecID:      webm.CodecAV1,
+		CodecPrivate: codecPrivate.Bytes(),
+		Width:        e.width,
+		Height:       e.height,
+	}, frames, durationMs)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/chromedp/cdproto/emulation"
 	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/runtime"
 	"github.com/chromedp/chromedp"
 
 	"github.com/user/loadshow/pkg/ports"
@@ -131,6 +132,15 @@ func (b *Browser) Launch(ctx context.Context, opts ports.BrowserOptions) error {
 		}
 	}
 
+	// The Resource Timing buffer keeps only 250 entries by default, which
+	// would cut the request waterfall of large pages short
+	if err := chromedp.Run(b.ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		_, err := page.AddScriptToEvaluateOnNewDocument(`performance.setResourceTimingBufferSize(10000)`).Do(ctx)
+		return err
+	})); err != nil {
+		return fmt.Errorf("set resource timing buffer size: %w", err)
+	}
+
 	return nil
 }
 
@@ -377,6 +387,244 @@ func (b *Browser) GetPerformanceTiming() (*ports.PerformanceTiming, error) {
 	}, nil
 }
 
+// GetLayoutShifts retrieves layout-shift entries using the Layout Instability API.
+// Entries are read from the performance buffer, so no observer needs to be installed before navigation.
+func (b *Browser) GetLayoutShifts() ([]ports.LayoutShift, error) {
+	type jsRect struct {
+		X      float64 `json:"x"`
+		Y      float64 `json:"y"`
+		Width  float64 `json:"width"`
+		Height float64 `json:"height"`
+	}
+	var entries []struct {
+		StartTime      float64 `json:"startTime"`
+		Value          float64 `json:"value"`
+		HadRecentInput bool    `json:"hadRecentInput"`
+		Sources        []struct {
+			PreviousRect jsRect `json:"previousRect"`
+			CurrentRect  jsRect `json:"currentRect"`
+		} `json:"sources"`
+	}
+
+	// Buffered entries are delivered to the observer asynchronously, so collect them in a promise
+	script := `
+		new Promise((resolve) => {
+			const rect = (r) => ({ x: r.x, y: r.y, width: r.width, height: r.height });
+			const toJSON = (e) => ({
+				startTime: e.startTime,
+				value: e.value,
+				hadRecentInput: e.hadRecentInput,
+				sources: (e.sources || []).map((s) => ({
+					previousRect: rect(s.previousRect),
+					currentRect: rect(s.currentRect)
+				}))
+			});
+			const entries = [];
+			try {
+				const observer = new PerformanceObserver((list) => {
+					entries.push(...list.getEntries().map(toJSON));
+				});
+				observer.observe({ type: 'layout-shift', buffered: true });
+				setTimeout(() => {
+					entries.push(...observer.takeRecords().map(toJSON));
+					observer.disconnect();
+					resolve(entries);
+				}, 0);
+			} catch (e) {
+				// Layout Instability API not supported
+				resolve(entries);
+			}
+		})
+	`
+
+	err := chromedp.Run(b.ctx, chromedp.Evaluate(script, &entries, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+		return p.WithAwaitPromise(true)
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("get layout shifts: %w", err)
+	}
+
+	toRect := func(r jsRect) ports.Rect {
+		return ports.Rect{X: r.X, Y: r.Y, Width: r.Width, Height: r.Height}
+	}
+
+	shifts := make([]ports.LayoutShift, 0, len(entries))
+	for _, e := range entries {
+		shift := ports.LayoutShift{
+			StartTime:      int64(e.StartTime),
+			Value:          e.Value,
+			HadRecentInput: e.HadRecentInput,
+		}
+		for _, src := range e.Sources {
+			shift.Sources = append(shift.Sources, ports.LayoutShiftSource{
+				PreviousRect: toRect(src.PreviousRect),
+				CurrentRect:  toRect(src.CurrentRect),
+			})
+		}
+		shifts = append(shifts, shift)
+	}
+
+	return shifts, nil
+}
+
+// GetLCPCandidates retrieves largest-contentful-paint entries using the Largest Contentful Paint API.
+// Element rects are measured at call time, since entries do not record the element position.
+func (b *Browser) GetLCPCandidates() ([]ports.LCPCandidate, error) {
+	var entries []struct {
+		StartTime float64 `json:"startTime"`
+		Size      float64 `json:"size"`
+		Element   string  `json:"element"`
+		URL       string  `json:"url"`
+		Rect      *struct {
+			X      float64 `json:"x"`
+			Y      float64 `json:"y"`
+			Width  float64 `json:"width"`
+			Height float64 `json:"height"`
+		} `json:"rect"`
+	}
+
+	script := `
+		new Promise((resolve) => {
+			const toJSON = (e) => {
+				const el = e.element;
+				let rect = null;
+				if (el && el.isConnected) {
+					const r = el.getBoundingClientRect();
+					rect = { x: r.x + window.scrollX, y: r.y + window.scrollY, width: r.width, height: r.height };
+				}
+				return {
+					startTime: e.startTime,
+					size: e.size,
+					element: el ? el.tagName.toLowerCase() : '',
+					url: e.url || '',
+					rect: rect
+				};
+			};
+			const entries = [];
+			try {
+				const observer = new PerformanceObserver((list) => {
+					entries.push(...list.getEntries().map(toJSON));
+				});
+				observer.observe({ type: 'largest-contentful-paint', buffered: true });
+				setTimeout(() => {
+					entries.push(...observer.takeRecords().map(toJSON));
+					observer.disconnect();
+					resolve(entries);
+				}, 0);
+			} catch (e) {
+				// Largest Contentful Paint API not supported
+				resolve(entries);
+			}
+		})
+	`
+
+	err := chromedp.Run(b.ctx, chromedp.Evaluate(script, &entries, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+		return p.WithAwaitPromise(true)
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("get LCP candidates: %w", err)
+	}
+
+	candidates := make([]ports.LCPCandidate, 0, len(entries))
+	for _, e := range entries {
+		candidate := ports.LCPCandidate{
+			StartTime: int64(e.StartTime),
+			Size:      int64(e.Size),
+			Element:   e.Element,
+			URL:       e.URL,
+		}
+		if e.Rect != nil {
+			candidate.Rect = ports.Rect{X: e.Rect.X, Y: e.Rect.Y, Width: e.Rect.Width, Height: e.Rect.Height}
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates, nil
+}
+
+// GetUserTimings retrieves user-timing marks and measures using the User Timing API.
+func (b *Browser) GetUserTimings() ([]ports.UserTiming, error) {
+	var entries []struct {
+		Name      string  `json:"name"`
+		EntryType string  `json:"entryType"`
+		StartTime float64 `json:"startTime"`
+		Duration  float64 `json:"duration"`
+	}
+
+	script := `
+		(function() {
+			return performance.getEntriesByType('mark')
+				.concat(performance.getEntriesByType('measure'))
+				.map((e) => ({
+					name: e.name,
+					entryType: e.entryType,
+					startTime: e.startTime,
+					duration: e.duration
+				}));
+		})()
+	`
+
+	err := chromedp.Run(b.ctx, chromedp.Evaluate(script, &entries))
+	if err != nil {
+		return nil, fmt.Errorf("get user timings: %w", err)
+	}
+
+	timings := make([]ports.UserTiming, 0, len(entries))
+	for _, e := range entries {
+		timings = append(timings, ports.UserTiming{
+			Name:      e.Name,
+			EntryType: e.EntryType,
+			StartTime: int64(e.StartTime),
+			Duration:  int64(e.Duration),
+		})
+	}
+
+	return timings, nil
+}
+
+// GetResourceTimings retrieves the navigation entry and resource entries using the Resource Timing API.
+func (b *Browser) GetResourceTimings() ([]ports.ResourceTiming, error) {
+	var entries []struct {
+		Name          string  `json:"name"`
+		InitiatorType string  `json:"initiatorType"`
+		StartTime     float64 `json:"startTime"`
+		Duration      float64 `json:"duration"`
+		TransferSize  float64 `json:"transferSize"`
+	}
+
+	script := `
+		(function() {
+			return performance.getEntriesByType('navigation')
+				.concat(performance.getEntriesByType('resource'))
+				.map((e) => ({
+					name: e.name,
+					initiatorType: e.entryType === 'navigation' ? 'navigation' : e.initiatorType,
+					startTime: e.startTime,
+					duration: e.duration,
+					transferSize: e.transferSize || 0
+				}));
+		})()
+	`
+
+	err := chromedp.Run(b.ctx, chromedp.Evaluate(script, &entries))
+	if err != nil {
+		return nil, fmt.Errorf("get resource timings: %w", err)
+	}
+
+	timings := make([]ports.ResourceTiming, 0, len(entries))
+	for _, e := range entries {
+		timings = append(timings, ports.ResourceTiming{
+			URL:           e.Name,
+			InitiatorType: e.InitiatorType,
+			StartTime:     int64(e.StartTime),
+			Duration:      int64(e.Duration),
+			TransferSize:  int64(e.TransferSize),
+		})
+	}
+
+	return timings, nil
+}
+
 // Close shuts down the browser.
 func (b *Browser) Close() error {
 	b.StopScreencast()
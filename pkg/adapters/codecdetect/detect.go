@@ -15,6 +15,7 @@ type Codec string
 const (
 	CodecH264    Codec = "h264"
 	CodecAV1     Codec = "av1"
+	CodecMJPEG   Codec = "mjpeg"
 	CodecUnknown Codec = "unknown"
 )
 
@@ -100,6 +101,9 @@ func detectCodecFromTrack(trak *mp4.TrakBox) Codec {
 		case "av01":
 			// AV1
 			return CodecAV1
+		case "jpeg":
+			// Motion JPEG
+			return CodecMJPEG
 		case "hvc1", "hev1":
 			// H.265/HEVC - not supported but detect it
 			return CodecUnknown
@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/Eyevinn/mp4ff/mp4"
+	"github.com/user/loadshow/pkg/frameiter"
 	"github.com/user/loadshow/pkg/ports"
 )
 
@@ -395,6 +396,32 @@ func avccToAnnexB(data []byte) []byte {
 	return result
 }
 
+// OpenFrames opens an MP4 file for incremental decoding.
+// Each iterator has its own decoder, so several can be open at once.
+func (r *MP4Reader) OpenFrames(path string) (ports.FrameIterator, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open file: %w", err)
+	}
+	defer f.Close()
+
+	raw, err := extractFrames(f)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]frameiter.Sample, len(raw))
+	for i, frame := range raw {
+		samples[i] = frameiter.Sample{
+			Data:        frame.Data,
+			TimestampMs: frame.TimestampMs,
+			Duration:    frame.Duration,
+			IsKeyframe:  frame.IsKeyframe,
+		}
+	}
+	return frameiter.New(samples, New()), nil
+}
+
 // Close releases resources.
 func (r *MP4Reader) Close() {
 	if r.decoder != nil {
@@ -404,16 +431,19 @@ func (r *MP4Reader) Close() {
 
 // RawFrame represents a raw H.264 frame without decoding.
 type RawFrame struct {
-	Data        []byte
+	Data        []byte // Annex B, with SPS/PPS prepended on keyframes
 	TimestampMs int
 	Duration    int
 	IsKeyframe  bool
+	SampleSize  int // Size of the sample as stored in the MP4 file
 }
 
 // ExtractFrames extracts raw H.264 frames from MP4 data without decoding.
 func ExtractFrames(mp4Data []byte) ([]RawFrame, error) {
-	reader := &bytesReadSeeker{data: mp4Data}
+	return extractFrames(&bytesReadSeeker{data: mp4Data})
+}
 
+func extractFrames(reader io.ReadSeeker) ([]RawFrame, error) {
 	mp4File, err := mp4.DecodeFile(reader)
 	if err != nil {
 		return nil, fmt.Errorf("decode mp4: %w", err)
@@ -527,6 +557,7 @@ func extractFramesProgressive(mp4File *mp4.File, reader io.ReadSeeker) ([]RawFra
 			TimestampMs: timestampMs,
 			Duration:    durationMs,
 			IsKeyframe:  isKeyframe,
+			SampleSize:  len(sample),
 		})
 	}
 
@@ -634,6 +665,7 @@ func extractFramesFragmented(mp4File *mp4.File, reader io.ReadSeeker) ([]RawFram
 						TimestampMs: timestampMs,
 						Duration:    durationMs,
 						IsKeyframe:  isKeyframe,
+						SampleSize:  len(sample.Data),
 					})
 
 					currentTime += uint64(sample.Dur)
@@ -130,22 +130,8 @@ func (e *ffmpegEncoder) init(width, height int, fps float64, opts ports.EncoderO
 		"-pix_fmt", "yuv420p", // Output pixel format
 	}
 
-	// Add quality settings
-	if opts.Quality > 0 && opts.Quality <= 63 {
-		// Convert our 0-63 scale to x264's CRF (0-51)
-		crf := opts.Quality * 51 / 63
-		if crf > 51 {
-			crf = 51
-		}
-		args = append(args, "-crf", fmt.Sprintf("%d", crf))
-	} else {
-		args = append(args, "-crf", "23") // Default quality
-	}
-
-	// Add bitrate if specified
-	if opts.Bitrate > 0 {
-		args = append(args, "-b:v", fmt.Sprintf("%dk", opts.Bitrate))
-	}
+	// Add quality or bitrate settings
+	args = append(args, rateArgs(opts)...)
 
 	// Profile for compatibility
 	args = append(args,
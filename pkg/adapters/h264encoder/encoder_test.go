@@ -5,6 +5,7 @@ import (
 	"image/color"
 	"os"
 	"runtime"
+	"slices"
 	"testing"
 
 	"github.com/user/loadshow/pkg/ports"
@@ -234,6 +235,23 @@ func TestEncoderNotInitialized(t *testing.T) {
 	}
 }
 
+func TestRateArgs(t *testing.T) {
+	tests := []struct {
+		opts ports.EncoderOptions
+		want []string
+	}{
+		{ports.EncoderOptions{}, []string{"-crf", "23"}},
+		{ports.EncoderOptions{Quality: 63}, []string{"-crf", "51"}},
+		// libx264 would ignore the bitrate next to a CRF
+		{ports.EncoderOptions{Quality: 30, Bitrate: 500}, []string{"-b:v", "500k", "-maxrate", "500k", "-bufsize", "1000k"}},
+	}
+	for _, tt := range tests {
+		if got := rateArgs(tt.opts); !slices.Equal(got, tt.want) {
+			t.Errorf("rateArgs(%+v) = %v, want %v", tt.opts, got, tt.want)
+		}
+	}
+}
+
 func BenchmarkEncode256x192(b *testing.B) {
 	enc := New()
 	width, height := 256, 192
@@ -147,22 +147,8 @@ func (e *FFmpegEncoder) Begin(width, height int, fps float64, opts ports.Encoder
 		"-pix_fmt", "yuv420p", // Output pixel format
 	}
 
-	// Add quality settings
-	if opts.Quality > 0 && opts.Quality <= 63 {
-		// Convert our 0-63 scale to x264's CRF (0-51)
-		crf := opts.Quality * 51 / 63
-		if crf > 51 {
-			crf = 51
-		}
-		args = append(args, "-crf", fmt.Sprintf("%d", crf))
-	} else {
-		args = append(args, "-crf", "23") // Default quality
-	}
-
-	// Add bitrate if specified
-	if opts.Bitrate > 0 {
-		args = append(args, "-b:v", fmt.Sprintf("%dk", opts.Bitrate))
-	}
+	// Add quality or bitrate settings
+	args = append(args, rateArgs(opts)...)
 
 	// Profile for compatibility
 	args = append(args,
@@ -250,3 +236,23 @@ func (e *FFmpegEncoder) End() ([]byte, error) {
 
 // Ensure FFmpegEncoder implements ports.VideoEncoder
 var _ ports.VideoEncoder = (*FFmpegEncoder)(nil)
+
+// rateArgs returns the libx264 rate control arguments. A bitrate replaces
+// the CRF, since libx264 ignores -b:v when -crf is given; it is enforced
+// as a cap so that the output size follows it.
+func rateArgs(opts ports.EncoderOptions) []string {
+	if opts.Bitrate > 0 {
+		return []string{
+			"-b:v", fmt.Sprintf("%dk", opts.Bitrate),
+			"-maxrate", fmt.Sprintf("%dk", opts.Bitrate),
+			"-bufsize", fmt.Sprintf("%dk", opts.Bitrate*2),
+		}
+	}
+
+	crf := 23 // Default quality
+	if opts.Quality > 0 && opts.Quality <= 63 {
+		// Convert our 0-63 scale to x264's CRF (0-51)
+		crf = opts.Quality * 51 / 63
+	}
+	return []string{"-crf", fmt.Sprintf("%d", crf)}
+}
@@ -41,18 +41,45 @@ func init() {
 		"Video encoded: %d bytes":        "動画エンコード完了: %d バイト",
 		"Encoding completed":             "エンコードが完了しました",
 
+		// Size-limited encoding
+		"Video is %d bytes, over the %d byte limit; raising CRF": "動画が %d バイトで上限 %d バイトを超えています。CRFを上げて再エンコードします",
+		"Pass %d: CRF %d, %d bytes":                              "パス %d: CRF %d、%d バイト",
+		"Pass %d: CRF %d at %d kbps, %d bytes":                   "パス %d: CRF %d、%d kbps、%d バイト",
+		"The encoder ignores the CRF; capping the bitrate":       "エンコーダーがCRFを無視するため、ビットレートを制限します",
+
+		// Filmstrip stage
+		"Generating filmstrip":              "フィルムストリップを生成中",
+		"Filmstrip saved to %s (%d frames)": "フィルムストリップを %s に保存しました（%d フレーム）",
+
+		// Poster stage
+		"Generating poster image":                                     "ポスター画像を生成中",
+		"Poster saved to %s (frame at %d ms)":                         "ポスター画像を %s に保存しました（%d ms のフレーム）",
+		"Thumbnail saved to %s (%dx%d)":                               "サムネイルを %s に保存しました（%dx%d）",
+		"LCP was not recorded; using the Load frame for the poster":   "LCPが記録されなかったため、ポスターにLoad時点のフレームを使用します",
+		"Load was not recorded; using the final frame for the poster": "Loadが記録されなかったため、ポスターに最終フレームを使用します",
+
+		// Subtitles
+		"Subtitles saved to %s": "字幕を %s に保存しました",
+
 		// Warnings
 		"Frame capture timeout, using collected frames": "フレームキャプチャがタイムアウトしました。収集したフレームを使用します",
 		"Some frames may be missing":                    "一部のフレームが欠落している可能性があります",
 
 		// Errors (pipeline level)
-		"Failed to calculate layout: %s": "レイアウト計算に失敗: %s",
-		"Failed to record page: %s":      "ページ記録に失敗: %s",
-		"Failed to generate banner: %s":  "バナー生成に失敗: %s",
-		"Failed to composite frames: %s": "フレーム合成に失敗: %s",
-		"Failed to encode video: %s":     "動画エンコードに失敗: %s",
-		"Failed to write output: %s":     "出力の書き込みに失敗: %s",
-		"Failed to launch browser: %s":   "ブラウザの起動に失敗: %s",
-		"Failed to navigate: %s":         "ページ移動に失敗: %s",
+		"Failed to calculate layout: %s":   "レイアウト計算に失敗: %s",
+		"Failed to record page: %s":        "ページ記録に失敗: %s",
+		"Failed to generate banner: %s":    "バナー生成に失敗: %s",
+		"Failed to composite frames: %s":   "フレーム合成に失敗: %s",
+		"Failed to encode video: %s":       "動画エンコードに失敗: %s",
+		"Failed to write output: %s":       "出力の書き込みに失敗: %s",
+		"Failed to generate filmstrip: %s": "フィルムストリップ生成に失敗: %s",
+		"Failed to write filmstrip: %s":    "フィルムストリップの書き込みに失敗: %s",
+		"Failed to write subtitles: %s":    "字幕の書き込みに失敗: %s",
+		"Failed to generate poster: %s":    "ポスター画像生成に失敗: %s",
+		"Failed to write poster: %s":       "ポスター画像の書き込みに失敗: %s",
+		"Failed to write thumbnail: %s":    "サムネイルの書き込みに失敗: %s",
+		"Failed to read frame image: %s":   "フレーム画像の読み込みに失敗: %s",
+		"Failed to launch browser: %s":     "ブラウザの起動に失敗: %s",
+		"Failed to navigate: %s":           "ページ移動に失敗: %s",
 	})
 }
@@ -0,0 +1,186 @@
+// Package mjpegdecoder reads Motion JPEG MP4 files written by mjpegencoder.
+// Decoding uses only the Go standard library.
+package mjpegdecoder
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"os"
+
+	"github.com/Eyevinn/mp4ff/mp4"
+	"github.com/user/loadshow/pkg/frameiter"
+	"github.com/user/loadshow/pkg/ports"
+)
+
+// MP4Reader reads and decodes Motion JPEG frames from an MP4 file.
+type MP4Reader struct{}
+
+// NewMP4Reader creates a new MP4 reader.
+func NewMP4Reader() *MP4Reader {
+	return &MP4Reader{}
+}
+
+// ReadFrames reads all frames from an MP4 file.
+func (r *MP4Reader) ReadFrames(path string) ([]ports.VideoFrame, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open file: %w", err)
+	}
+	defer f.Close()
+
+	return r.ReadFramesFromReader(f)
+}
+
+// ReadFramesFromReader reads all frames from an io.ReadSeeker.
+func (r *MP4Reader) ReadFramesFromReader(reader io.ReadSeeker) ([]ports.VideoFrame, error) {
+	samples, err := extractFrames(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	frames := make([]ports.VideoFrame, 0, len(samples))
+	for _, sample := range samples {
+		img, err := jpeg.Decode(bytes.NewReader(sample.Data))
+		if err != nil {
+			return nil, fmt.Errorf("decode frame at %dms: %w", sample.TimestampMs, err)
+		}
+
+		frames = append(frames, ports.VideoFrame{
+			Image:       img,
+			TimestampMs: sample.TimestampMs,
+			Duration:    sample.Duration,
+		})
+	}
+
+	return frames, nil
+}
+
+// OpenFrames opens an MP4 file for incremental decoding.
+func (r *MP4Reader) OpenFrames(path string) (ports.FrameIterator, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open file: %w", err)
+	}
+	defer f.Close()
+
+	raw, err := extractFrames(f)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]frameiter.Sample, len(raw))
+	for i, frame := range raw {
+		samples[i] = frameiter.Sample(frame)
+	}
+	return frameiter.New(samples, jpegDecoder{}), nil
+}
+
+// RawFrame represents a raw JPEG frame without decoding.
+type RawFrame struct {
+	Data        []byte
+	TimestampMs int
+	Duration    int
+	IsKeyframe  bool
+}
+
+// ExtractFrames extracts raw JPEG frames from MP4 data without decoding.
+// Every frame is a keyframe.
+func ExtractFrames(mp4Data []byte) ([]RawFrame, error) {
+	return extractFrames(bytes.NewReader(mp4Data))
+}
+
+func extractFrames(reader io.ReadSeeker) ([]RawFrame, error) {
+	mp4File, err := mp4.DecodeFile(reader)
+	if err != nil {
+		return nil, fmt.Errorf("decode mp4: %w", err)
+	}
+
+	if !mp4File.IsFragmented() || mp4File.Init == nil || mp4File.Init.Moov == nil {
+		return nil, fmt.Errorf("progressive MP4 not supported, use fragmented MP4")
+	}
+
+	// Find video track, its timescale and trex
+	var videoTrackID uint32
+	var timescale uint32 = 1000
+	for _, trak := range mp4File.Init.Moov.Traks {
+		if trak.Mdia != nil && trak.Mdia.Hdlr != nil && trak.Mdia.Hdlr.HandlerType == "vide" {
+			videoTrackID = trak.Tkhd.TrackID
+			if trak.Mdia.Mdhd != nil {
+				timescale = trak.Mdia.Mdhd.Timescale
+			}
+			break
+		}
+	}
+	if videoTrackID == 0 {
+		return nil, fmt.Errorf("no video track found")
+	}
+
+	var trex *mp4.TrexBox
+	if mp4File.Init.Moov.Mvex != nil {
+		for _, t := range mp4File.Init.Moov.Mvex.Trexs {
+			if t.TrackID == videoTrackID {
+				trex = t
+				break
+			}
+		}
+	}
+
+	var frames []RawFrame
+	for _, seg := range mp4File.Segments {
+		for _, frag := range seg.Fragments {
+			if frag.Moof == nil {
+				continue
+			}
+
+			for _, traf := range frag.Moof.Trafs {
+				if traf.Tfhd.TrackID != videoTrackID {
+					continue
+				}
+
+				var baseDecodeTime uint64
+				if traf.Tfdt != nil {
+					baseDecodeTime = traf.Tfdt.BaseMediaDecodeTime()
+				}
+
+				samples, err := frag.GetFullSamples(trex)
+				if err != nil {
+					return nil, fmt.Errorf("get samples: %w", err)
+				}
+
+				currentTime := baseDecodeTime
+				for _, sample := range samples {
+					frames = append(frames, RawFrame{
+						Data:        sample.Data,
+						TimestampMs: int(currentTime * 1000 / uint64(timescale)),
+						Duration:    int(uint64(sample.Dur) * 1000 / uint64(timescale)),
+						IsKeyframe:  true,
+					})
+
+					currentTime += uint64(sample.Dur)
+				}
+			}
+		}
+	}
+
+	return frames, nil
+}
+
+// jpegDecoder adapts image/jpeg to frameiter.Decoder.
+type jpegDecoder struct{}
+
+func (jpegDecoder) Init() error { return nil }
+
+func (jpegDecoder) DecodeFrame(data []byte) (image.Image, error) {
+	return jpeg.Decode(bytes.NewReader(data))
+}
+
+func (jpegDecoder) Close() {}
+
+// Close releases resources.
+func (r *MP4Reader) Close() {}
+
+// Ensure MP4Reader implements ports.VideoDecoder
+var _ ports.VideoDecoder = (*MP4Reader)(nil)
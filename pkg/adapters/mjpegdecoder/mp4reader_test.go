@@ -0,0 +1,124 @@
+package mjpegdecoder
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/user/loadshow/pkg/adapters/mjpegencoder"
+	"github.com/user/loadshow/pkg/ports"
+)
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	enc := mjpegencoder.New()
+	if err := enc.Begin(64, 48, 30, ports.EncoderOptions{Quality: 10}); err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+
+	timestamps := []int{0, 33, 250, 1000}
+	shades := []uint8{0, 80, 160, 240}
+	for i, ts := range timestamps {
+		img := image.NewRGBA(image.Rect(0, 0, 64, 48))
+		for y := 0; y < 48; y++ {
+			for x := 0; x < 64; x++ {
+				img.Set(x, y, color.RGBA{shades[i], shades[i], shades[i], 255})
+			}
+		}
+		if err := enc.EncodeFrame(img, ts); err != nil {
+			t.Fatalf("EncodeFrame failed: %v", err)
+		}
+	}
+
+	data, err := enc.End()
+	if err != nil {
+		t.Fatalf("End failed: %v", err)
+	}
+
+	frames, err := NewMP4Reader().ReadFramesFromReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ReadFramesFromReader failed: %v", err)
+	}
+	if len(frames) != len(timestamps) {
+		t.Fatalf("got %d frames, want %d", len(frames), len(timestamps))
+	}
+
+	for i, f := range frames {
+		if f.TimestampMs != timestamps[i] {
+			t.Errorf("frame %d timestamp = %d, want %d", i, f.TimestampMs, timestamps[i])
+		}
+		if b := f.Image.Bounds(); b.Dx() != 64 || b.Dy() != 48 {
+			t.Errorf("frame %d size = %dx%d, want 64x48", i, b.Dx(), b.Dy())
+		}
+		r, _, _, _ := f.Image.At(32, 24).RGBA()
+		if diff := int(r>>8) - int(shades[i]); diff < -4 || diff > 4 {
+			t.Errorf("frame %d shade = %d, want ~%d", i, r>>8, shades[i])
+		}
+	}
+
+	// Durations follow the variable frame intervals
+	if frames[1].Duration != 217 {
+		t.Errorf("frame 1 duration = %d, want 217", frames[1].Duration)
+	}
+}
+
+func TestReadFrames_InvalidData(t *testing.T) {
+	if _, err := NewMP4Reader().ReadFramesFromReader(bytes.NewReader([]byte("not mp4"))); err == nil {
+		t.Error("expected error for invalid data")
+	}
+}
+
+func TestOpenFrames(t *testing.T) {
+	enc := mjpegencoder.New()
+	if err := enc.Begin(32, 24, 30, ports.EncoderOptions{Quality: 10}); err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	timestamps := []int{0, 100, 400}
+	for _, ts := range timestamps {
+		if err := enc.EncodeFrame(image.NewRGBA(image.Rect(0, 0, 32, 24)), ts); err != nil {
+			t.Fatalf("EncodeFrame failed: %v", err)
+		}
+	}
+	data, err := enc.End()
+	if err != nil {
+		t.Fatalf("End failed: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "video.mp4")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	it, err := NewMP4Reader().OpenFrames(path)
+	if err != nil {
+		t.Fatalf("OpenFrames failed: %v", err)
+	}
+	defer it.Close()
+
+	for _, want := range timestamps {
+		f, err := it.Next()
+		if err != nil {
+			t.Fatalf("Next failed: %v", err)
+		}
+		if f.TimestampMs != want {
+			t.Errorf("timestamp = %d, want %d", f.TimestampMs, want)
+		}
+	}
+	if _, err := it.Next(); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+
+	// Seek back to the frame shown at 250ms
+	if err := it.Seek(250); err != nil {
+		t.Fatalf("Seek failed: %v", err)
+	}
+	if f, err := it.Next(); err != nil || f.TimestampMs != 100 {
+		t.Errorf("after Seek(250) got %dms (%v), want 100ms", f.TimestampMs, err)
+	}
+	if it.DurationMs() <= 400 {
+		t.Errorf("DurationMs = %d, want > 400", it.DurationMs())
+	}
+}
@@ -0,0 +1,235 @@
+// Package mjpegencoder provides a dependency-free Motion JPEG encoder.
+// Each frame is stored as a baseline JPEG in a fragmented MP4 file. It is
+// used as a last resort when neither FFmpeg nor libaom is available.
+package mjpegencoder
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"sync"
+
+	"github.com/Eyevinn/mp4ff/mp4"
+	"github.com/user/loadshow/pkg/ports"
+)
+
+var (
+	// ErrNotInitialized is returned when encoder methods are called before initialization.
+	ErrNotInitialized = errors.New("mjpegencoder: encoder not initialized")
+
+	// ErrUnsupportedContainer is returned when a container other than MP4 is requested.
+	ErrUnsupportedContainer = errors.New("mjpegencoder: Motion JPEG output requires the MP4 container")
+)
+
+// SampleEntry is the MP4 sample entry type used for Motion JPEG tracks.
+const SampleEntry = "jpeg"
+
+// minQuality is the lowest JPEG quality used to meet a bitrate.
+const minQuality = 5
+
+type encodedFrame struct {
+	data        []byte
+	timestampMs int64
+}
+
+// Encoder implements ports.VideoEncoder by JPEG-compressing every frame.
+// With a bitrate, frames over their share of it are compressed at a lower
+// quality.
+type Encoder struct {
+	mu sync.Mutex
+
+	width   int
+	height  int
+	fps     float64
+	quality int
+
+	// frameBytes is the per-frame size budget (0 = unlimited)
+	frameBytes int
+
+	frames  []encodedFrame
+	started bool
+}
+
+// New creates a new Motion JPEG encoder.
+func New() *Encoder {
+	return &Encoder{}
+}
+
+// Begin initializes the encoder.
+func (e *Encoder) Begin(width, height int, fps float64, opts ports.EncoderOptions) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if opts.Container != "" && opts.Container != ports.ContainerMP4 {
+		return ErrUnsupportedContainer
+	}
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("invalid dimensions: %dx%d", width, height)
+	}
+
+	e.width = width
+	e.height = height
+	e.fps = fps
+	e.quality = jpegQuality(opts.Quality)
+	e.frameBytes = 0
+	if opts.Bitrate > 0 && fps > 0 {
+		e.frameBytes = int(float64(opts.Bitrate) * 1000 / 8 / fps)
+	}
+	e.frames = nil
+	e.started = true
+	return nil
+}
+
+// jpegQuality maps the 0-63 CRF scale (lower is better) to JPEG quality.
+func jpegQuality(crf int) int {
+	if crf <= 0 || crf > 63 {
+		crf = 25
+	}
+	return 100 - crf*60/63
+}
+
+// EncodeFrame compresses a single frame.
+func (e *Encoder) EncodeFrame(img image.Image, timestampMs int) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.started {
+		return ErrNotInitialized
+	}
+
+	data, err := encodeJPEG(img, e.quality)
+	if err != nil {
+		return err
+	}
+	if e.frameBytes > 0 && len(data) > e.frameBytes {
+		data, err = e.fitFrame(img, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	e.frames = append(e.frames, encodedFrame{
+		data:        data,
+		timestampMs: int64(timestampMs),
+	})
+	return nil
+}
+
+// fitFrame binary-searches the highest JPEG quality below e.quality whose
+// frame fits in the per-frame budget. If none does, the frame at
+// minQuality is returned.
+func (e *Encoder) fitFrame(img image.Image, data []byte) ([]byte, error) {
+	best := data
+	low, high := minQuality, e.quality-1
+	for low <= high {
+		mid := (low + high) / 2
+		encoded, err := encodeJPEG(img, mid)
+		if err != nil {
+			return nil, err
+		}
+		if len(encoded) <= e.frameBytes {
+			best = encoded
+			low = mid + 1
+			continue
+		}
+		if len(encoded) < len(best) {
+			best = encoded
+		}
+		high = mid - 1
+	}
+	return best, nil
+}
+
+// encodeJPEG compresses img at the given quality.
+func encodeJPEG(img image.Image, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, fmt.Errorf("encode jpeg: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// End finalizes encoding and returns the MP4 data.
+func (e *Encoder) End() ([]byte, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.started {
+		return nil, ErrNotInitialized
+	}
+	e.started = false
+
+	return e.buildMP4()
+}
+
+// buildMP4 creates a fragmented MP4 container from the JPEG frames.
+// Timestamps are kept in milliseconds so variable frame intervals survive.
+func (e *Encoder) buildMP4() ([]byte, error) {
+	if len(e.frames) == 0 {
+		return nil, fmt.Errorf("no frames to encode")
+	}
+
+	const timescale = 1000
+	trackID := uint32(1)
+
+	init := mp4.CreateEmptyInit()
+	init.AddEmptyTrack(timescale, "video", "en")
+
+	trak := init.Moov.Trak
+	entry := mp4.CreateVisualSampleEntryBox(SampleEntry, uint16(e.width), uint16(e.height), nil)
+	trak.Mdia.Minf.Stbl.Stsd.AddChild(entry)
+	trak.Tkhd.Width = mp4.Fixed32(e.width << 16)
+	trak.Tkhd.Height = mp4.Fixed32(e.height << 16)
+
+	frag, err := mp4.CreateFragment(1, trackID)
+	if err != nil {
+		return nil, fmt.Errorf("create fragment: %w", err)
+	}
+
+	// The last frame is held for one nominal frame interval
+	lastDur := uint32(timescale / 30)
+	if e.fps > 0 {
+		lastDur = uint32(float64(timescale) / e.fps)
+	}
+
+	for i, frame := range e.frames {
+		dur := lastDur
+		if i < len(e.frames)-1 {
+			dur = uint32(e.frames[i+1].timestampMs - frame.timestampMs)
+		}
+		if dur == 0 {
+			dur = 1
+		}
+
+		// Every JPEG frame is independently decodable
+		frag.AddFullSample(mp4.FullSample{
+			Sample: mp4.Sample{
+				Flags: mp4.SyncSampleFlags,
+				Size:  uint32(len(frame.data)),
+				Dur:   dur,
+			},
+			DecodeTime: uint64(frame.timestampMs),
+			Data:       frame.data,
+		})
+	}
+
+	var buf bytes.Buffer
+
+	ftyp := mp4.NewFtyp("isom", 0x200, []string{"isom", "iso2", "mp41"})
+	if err := ftyp.Encode(&buf); err != nil {
+		return nil, fmt.Errorf("encode ftyp: %w", err)
+	}
+	if err := init.Moov.Encode(&buf); err != nil {
+		return nil, fmt.Errorf("encode moov: %w", err)
+	}
+	if err := frag.Encode(&buf); err != nil {
+		return nil, fmt.Errorf("encode fragment: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Ensure Encoder implements ports.VideoEncoder
+var _ ports.VideoEncoder = (*Encoder)(nil)
@@ -0,0 +1,103 @@
+package mjpegencoder
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"math/rand"
+	"testing"
+
+	"github.com/user/loadshow/pkg/ports"
+)
+
+func TestEncoder_End(t *testing.T) {
+	enc := New()
+	if err := enc.Begin(64, 48, 30, ports.EncoderOptions{Quality: 25}); err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+
+	for i, ts := range []int{0, 40, 500} {
+		img := image.NewRGBA(image.Rect(0, 0, 64, 48))
+		for p := range img.Pix {
+			img.Pix[p] = uint8(i * 80)
+		}
+		img.Set(i, i, color.White)
+		if err := enc.EncodeFrame(img, ts); err != nil {
+			t.Fatalf("EncodeFrame failed: %v", err)
+		}
+	}
+
+	data, err := enc.End()
+	if err != nil {
+		t.Fatalf("End failed: %v", err)
+	}
+	if !bytes.Contains(data[:16], []byte("ftyp")) {
+		t.Error("expected MP4 output starting with ftyp")
+	}
+	if !bytes.Contains(data, []byte(SampleEntry)) {
+		t.Errorf("expected %q sample entry", SampleEntry)
+	}
+}
+
+func TestEncoder_EndWithoutFrames(t *testing.T) {
+	enc := New()
+	if err := enc.Begin(64, 48, 30, ports.EncoderOptions{}); err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	if _, err := enc.End(); err == nil {
+		t.Error("expected error for no frames")
+	}
+}
+
+func TestEncoder_EncodeWithoutBegin(t *testing.T) {
+	err := New().EncodeFrame(image.NewRGBA(image.Rect(0, 0, 8, 8)), 0)
+	if !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("expected ErrNotInitialized, got %v", err)
+	}
+}
+
+func TestEncoder_RejectsWebM(t *testing.T) {
+	err := New().Begin(64, 48, 30, ports.EncoderOptions{Container: ports.ContainerWebM})
+	if !errors.Is(err, ErrUnsupportedContainer) {
+		t.Errorf("expected ErrUnsupportedContainer, got %v", err)
+	}
+}
+
+func TestJPEGQuality(t *testing.T) {
+	if q := jpegQuality(0); q != jpegQuality(25) {
+		t.Errorf("jpegQuality(0) = %d, want default %d", q, jpegQuality(25))
+	}
+	if jpegQuality(10) <= jpegQuality(40) {
+		t.Error("lower CRF should give higher JPEG quality")
+	}
+	if q := jpegQuality(63); q < 1 || q > 100 {
+		t.Errorf("jpegQuality(63) = %d out of range", q)
+	}
+}
+
+func TestEncoder_Bitrate(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 128, 96))
+	rng := rand.New(rand.NewSource(1))
+	for p := range img.Pix {
+		img.Pix[p] = uint8(rng.Intn(256))
+	}
+
+	encodedSize := func(bitrate int) int {
+		enc := New()
+		if err := enc.Begin(128, 96, 10, ports.EncoderOptions{Quality: 63, Bitrate: bitrate}); err != nil {
+			t.Fatalf("Begin failed: %v", err)
+		}
+		if err := enc.EncodeFrame(img, 0); err != nil {
+			t.Fatalf("EncodeFrame failed: %v", err)
+		}
+		return len(enc.frames[0].data)
+	}
+
+	unlimited := encodedSize(0)
+	// Half the unlimited frame size per frame at 10 fps
+	bitrate := unlimited / 2 * 8 * 10 / 1000
+	if got, budget := encodedSize(bitrate), bitrate*1000/8/10; got > budget {
+		t.Errorf("frame is %d bytes, over the %d byte budget (unlimited: %d)", got, budget, unlimited)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/user/loadshow/pkg/adapters/av1decoder"
 	"github.com/user/loadshow/pkg/adapters/codecdetect"
 	"github.com/user/loadshow/pkg/adapters/h264decoder"
+	"github.com/user/loadshow/pkg/adapters/mjpegdecoder"
 	"github.com/user/loadshow/pkg/ports"
 )
 
@@ -20,6 +21,8 @@ const (
 	CodecH264 = codecdetect.CodecH264
 	// CodecAV1 represents AV1 codec.
 	CodecAV1 = codecdetect.CodecAV1
+	// CodecMJPEG represents Motion JPEG.
+	CodecMJPEG = codecdetect.CodecMJPEG
 	// CodecUnknown represents an unknown codec.
 	CodecUnknown = codecdetect.CodecUnknown
 )
@@ -34,6 +37,8 @@ const (
 	BackendFFmpeg Backend = "ffmpeg"
 	// BackendLibaom represents libaom for AV1 decoding.
 	BackendLibaom Backend = "libaom"
+	// BackendPureGo represents decoding with the Go standard library (Motion JPEG).
+	BackendPureGo Backend = "go"
 )
 
 // Info contains information about the selected decoder.
@@ -68,6 +73,7 @@ type Decoder struct {
 // The selection flow:
 //   - AV1: Use libaom decoder
 //   - H.264: Try OS-native decoder, then FFmpeg decoder
+//   - Motion JPEG: Use the pure-Go decoder
 func NewFromFile(path string, opts Options) (*Decoder, Info, error) {
 	// Set custom FFmpeg path if provided
 	if opts.FFmpegPath != "" {
@@ -123,6 +129,11 @@ func NewForCodec(codec Codec, opts Options) (*Decoder, Info, error) {
 func createDecoder(codec Codec, opts Options) (*Decoder, Info, error) {
 	switch codec {
 	case CodecAV1:
+		// libaom is not linked in builds without cgo
+		if !av1decoder.IsAvailable() {
+			return nil, Info{}, ErrNoDecoderAvailable
+		}
+
 		return &Decoder{
 				inner: av1decoder.NewMP4Reader(),
 				info: Info{
@@ -158,6 +169,18 @@ func createDecoder(codec Codec, opts Options) (*Decoder, Info, error) {
 				Backend: backend,
 			}, nil
 
+	case CodecMJPEG:
+		return &Decoder{
+				inner: mjpegdecoder.NewMP4Reader(),
+				info: Info{
+					Codec:   CodecMJPEG,
+					Backend: BackendPureGo,
+				},
+			}, Info{
+				Codec:   CodecMJPEG,
+				Backend: BackendPureGo,
+			}, nil
+
 	case CodecUnknown:
 		return nil, Info{}, ErrUnsupportedCodec
 
@@ -189,6 +212,11 @@ func (d *Decoder) ReadFramesFromReader(reader io.ReadSeeker) ([]ports.VideoFrame
 	return d.inner.ReadFramesFromReader(reader)
 }
 
+// OpenFrames opens a video file for incremental decoding.
+func (d *Decoder) OpenFrames(path string) (ports.FrameIterator, error) {
+	return d.inner.OpenFrames(path)
+}
+
 // Close releases decoder resources.
 func (d *Decoder) Close() {
 	d.inner.Close()
@@ -219,9 +247,10 @@ func IsH264Available() bool {
 	return h264decoder.IsAvailable()
 }
 
-// IsAV1Available always returns true (libaom is always linked).
+// IsAV1Available checks if AV1 decoding is available (libaom is linked
+// unless the binary was built without cgo).
 func IsAV1Available() bool {
-	return true
+	return av1decoder.IsAvailable()
 }
 
 // Ensure Decoder implements ports.VideoDecoder
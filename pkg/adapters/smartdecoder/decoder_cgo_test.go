@@ -0,0 +1,32 @@
+//go:build cgo
+
+package smartdecoder
+
+import (
+	"testing"
+)
+
+func TestNewForCodecAV1(t *testing.T) {
+	decoder, info, err := NewForCodec(CodecAV1, Options{})
+	if err != nil {
+		t.Fatalf("failed to create AV1 decoder: %v", err)
+	}
+	if decoder == nil {
+		t.Fatal("decoder is nil")
+	}
+	defer decoder.Close()
+
+	if info.Codec != CodecAV1 {
+		t.Errorf("expected codec AV1, got %s", info.Codec)
+	}
+	if info.Backend != BackendLibaom {
+		t.Errorf("expected backend libaom, got %s", info.Backend)
+	}
+}
+
+func TestIsAV1Available(t *testing.T) {
+	// libaom is linked in cgo builds
+	if !IsAV1Available() {
+		t.Error("AV1 should be available in cgo builds")
+	}
+}
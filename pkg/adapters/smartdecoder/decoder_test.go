@@ -1,26 +1,13 @@
 package smartdecoder
 
 import (
+	"bytes"
+	"image"
 	"testing"
-)
 
-func TestNewForCodecAV1(t *testing.T) {
-	decoder, info, err := NewForCodec(CodecAV1, Options{})
-	if err != nil {
-		t.Fatalf("failed to create AV1 decoder: %v", err)
-	}
-	if decoder == nil {
-		t.Fatal("decoder is nil")
-	}
-	defer decoder.Close()
-
-	if info.Codec != CodecAV1 {
-		t.Errorf("expected codec AV1, got %s", info.Codec)
-	}
-	if info.Backend != BackendLibaom {
-		t.Errorf("expected backend libaom, got %s", info.Backend)
-	}
-}
+	"github.com/user/loadshow/pkg/adapters/mjpegencoder"
+	"github.com/user/loadshow/pkg/ports"
+)
 
 func TestNewForCodecH264(t *testing.T) {
 	if !IsH264Available() {
@@ -53,10 +40,37 @@ func TestNewForCodecUnknown(t *testing.T) {
 	}
 }
 
-func TestIsAV1Available(t *testing.T) {
-	// AV1 should always be available (libaom is linked)
-	if !IsAV1Available() {
-		t.Error("AV1 should always be available")
+func TestNewFromBytesMJPEG(t *testing.T) {
+	enc := mjpegencoder.New()
+	if err := enc.Begin(32, 32, 30, ports.EncoderOptions{}); err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	for _, ts := range []int{0, 100} {
+		if err := enc.EncodeFrame(image.NewRGBA(image.Rect(0, 0, 32, 32)), ts); err != nil {
+			t.Fatalf("EncodeFrame failed: %v", err)
+		}
+	}
+	data, err := enc.End()
+	if err != nil {
+		t.Fatalf("End failed: %v", err)
+	}
+
+	decoder, info, err := NewFromBytes(data, Options{})
+	if err != nil {
+		t.Fatalf("failed to create decoder: %v", err)
+	}
+	defer decoder.Close()
+
+	if info.Codec != CodecMJPEG || info.Backend != BackendPureGo {
+		t.Errorf("expected mjpeg/go, got %s/%s", info.Codec, info.Backend)
+	}
+
+	frames, err := decoder.ReadFramesFromReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ReadFramesFromReader failed: %v", err)
+	}
+	if len(frames) != 2 || frames[1].TimestampMs != 100 {
+		t.Errorf("unexpected frames: %d", len(frames))
 	}
 }
 
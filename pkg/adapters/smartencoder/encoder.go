@@ -7,6 +7,8 @@ import (
 
 	"github.com/user/loadshow/pkg/adapters/av1encoder"
 	"github.com/user/loadshow/pkg/adapters/h264encoder"
+	"github.com/user/loadshow/pkg/adapters/mjpegencoder"
+	"github.com/user/loadshow/pkg/adapters/vp9encoder"
 	"github.com/user/loadshow/pkg/ports"
 )
 
@@ -18,6 +20,10 @@ const (
 	CodecH264 Codec = "h264"
 	// CodecAV1 represents AV1 codec.
 	CodecAV1 Codec = "av1"
+	// CodecVP9 represents VP9 codec (WebM output only).
+	CodecVP9 Codec = "vp9"
+	// CodecMJPEG represents Motion JPEG (pure-Go last-resort fallback).
+	CodecMJPEG Codec = "mjpeg"
 )
 
 // Backend represents the encoding backend used.
@@ -30,6 +36,8 @@ const (
 	BackendFFmpeg Backend = "ffmpeg"
 	// BackendLibaom represents libaom for AV1 encoding.
 	BackendLibaom Backend = "libaom"
+	// BackendPureGo represents encoding with the Go standard library (Motion JPEG).
+	BackendPureGo Backend = "go"
 )
 
 // Info contains information about the selected encoder.
@@ -48,16 +56,23 @@ type Info struct {
 type Options struct {
 	// FFmpegPath is an optional custom path to the ffmpeg binary.
 	FFmpegPath string
-	// AllowFallback enables fallback to AV1 when H.264 is not available.
+	// AllowFallback enables fallback to AV1 when H.264 or VP9 is not
+	// available, and to Motion JPEG when AV1 is not available either.
 	// Defaults to true.
 	AllowFallback bool
 	// Logger is used to log fallback warnings.
 	Logger ports.Logger
+	// Container is the output container (empty = MP4). Motion JPEG cannot be
+	// written to WebM, so WebM falls back between AV1 and VP9 only.
+	Container ports.Container
 }
 
 var (
 	// ErrNoEncoderAvailable is returned when no encoder is available.
 	ErrNoEncoderAvailable = errors.New("smartencoder: no encoder available")
+
+	// ErrWebMNotAvailable is returned when neither AV1 nor VP9 can be encoded for WebM output.
+	ErrWebMNotAvailable = errors.New("smartencoder: WebM output needs libaom (a cgo build) or ffmpeg with libvpx-vp9")
 )
 
 // New creates a new video encoder with automatic codec selection.
@@ -66,8 +81,14 @@ var (
 //  1. Try OS-native encoder (VideoToolbox on macOS, Media Foundation on Windows)
 //  2. Try FFmpeg encoder
 //  3. If AllowFallback is true, fall back to AV1 (libaom)
+//  4. If libaom is not linked either, fall back to Motion JPEG (pure Go)
+//
+// For VP9, FFmpeg with libvpx-vp9 is used, falling back the same way.
 //
-// For AV1, libaom is always used.
+// For AV1, libaom is used, falling back to Motion JPEG in builds without cgo.
+//
+// For WebM output, AV1 falls back to VP9 instead, and ErrWebMNotAvailable
+// is returned when neither is available.
 func New(preferred Codec, opts Options) (ports.VideoEncoder, Info, error) {
 	// Set default for AllowFallback
 	if !opts.AllowFallback {
@@ -89,12 +110,11 @@ func New(preferred Codec, opts Options) (ports.VideoEncoder, Info, error) {
 	case CodecH264:
 		return selectH264Encoder(opts, info)
 	case CodecAV1:
-		return av1encoder.New(), Info{
-			Codec:          CodecAV1,
-			Backend:        BackendLibaom,
-			RequestedCodec: CodecAV1,
-			FallbackUsed:   false,
-		}, nil
+		return selectAV1Encoder(opts, info)
+	case CodecVP9:
+		return selectVP9Encoder(opts, info)
+	case CodecMJPEG:
+		return newMJPEGEncoder(info, false)
 	default:
 		// Default to H.264 selection
 		return selectH264Encoder(opts, info)
@@ -121,12 +141,11 @@ func newInternal(preferred Codec, opts Options) (ports.VideoEncoder, Info, error
 	case CodecH264:
 		return selectH264Encoder(opts, info)
 	case CodecAV1:
-		return av1encoder.New(), Info{
-			Codec:          CodecAV1,
-			Backend:        BackendLibaom,
-			RequestedCodec: CodecAV1,
-			FallbackUsed:   false,
-		}, nil
+		return selectAV1Encoder(opts, info)
+	case CodecVP9:
+		return selectVP9Encoder(opts, info)
+	case CodecMJPEG:
+		return newMJPEGEncoder(info, false)
 	default:
 		return selectH264Encoder(opts, info)
 	}
@@ -158,17 +177,92 @@ func selectH264Encoder(opts Options, info Info) (ports.VideoEncoder, Info, error
 		return nil, Info{}, ErrNoEncoderAvailable
 	}
 
-	// Log fallback warning
+	return fallbackEncoder("H.264", opts, info)
+}
+
+func selectVP9Encoder(opts Options, info Info) (ports.VideoEncoder, Info, error) {
+	if vp9encoder.IsAvailable() {
+		return vp9encoder.New(), Info{
+			Codec:          CodecVP9,
+			Backend:        BackendFFmpeg,
+			RequestedCodec: info.RequestedCodec,
+			FallbackUsed:   false,
+		}, nil
+	}
+
+	if !opts.AllowFallback {
+		return nil, Info{}, ErrNoEncoderAvailable
+	}
+
+	return fallbackEncoder("VP9", opts, info)
+}
+
+func selectAV1Encoder(opts Options, info Info) (ports.VideoEncoder, Info, error) {
+	if av1encoder.IsAvailable() {
+		return av1encoder.New(), Info{
+			Codec:          CodecAV1,
+			Backend:        BackendLibaom,
+			RequestedCodec: info.RequestedCodec,
+			FallbackUsed:   false,
+		}, nil
+	}
+
+	if !opts.AllowFallback {
+		return nil, Info{}, ErrNoEncoderAvailable
+	}
+
+	if opts.Container == ports.ContainerWebM {
+		if !vp9encoder.IsAvailable() {
+			return nil, Info{}, ErrWebMNotAvailable
+		}
+		if opts.Logger != nil {
+			opts.Logger.Warn("AV1 encoder not available, falling back to VP9")
+		}
+		return vp9encoder.New(), Info{
+			Codec:          CodecVP9,
+			Backend:        BackendFFmpeg,
+			RequestedCodec: info.RequestedCodec,
+			FallbackUsed:   true,
+		}, nil
+	}
+
 	if opts.Logger != nil {
-		opts.Logger.Warn("H.264 encoder not available, falling back to AV1")
+		opts.Logger.Warn("AV1 encoder not available, falling back to Motion JPEG")
 	}
+	return newMJPEGEncoder(info, true)
+}
 
-	// Fall back to AV1
-	return av1encoder.New(), Info{
-		Codec:          CodecAV1,
-		Backend:        BackendLibaom,
+// fallbackEncoder returns AV1, or Motion JPEG when libaom is not linked,
+// in place of the unavailable codec name. WebM output has no Motion JPEG
+// fallback.
+func fallbackEncoder(name string, opts Options, info Info) (ports.VideoEncoder, Info, error) {
+	if av1encoder.IsAvailable() {
+		if opts.Logger != nil {
+			opts.Logger.Warn("%s encoder not available, falling back to AV1", name)
+		}
+		return av1encoder.New(), Info{
+			Codec:          CodecAV1,
+			Backend:        BackendLibaom,
+			RequestedCodec: info.RequestedCodec,
+			FallbackUsed:   true,
+		}, nil
+	}
+
+	if opts.Container == ports.ContainerWebM {
+		return nil, Info{}, ErrWebMNotAvailable
+	}
+	if opts.Logger != nil {
+		opts.Logger.Warn("%s encoder not available, falling back to Motion JPEG", name)
+	}
+	return newMJPEGEncoder(info, true)
+}
+
+func newMJPEGEncoder(info Info, fallback bool) (ports.VideoEncoder, Info, error) {
+	return mjpegencoder.New(), Info{
+		Codec:          CodecMJPEG,
+		Backend:        BackendPureGo,
 		RequestedCodec: info.RequestedCodec,
-		FallbackUsed:   true,
+		FallbackUsed:   fallback,
 	}, nil
 }
 
@@ -189,7 +283,13 @@ func IsH264FFmpegAvailable() bool {
 	return h264encoder.IsFFmpegAvailable()
 }
 
-// IsAV1Available always returns true (libaom is always linked).
+// IsVP9Available checks if FFmpeg with libvpx-vp9 is available.
+func IsVP9Available() bool {
+	return vp9encoder.IsAvailable()
+}
+
+// IsAV1Available checks if AV1 encoding is available (libaom is linked
+// unless the binary was built without cgo).
 func IsAV1Available() bool {
-	return true
+	return av1encoder.IsAvailable()
 }
@@ -0,0 +1,33 @@
+//go:build cgo
+
+package smartencoder
+
+import (
+	"testing"
+)
+
+func TestNewAV1Encoder(t *testing.T) {
+	encoder, info, err := New(CodecAV1, Options{})
+	if err != nil {
+		t.Fatalf("failed to create AV1 encoder: %v", err)
+	}
+	if encoder == nil {
+		t.Fatal("encoder is nil")
+	}
+	if info.Codec != CodecAV1 {
+		t.Errorf("expected codec AV1, got %s", info.Codec)
+	}
+	if info.Backend != BackendLibaom {
+		t.Errorf("expected backend libaom, got %s", info.Backend)
+	}
+	if info.FallbackUsed {
+		t.Error("fallback should not be used for AV1")
+	}
+}
+
+func TestIsAV1Available(t *testing.T) {
+	// libaom is linked in cgo builds
+	if !IsAV1Available() {
+		t.Error("AV1 should be available in cgo builds")
+	}
+}
@@ -0,0 +1,45 @@
+//go:build !cgo
+
+package smartencoder
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/user/loadshow/pkg/ports"
+)
+
+func TestNewAV1Encoder_Fallback(t *testing.T) {
+	_, info, err := New(CodecAV1, Options{})
+	if err != nil {
+		t.Fatalf("failed to create encoder: %v", err)
+	}
+	if info.Codec != CodecMJPEG || !info.FallbackUsed {
+		t.Errorf("expected Motion JPEG fallback without libaom, got %+v", info)
+	}
+}
+
+func TestNewAV1Encoder_WebM(t *testing.T) {
+	_, info, err := New(CodecAV1, Options{Container: ports.ContainerWebM})
+	if IsVP9Available() {
+		if err != nil {
+			t.Fatalf("failed to create encoder: %v", err)
+		}
+		if info.Codec != CodecVP9 || !info.FallbackUsed {
+			t.Errorf("expected VP9 fallback for WebM, got %+v", info)
+		}
+		return
+	}
+	if !errors.Is(err, ErrWebMNotAvailable) {
+		t.Errorf("expected ErrWebMNotAvailable, got %v", err)
+	}
+}
+
+func TestNewVP9Encoder_WebM(t *testing.T) {
+	if IsVP9Available() {
+		t.Skip("ffmpeg with libvpx-vp9 is available")
+	}
+	if _, _, err := New(CodecVP9, Options{Container: ports.ContainerWebM}); !errors.Is(err, ErrWebMNotAvailable) {
+		t.Errorf("expected ErrWebMNotAvailable, got %v", err)
+	}
+}
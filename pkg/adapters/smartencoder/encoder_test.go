@@ -4,25 +4,6 @@ import (
 	"testing"
 )
 
-func TestNewAV1Encoder(t *testing.T) {
-	encoder, info, err := New(CodecAV1, Options{})
-	if err != nil {
-		t.Fatalf("failed to create AV1 encoder: %v", err)
-	}
-	if encoder == nil {
-		t.Fatal("encoder is nil")
-	}
-	if info.Codec != CodecAV1 {
-		t.Errorf("expected codec AV1, got %s", info.Codec)
-	}
-	if info.Backend != BackendLibaom {
-		t.Errorf("expected backend libaom, got %s", info.Backend)
-	}
-	if info.FallbackUsed {
-		t.Error("fallback should not be used for AV1")
-	}
-}
-
 func TestNewH264Encoder(t *testing.T) {
 	encoder, info, err := New(CodecH264, Options{
 		AllowFallback: true,
@@ -34,9 +15,9 @@ func TestNewH264Encoder(t *testing.T) {
 		t.Fatal("encoder is nil")
 	}
 
-	// Either H.264 or AV1 (fallback) should be selected
-	if info.Codec != CodecH264 && info.Codec != CodecAV1 {
-		t.Errorf("expected codec H.264 or AV1, got %s", info.Codec)
+	// Either H.264, AV1 or Motion JPEG (fallbacks) should be selected
+	if info.Codec != CodecH264 && info.Codec != CodecAV1 && info.Codec != CodecMJPEG {
+		t.Errorf("expected codec H.264, AV1 or Motion JPEG, got %s", info.Codec)
 	}
 
 	// Verify requested codec is H.264
@@ -48,10 +29,48 @@ func TestNewH264Encoder(t *testing.T) {
 		info.Codec, info.Backend, info.FallbackUsed)
 }
 
-func TestIsAV1Available(t *testing.T) {
-	// AV1 should always be available (libaom is linked)
-	if !IsAV1Available() {
-		t.Error("AV1 should always be available")
+func TestNewVP9Encoder(t *testing.T) {
+	encoder, info, err := New(CodecVP9, Options{
+		AllowFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create VP9 encoder: %v", err)
+	}
+	if encoder == nil {
+		t.Fatal("encoder is nil")
+	}
+
+	// Either VP9 or a fallback should be selected
+	switch info.Codec {
+	case CodecVP9:
+		if info.Backend != BackendFFmpeg || info.FallbackUsed {
+			t.Errorf("unexpected VP9 selection: %+v", info)
+		}
+	case CodecAV1, CodecMJPEG:
+		if !info.FallbackUsed {
+			t.Error("expected fallback flag when AV1 replaces VP9")
+		}
+	default:
+		t.Errorf("expected codec VP9, AV1 or Motion JPEG, got %s", info.Codec)
+	}
+	if info.RequestedCodec != CodecVP9 {
+		t.Errorf("expected requested codec VP9, got %s", info.RequestedCodec)
+	}
+}
+
+func TestNewMJPEGEncoder(t *testing.T) {
+	encoder, info, err := New(CodecMJPEG, Options{})
+	if err != nil {
+		t.Fatalf("failed to create Motion JPEG encoder: %v", err)
+	}
+	if encoder == nil {
+		t.Fatal("encoder is nil")
+	}
+	if info.Codec != CodecMJPEG || info.Backend != BackendPureGo {
+		t.Errorf("expected mjpeg/go, got %s/%s", info.Codec, info.Backend)
+	}
+	if info.FallbackUsed {
+		t.Error("fallback should not be used when Motion JPEG is requested")
 	}
 }
 
@@ -60,5 +79,6 @@ func TestAvailabilityChecks(t *testing.T) {
 	t.Logf("H.264 available: %v", IsH264Available())
 	t.Logf("H.264 native available: %v", IsH264NativeAvailable())
 	t.Logf("H.264 FFmpeg available: %v", IsH264FFmpegAvailable())
+	t.Logf("VP9 available: %v", IsVP9Available())
 	t.Logf("AV1 available: %v", IsAV1Available())
 }
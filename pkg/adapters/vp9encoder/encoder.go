@@ -0,0 +1,254 @@
+// Package vp9encoder provides VP9 video encoding using ffmpeg (libvpx-vp9).
+// The encoded stream is muxed into WebM with the original frame timestamps.
+package vp9encoder
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/user/loadshow/pkg/adapters/h264encoder"
+	"github.com/user/loadshow/pkg/adapters/webm"
+	"github.com/user/loadshow/pkg/ports"
+)
+
+var (
+	// ErrNotInitialized is returned when encoder methods are called before initialization.
+	ErrNotInitialized = errors.New("vp9encoder: encoder not initialized")
+
+	// ErrLibvpxNotFound is returned when ffmpeg was built without libvpx-vp9.
+	ErrLibvpxNotFound = errors.New("vp9encoder: ffmpeg has no libvpx-vp9 encoder")
+
+	// ErrUnsupportedContainer is returned when a container other than WebM is requested.
+	ErrUnsupportedContainer = errors.New("vp9encoder: VP9 output requires the WebM container")
+
+	// ErrInvalidFrameRate is returned when Begin is called with a non-positive frame rate.
+	ErrInvalidFrameRate = errors.New("vp9encoder: frame rate must be positive")
+)
+
+// IsAvailable checks if ffmpeg with the libvpx-vp9 encoder is available.
+// The ffmpeg binary is located the same way as for H.264 (see h264encoder.SetFFmpegPath).
+func IsAvailable() bool {
+	ffmpegPath, err := h264encoder.FindFFmpeg()
+	if err != nil {
+		return false
+	}
+	return hasLibvpx(ffmpegPath)
+}
+
+// hasLibvpx reports whether the ffmpeg binary lists the libvpx-vp9 encoder.
+func hasLibvpx(ffmpegPath string) bool {
+	out, err := exec.Command(ffmpegPath, "-hide_banner", "-encoders").Output()
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(out), "libvpx-vp9")
+}
+
+// Encoder implements ports.VideoEncoder using an ffmpeg external process.
+//
+// Frames are piped to ffmpeg at a fixed nominal rate and read back from an
+// IVF file. Each packet is then re-timed with the timestamp of the input
+// frame it came from, so the variable frame intervals of a recording are
+// kept in the WebM output.
+type Encoder struct {
+	mu sync.Mutex
+
+	width  int
+	height int
+	fps    float64
+
+	cmd        *exec.Cmd
+	stdin      io.WriteCloser
+	stderr     bytes.Buffer
+	tempPath   string
+	timestamps []int64
+}
+
+// New creates a new VP9 encoder.
+func New() *Encoder {
+	return &Encoder{}
+}
+
+// Begin initializes the encoder and starts ffmpeg.
+func (e *Encoder) Begin(width, height int, fps float64, opts ports.EncoderOptions) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if opts.Container != ports.ContainerWebM {
+		return ErrUnsupportedContainer
+	}
+	if fps <= 0 {
+		return ErrInvalidFrameRate
+	}
+
+	ffmpegPath, err := h264encoder.FindFFmpeg()
+	if err != nil {
+		return err
+	}
+	if !hasLibvpx(ffmpegPath) {
+		return ErrLibvpxNotFound
+	}
+
+	e.width = width
+	e.height = height
+	e.fps = fps
+	e.timestamps = nil
+	e.stderr.Reset()
+
+	tmpFile, err := os.CreateTemp("", "vp9encode_*.ivf")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	e.tempPath = tmpFile.Name()
+	tmpFile.Close()
+
+	args := []string{
+		"-y",             // Overwrite output
+		"-f", "rawvideo", // Input format
+		"-pix_fmt", "rgba", // Input pixel format
+		"-s", fmt.Sprintf("%dx%d", width, height), // Input size
+		"-r", fmt.Sprintf("%.2f", fps), // Nominal input rate (re-timed on mux)
+		"-i", "pipe:0", // Read from stdin
+		"-c:v", "libvpx-vp9",
+		"-pix_fmt", "yuv420p",
+		"-deadline", "realtime", // Match the AV1 encoder's realtime mode
+		"-cpu-used", "8",
+		"-row-mt", "1",
+		// One packet per input frame so packets map back to timestamps
+		"-auto-alt-ref", "0",
+		"-lag-in-frames", "0",
+	}
+
+	// VP9 uses the same 0-63 quantizer scale as our CRF value
+	crf := 31
+	if opts.Quality > 0 && opts.Quality <= 63 {
+		crf = opts.Quality
+	}
+	args = append(args, "-crf", fmt.Sprintf("%d", crf))
+
+	// A zero bitrate selects constant quality mode
+	args = append(args, "-b:v", fmt.Sprintf("%dk", max(opts.Bitrate, 0)))
+
+	args = append(args, "-f", "ivf", e.tempPath)
+
+	e.cmd = exec.Command(ffmpegPath, args...)
+	e.cmd.Stderr = &e.stderr
+
+	stdin, err := e.cmd.StdinPipe()
+	if err != nil {
+		os.Remove(e.tempPath)
+		return fmt.Errorf("failed to get stdin pipe: %w", err)
+	}
+	e.stdin = stdin
+
+	if err := e.cmd.Start(); err != nil {
+		os.Remove(e.tempPath)
+		return fmt.Errorf("failed to start ffmpeg: %w", err)
+	}
+
+	return nil
+}
+
+// EncodeFrame writes a single frame to ffmpeg.
+func (e *Encoder) EncodeFrame(img image.Image, timestampMs int) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.stdin == nil {
+		return ErrNotInitialized
+	}
+
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, e.width, e.height))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+
+	if _, err := e.stdin.Write(rgba.Pix); err != nil {
+		return fmt.Errorf("failed to write frame: %w", err)
+	}
+
+	e.timestamps = append(e.timestamps, int64(timestampMs))
+	return nil
+}
+
+// End finalizes encoding and returns the WebM data.
+func (e *Encoder) End() ([]byte, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.stdin == nil {
+		return nil, ErrNotInitialized
+	}
+	defer func() {
+		os.Remove(e.tempPath)
+		e.tempPath = ""
+	}()
+
+	e.stdin.Close()
+	e.stdin = nil
+
+	if err := e.cmd.Wait(); err != nil {
+		return nil, fmt.Errorf("ffmpeg encoding failed: %w\nstderr: %s", err, e.stderr.String())
+	}
+
+	data, err := os.ReadFile(e.tempPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read output: %w", err)
+	}
+
+	packets, err := readIVF(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse ivf: %w", err)
+	}
+
+	frames, err := e.retime(packets)
+	if err != nil {
+		return nil, err
+	}
+
+	durationMs := int64(0)
+	if n := len(frames); n > 0 {
+		durationMs = frames[n-1].TimestampMs + int64(1000/e.fps)
+	}
+
+	return webm.Mux(webm.Track{
+		CodecID: webm.CodecVP9,
+		Width:   e.width,
+		Height:  e.height,
+	}, frames, durationMs)
+}
+
+// retime maps each packet back to the input frame it was encoded from and
+// gives it that frame's timestamp. ffmpeg only saw the frames at the
+// nominal rate, so a packet's pts is the index of its input frame.
+func (e *Encoder) retime(packets []ivfPacket) ([]webm.Frame, error) {
+	// ffmpeg was given the rate rounded to two decimals
+	rate := math.Round(e.fps*100) / 100
+
+	frames := make([]webm.Frame, len(packets))
+	last := -1
+	for i, p := range packets {
+		idx := p.frameIndex(rate)
+		if idx <= last || idx >= len(e.timestamps) {
+			return nil, fmt.Errorf("packet %d (pts %dms) does not match any of the %d input frames", i, p.timestampMs, len(e.timestamps))
+		}
+		last = idx
+		frames[i] = webm.Frame{
+			Data:        p.data,
+			TimestampMs: e.timestamps[idx],
+			Keyframe:    isKeyframe(p.data),
+		}
+	}
+	return frames, nil
+}
+
+// Ensure Encoder implements ports.VideoEncoder
+var _ ports.VideoEncoder = (*Encoder)(nil)
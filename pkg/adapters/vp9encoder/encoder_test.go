@@ -0,0 +1,171 @@
+package vp9encoder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/user/loadshow/pkg/ports"
+)
+
+func buildIVF(rate, scale uint32, frames [][]byte, pts []uint64) []byte {
+	var buf bytes.Buffer
+	hdr := make([]byte, 32)
+	copy(hdr[0:4], "DKIF")
+	binary.LittleEndian.PutUint16(hdr[6:8], 32)
+	copy(hdr[8:12], "VP90")
+	binary.LittleEndian.PutUint32(hdr[16:20], rate)
+	binary.LittleEndian.PutUint32(hdr[20:24], scale)
+	binary.LittleEndian.PutUint32(hdr[24:28], uint32(len(frames)))
+	buf.Write(hdr)
+	for i, f := range frames {
+		fh := make([]byte, 12)
+		binary.LittleEndian.PutUint32(fh[0:4], uint32(len(f)))
+		binary.LittleEndian.PutUint64(fh[4:12], pts[i])
+		buf.Write(fh)
+		buf.Write(f)
+	}
+	return buf.Bytes()
+}
+
+func TestReadIVF(t *testing.T) {
+	data := buildIVF(30, 1, [][]byte{{0x82, 1}, {0x86, 2, 3}}, []uint64{0, 3})
+
+	packets, err := readIVF(data)
+	if err != nil {
+		t.Fatalf("readIVF failed: %v", err)
+	}
+	if len(packets) != 2 {
+		t.Fatalf("got %d packets, want 2", len(packets))
+	}
+	if packets[1].timestampMs != 100 {
+		t.Errorf("packet 1 timestamp = %d, want 100", packets[1].timestampMs)
+	}
+	if !bytes.Equal(packets[1].data, []byte{0x86, 2, 3}) {
+		t.Errorf("packet 1 data = %v", packets[1].data)
+	}
+}
+
+func TestReadIVF_Invalid(t *testing.T) {
+	if _, err := readIVF([]byte("not ivf")); err == nil {
+		t.Error("expected error for non-IVF data")
+	}
+
+	data := buildIVF(30, 1, [][]byte{{0x82, 1, 2, 3}}, []uint64{0})
+	if _, err := readIVF(data[:len(data)-2]); err == nil {
+		t.Error("expected error for truncated frame")
+	}
+}
+
+func TestIsKeyframe(t *testing.T) {
+	tests := []struct {
+		name string
+		b    byte
+		want bool
+	}{
+		{"profile 0 keyframe", 0b10_00_0_0_00, true},
+		{"profile 0 inter frame", 0b10_00_0_1_00, false},
+		{"profile 0 show existing", 0b10_00_1_0_00, false},
+		{"profile 1 keyframe", 0b10_10_0_0_00, true},
+		{"profile 3 keyframe", 0b10_11_0_0_0_0, true},
+		{"profile 3 inter frame", 0b10_11_0_0_1_0, false},
+		{"bad frame marker", 0b00_00_0_0_00, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isKeyframe([]byte{tt.b}); got != tt.want {
+				t.Errorf("isKeyframe(%08b) = %v, want %v", tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetime(t *testing.T) {
+	// ffmpeg dropped the second input frame
+	data := buildIVF(30, 1, [][]byte{{0x82}, {0x86}, {0x86}}, []uint64{0, 2, 3})
+	packets, err := readIVF(data)
+	if err != nil {
+		t.Fatalf("readIVF failed: %v", err)
+	}
+
+	e := &Encoder{fps: 30, timestamps: []int64{0, 40, 300, 1200}}
+	frames, err := e.retime(packets)
+	if err != nil {
+		t.Fatalf("retime failed: %v", err)
+	}
+	want := []int64{0, 300, 1200}
+	for i, f := range frames {
+		if f.TimestampMs != want[i] {
+			t.Errorf("frame %d timestamp = %d, want %d", i, f.TimestampMs, want[i])
+		}
+	}
+	if !frames[0].Keyframe || frames[1].Keyframe {
+		t.Error("keyframe flags not carried over")
+	}
+}
+
+func TestRetime_Mismatch(t *testing.T) {
+	e := &Encoder{fps: 30, timestamps: []int64{0, 40}}
+
+	// More packets than input frames
+	data := buildIVF(30, 1, [][]byte{{0x82}, {0x86}, {0x86}}, []uint64{0, 1, 2})
+	packets, _ := readIVF(data)
+	if _, err := e.retime(packets); err == nil {
+		t.Error("expected error for a packet past the last input frame")
+	}
+
+	// Two packets for the same input frame
+	data = buildIVF(30, 1, [][]byte{{0x82}, {0x86}}, []uint64{0, 0})
+	packets, _ = readIVF(data)
+	if _, err := e.retime(packets); err == nil {
+		t.Error("expected error for duplicate packet timestamps")
+	}
+}
+
+func TestBegin_InvalidFrameRate(t *testing.T) {
+	err := New().Begin(64, 64, 0, ports.EncoderOptions{Container: ports.ContainerWebM})
+	if !errors.Is(err, ErrInvalidFrameRate) {
+		t.Errorf("expected ErrInvalidFrameRate, got %v", err)
+	}
+}
+
+func TestBegin_RequiresWebM(t *testing.T) {
+	err := New().Begin(64, 64, 30, ports.EncoderOptions{Container: ports.ContainerMP4})
+	if !errors.Is(err, ErrUnsupportedContainer) {
+		t.Errorf("expected ErrUnsupportedContainer, got %v", err)
+	}
+}
+
+func TestEncoder_WebM(t *testing.T) {
+	if !IsAvailable() {
+		t.Skip("ffmpeg with libvpx-vp9 not available")
+	}
+
+	enc := New()
+	if err := enc.Begin(64, 64, 30, ports.EncoderOptions{Quality: 40, Container: ports.ContainerWebM}); err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+
+	timestamps := []int{0, 40, 300, 1200}
+	for i, ts := range timestamps {
+		img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+		for p := range img.Pix {
+			img.Pix[p] = uint8(i * 60)
+		}
+		img.Set(i*10, i*10, color.White)
+		if err := enc.EncodeFrame(img, ts); err != nil {
+			t.Fatalf("EncodeFrame failed: %v", err)
+		}
+	}
+
+	data, err := enc.End()
+	if err != nil {
+		t.Fatalf("End failed: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte{0x1A, 0x45, 0xDF, 0xA3}) {
+		t.Error("output does not start with an EBML header")
+	}
+}
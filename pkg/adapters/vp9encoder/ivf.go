@@ -0,0 +1,84 @@
+package vp9encoder
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// ivfPacket is one frame read from an IVF file.
+type ivfPacket struct {
+	data        []byte
+	timestampMs int64
+	timeSec     float64 // Exact presentation time, for mapping back to input frames
+}
+
+// frameIndex returns the index of the input frame a packet was encoded
+// from, given the constant rate the frames were fed to the encoder at.
+func (p ivfPacket) frameIndex(fps float64) int {
+	return int(math.Round(p.timeSec * fps))
+}
+
+// readIVF parses an IVF file. Packet timestamps are converted from the
+// file's timebase to milliseconds.
+func readIVF(data []byte) ([]ivfPacket, error) {
+	const headerSize = 32
+	if len(data) < headerSize || string(data[0:4]) != "DKIF" {
+		return nil, fmt.Errorf("not an IVF file")
+	}
+	hdrLen := int(binary.LittleEndian.Uint16(data[6:8]))
+	if hdrLen < headerSize || hdrLen > len(data) {
+		return nil, fmt.Errorf("invalid IVF header length %d", hdrLen)
+	}
+	rate := int64(binary.LittleEndian.Uint32(data[16:20]))
+	scale := int64(binary.LittleEndian.Uint32(data[20:24]))
+	if rate == 0 || scale == 0 {
+		return nil, fmt.Errorf("invalid IVF timebase %d/%d", scale, rate)
+	}
+
+	var packets []ivfPacket
+	pos := hdrLen
+	for pos < len(data) {
+		if pos+12 > len(data) {
+			return nil, fmt.Errorf("truncated frame header at %d", pos)
+		}
+		size := int(binary.LittleEndian.Uint32(data[pos : pos+4]))
+		pts := int64(binary.LittleEndian.Uint64(data[pos+4 : pos+12]))
+		pos += 12
+		if size > len(data)-pos {
+			return nil, fmt.Errorf("truncated frame at %d", pos)
+		}
+		packets = append(packets, ivfPacket{
+			data:        data[pos : pos+size],
+			timestampMs: pts * scale * 1000 / rate,
+			timeSec:     float64(pts*scale) / float64(rate),
+		})
+		pos += size
+	}
+
+	return packets, nil
+}
+
+// isKeyframe reports whether a VP9 frame is a keyframe by reading the
+// start of its uncompressed header.
+func isKeyframe(frame []byte) bool {
+	if len(frame) == 0 {
+		return false
+	}
+	b := frame[0]
+	if b>>6 != 0x2 { // frame_marker
+		return false
+	}
+	profile := (b>>5)&1 | ((b>>4)&1)<<1
+	bit := 4 // next bit index counted from the MSB
+	if profile == 3 {
+		bit++ // reserved_zero
+	}
+	showExisting := (b >> (7 - bit)) & 1
+	if showExisting == 1 {
+		return false
+	}
+	bit++
+	frameType := (b >> (7 - bit)) & 1
+	return frameType == 0
+}
@@ -0,0 +1,314 @@
+// Package webm provides a minimal WebM (Matroska) muxer for a single
+// video track. It is used by the AV1 and VP9 encoders to write .webm
+// output with the original variable-rate frame timestamps.
+package webm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Codec IDs understood by WebM players.
+const (
+	CodecAV1 = "V_AV1"
+	CodecVP9 = "V_VP9"
+)
+
+// Element IDs used by the muxer.
+const (
+	idEBML               = 0x1A45DFA3
+	idEBMLVersion        = 0x4286
+	idEBMLReadVersion    = 0x42F7
+	idEBMLMaxIDLength    = 0x42F2
+	idEBMLMaxSizeLength  = 0x42F3
+	idDocType            = 0x4282
+	idDocTypeVersion     = 0x4287
+	idDocTypeReadVersion = 0x4285
+
+	idSegment      = 0x18538067
+	idSeekHead     = 0x114D9B74
+	idSeek         = 0x4DBB
+	idSeekID       = 0x53AB
+	idSeekPosition = 0x53AC
+
+	idInfo           = 0x1549A966
+	idTimestampScale = 0x2AD7B1
+	idMuxingApp      = 0x4D80
+	idWritingApp     = 0x5741
+	idDuration       = 0x4489
+
+	idTracks       = 0x1654AE6B
+	idTrackEntry   = 0xAE
+	idTrackNumber  = 0xD7
+	idTrackUID     = 0x73C5
+	idTrackType    = 0x83
+	idFlagLacing   = 0x9C
+	idCodecID      = 0x86
+	idCodecPrivate = 0x63A2
+	idVideo        = 0xE0
+	idPixelWidth   = 0xB0
+	idPixelHeight  = 0xBA
+
+	idCluster     = 0x1F43B675
+	idTimestamp   = 0xE7
+	idSimpleBlock = 0xA3
+
+	idCues               = 0x1C53BB6B
+	idCuePoint           = 0xBB
+	idCueTime            = 0xB3
+	idCueTrackPositions  = 0xB7
+	idCueTrack           = 0xF7
+	idCueClusterPosition = 0xF1
+)
+
+// appName is written to MuxingApp and WritingApp.
+const appName = "loadshow"
+
+// maxClusterSpanMs is the largest timestamp offset a SimpleBlock can
+// carry relative to its cluster (signed 16-bit).
+const maxClusterSpanMs = math.MaxInt16
+
+// Track describes the single video track of a WebM file.
+type Track struct {
+	CodecID      string // CodecAV1 or CodecVP9
+	CodecPrivate []byte // av1C record for AV1, empty for VP9
+	Width        int
+	Height       int
+}
+
+// Frame is one encoded video frame.
+type Frame struct {
+	Data        []byte
+	TimestampMs int64
+	Keyframe    bool
+}
+
+// Mux writes frames into a WebM file. Timestamps are in milliseconds
+// and kept as-is, so variable frame intervals are preserved.
+// durationMs is the presentation duration written to the segment info;
+// when zero, the last frame timestamp is used.
+func Mux(track Track, frames []Frame, durationMs int64) ([]byte, error) {
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("no frames to mux")
+	}
+	if !frames[0].Keyframe {
+		return nil, fmt.Errorf("first frame must be a keyframe")
+	}
+	if track.CodecID == "" {
+		return nil, fmt.Errorf("codec id is required")
+	}
+	for i := 1; i < len(frames); i++ {
+		if frames[i].TimestampMs < frames[i-1].TimestampMs {
+			return nil, fmt.Errorf("frame %d timestamp %dms goes backwards", i, frames[i].TimestampMs)
+		}
+	}
+	if durationMs <= 0 {
+		durationMs = frames[len(frames)-1].TimestampMs
+	}
+
+	info := element(idInfo, concat(
+		uintElement(idTimestampScale, 1000000), // 1ms per tick
+		stringElement(idMuxingApp, appName),
+		stringElement(idWritingApp, appName),
+		floatElement(idDuration, float64(durationMs)),
+	))
+
+	tracks := element(idTracks, element(idTrackEntry, concat(
+		uintElement(idTrackNumber, 1),
+		uintElement(idTrackUID, 1),
+		uintElement(idTrackType, 1), // video
+		uintElement(idFlagLacing, 0),
+		stringElement(idCodecID, track.CodecID),
+		optionalElement(idCodecPrivate, track.CodecPrivate),
+		element(idVideo, concat(
+			uintElement(idPixelWidth, uint64(track.Width)),
+			uintElement(idPixelHeight, uint64(track.Height)),
+		)),
+	)))
+
+	// The SeekHead has a fixed size because positions are written as
+	// 8-byte integers, so the offsets of the following elements can be
+	// computed before the real positions are known.
+	seekHeadSize := len(seekHead(0, 0, 0))
+	infoPos := int64(seekHeadSize)
+	tracksPos := infoPos + int64(len(info))
+	clustersPos := tracksPos + int64(len(tracks))
+
+	clusters, cues := buildClusters(frames, clustersPos)
+	cuesPos := clustersPos + int64(len(clusters))
+
+	var segment bytes.Buffer
+	segment.Write(seekHead(infoPos, tracksPos, cuesPos))
+	segment.Write(info)
+	segment.Write(tracks)
+	segment.Write(clusters)
+	segment.Write(cues)
+
+	var buf bytes.Buffer
+	buf.Write(element(idEBML, concat(
+		uintElement(idEBMLVersion, 1),
+		uintElement(idEBMLReadVersion, 1),
+		uintElement(idEBMLMaxIDLength, 4),
+		uintElement(idEBMLMaxSizeLength, 8),
+		stringElement(idDocType, "webm"),
+		uintElement(idDocTypeVersion, 4),
+		uintElement(idDocTypeReadVersion, 2),
+	)))
+	buf.Write(element(idSegment, segment.Bytes()))
+
+	return buf.Bytes(), nil
+}
+
+// buildClusters groups frames into clusters and returns the encoded
+// clusters together with a Cues element pointing at each of them.
+// A new cluster starts at every keyframe, or when the next frame would
+// overflow the 16-bit relative timestamp. basePos is the segment-relative
+// offset of the first cluster.
+func buildClusters(frames []Frame, basePos int64) (clusters, cues []byte) {
+	var out, cuePoints bytes.Buffer
+
+	var body bytes.Buffer
+	clusterStart := int64(-1)
+	flush := func() {
+		if clusterStart < 0 {
+			return
+		}
+		out.Write(element(idCluster, concat(uintElement(idTimestamp, uint64(clusterStart)), body.Bytes())))
+		body.Reset()
+	}
+
+	for _, f := range frames {
+		if clusterStart < 0 || f.Keyframe || f.TimestampMs-clusterStart > maxClusterSpanMs {
+			flush()
+			clusterStart = f.TimestampMs
+			if f.Keyframe {
+				cuePoints.Write(element(idCuePoint, concat(
+					uintElement(idCueTime, uint64(f.TimestampMs)),
+					element(idCueTrackPositions, concat(
+						uintElement(idCueTrack, 1),
+						uintElement(idCueClusterPosition, uint64(basePos+int64(out.Len()))),
+					)),
+				)))
+			}
+		}
+		body.Write(simpleBlock(f, f.TimestampMs-clusterStart))
+	}
+	flush()
+
+	return out.Bytes(), element(idCues, cuePoints.Bytes())
+}
+
+// simpleBlock encodes a frame as a SimpleBlock on track 1.
+func simpleBlock(f Frame, relMs int64) []byte {
+	payload := make([]byte, 4, 4+len(f.Data))
+	payload[0] = 0x81 // track number 1 as a 1-byte vint
+	binary.BigEndian.PutUint16(payload[1:3], uint16(int16(relMs)))
+	if f.Keyframe {
+		payload[3] = 0x80
+	}
+	payload = append(payload, f.Data...)
+	return element(idSimpleBlock, payload)
+}
+
+// seekHead builds a SeekHead with fixed-width positions.
+func seekHead(infoPos, tracksPos, cuesPos int64) []byte {
+	seek := func(id uint32, pos int64) []byte {
+		return element(idSeek, concat(
+			element(idSeekID, idBytes(id)),
+			fixedUintElement(idSeekPosition, uint64(pos)),
+		))
+	}
+	return element(idSeekHead, concat(
+		seek(idInfo, infoPos),
+		seek(idTracks, tracksPos),
+		seek(idCues, cuesPos),
+	))
+}
+
+// element encodes an EBML element with the given ID and payload.
+func element(id uint32, payload []byte) []byte {
+	out := idBytes(id)
+	out = append(out, sizeBytes(uint64(len(payload)))...)
+	return append(out, payload...)
+}
+
+// optionalElement encodes an element only when payload is non-empty.
+func optionalElement(id uint32, payload []byte) []byte {
+	if len(payload) == 0 {
+		return nil
+	}
+	return element(id, payload)
+}
+
+func uintElement(id uint32, v uint64) []byte {
+	n := 1
+	for n < 8 && v>>(8*n) != 0 {
+		n++
+	}
+	b := make([]byte, n)
+	for i := 0; i < n; i++ {
+		b[n-1-i] = byte(v >> (8 * i))
+	}
+	return element(id, b)
+}
+
+func fixedUintElement(id uint32, v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	return element(id, b)
+}
+
+func floatElement(id uint32, v float64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, math.Float64bits(v))
+	return element(id, b)
+}
+
+func stringElement(id uint32, s string) []byte {
+	return element(id, []byte(s))
+}
+
+// idBytes returns the big-endian bytes of an element ID. IDs already
+// carry their vint length marker, so leading zero bytes are dropped.
+func idBytes(id uint32) []byte {
+	switch {
+	case id > 0xFFFFFF:
+		return []byte{byte(id >> 24), byte(id >> 16), byte(id >> 8), byte(id)}
+	case id > 0xFFFF:
+		return []byte{byte(id >> 16), byte(id >> 8), byte(id)}
+	case id > 0xFF:
+		return []byte{byte(id >> 8), byte(id)}
+	default:
+		return []byte{byte(id)}
+	}
+}
+
+// sizeBytes encodes an element data size as the shortest vint. The
+// all-ones value of each length is reserved for "unknown", so it is
+// skipped.
+func sizeBytes(size uint64) []byte {
+	n := 1
+	for n < 8 && size >= (uint64(1)<<(7*n))-1 {
+		n++
+	}
+	b := make([]byte, n)
+	v := size | uint64(1)<<(7*n)
+	for i := 0; i < n; i++ {
+		b[n-1-i] = byte(v >> (8 * i))
+	}
+	return b
+}
+
+func concat(parts ...[]byte) []byte {
+	size := 0
+	for _, p := range parts {
+		size += len(p)
+	}
+	out := make([]byte, 0, size)
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
@@ -0,0 +1,224 @@
+package webm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+// ebmlElement is a parsed element used by the tests.
+type ebmlElement struct {
+	id      uint32
+	offset  int // offset of the element header within its parent data
+	payload []byte
+}
+
+func readVint(data []byte, keepMarker bool) (uint64, int) {
+	if len(data) == 0 || data[0] == 0 {
+		return 0, 0
+	}
+	n := 1
+	for data[0]&(0x80>>(n-1)) == 0 {
+		n++
+	}
+	if len(data) < n {
+		return 0, 0
+	}
+	v := uint64(data[0])
+	if !keepMarker {
+		v &= uint64(0xFF >> n)
+	}
+	for i := 1; i < n; i++ {
+		v = v<<8 | uint64(data[i])
+	}
+	return v, n
+}
+
+func parseElements(t *testing.T, data []byte) []ebmlElement {
+	t.Helper()
+	var out []ebmlElement
+	pos := 0
+	for pos < len(data) {
+		id, n := readVint(data[pos:], true)
+		if n == 0 {
+			t.Fatalf("bad element id at %d", pos)
+		}
+		size, m := readVint(data[pos+n:], false)
+		if m == 0 {
+			t.Fatalf("bad element size at %d", pos)
+		}
+		start := pos + n + m
+		end := start + int(size)
+		if end > len(data) {
+			t.Fatalf("element 0x%X at %d overruns parent (%d > %d)", id, pos, end, len(data))
+		}
+		out = append(out, ebmlElement{id: uint32(id), offset: pos, payload: data[start:end]})
+		pos = end
+	}
+	return out
+}
+
+func find(elems []ebmlElement, id uint32) []ebmlElement {
+	var out []ebmlElement
+	for _, e := range elems {
+		if e.id == id {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func readUint(b []byte) uint64 {
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
+
+func testFrames() []Frame {
+	return []Frame{
+		{Data: []byte{1, 2, 3}, TimestampMs: 0, Keyframe: true},
+		{Data: []byte{4}, TimestampMs: 33},
+		{Data: []byte{5, 6}, TimestampMs: 250}, // variable interval
+		{Data: []byte{7}, TimestampMs: 40000},  // beyond int16 span
+		{Data: []byte{8, 9}, TimestampMs: 40100, Keyframe: true},
+	}
+}
+
+func TestMux_Structure(t *testing.T) {
+	frames := testFrames()
+	data, err := Mux(Track{CodecID: CodecAV1, CodecPrivate: []byte{0x81, 0, 0, 0}, Width: 320, Height: 240}, frames, 41000)
+	if err != nil {
+		t.Fatalf("Mux failed: %v", err)
+	}
+
+	top := parseElements(t, data)
+	if len(top) != 2 || top[0].id != idEBML || top[1].id != idSegment {
+		t.Fatalf("unexpected top-level elements: %+v", top)
+	}
+
+	header := parseElements(t, top[0].payload)
+	docType := find(header, idDocType)
+	if len(docType) != 1 || string(docType[0].payload) != "webm" {
+		t.Fatalf("DocType = %v, want webm", docType)
+	}
+
+	segData := top[1].payload
+	segment := parseElements(t, segData)
+
+	// SeekHead positions must point at the referenced elements.
+	seeks := find(parseElements(t, find(segment, idSeekHead)[0].payload), idSeek)
+	if len(seeks) != 3 {
+		t.Fatalf("got %d seek entries, want 3", len(seeks))
+	}
+	for _, s := range seeks {
+		fields := parseElements(t, s.payload)
+		target, _ := readVint(find(fields, idSeekID)[0].payload, true)
+		pos := int(readUint(find(fields, idSeekPosition)[0].payload))
+		got, n := readVint(segData[pos:], true)
+		if n == 0 || got != target {
+			t.Errorf("seek to 0x%X at %d found 0x%X", target, pos, got)
+		}
+	}
+
+	// Duration and codec settings.
+	info := parseElements(t, find(segment, idInfo)[0].payload)
+	dur := math.Float64frombits(binary.BigEndian.Uint64(find(info, idDuration)[0].payload))
+	if dur != 41000 {
+		t.Errorf("Duration = %v, want 41000", dur)
+	}
+	entry := parseElements(t, find(parseElements(t, find(segment, idTracks)[0].payload), idTrackEntry)[0].payload)
+	if got := string(find(entry, idCodecID)[0].payload); got != CodecAV1 {
+		t.Errorf("CodecID = %q, want %q", got, CodecAV1)
+	}
+	if got := find(entry, idCodecPrivate); len(got) != 1 || !bytes.Equal(got[0].payload, []byte{0x81, 0, 0, 0}) {
+		t.Errorf("CodecPrivate = %v", got)
+	}
+	video := parseElements(t, find(entry, idVideo)[0].payload)
+	if w := readUint(find(video, idPixelWidth)[0].payload); w != 320 {
+		t.Errorf("PixelWidth = %d, want 320", w)
+	}
+
+	// Blocks must reproduce the original timestamps, keyframe flags and data.
+	clusters := find(segment, idCluster)
+	if len(clusters) != 3 {
+		t.Fatalf("got %d clusters, want 3", len(clusters))
+	}
+	var got []Frame
+	for _, c := range clusters {
+		fields := parseElements(t, c.payload)
+		base := int64(readUint(find(fields, idTimestamp)[0].payload))
+		for _, b := range find(fields, idSimpleBlock) {
+			if b.payload[0] != 0x81 {
+				t.Fatalf("block track = 0x%X, want 0x81", b.payload[0])
+			}
+			rel := int64(int16(binary.BigEndian.Uint16(b.payload[1:3])))
+			got = append(got, Frame{
+				Data:        b.payload[4:],
+				TimestampMs: base + rel,
+				Keyframe:    b.payload[3]&0x80 != 0,
+			})
+		}
+	}
+	if len(got) != len(frames) {
+		t.Fatalf("got %d blocks, want %d", len(got), len(frames))
+	}
+	for i := range frames {
+		if got[i].TimestampMs != frames[i].TimestampMs || got[i].Keyframe != frames[i].Keyframe || !bytes.Equal(got[i].Data, frames[i].Data) {
+			t.Errorf("block %d = %+v, want %+v", i, got[i], frames[i])
+		}
+	}
+
+	// One cue per keyframe, each pointing at a cluster.
+	cuePoints := find(parseElements(t, find(segment, idCues)[0].payload), idCuePoint)
+	if len(cuePoints) != 2 {
+		t.Fatalf("got %d cue points, want 2", len(cuePoints))
+	}
+	for _, cp := range cuePoints {
+		fields := parseElements(t, cp.payload)
+		positions := parseElements(t, find(fields, idCueTrackPositions)[0].payload)
+		pos := int(readUint(find(positions, idCueClusterPosition)[0].payload))
+		if id, _ := readVint(segData[pos:], true); id != idCluster {
+			t.Errorf("cue position %d points at 0x%X, want cluster", pos, id)
+		}
+	}
+}
+
+func TestMux_Errors(t *testing.T) {
+	track := Track{CodecID: CodecVP9, Width: 16, Height: 16}
+
+	if _, err := Mux(track, nil, 0); err == nil {
+		t.Error("expected error for no frames")
+	}
+	if _, err := Mux(track, []Frame{{Data: []byte{1}}}, 0); err == nil {
+		t.Error("expected error when first frame is not a keyframe")
+	}
+	if _, err := Mux(track, []Frame{
+		{Data: []byte{1}, TimestampMs: 100, Keyframe: true},
+		{Data: []byte{2}, TimestampMs: 50},
+	}, 0); err == nil {
+		t.Error("expected error for decreasing timestamps")
+	}
+	if _, err := Mux(Track{}, []Frame{{Data: []byte{1}, Keyframe: true}}, 0); err == nil {
+		t.Error("expected error for missing codec id")
+	}
+}
+
+func TestSizeBytes(t *testing.T) {
+	tests := []struct {
+		size uint64
+		want []byte
+	}{
+		{0, []byte{0x80}},
+		{126, []byte{0xFE}},
+		{127, []byte{0x40, 0x7F}}, // 0xFF is reserved for unknown size
+		{300, []byte{0x41, 0x2C}},
+	}
+	for _, tt := range tests {
+		if got := sizeBytes(tt.size); !bytes.Equal(got, tt.want) {
+			t.Errorf("sizeBytes(%d) = % X, want % X", tt.size, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// Package budget checks recording results against performance budgets.
+package budget
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/user/loadshow/pkg/loadshow"
+	"gopkg.in/yaml.v3"
+)
+
+// File is a budgets file: a list of budgets for URL patterns.
+//
+//	budgets:
+//	  - url: "https://example.com/*"
+//	    load_ms: 3000
+//	    total_bytes: 2MB
+//	    marks:
+//	      hero-visible: 1500
+type File struct {
+	Budgets []Budget `yaml:"budgets"`
+}
+
+// Budget sets limits for the pages whose URL matches URL.
+// Limits left at zero (nil for CLS) are not checked.
+type Budget struct {
+	URL                      string         `yaml:"url"` // * matches any characters ("" = every page)
+	LoadMs                   int            `yaml:"load_ms"`
+	DOMContentLoadedMs       int            `yaml:"dom_content_loaded_ms"`
+	LargestContentfulPaintMs int            `yaml:"lcp_ms"`
+	CumulativeLayoutShift    *float64       `yaml:"cls"`
+	TotalBytes               Size           `yaml:"total_bytes"`
+	Requests                 int            `yaml:"requests"`
+	Marks                    map[string]int `yaml:"marks"` // User-timing mark or measure name to max ms
+
+	re *regexp.Regexp // URL compiled by Parse (nil = compile on use)
+}
+
+// Size is a byte count written as a number or with a unit (e.g. 2MB, 800KB).
+type Size int64
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (s *Size) UnmarshalYAML(node *yaml.Node) error {
+	var n int64
+	if err := node.Decode(&n); err == nil {
+		*s = Size(n)
+		return nil
+	}
+	n, err := loadshow.ParseSize(node.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", node.Line, err)
+	}
+	*s = Size(n)
+	return nil
+}
+
+// Load reads a budgets file.
+func Load(path string) (File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return File{}, fmt.Errorf("read budgets: %w", err)
+	}
+	f, err := Parse(data)
+	if err != nil {
+		return File{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return f, nil
+}
+
+// Parse parses a budgets file.
+func Parse(data []byte) (File, error) {
+	var f File
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return File{}, fmt.Errorf("parse budgets: %w", err)
+	}
+	if len(f.Budgets) == 0 {
+		return File{}, fmt.Errorf("no budgets defined")
+	}
+	for i := range f.Budgets {
+		b := &f.Budgets[i]
+		re, err := compilePattern(b.URL)
+		if err != nil {
+			return File{}, fmt.Errorf("budget %d: invalid url pattern %q: %w", i+1, b.URL, err)
+		}
+		b.re = re
+		if b.LoadMs < 0 || b.DOMContentLoadedMs < 0 || b.LargestContentfulPaintMs < 0 || b.TotalBytes < 0 || b.Requests < 0 ||
+			(b.CumulativeLayoutShift != nil && *b.CumulativeLayoutShift < 0) {
+			return File{}, fmt.Errorf("budget %d (%s): limits must not be negative", i+1, b.pattern())
+		}
+		for name, ms := range b.Marks {
+			if ms <= 0 {
+				return File{}, fmt.Errorf("budget %d (%s): invalid limit for mark %s: %d", i+1, b.pattern(), name, ms)
+			}
+		}
+	}
+	return f, nil
+}
+
+// Match returns the budgets whose pattern matches url, in file order.
+func (f File) Match(url string) []Budget {
+	var matched []Budget
+	for _, b := range f.Budgets {
+		if b.Matches(url) {
+			matched = append(matched, b)
+		}
+	}
+	return matched
+}
+
+// Matches reports whether the budget applies to url. Patterns without a
+// wildcard must match the whole URL.
+func (b Budget) Matches(url string) bool {
+	if b.URL == "" {
+		return true
+	}
+	re := b.re
+	if re == nil {
+		var err error
+		if re, err = compilePattern(b.URL); err != nil {
+			return false
+		}
+	}
+	return re.MatchString(url)
+}
+
+// compilePattern compiles a URL pattern in which * matches any characters.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	parts := strings.Split(pattern, "*")
+	for i, p := range parts {
+		parts[i] = regexp.QuoteMeta(p)
+	}
+	return regexp.Compile("^" + strings.Join(parts, ".*") + "$")
+}
+
+// pattern returns the URL pattern for messages.
+func (b Budget) pattern() string {
+	if b.URL == "" {
+		return "*"
+	}
+	return b.URL
+}
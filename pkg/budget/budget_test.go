@@ -0,0 +1,215 @@
+package budget
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/user/loadshow/pkg/orchestrator"
+)
+
+const testBudgets = `
+budgets:
+  - url: "https://example.com/*"
+    load_ms: 3000
+    dom_content_loaded_ms: 1500
+    total_bytes: 1MB
+    requests: 2
+    cls: 0.1
+    marks:
+      hero: 1000
+      ads: 2000
+  - url: "https://example.com/shop"
+    lcp_ms: 2000
+  - load_ms: 10000
+`
+
+func TestParse(t *testing.T) {
+	f, err := Parse([]byte(testBudgets))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(f.Budgets) != 3 {
+		t.Fatalf("got %d budgets, want 3", len(f.Budgets))
+	}
+	b := f.Budgets[0]
+	if b.TotalBytes != 1<<20 || b.Requests != 2 || *b.CumulativeLayoutShift != 0.1 || b.Marks["hero"] != 1000 {
+		t.Errorf("unexpected budget: %+v", b)
+	}
+	for i, b := range f.Budgets {
+		if b.re == nil {
+			t.Errorf("budget %d: url pattern not compiled", i+1)
+		}
+	}
+
+	f, err = Parse([]byte("budgets:\n  - total_bytes: 2048\n"))
+	if err != nil || f.Budgets[0].TotalBytes != 2048 {
+		t.Errorf("plain byte count: %v, %v", f.Budgets, err)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		errMsg string
+	}{
+		{"empty", "budgets: []\n", "no budgets"},
+		{"negative", "budgets:\n  - load_ms: -1\n", "must not be negative"},
+		{"bad size", "budgets:\n  - total_bytes: lots\n", "invalid size"},
+		{"bad mark", "budgets:\n  - marks:\n      hero: 0\n", "invalid limit for mark hero"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("error = %v, want %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestBudget_Matches(t *testing.T) {
+	tests := []struct {
+		pattern string
+		url     string
+		want    bool
+	}{
+		{"", "https://example.com/", true},
+		{"https://example.com/*", "https://example.com/shop?id=1", true},
+		{"https://example.com/*", "https://example.org/", false},
+		{"https://example.com/shop", "https://example.com/shop/cart", false},
+		{"*://example.com/a.b", "https://example.com/aXb", false},
+	}
+	for _, tt := range tests {
+		if got := (Budget{URL: tt.pattern}).Matches(tt.url); got != tt.want {
+			t.Errorf("Matches(%q, %q) = %v, want %v", tt.pattern, tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	f, err := Parse([]byte(testBudgets))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	result := orchestrator.RunResult{
+		DOMContentLoadedMs:    800,
+		LoadCompleteMs:        3200,
+		CumulativeLayoutShift: 0.05,
+		LayoutShiftsRecorded:  true,
+		TotalBytes:            500000,
+		RequestCount:          3,
+		UserTimings:           map[string]int{"hero": 900},
+		TimeoutSec:            30,
+	}
+
+	report := f.Evaluate("https://example.com/shop", result)
+
+	got := map[string]bool{}
+	for _, r := range report.Results {
+		if r.Pattern == "https://example.com/*" {
+			got[r.Metric] = r.Passed
+		}
+	}
+	want := map[string]bool{
+		"load":               false, // 3200 > 3000
+		"dom_content_loaded": true,
+		"cls":                true,
+		"total_bytes":        true,
+		"requests":           false, // 3 > 2
+		"mark:hero":          true,
+		"mark:ads":           false, // not recorded
+	}
+	for metric, passed := range want {
+		if p, ok := got[metric]; !ok || p != passed {
+			t.Errorf("%s: passed = %v (checked %v), want %v", metric, p, ok, passed)
+		}
+	}
+
+	// The shop budget has an LCP limit, but LCP was not recorded; the
+	// catch-all budget passes
+	if len(report.Results) != len(want)+2 {
+		t.Fatalf("got %d results, want %d", len(report.Results), len(want)+2)
+	}
+	lcp := report.Results[len(want)]
+	if lcp.Metric != "lcp" || lcp.Passed || lcp.String() != "lcp: not recorded (budget 2000 ms)" {
+		t.Errorf("unexpected LCP result: %+v (%s)", lcp, lcp)
+	}
+	if report.Failures() != 4 || report.Passed() {
+		t.Errorf("failures = %d, want 4", report.Failures())
+	}
+
+	if other := f.Evaluate("https://example.org/", result); len(other.Results) != 1 || !other.Passed() {
+		t.Errorf("only the catch-all budget should apply: %+v", other.Results)
+	}
+}
+
+func TestEvaluate_NotCollected(t *testing.T) {
+	cls := 0.1
+	f := File{Budgets: []Budget{{Requests: 50, CumulativeLayoutShift: &cls}}}
+	report := f.Evaluate("https://example.com/", orchestrator.RunResult{
+		Requests: make([]orchestrator.RequestResult, 3), // Resource timings alone do not count
+	})
+	if len(report.Results) != 2 {
+		t.Fatalf("got %d results, want 2", len(report.Results))
+	}
+	for _, r := range report.Results {
+		if !r.Skipped || r.Passed {
+			t.Errorf("%s: expected skipped as not collected, got %+v", r.Metric, r)
+		}
+	}
+	if report.Skipped() != 2 || report.Failures() != 0 || !report.Passed() {
+		t.Errorf("skipped = %d, failures = %d; want 2 skipped and no failures", report.Skipped(), report.Failures())
+	}
+	if got := report.Results[0].String(); got != "cls: not collected, skipped (budget 0.1)" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestEvaluate_Timeout(t *testing.T) {
+	f := File{Budgets: []Budget{{LoadMs: 5000, DOMContentLoadedMs: 5000}}}
+	report := f.Evaluate("https://example.com/", orchestrator.RunResult{
+		DOMContentLoadedMs: 2000,
+		LoadCompleteMs:     1000,
+		TimedOut:           true,
+		TimeoutSec:         1,
+	})
+	for _, r := range report.Results {
+		if r.Recorded || r.Passed {
+			t.Errorf("%s should fail as not recorded after a timeout", r.Metric)
+		}
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	report := Report{
+		URL: "https://example.com/",
+		Results: []Result{
+			{Pattern: "*", Metric: "load", Limit: 3000, Actual: 2000, Unit: "ms", Recorded: true, Passed: true},
+			{Pattern: "*", Metric: "total_bytes", Limit: 1000, Actual: 2048, Unit: "bytes", Recorded: true},
+			{Pattern: "*", Metric: "cls", Limit: 0.1, Skipped: true},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, report); err != nil {
+		t.Fatalf("WriteJUnit failed: %v", err)
+	}
+
+	var doc junitSuites
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, buf.String())
+	}
+	if doc.Tests != 3 || doc.Failures != 1 || doc.Skipped != 1 || len(doc.Suites) != 1 {
+		t.Fatalf("unexpected totals: %+v", doc)
+	}
+	cases := doc.Suites[0].Cases
+	if cases[0].Failure != nil || cases[1].Failure == nil || cases[2].Failure != nil || cases[2].Skipped == nil {
+		t.Fatalf("unexpected cases: %+v", cases)
+	}
+	if msg := cases[1].Failure.Message; msg != "total_bytes: 2048 bytes > 1000 bytes" {
+		t.Errorf("failure message = %q", msg)
+	}
+}
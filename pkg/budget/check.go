@@ -0,0 +1,145 @@
+package budget
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/user/loadshow/pkg/orchestrator"
+)
+
+// Result is the outcome of one budget limit.
+type Result struct {
+	Pattern  string  // URL pattern of the budget
+	Metric   string  // e.g. "load", "lcp", "mark:hero"
+	Limit    float64 // Maximum allowed value
+	Actual   float64 // Measured value (0 if not recorded)
+	Unit     string  // "ms", "bytes" or "" for counts and scores
+	Recorded bool    // False if the page never produced the metric
+	Skipped  bool    // The metric could not be collected, so the limit was not checked
+	Passed   bool
+}
+
+// String formats the result, e.g. "load: 3200 ms > 3000 ms".
+func (r Result) String() string {
+	if r.Skipped {
+		return fmt.Sprintf("%s: not collected, skipped (budget %s)", r.Metric, r.format(r.Limit))
+	}
+	if !r.Recorded {
+		return fmt.Sprintf("%s: not recorded (budget %s)", r.Metric, r.format(r.Limit))
+	}
+	op := "<="
+	if !r.Passed {
+		op = ">"
+	}
+	return fmt.Sprintf("%s: %s %s %s", r.Metric, r.format(r.Actual), op, r.format(r.Limit))
+}
+
+func (r Result) format(v float64) string {
+	if r.Unit == "" {
+		return fmt.Sprintf("%g", v)
+	}
+	return fmt.Sprintf("%.0f %s", v, r.Unit)
+}
+
+// Report contains the results of every budget matching a page.
+type Report struct {
+	URL     string
+	Results []Result
+}
+
+// Failures returns the number of exceeded limits.
+func (r Report) Failures() int {
+	n := 0
+	for _, res := range r.Results {
+		if !res.Passed && !res.Skipped {
+			n++
+		}
+	}
+	return n
+}
+
+// Skipped returns the number of limits whose metric could not be collected.
+func (r Report) Skipped() int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Skipped {
+			n++
+		}
+	}
+	return n
+}
+
+// Passed reports whether every limit was met.
+func (r Report) Passed() bool {
+	return r.Failures() == 0
+}
+
+// Evaluate checks a recording of url against every matching budget.
+// Metrics the page did not produce (for example Load after a timeout)
+// fail their limits. Metrics the recorder could not collect (layout shifts,
+// request counts) are skipped.
+func (f File) Evaluate(url string, result orchestrator.RunResult) Report {
+	report := Report{URL: url}
+
+	for _, b := range f.Match(url) {
+		check := func(metric string, limit, actual float64, unit string, recorded bool) {
+			report.Results = append(report.Results, Result{
+				Pattern:  b.pattern(),
+				Metric:   metric,
+				Limit:    limit,
+				Actual:   actual,
+				Unit:     unit,
+				Recorded: recorded,
+				Passed:   recorded && actual <= limit,
+			})
+		}
+		skip := func(metric string, limit float64, unit string) {
+			report.Results = append(report.Results, Result{
+				Pattern: b.pattern(),
+				Metric:  metric,
+				Limit:   limit,
+				Unit:    unit,
+				Skipped: true,
+			})
+		}
+
+		if b.LoadMs > 0 {
+			check("load", float64(b.LoadMs), float64(result.LoadCompleteMs), "ms", result.LoadRecorded())
+		}
+		if b.DOMContentLoadedMs > 0 {
+			check("dom_content_loaded", float64(b.DOMContentLoadedMs), float64(result.DOMContentLoadedMs), "ms", result.DOMContentLoadedRecorded())
+		}
+		if b.LargestContentfulPaintMs > 0 {
+			check("lcp", float64(b.LargestContentfulPaintMs), float64(result.LargestContentfulPaintMs), "ms", result.LargestContentfulPaintMs > 0)
+		}
+		if b.CumulativeLayoutShift != nil {
+			if result.LayoutShiftsRecorded {
+				check("cls", *b.CumulativeLayoutShift, result.CumulativeLayoutShift, "", true)
+			} else {
+				skip("cls", *b.CumulativeLayoutShift, "")
+			}
+		}
+		if b.TotalBytes > 0 {
+			check("total_bytes", float64(b.TotalBytes), float64(result.TotalBytes), "bytes", true)
+		}
+		if b.Requests > 0 {
+			if result.RequestCount > 0 {
+				check("requests", float64(b.Requests), float64(result.RequestCount), "", true)
+			} else {
+				skip("requests", float64(b.Requests), "")
+			}
+		}
+
+		names := make([]string, 0, len(b.Marks))
+		for name := range b.Marks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			ms, recorded := result.UserTimings[name]
+			check("mark:"+name, float64(b.Marks[name]), float64(ms), "ms", recorded)
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,76 @@
+package budget
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// JUnit XML elements, as read by CI systems
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure"`
+	Skipped   *junitSkipped `xml:"skipped"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// WriteJUnit writes reports as JUnit XML: one test suite per page and
+// one test case per budget limit. Limits whose metric could not be
+// collected are skipped test cases.
+func WriteJUnit(w io.Writer, reports ...Report) error {
+	doc := junitSuites{Name: "loadshow budgets"}
+	for _, report := range reports {
+		suite := junitSuite{Name: report.URL, Tests: len(report.Results), Failures: report.Failures(), Skipped: report.Skipped()}
+		for _, r := range report.Results {
+			tc := junitCase{Name: r.Metric, ClassName: r.Pattern}
+			switch {
+			case r.Skipped:
+				tc.Skipped = &junitSkipped{Message: r.String()}
+			case !r.Passed:
+				tc.Failure = &junitFailure{Message: r.String(), Type: "budget"}
+			}
+			suite.Cases = append(suite.Cases, tc)
+		}
+		doc.Suites = append(doc.Suites, suite)
+		doc.Tests += suite.Tests
+		doc.Failures += suite.Failures
+		doc.Skipped += suite.Skipped
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("write JUnit: %w", err)
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("write JUnit: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
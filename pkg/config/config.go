@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/user/loadshow/pkg/orchestrator"
+	"github.com/user/loadshow/pkg/pipeline"
 	"github.com/user/loadshow/pkg/ports"
 	"gopkg.in/yaml.v3"
 )
@@ -28,6 +29,11 @@ type Config struct {
 	BannerHeight   int `yaml:"banner_height"`
 	ProgressHeight int `yaml:"progress_height"`
 
+	// Device frame
+	DeviceFrame string `yaml:"device_frame"` // phone, browser
+	FrameImage  string `yaml:"frame_image"`  // 9-slice PNG path (overrides device_frame)
+	FrameSlice  [4]int `yaml:"frame_slice"`  // top, right, bottom, left
+
 	// Recording
 	ViewportWidth int               `yaml:"viewport_width"`
 	TimeoutMs     int               `yaml:"timeout_ms"`
@@ -43,9 +49,14 @@ type Config struct {
 	BannerTheme   ThemeConfig `yaml:"banner_theme"`
 
 	// Composite
-	Workers      int         `yaml:"workers"`
-	ShowProgress bool        `yaml:"show_progress"`
-	Theme        ThemeConfig `yaml:"theme"`
+	Workers          int                `yaml:"workers"`
+	ShowProgress     bool               `yaml:"show_progress"`
+	HighlightChanges bool               `yaml:"highlight_changes"`
+	HighlightFadeMs  int                `yaml:"highlight_fade_ms"`
+	ShowLayoutShifts bool               `yaml:"show_layout_shifts"`
+	ShowLCP          bool               `yaml:"show_lcp"`
+	TimingMarks      []TimingMarkConfig `yaml:"timing_marks"`
+	Theme            ThemeConfig        `yaml:"theme"`
 
 	// Encoding
 	VideoCRF int     `yaml:"video_crf"`
@@ -66,6 +77,13 @@ type NetworkConfig struct {
 	Offline       bool `yaml:"offline"`
 }
 
+// TimingMarkConfig selects a user-timing mark or measure to show as a badge.
+type TimingMarkConfig struct {
+	Name  string `yaml:"name"`
+	Label string `yaml:"label"`
+	Color string `yaml:"color"` // Hex color (empty = default)
+}
+
 // ThemeConfig represents theming options.
 type ThemeConfig struct {
 	BackgroundColor  string `yaml:"background_color"`
@@ -105,8 +123,9 @@ func Defaults() Config {
 		},
 
 		// Composite
-		Workers:      4,
-		ShowProgress: true,
+		Workers:         4,
+		ShowProgress:    true,
+		HighlightFadeMs: 500,
 		Theme: ThemeConfig{
 			BackgroundColor:  "#dcdcdc",
 			BorderColor:      "#b4b4b4",
@@ -195,6 +214,31 @@ func hexValue(c byte) uint8 {
 	}
 }
 
+// deviceFrameStyle returns the frame style, preferring a frame image when set.
+func (c Config) deviceFrameStyle() pipeline.FrameStyle {
+	if c.FrameImage != "" {
+		return pipeline.FrameImage
+	}
+	return pipeline.FrameStyle(c.DeviceFrame)
+}
+
+// timingMarks converts the configured timing marks to orchestrator timing marks.
+func (c Config) timingMarks() []orchestrator.TimingMark {
+	if len(c.TimingMarks) == 0 {
+		return nil
+	}
+	marks := make([]orchestrator.TimingMark, 0, len(c.TimingMarks))
+	for _, m := range c.TimingMarks {
+		mark := orchestrator.TimingMark{Name: m.Name, Label: m.Label}
+		if m.Color != "" {
+			r, g, b, a := ParseColor(m.Color).RGBA()
+			mark.Color = [4]uint8{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), uint8(a >> 8)}
+		}
+		marks = append(marks, mark)
+	}
+	return marks
+}
+
 // ToOrchestratorConfig converts Config to orchestrator.Config.
 func (c Config) ToOrchestratorConfig() orchestrator.Config {
 	return orchestrator.Config{
@@ -211,6 +255,15 @@ func (c Config) ToOrchestratorConfig() orchestrator.Config {
 		Outdent:        c.Outdent,
 		ProgressHeight: c.ProgressHeight,
 
+		DeviceFrame:    c.deviceFrameStyle(),
+		FrameImagePath: c.FrameImage,
+		FrameSlice: pipeline.Insets{
+			Top:    c.FrameSlice[0],
+			Right:  c.FrameSlice[1],
+			Bottom: c.FrameSlice[2],
+			Left:   c.FrameSlice[3],
+		},
+
 		ViewportWidth: c.ViewportWidth,
 		TimeoutMs:     c.TimeoutMs,
 		NetworkConditions: ports.NetworkConditions{
@@ -226,6 +279,12 @@ func (c Config) ToOrchestratorConfig() orchestrator.Config {
 		BannerHeight:  c.BannerHeight,
 		ShowProgress:  c.ShowProgress,
 
+		HighlightChanges: c.HighlightChanges,
+		HighlightFadeMs:  c.HighlightFadeMs,
+		ShowLayoutShifts: c.ShowLayoutShifts,
+		ShowLCP:          c.ShowLCP,
+		TimingMarks:      c.timingMarks(),
+
 		VideoCRF: c.VideoCRF,
 		Bitrate:  c.Bitrate,
 		FPS:      c.FPS,
@@ -0,0 +1,157 @@
+// Package frameiter implements ports.FrameIterator over demuxed video samples.
+// Codec packages supply the compressed samples and a decoder; the iterator
+// decodes them on demand and handles seeking via the nearest keyframe.
+package frameiter
+
+import (
+	"fmt"
+	"image"
+	"io"
+
+	"github.com/user/loadshow/pkg/ports"
+)
+
+// Sample is one compressed video frame in decode order.
+type Sample struct {
+	Data        []byte
+	TimestampMs int
+	Duration    int
+	IsKeyframe  bool
+}
+
+// Decoder decodes compressed samples of one codec.
+type Decoder interface {
+	Init() error
+	DecodeFrame(data []byte) (image.Image, error)
+	Close()
+}
+
+// Iterator decodes samples lazily. Only the compressed samples and the
+// decoder state are held in memory.
+type Iterator struct {
+	samples []Sample
+	decoder Decoder
+	ready   bool // decoder initialized
+
+	pos      int               // next sample to decode
+	target   int               // samples before target are decoded only as references
+	buffered *ports.VideoFrame // frame decoded ahead of a returned seek result
+}
+
+// New creates an iterator over samples using the given decoder.
+// The iterator owns the decoder and closes it on Close.
+func New(samples []Sample, decoder Decoder) *Iterator {
+	return &Iterator{
+		samples: samples,
+		decoder: decoder,
+	}
+}
+
+// Next decodes and returns the next frame. Samples that cannot be decoded
+// (e.g. missing reference frames) are skipped.
+func (it *Iterator) Next() (ports.VideoFrame, error) {
+	if it.buffered != nil {
+		frame := *it.buffered
+		it.buffered = nil
+		return frame, nil
+	}
+
+	// pending is the latest frame before the seek target; it is returned
+	// if the target sample itself cannot be decoded.
+	var pending *ports.VideoFrame
+	for it.pos < len(it.samples) {
+		i := it.pos
+		it.pos++
+
+		frame, ok, err := it.decode(it.samples[i])
+		if err != nil {
+			return ports.VideoFrame{}, err
+		}
+		if !ok {
+			continue
+		}
+		if i < it.target {
+			pending = &frame
+			continue
+		}
+		if i > it.target && pending != nil {
+			it.buffered = &frame
+			return *pending, nil
+		}
+		return frame, nil
+	}
+
+	if pending != nil {
+		return *pending, nil
+	}
+	return ports.VideoFrame{}, io.EOF
+}
+
+// Seek positions the iterator at the frame shown at timestampMs. Decoding
+// restarts from the keyframe at or before it unless the iterator is already
+// between that keyframe and the target.
+func (it *Iterator) Seek(timestampMs int) error {
+	target := 0
+	for i, s := range it.samples {
+		if s.TimestampMs > timestampMs {
+			break
+		}
+		target = i
+	}
+	key := target
+	for key > 0 && !it.samples[key].IsKeyframe {
+		key--
+	}
+
+	if !it.ready || it.buffered != nil || it.pos <= key || it.pos > target {
+		it.reset()
+		it.pos = key
+	}
+	it.buffered = nil
+	it.target = target
+	return nil
+}
+
+// DurationMs returns the end time of the last sample.
+func (it *Iterator) DurationMs() int {
+	if len(it.samples) == 0 {
+		return 0
+	}
+	last := it.samples[len(it.samples)-1]
+	return last.TimestampMs + last.Duration
+}
+
+// Close releases the decoder.
+func (it *Iterator) Close() {
+	it.reset()
+	it.samples = nil
+}
+
+func (it *Iterator) decode(s Sample) (ports.VideoFrame, bool, error) {
+	if !it.ready {
+		if err := it.decoder.Init(); err != nil {
+			return ports.VideoFrame{}, false, fmt.Errorf("init decoder: %w", err)
+		}
+		it.ready = true
+	}
+
+	img, err := it.decoder.DecodeFrame(s.Data)
+	if err != nil || img == nil {
+		return ports.VideoFrame{}, false, nil
+	}
+	return ports.VideoFrame{
+		Image:       img,
+		TimestampMs: s.TimestampMs,
+		Duration:    s.Duration,
+	}, true, nil
+}
+
+func (it *Iterator) reset() {
+	if it.ready {
+		it.decoder.Close()
+		it.ready = false
+	}
+}
+
+// Ensure Iterator implements ports.FrameIterator
+var _ ports.FrameIterator = (*Iterator)(nil)
@@ -0,0 +1,176 @@
+package frameiter
+
+import (
+	"errors"
+	"image"
+	"io"
+	"testing"
+)
+
+// refDecoder decodes a sample only if it is a keyframe or directly follows
+// the previously decoded sample, like an inter-frame codec. Data[0] is the
+// sample index; Data[1] == 1 marks samples that fail to decode.
+type refDecoder struct {
+	inits   int
+	decoded []int
+	last    int
+}
+
+func (d *refDecoder) Init() error {
+	d.inits++
+	d.last = -2
+	return nil
+}
+
+func (d *refDecoder) DecodeFrame(data []byte) (image.Image, error) {
+	idx := int(data[0])
+	key := data[2] == 1
+	if data[1] == 1 || (!key && idx != d.last+1) {
+		return nil, errors.New("missing reference")
+	}
+	d.last = idx
+	d.decoded = append(d.decoded, idx)
+	return image.NewGray(image.Rect(0, 0, 1, 1)), nil
+}
+
+func (d *refDecoder) Close() {}
+
+// testSamples returns samples every 100ms with keyframes at 0 and 400ms.
+func testSamples(broken ...int) []Sample {
+	var samples []Sample
+	for i := 0; i < 8; i++ {
+		key := i%4 == 0
+		data := []byte{byte(i), 0, 0}
+		if key {
+			data[2] = 1
+		}
+		for _, b := range broken {
+			if b == i {
+				data[1] = 1
+			}
+		}
+		samples = append(samples, Sample{Data: data, TimestampMs: i * 100, Duration: 100, IsKeyframe: key})
+	}
+	return samples
+}
+
+func timestamps(t *testing.T, it *Iterator, n int) []int {
+	t.Helper()
+	var got []int
+	for i := 0; i < n; i++ {
+		f, err := it.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next failed: %v", err)
+		}
+		got = append(got, f.TimestampMs)
+	}
+	return got
+}
+
+func equal(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestIterator_Next(t *testing.T) {
+	dec := &refDecoder{}
+	it := New(testSamples(), dec)
+	defer it.Close()
+
+	got := timestamps(t, it, 100)
+	want := []int{0, 100, 200, 300, 400, 500, 600, 700}
+	if !equal(got, want) {
+		t.Errorf("timestamps = %v, want %v", got, want)
+	}
+	if _, err := it.Next(); err != io.EOF {
+		t.Errorf("expected io.EOF after last frame, got %v", err)
+	}
+	if it.DurationMs() != 800 {
+		t.Errorf("DurationMs = %d, want 800", it.DurationMs())
+	}
+	if dec.inits != 1 {
+		t.Errorf("decoder initialized %d times, want 1", dec.inits)
+	}
+}
+
+func TestIterator_Seek(t *testing.T) {
+	tests := []struct {
+		name   string
+		seekMs int
+		want   []int
+	}{
+		{"start", 0, []int{0, 100}},
+		{"between frames", 250, []int{200, 300}},
+		{"second keyframe", 400, []int{400, 500}},
+		{"after keyframe", 650, []int{600, 700}},
+		{"before first", -50, []int{0, 100}},
+		{"past end", 5000, []int{700}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dec := &refDecoder{}
+			it := New(testSamples(), dec)
+			defer it.Close()
+
+			timestamps(t, it, 3) // decode past the first keyframe
+			if err := it.Seek(tt.seekMs); err != nil {
+				t.Fatalf("Seek failed: %v", err)
+			}
+			if got := timestamps(t, it, 2); !equal(got, tt.want) {
+				t.Errorf("after Seek(%d) got %v, want %v", tt.seekMs, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIterator_SeekForwardWithinGOP(t *testing.T) {
+	dec := &refDecoder{}
+	it := New(testSamples(), dec)
+	defer it.Close()
+
+	timestamps(t, it, 1)
+	it.Seek(300)
+	if got := timestamps(t, it, 1); !equal(got, []int{300}) {
+		t.Fatalf("got %v, want [300]", got)
+	}
+	// Seeking forward in the same GOP continues decoding without a restart
+	if dec.inits != 1 {
+		t.Errorf("decoder initialized %d times, want 1", dec.inits)
+	}
+	if !equal(dec.decoded, []int{0, 1, 2, 3}) {
+		t.Errorf("decoded samples = %v", dec.decoded)
+	}
+}
+
+func TestIterator_SeekBrokenTarget(t *testing.T) {
+	it := New(testSamples(5), &refDecoder{})
+	defer it.Close()
+
+	// Sample 5 fails, so sample 4 is still shown at 550ms.
+	// Samples 6 and 7 lose their reference and are skipped too.
+	it.Seek(550)
+	if got := timestamps(t, it, 10); !equal(got, []int{400}) {
+		t.Errorf("got %v, want [400]", got)
+	}
+}
+
+func TestIterator_Empty(t *testing.T) {
+	it := New(nil, &refDecoder{})
+	if _, err := it.Next(); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+	if it.DurationMs() != 0 {
+		t.Errorf("DurationMs = %d, want 0", it.DurationMs())
+	}
+}
@@ -0,0 +1,80 @@
+package frameiter
+
+import (
+	"fmt"
+	"image"
+	"io"
+
+	"github.com/user/loadshow/pkg/ports"
+)
+
+// Player shows a video at increasing timestamps, holding each frame until
+// the next one is due. Only the frame on screen and the one after it are
+// held in memory.
+type Player struct {
+	frames  ports.FrameIterator
+	current ports.VideoFrame  // Frame shown at the last requested time
+	next    *ports.VideoFrame // Following frame (nil = end of video)
+	size    image.Point       // Frame dimensions (from the first frame)
+}
+
+// NewPlayer reads the first frames of frames from its current position.
+// The player owns the iterator and closes it on Close.
+func NewPlayer(frames ports.FrameIterator) (*Player, error) {
+	first, err := frames.Next()
+	if err == io.EOF {
+		return nil, fmt.Errorf("no frames")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Player{
+		frames:  frames,
+		current: first,
+		size:    first.Image.Bounds().Size(),
+	}
+	return p, p.readNext()
+}
+
+// FrameAt returns the frame at or before the given timestamp, or the first
+// frame if the timestamp is before it. Timestamps must not decrease between
+// calls; past the last frame, the last frame is held.
+func (p *Player) FrameAt(timestampMs int) (ports.VideoFrame, error) {
+	for p.next != nil && p.next.TimestampMs <= timestampMs {
+		p.current = *p.next
+		if err := p.readNext(); err != nil {
+			return ports.VideoFrame{}, err
+		}
+	}
+	return p.current, nil
+}
+
+// Size returns the dimensions of the first frame.
+func (p *Player) Size() image.Point {
+	return p.size
+}
+
+// DurationMs returns the end time of the last frame.
+func (p *Player) DurationMs() int {
+	return p.frames.DurationMs()
+}
+
+// Close releases the frame iterator.
+func (p *Player) Close() {
+	p.frames.Close()
+}
+
+// readNext decodes the frame after the current one.
+func (p *Player) readNext() error {
+	frame, err := p.frames.Next()
+	if err == io.EOF {
+		p.next = nil
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	p.next = &frame
+	return nil
+}
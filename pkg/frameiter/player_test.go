@@ -0,0 +1,48 @@
+package frameiter
+
+import (
+	"image"
+	"testing"
+
+	"github.com/user/loadshow/pkg/mocks"
+	"github.com/user/loadshow/pkg/ports"
+)
+
+func TestPlayer_FrameAt(t *testing.T) {
+	var frames []ports.VideoFrame
+	for _, ts := range []int{0, 100, 250} {
+		frames = append(frames, ports.VideoFrame{Image: image.NewGray(image.Rect(0, 0, 4, 2)), TimestampMs: ts, Duration: 100})
+	}
+	it := mocks.NewFrameIterator(frames)
+	it.Frames[0].TimestampMs = 50
+
+	p, err := NewPlayer(it)
+	if err != nil {
+		t.Fatalf("NewPlayer failed: %v", err)
+	}
+	if p.Size() != image.Pt(4, 2) || p.DurationMs() != 350 {
+		t.Errorf("size = %v, duration = %d", p.Size(), p.DurationMs())
+	}
+
+	// Before the first frame, at, between and past the frames
+	for _, tt := range []struct{ at, want int }{{0, 50}, {100, 100}, {249, 100}, {250, 250}, {1000, 250}} {
+		frame, err := p.FrameAt(tt.at)
+		if err != nil {
+			t.Fatalf("FrameAt(%d) failed: %v", tt.at, err)
+		}
+		if frame.TimestampMs != tt.want {
+			t.Errorf("FrameAt(%d) = frame at %dms, want %dms", tt.at, frame.TimestampMs, tt.want)
+		}
+	}
+
+	p.Close()
+	if !it.Closed {
+		t.Error("expected Close to close the iterator")
+	}
+}
+
+func TestPlayer_NoFrames(t *testing.T) {
+	if _, err := NewPlayer(mocks.NewFrameIterator(nil)); err == nil {
+		t.Error("expected an error for an empty video")
+	}
+}
@@ -0,0 +1,172 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/user/loadshow/pkg/summarizer"
+)
+
+// WriteRunsText writes the runs of each series as a table, oldest first.
+func WriteRunsText(w io.Writer, series []Series) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for i, s := range series {
+		if i > 0 {
+			fmt.Fprintln(tw)
+		}
+		fmt.Fprintf(tw, "%s\n", seriesTitle(s))
+
+		metrics := metricNames(s.Entries)
+		fmt.Fprintf(tw, "Time\t%s\tVideo\n", strings.Join(metrics, "\t"))
+		for _, e := range s.Entries {
+			fmt.Fprint(tw, e.Time.Local().Format(time.DateTime))
+			for _, m := range metrics {
+				v, ok := e.Value(m)
+				fmt.Fprintf(tw, "\t%s", formatValue(Unit(m), v, ok))
+			}
+			fmt.Fprintf(tw, "\t%s\n", e.Video)
+		}
+	}
+	return tw.Flush()
+}
+
+// WriteTrendsText writes a sparkline table of each series over its last n runs.
+func WriteTrendsText(w io.Writer, series []Series, n int) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for i, s := range series {
+		if i > 0 {
+			fmt.Fprintln(tw)
+		}
+		fmt.Fprintf(tw, "%s: last %d of %d runs\n", seriesTitle(s), len(s.Last(n)), len(s.Entries))
+		fmt.Fprintln(tw, "Metric\tTrend\tMedian\tMin\tMax\tLatest")
+		for _, t := range s.Trends(n) {
+			last := len(t.Values) - 1
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				t.Metric, t.Sparkline(),
+				formatValue(t.Unit, t.Median, true),
+				formatValue(t.Unit, t.Min, true),
+				formatValue(t.Unit, t.Max, true),
+				formatValue(t.Unit, t.Values[last], t.Recorded[last]))
+		}
+	}
+	return tw.Flush()
+}
+
+// WriteChecksText writes the latest run of each series against its
+// rolling baseline of window runs.
+func WriteChecksText(w io.Writer, series []Series, window int, th summarizer.Thresholds) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for i, s := range series {
+		if i > 0 {
+			fmt.Fprintln(tw)
+		}
+		checks := s.Check(window, th)
+		if len(checks) == 0 {
+			fmt.Fprintf(tw, "%s: no previous runs to compare with\n", seriesTitle(s))
+			continue
+		}
+		fmt.Fprintf(tw, "%s: run of %s against the median of previous runs\n",
+			seriesTitle(s), s.Latest().Time.Local().Format(time.DateTime))
+		fmt.Fprintln(tw, "Metric\tBaseline\tRuns\tLatest\tChange\tStatus")
+		for _, c := range checks {
+			change := "-"
+			if pct, ok := c.Percent(); ok && pct == 0 {
+				change = "0.0%"
+			} else if ok {
+				change = fmt.Sprintf("%+.1f%%", pct)
+			}
+			status := "ok"
+			if c.Regressed {
+				status = "REGRESSION"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%s\n",
+				c.Metric, formatValue(c.Unit, c.Baseline, true), c.BaselineRuns,
+				formatValue(c.Unit, c.Latest, c.Recorded), change, status)
+		}
+	}
+	return tw.Flush()
+}
+
+type jsonSeries struct {
+	URL         string   `json:"url"`
+	Profile     string   `json:"profile"`
+	Runs        int      `json:"runs"`
+	Entries     []Entry  `json:"entries,omitempty"`
+	Trends      []Trend  `json:"trends,omitempty"`
+	Checks      []Check  `json:"checks,omitempty"`
+	Regressions *int     `json:"regressions,omitempty"`
+	LatestRun   *jsonRun `json:"latestRun,omitempty"`
+}
+
+type jsonRun struct {
+	Time  time.Time `json:"time"`
+	Video string    `json:"video,omitempty"`
+}
+
+// WriteRunsJSON writes the runs of each series as JSON.
+func WriteRunsJSON(w io.Writer, series []Series) error {
+	doc := []jsonSeries{}
+	for _, s := range series {
+		doc = append(doc, jsonSeries{URL: s.URL, Profile: s.Profile, Runs: len(s.Entries), Entries: s.Entries})
+	}
+	return writeJSON(w, doc)
+}
+
+// WriteTrendsJSON writes the trends of each series over its last n runs as JSON.
+func WriteTrendsJSON(w io.Writer, series []Series, n int) error {
+	doc := []jsonSeries{}
+	for _, s := range series {
+		doc = append(doc, jsonSeries{URL: s.URL, Profile: s.Profile, Runs: len(s.Entries), Trends: s.Trends(n)})
+	}
+	return writeJSON(w, doc)
+}
+
+// WriteChecksJSON writes the regression checks of each series as JSON.
+func WriteChecksJSON(w io.Writer, series []Series, window int, th summarizer.Thresholds) error {
+	doc := []jsonSeries{}
+	for _, s := range series {
+		checks := s.Check(window, th)
+		n := len(Regressions(checks))
+		latest := s.Latest()
+		doc = append(doc, jsonSeries{
+			URL:         s.URL,
+			Profile:     s.Profile,
+			Runs:        len(s.Entries),
+			Checks:      checks,
+			Regressions: &n,
+			LatestRun:   &jsonRun{Time: latest.Time, Video: latest.Video},
+		})
+	}
+	return writeJSON(w, doc)
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func seriesTitle(s Series) string {
+	if s.Profile == "" {
+		return s.URL
+	}
+	return fmt.Sprintf("%s (%s)", s.URL, s.Profile)
+}
+
+func formatValue(unit string, v float64, recorded bool) string {
+	if !recorded {
+		return "-"
+	}
+	switch unit {
+	case "ms":
+		return fmt.Sprintf("%.0fms", v)
+	case "bytes":
+		return fmt.Sprintf("%.0f", v)
+	default:
+		return fmt.Sprintf("%.4g", v)
+	}
+}
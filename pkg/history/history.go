@@ -0,0 +1,125 @@
+// Package history keeps recording results in a local JSON-lines file and
+// reports trends and regressions over them.
+package history
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/user/loadshow/pkg/orchestrator"
+	"github.com/user/loadshow/pkg/summarizer"
+)
+
+// Entry is one recorded run. Runs of the same URL and profile form a Series.
+type Entry struct {
+	Time    time.Time          `json:"time"`
+	URL     string             `json:"url"`
+	Profile string             `json:"profile"` // e.g. the device preset
+	Title   string             `json:"title,omitempty"`
+	Video   string             `json:"video,omitempty"`
+	Metrics map[string]float64 `json:"metrics"` // Metrics that were not recorded are omitted
+}
+
+// Metric names, as in budgets files. User-timing marks are "mark:<name>".
+const (
+	MetricDOMContentLoaded = "dom_content_loaded"
+	MetricLoad             = "load"
+	MetricLCP              = "lcp"
+	MetricCLS              = "cls"
+	MetricTotalBytes       = "total_bytes"
+	MetricRequests         = "requests"
+
+	markPrefix = "mark:"
+)
+
+// metricOrder is the display order of the fixed metrics; marks follow.
+var metricOrder = []string{
+	MetricDOMContentLoaded,
+	MetricLoad,
+	MetricLCP,
+	MetricCLS,
+	MetricTotalBytes,
+	MetricRequests,
+}
+
+// Unit returns the unit of a metric: "ms", "bytes", "score" for CLS or ""
+// for counts.
+func Unit(metric string) string {
+	switch metric {
+	case MetricTotalBytes:
+		return string(summarizer.UnitBytes)
+	case MetricCLS:
+		return string(summarizer.UnitScore)
+	case MetricRequests:
+		return string(summarizer.UnitCount)
+	default:
+		return string(summarizer.UnitMs)
+	}
+}
+
+// NewEntry creates an entry from a recording of url. Marks selected with
+// Config.TimingMarks are stored; unrecorded metrics are left out.
+func NewEntry(url, profile string, at time.Time, result orchestrator.RunResult) Entry {
+	e := Entry{
+		Time:    at,
+		URL:     url,
+		Profile: profile,
+		Title:   result.PageTitle,
+		Metrics: map[string]float64{
+			MetricCLS:        result.CumulativeLayoutShift,
+			MetricTotalBytes: float64(result.TotalBytes),
+		},
+	}
+
+	if result.DOMContentLoadedRecorded() {
+		e.Metrics[MetricDOMContentLoaded] = float64(result.DOMContentLoadedMs)
+	}
+	if result.LoadRecorded() {
+		e.Metrics[MetricLoad] = float64(result.LoadCompleteMs)
+	}
+	if result.RequestCount > 0 {
+		e.Metrics[MetricRequests] = float64(result.RequestCount)
+	}
+	if result.LargestContentfulPaintMs > 0 {
+		e.Metrics[MetricLCP] = float64(result.LargestContentfulPaintMs)
+	}
+	for _, m := range result.TimingMarks {
+		if m.Recorded {
+			e.Metrics[markPrefix+m.Name] = float64(m.TimeMs)
+		}
+	}
+	return e
+}
+
+// Value returns a metric of the run and whether it was recorded.
+func (e Entry) Value(metric string) (float64, bool) {
+	v, ok := e.Metrics[metric]
+	return v, ok
+}
+
+// metricNames returns the metrics recorded in any of entries, fixed
+// metrics first and marks sorted by name.
+func metricNames(entries []Entry) []string {
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		for name := range e.Metrics {
+			seen[name] = true
+		}
+	}
+
+	var names, marks []string
+	for _, name := range metricOrder {
+		if seen[name] {
+			names = append(names, name)
+		}
+		delete(seen, name)
+	}
+	for name := range seen {
+		if strings.HasPrefix(name, markPrefix) {
+			marks = append(marks, name)
+		}
+	}
+	sort.Strings(marks)
+	return append(names, marks...)
+}
@@ -0,0 +1,234 @@
+package history
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/loadshow/pkg/orchestrator"
+	"github.com/user/loadshow/pkg/summarizer"
+)
+
+var t0 = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func run(i int, loadMs, totalBytes float64) Entry {
+	return Entry{
+		Time:    t0.Add(time.Duration(i) * time.Hour),
+		URL:     "https://example.com/",
+		Profile: "mobile",
+		Metrics: map[string]float64{MetricLoad: loadMs, MetricTotalBytes: totalBytes, "mark:hero": 500},
+	}
+}
+
+func TestNewEntry(t *testing.T) {
+	e := NewEntry("https://example.com/", "mobile", t0, orchestrator.RunResult{
+		DOMContentLoadedMs:    2000,
+		CumulativeLayoutShift: 0.05,
+		TotalBytes:            4096,
+		RequestCount:          3,
+		TimingMarks: []orchestrator.TimingMarkResult{
+			{Name: "hero", TimeMs: 900, Recorded: true},
+			{Name: "ads"},
+		},
+		TimedOut:   true,
+		TimeoutSec: 1,
+	})
+
+	want := map[string]float64{
+		MetricCLS:        0.05,
+		MetricTotalBytes: 4096,
+		MetricRequests:   3,
+		"mark:hero":      900,
+	}
+	if len(e.Metrics) != len(want) {
+		t.Errorf("metrics = %v, want %v", e.Metrics, want)
+	}
+	for name, v := range want {
+		if got, ok := e.Value(name); !ok || got != v {
+			t.Errorf("%s = %v (%v), want %v", name, got, ok, v)
+		}
+	}
+}
+
+func TestNewEntry_RequestsNotRecorded(t *testing.T) {
+	// Resource timings alone are not a request count
+	e := NewEntry("https://example.com/", "mobile", t0, orchestrator.RunResult{
+		Requests: make([]orchestrator.RequestResult, 3),
+	})
+	if _, ok := e.Value(MetricRequests); ok {
+		t.Errorf("expected requests not to be recorded, got %v", e.Metrics)
+	}
+}
+
+func TestAppendLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runs", "history.jsonl")
+
+	if err := Append(path, run(0, 1000, 100)); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	if err := Append(path, run(1, 1100, 100), run(2, 1200, 100)); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	entries, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(entries) != 3 || entries[2].Metrics[MetricLoad] != 1200 || !entries[0].Time.Equal(t0) {
+		t.Errorf("entries = %+v", entries)
+	}
+
+	if err := os.WriteFile(path, []byte("{}\n\nnot json\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), ":3:") {
+		t.Errorf("error = %v, want line 3", err)
+	}
+}
+
+func TestGroup(t *testing.T) {
+	other := run(0, 500, 100)
+	other.Profile = "desktop"
+	entries := []Entry{run(2, 1200, 100), other, run(1, 1100, 100)}
+
+	series := Group(entries, "", "")
+	if len(series) != 2 || series[0].Profile != "mobile" || series[1].Profile != "desktop" {
+		t.Fatalf("series = %+v", series)
+	}
+	if series[0].Latest().Metrics[MetricLoad] != 1200 || series[0].Entries[0].Metrics[MetricLoad] != 1100 {
+		t.Error("expected entries in time order")
+	}
+	if got := Group(entries, "https://example.com/", "desktop"); len(got) != 1 || len(got[0].Entries) != 1 {
+		t.Errorf("filtered = %+v", got)
+	}
+	if got := Group(entries, "https://example.org/", ""); len(got) != 0 {
+		t.Errorf("expected no series, got %+v", got)
+	}
+}
+
+func TestTrends(t *testing.T) {
+	s := Series{Entries: []Entry{run(0, 9000, 100), run(1, 1000, 100), run(2, 3000, 100), run(3, 2000, 100)}}
+	delete(s.Entries[2].Metrics, MetricLoad)
+
+	trends := s.Trends(3)
+	if len(trends) != 3 || trends[0].Metric != MetricLoad || trends[1].Metric != MetricTotalBytes || trends[2].Metric != "mark:hero" {
+		t.Fatalf("trends = %+v", trends)
+	}
+	load := trends[0]
+	if load.Median != 1500 || load.Min != 1000 || load.Max != 2000 {
+		t.Errorf("load = %+v", load)
+	}
+	if got := load.Sparkline(); got != "▁ █" {
+		t.Errorf("sparkline = %q", got)
+	}
+	if got := trends[1].Sparkline(); got != "▁▁▁" {
+		t.Errorf("flat sparkline = %q", got)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	th := summarizer.DefaultThresholds()
+
+	tests := []struct {
+		name   string
+		latest Entry
+		want   map[string]bool
+	}{
+		{"stable", run(9, 1050, 100), map[string]bool{MetricLoad: false, MetricTotalBytes: false, "mark:hero": false}},
+		{"slower", run(9, 1500, 100), map[string]bool{MetricLoad: true, MetricTotalBytes: false, "mark:hero": false}},
+		{"small absolute change", run(9, 1090, 100), map[string]bool{MetricLoad: false, MetricTotalBytes: false, "mark:hero": false}},
+		{"heavier", run(9, 1000, 100+20*1024), map[string]bool{MetricLoad: false, MetricTotalBytes: true, "mark:hero": false}},
+		{"mark lost", Entry{Time: t0.Add(9 * time.Hour), Metrics: map[string]float64{MetricLoad: 900, MetricTotalBytes: 100}},
+			map[string]bool{MetricLoad: false, MetricTotalBytes: false, "mark:hero": true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The 5000 ms run falls outside the window of 3
+			s := Series{Entries: []Entry{run(0, 5000, 100), run(1, 900, 100), run(2, 1000, 100), run(3, 1100, 100), tt.latest}}
+			checks := s.Check(3, th)
+			if len(checks) != len(tt.want) {
+				t.Fatalf("checks = %+v", checks)
+			}
+			for _, c := range checks {
+				if c.Regressed != tt.want[c.Metric] {
+					t.Errorf("%s: regressed = %v, want %v (%+v)", c.Metric, c.Regressed, tt.want[c.Metric], c)
+				}
+				if c.Metric == MetricLoad && (c.Baseline != 1000 || c.BaselineRuns != 3) {
+					t.Errorf("baseline = %v over %d runs, want 1000 over 3", c.Baseline, c.BaselineRuns)
+				}
+			}
+		})
+	}
+
+	if checks := (Series{Entries: []Entry{run(0, 1000, 100)}}).Check(5, th); checks != nil {
+		t.Errorf("expected no checks for a single run, got %+v", checks)
+	}
+}
+
+func TestCheck_UnitlessMetrics(t *testing.T) {
+	entry := func(i int, cls, requests float64) Entry {
+		return Entry{Time: t0.Add(time.Duration(i) * time.Hour), Metrics: map[string]float64{MetricCLS: cls, MetricRequests: requests}}
+	}
+
+	tests := []struct {
+		name     string
+		cls      float64
+		requests float64
+		want     map[string]bool
+	}{
+		{"stable", 0.0001, 41, map[string]bool{MetricCLS: false, MetricRequests: false}},
+		{"layout shift", 0.05, 40, map[string]bool{MetricCLS: true, MetricRequests: false}},
+		{"more requests", 0, 50, map[string]bool{MetricCLS: false, MetricRequests: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A CLS baseline of 0 has no percentage; only the absolute threshold applies
+			s := Series{Entries: []Entry{entry(0, 0, 40), entry(1, 0, 40), entry(2, tt.cls, tt.requests)}}
+			for _, c := range s.Check(5, summarizer.DefaultThresholds()) {
+				if c.Regressed != tt.want[c.Metric] {
+					t.Errorf("%s: regressed = %v, want %v (%+v)", c.Metric, c.Regressed, tt.want[c.Metric], c)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	series := Group([]Entry{run(0, 1000, 100), run(1, 1000, 100), run(2, 2000, 100)}, "", "")
+	th := summarizer.DefaultThresholds()
+
+	var buf bytes.Buffer
+	if err := WriteRunsText(&buf, series); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "https://example.com/ (mobile)") || !strings.Contains(buf.String(), "2000ms") {
+		t.Errorf("runs:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteTrendsText(&buf, series, 10); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "last 3 of 3 runs") || !strings.Contains(buf.String(), "▁▁█") {
+		t.Errorf("trends:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteChecksText(&buf, series, 5, th); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "+100.0%") || !strings.Contains(buf.String(), "REGRESSION") {
+		t.Errorf("checks:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteChecksJSON(&buf, series, 5, th); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"regressions": 1`) {
+		t.Errorf("checks JSON:\n%s", buf.String())
+	}
+}
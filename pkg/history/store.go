@@ -0,0 +1,118 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Append adds entries to the history file at path, one JSON object per
+// line. The file and its directory are created if needed.
+func Append(path string, entries ...Entry) error {
+	if dir := filepath.Dir(path); dir != "" && dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("create directory: %w", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	for _, e := range entries {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("encode history entry: %w", err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open history: %w", err)
+	}
+	// A single write keeps concurrent runs from interleaving lines
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return fmt.Errorf("write history: %w", err)
+	}
+	return f.Close()
+}
+
+// Load reads every entry of the history file at path. Blank lines are
+// skipped.
+func Load(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("read history: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read history: %w", err)
+	}
+	return entries, nil
+}
+
+// Series is the runs of one URL and profile in time order.
+type Series struct {
+	URL     string
+	Profile string
+	Entries []Entry
+}
+
+// Latest returns the most recent run.
+func (s Series) Latest() Entry {
+	return s.Entries[len(s.Entries)-1]
+}
+
+// Last returns the most recent n runs (all runs if n <= 0).
+func (s Series) Last(n int) []Entry {
+	if n <= 0 || n >= len(s.Entries) {
+		return s.Entries
+	}
+	return s.Entries[len(s.Entries)-n:]
+}
+
+// Group splits entries into series by URL and profile, in order of first
+// appearance. Only entries matching url and profile are kept; an empty
+// filter matches everything.
+func Group(entries []Entry, url, profile string) []Series {
+	var series []Series
+	index := make(map[[2]string]int)
+	for _, e := range entries {
+		if (url != "" && e.URL != url) || (profile != "" && e.Profile != profile) {
+			continue
+		}
+		key := [2]string{e.URL, e.Profile}
+		i, ok := index[key]
+		if !ok {
+			i = len(series)
+			index[key] = i
+			series = append(series, Series{URL: e.URL, Profile: e.Profile})
+		}
+		series[i].Entries = append(series[i].Entries, e)
+	}
+	for _, s := range series {
+		sort.SliceStable(s.Entries, func(i, j int) bool {
+			return s.Entries[i].Time.Before(s.Entries[j].Time)
+		})
+	}
+	return series
+}
@@ -0,0 +1,168 @@
+package history
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/user/loadshow/pkg/summarizer"
+)
+
+// Trend summarizes one metric over the recent runs of a series.
+type Trend struct {
+	Metric   string    `json:"metric"`
+	Unit     string    `json:"unit"`
+	Values   []float64 `json:"values"` // Oldest first; 0 where not recorded
+	Recorded []bool    `json:"recorded"`
+	Median   float64   `json:"median"` // Of the recorded values
+	Min      float64   `json:"min"`
+	Max      float64   `json:"max"`
+}
+
+// Trends returns the trend of every metric over the last n runs (all runs
+// if n <= 0). Metrics recorded in none of those runs are left out.
+func (s Series) Trends(n int) []Trend {
+	entries := s.Last(n)
+	var trends []Trend
+	for _, metric := range metricNames(entries) {
+		t := Trend{Metric: metric, Unit: Unit(metric)}
+		var recorded []float64
+		for _, e := range entries {
+			v, ok := e.Value(metric)
+			t.Values = append(t.Values, v)
+			t.Recorded = append(t.Recorded, ok)
+			if ok {
+				recorded = append(recorded, v)
+			}
+		}
+		t.Median = Median(recorded)
+		t.Min, t.Max = minMax(recorded)
+		trends = append(trends, t)
+	}
+	return trends
+}
+
+// Sparkline draws the values as block characters scaled between Min and
+// Max. Unrecorded values are shown as a space.
+func (t Trend) Sparkline() string {
+	const blocks = "▁▂▃▄▅▆▇█"
+	levels := []rune(blocks)
+
+	var sb strings.Builder
+	for i, v := range t.Values {
+		if !t.Recorded[i] {
+			sb.WriteRune(' ')
+			continue
+		}
+		level := 0
+		if t.Max > t.Min {
+			level = int(math.Round((v - t.Min) / (t.Max - t.Min) * float64(len(levels)-1)))
+		}
+		sb.WriteRune(levels[level])
+	}
+	return sb.String()
+}
+
+// Check is the latest run of a metric compared with its rolling baseline.
+type Check struct {
+	Metric       string  `json:"metric"`
+	Unit         string  `json:"unit"`
+	Baseline     float64 `json:"baseline"`     // Median of the previous runs
+	BaselineRuns int     `json:"baselineRuns"` // Previous runs that recorded the metric
+	Latest       float64 `json:"latest"`
+	Recorded     bool    `json:"recorded"` // False if the latest run did not record the metric
+	Regressed    bool    `json:"regressed"`
+}
+
+// Percent returns the change from the baseline in percent. ok is false if
+// the latest value is missing or the baseline is 0.
+func (c Check) Percent() (pct float64, ok bool) {
+	if !c.Recorded || c.Baseline == 0 {
+		return 0, false
+	}
+	return (c.Latest - c.Baseline) / c.Baseline * 100, true
+}
+
+// Check compares the latest run with the median of up to window previous
+// runs (all previous runs if window <= 0). An increase is a regression
+// when it exceeds the thresholds as in summarizer.Compare; a metric the
+// latest run did not record always is. Metrics without a previous value
+// are not checked.
+func (s Series) Check(window int, th summarizer.Thresholds) []Check {
+	if len(s.Entries) < 2 {
+		return nil
+	}
+	latest := s.Latest()
+	previous := s.Entries[:len(s.Entries)-1]
+	if window > 0 && len(previous) > window {
+		previous = previous[len(previous)-window:]
+	}
+
+	var checks []Check
+	for _, metric := range metricNames(s.Entries[len(s.Entries)-1-len(previous):]) {
+		var baseline []float64
+		for _, e := range previous {
+			if v, ok := e.Value(metric); ok {
+				baseline = append(baseline, v)
+			}
+		}
+		if len(baseline) == 0 {
+			continue
+		}
+
+		c := Check{
+			Metric:       metric,
+			Unit:         Unit(metric),
+			Baseline:     Median(baseline),
+			BaselineRuns: len(baseline),
+		}
+		c.Latest, c.Recorded = latest.Value(metric)
+		c.Regressed = regressed(c, th)
+		checks = append(checks, c)
+	}
+	return checks
+}
+
+// Regressions returns the checks that regressed.
+func Regressions(checks []Check) []Check {
+	var failed []Check
+	for _, c := range checks {
+		if c.Regressed {
+			failed = append(failed, c)
+		}
+	}
+	return failed
+}
+
+func regressed(c Check, th summarizer.Thresholds) bool {
+	if !c.Recorded {
+		return true
+	}
+	return c.Latest > c.Baseline && th.Exceeds(summarizer.Unit(c.Unit), c.Baseline, c.Latest)
+}
+
+// Median returns the median of values (0 if empty).
+func Median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+func minMax(values []float64) (lo, hi float64) {
+	for i, v := range values {
+		if i == 0 || v < lo {
+			lo = v
+		}
+		if i == 0 || v > hi {
+			hi = v
+		}
+	}
+	return lo, hi
+}
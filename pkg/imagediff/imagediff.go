@@ -0,0 +1,65 @@
+// Package imagediff compares video frames pixel by pixel.
+package imagediff
+
+import (
+	"image"
+	"image/draw"
+)
+
+// Visual change parameters shared by the filmstrip, juxtapose alignment
+// and the changed-region highlights. Compression noise and small UI
+// elements such as a progress bar are ignored.
+const (
+	// PixelThreshold is the minimum per-channel difference for a pixel to count as changed.
+	PixelThreshold = 40
+	// ChangeRatio is the fraction of changed pixels required for a visual change.
+	ChangeRatio = 0.002
+)
+
+// Changed reports whether enough pixels differ between two frames to be a
+// visual change. Frames of different sizes always differ.
+func Changed(prev, curr *image.RGBA) bool {
+	if prev.Bounds() != curr.Bounds() {
+		return true
+	}
+
+	total := curr.Bounds().Dx() * curr.Bounds().Dy()
+	limit := int(float64(total) * ChangeRatio)
+
+	count := 0
+	for i := 0; i+3 < len(curr.Pix); i += 4 {
+		if AbsDiff(prev.Pix[i], curr.Pix[i]) > PixelThreshold ||
+			AbsDiff(prev.Pix[i+1], curr.Pix[i+1]) > PixelThreshold ||
+			AbsDiff(prev.Pix[i+2], curr.Pix[i+2]) > PixelThreshold {
+			count++
+			if count > limit {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AbsDiff returns the absolute difference of two channel values.
+func AbsDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// ToRGBA converts an image to *image.RGBA with bounds starting at (0,0).
+// RGBA images already at the origin are returned as is; nil gives an
+// empty image.
+func ToRGBA(img image.Image) *image.RGBA {
+	if img == nil {
+		return image.NewRGBA(image.Rect(0, 0, 0, 0))
+	}
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Bounds().Min == (image.Point{}) {
+		return rgba
+	}
+	b := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, b.Min, draw.Src)
+	return rgba
+}
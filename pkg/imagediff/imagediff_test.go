@@ -0,0 +1,62 @@
+package imagediff
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+func solid(w, h int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+	return img
+}
+
+func TestChanged(t *testing.T) {
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	base := solid(100, 100, white)
+
+	// A few pixels, like the progress bar advancing or a spinner
+	small := solid(100, 100, white)
+	draw.Draw(small, image.Rect(0, 0, 4, 4), image.NewUniform(color.Black), image.Point{}, draw.Src)
+	if Changed(base, small) {
+		t.Error("expected a 16-pixel change to be ignored")
+	}
+
+	// Compression noise below the pixel threshold
+	if Changed(base, solid(100, 100, color.RGBA{R: 230, G: 230, B: 230, A: 255})) {
+		t.Error("expected noise to be ignored")
+	}
+
+	draw.Draw(small, image.Rect(0, 0, 100, 20), image.NewUniform(color.Black), image.Point{}, draw.Src)
+	if !Changed(base, small) {
+		t.Error("expected a large change to be detected")
+	}
+	if !Changed(base, solid(50, 100, white)) {
+		t.Error("expected a size change to be detected")
+	}
+}
+
+func TestToRGBA(t *testing.T) {
+	rgba := solid(4, 4, color.Black)
+	if ToRGBA(rgba) != rgba {
+		t.Error("expected an RGBA image at the origin to be returned as is")
+	}
+
+	sub := rgba.SubImage(image.Rect(1, 1, 3, 3))
+	got := ToRGBA(sub)
+	if got.Bounds() != image.Rect(0, 0, 2, 2) || got.RGBAAt(0, 0) != (color.RGBA{A: 255}) {
+		t.Errorf("sub-image converted to %v", got.Bounds())
+	}
+
+	gray := image.NewGray(image.Rect(0, 0, 2, 2))
+	gray.SetGray(1, 1, color.Gray{Y: 200})
+	if got := ToRGBA(gray).RGBAAt(1, 1); got != (color.RGBA{R: 200, G: 200, B: 200, A: 255}) {
+		t.Errorf("gray pixel = %v", got)
+	}
+
+	if ToRGBA(nil).Bounds().Dx() != 0 {
+		t.Error("expected an empty image for nil")
+	}
+}
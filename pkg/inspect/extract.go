@@ -0,0 +1,49 @@
+package inspect
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/user/loadshow/pkg/ports"
+)
+
+// ExtractFrames decodes the frames shown at the given timestamps. Frames
+// are returned in the order of timestampsMs.
+func ExtractFrames(decoder ports.VideoDecoder, path string, timestampsMs []int) ([]ports.VideoFrame, error) {
+	frames, err := decoder.OpenFrames(path)
+	if err != nil {
+		return nil, fmt.Errorf("open video: %w", err)
+	}
+	defer frames.Close()
+
+	// Visit timestamps in ascending order so seeks only move forward
+	order := make([]int, len(timestampsMs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return timestampsMs[order[a]] < timestampsMs[order[b]]
+	})
+
+	result := make([]ports.VideoFrame, len(timestampsMs))
+	for _, i := range order {
+		ts := timestampsMs[i]
+		if ts < 0 || ts >= frames.DurationMs() {
+			return nil, fmt.Errorf("timestamp %dms is outside the video (0-%dms)", ts, frames.DurationMs())
+		}
+		if err := frames.Seek(ts); err != nil {
+			return nil, fmt.Errorf("seek to %dms: %w", ts, err)
+		}
+		frame, err := frames.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("no frame at %dms", ts)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decode frame at %dms: %w", ts, err)
+		}
+		result[i] = frame
+	}
+	return result, nil
+}
@@ -0,0 +1,107 @@
+package inspect
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// WriteText writes a human-readable report. The per-frame table is
+// included only when frames is true.
+func WriteText(w io.Writer, r Report, frames bool) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	if r.Path != "" {
+		fmt.Fprintf(tw, "File:\t%s\n", r.Path)
+	}
+	fmt.Fprintf(tw, "Size:\t%d bytes\n", r.FileSize)
+	fmt.Fprintf(tw, "Codec:\t%s\n", r.Codec)
+	container := "progressive"
+	if r.Fragmented {
+		container = "fragmented"
+	}
+	if r.MajorBrand != "" {
+		container += " (" + r.MajorBrand + ")"
+	}
+	fmt.Fprintf(tw, "Container:\t%s\n", container)
+	fmt.Fprintf(tw, "Dimensions:\t%dx%d\n", r.Width, r.Height)
+	fmt.Fprintf(tw, "Timescale:\t%d\n", r.Timescale)
+	fmt.Fprintf(tw, "Frames:\t%d\n", r.FrameCount)
+	fmt.Fprintf(tw, "Duration:\t%dms\n", r.DurationMs)
+	fmt.Fprintf(tw, "Frame rate:\t%.2f fps\n", r.FPS)
+	fmt.Fprintf(tw, "Bitrate:\t%.1f kbps\n", r.BitrateKbps)
+	fmt.Fprintf(tw, "Keyframes:\t%s\n", joinInts(r.Keyframes))
+
+	if md := r.Metadata; md != nil {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "Metadata:")
+		if md.Title != "" {
+			fmt.Fprintf(tw, "  Title:\t%s\n", md.Title)
+		}
+		if md.URL != "" {
+			fmt.Fprintf(tw, "  URL:\t%s\n", md.URL)
+		}
+		if md.RecordedAt != nil {
+			fmt.Fprintf(tw, "  Recorded:\t%s\n", md.RecordedAt.Format(time.RFC3339))
+		}
+		if md.Generator != "" {
+			fmt.Fprintf(tw, "  Generator:\t%s\n", md.Generator)
+		}
+		for _, k := range sortedKeys(md.Settings) {
+			fmt.Fprintf(tw, "  %s:\t%s\n", k, md.Settings[k])
+		}
+		if len(md.Chapters) > 0 {
+			fmt.Fprintln(tw, "  Chapters:")
+			for _, ch := range md.Chapters {
+				fmt.Fprintf(tw, "    %dms\t%s\n", ch.StartMs, ch.Title)
+			}
+		}
+	}
+
+	if len(r.Subtitles) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "Subtitles:")
+		for _, c := range r.Subtitles {
+			fmt.Fprintf(tw, "  %dms-%dms\t%s\n", c.StartMs, c.EndMs, c.Text)
+		}
+	}
+
+	if frames && len(r.Frames) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "#\tTime\tDuration\tSize\tKey")
+		for _, f := range r.Frames {
+			key := ""
+			if f.Keyframe {
+				key = "*"
+			}
+			fmt.Fprintf(tw, "%d\t%dms\t%dms\t%d\t%s\n", f.Index, f.TimestampMs, f.DurationMs, f.Size, key)
+		}
+	}
+
+	return tw.Flush()
+}
+
+// WriteJSON writes the report as indented JSON. The per-frame list is
+// included only when frames is true.
+func WriteJSON(w io.Writer, r Report, frames bool) error {
+	if !frames {
+		r.Frames = nil
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+func joinInts(values []int) string {
+	if len(values) == 0 {
+		return "-"
+	}
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprint(v)
+	}
+	return strings.Join(parts, ", ")
+}
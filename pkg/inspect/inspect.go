@@ -0,0 +1,249 @@
+// Package inspect reports the container, frames and embedded loadshow
+// metadata of an MP4 video.
+package inspect
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/Eyevinn/mp4ff/mp4"
+	"github.com/user/loadshow/pkg/adapters/av1decoder"
+	"github.com/user/loadshow/pkg/adapters/codecdetect"
+	"github.com/user/loadshow/pkg/adapters/h264decoder"
+	"github.com/user/loadshow/pkg/adapters/mjpegdecoder"
+	"github.com/user/loadshow/pkg/mp4meta"
+	"github.com/user/loadshow/pkg/ports"
+)
+
+// Report describes an MP4 video.
+type Report struct {
+	Path        string    `json:"path,omitempty"`
+	FileSize    int       `json:"fileSize"`
+	Codec       string    `json:"codec"`
+	Fragmented  bool      `json:"fragmented"`
+	MajorBrand  string    `json:"majorBrand,omitempty"`
+	Width       int       `json:"width"`
+	Height      int       `json:"height"`
+	Timescale   uint32    `json:"timescale"`
+	FrameCount  int       `json:"frameCount"`
+	DurationMs  int       `json:"durationMs"`
+	FPS         float64   `json:"fps"`         // Average frame rate
+	BitrateKbps float64   `json:"bitrateKbps"` // Average video bitrate
+	Keyframes   []int     `json:"keyframes"`   // Indices of keyframes in Frames
+	Frames      []Frame   `json:"frames,omitempty"`
+	Metadata    *Metadata `json:"metadata,omitempty"`
+	Subtitles   []Cue     `json:"subtitles,omitempty"`
+}
+
+// Frame describes one video sample.
+type Frame struct {
+	Index       int  `json:"index"`
+	TimestampMs int  `json:"timestampMs"`
+	DurationMs  int  `json:"durationMs"`
+	Size        int  `json:"size"` // Sample size in bytes
+	Keyframe    bool `json:"keyframe"`
+}
+
+// Metadata is the recording metadata embedded by loadshow record.
+type Metadata struct {
+	Title      string            `json:"title,omitempty"`
+	URL        string            `json:"url,omitempty"`
+	RecordedAt *time.Time        `json:"recordedAt,omitempty"`
+	Generator  string            `json:"generator,omitempty"`
+	Settings   map[string]string `json:"settings,omitempty"`
+	Chapters   []Chapter         `json:"chapters,omitempty"`
+}
+
+// Chapter is a chapter marker.
+type Chapter struct {
+	StartMs int    `json:"startMs"`
+	Title   string `json:"title"`
+}
+
+// Cue is a subtitle cue.
+type Cue struct {
+	StartMs int    `json:"startMs"`
+	EndMs   int    `json:"endMs"`
+	Text    string `json:"text"`
+}
+
+// InspectFile reads and inspects an MP4 file on disk.
+func InspectFile(path string) (Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Report{}, fmt.Errorf("read file: %w", err)
+	}
+	report, err := Inspect(data)
+	report.Path = path
+	return report, err
+}
+
+// Inspect inspects MP4 data. Frames are listed for H.264, AV1 and Motion
+// JPEG videos; other codecs report the container only.
+func Inspect(data []byte) (Report, error) {
+	report := Report{
+		FileSize: len(data),
+		Codec:    string(codecdetect.CodecUnknown),
+	}
+
+	mp4File, err := mp4.DecodeFile(bytes.NewReader(data))
+	if err != nil {
+		return report, fmt.Errorf("decode mp4: %w", err)
+	}
+	report.Fragmented = mp4File.IsFragmented()
+	if mp4File.Ftyp != nil {
+		report.MajorBrand = mp4File.Ftyp.MajorBrand()
+	}
+
+	moov := mp4File.Moov
+	if mp4File.Init != nil && mp4File.Init.Moov != nil {
+		moov = mp4File.Init.Moov
+	}
+	if moov == nil {
+		return report, fmt.Errorf("no moov box found")
+	}
+	trak := videoTrack(moov)
+	if trak == nil {
+		return report, fmt.Errorf("no video track found")
+	}
+	if trak.Tkhd != nil {
+		report.Width = int(uint32(trak.Tkhd.Width) >> 16)
+		report.Height = int(uint32(trak.Tkhd.Height) >> 16)
+	}
+	if trak.Mdia.Mdhd != nil {
+		report.Timescale = trak.Mdia.Mdhd.Timescale
+	}
+
+	codec, err := codecdetect.DetectFromBytes(data)
+	if err != nil {
+		return report, err
+	}
+	report.Codec = string(codec)
+
+	frames, err := extractFrames(codec, data)
+	if err != nil {
+		return report, fmt.Errorf("read frames: %w", err)
+	}
+	report.setFrames(frames)
+
+	if md, err := mp4meta.Read(data); err == nil {
+		report.Metadata = newMetadata(md)
+	}
+	if cues, err := mp4meta.ReadSubtitles(data); err == nil {
+		for _, c := range cues {
+			report.Subtitles = append(report.Subtitles, Cue{StartMs: c.StartMs, EndMs: c.EndMs, Text: c.Text})
+		}
+	}
+
+	return report, nil
+}
+
+// setFrames fills the frame list and the statistics derived from it.
+func (r *Report) setFrames(frames []Frame) {
+	r.Frames = frames
+	r.FrameCount = len(frames)
+	r.Keyframes = []int{}
+
+	total := 0
+	for _, f := range frames {
+		total += f.Size
+		if f.Keyframe {
+			r.Keyframes = append(r.Keyframes, f.Index)
+		}
+	}
+	if len(frames) > 0 {
+		last := frames[len(frames)-1]
+		r.DurationMs = last.TimestampMs + last.DurationMs
+	}
+	if r.DurationMs > 0 {
+		r.FPS = float64(len(frames)) * 1000 / float64(r.DurationMs)
+		r.BitrateKbps = float64(total) * 8 / float64(r.DurationMs)
+	}
+}
+
+// videoTrack returns the first video track.
+func videoTrack(moov *mp4.MoovBox) *mp4.TrakBox {
+	for _, trak := range moov.Traks {
+		if trak.Mdia != nil && trak.Mdia.Hdlr != nil && trak.Mdia.Hdlr.HandlerType == "vide" {
+			return trak
+		}
+	}
+	return nil
+}
+
+// extractFrames lists the samples of the video track using the MP4 reader
+// of the codec.
+func extractFrames(codec codecdetect.Codec, data []byte) ([]Frame, error) {
+	var frames []Frame
+	add := func(timestampMs, durationMs, size int, keyframe bool) {
+		frames = append(frames, Frame{
+			Index:       len(frames),
+			TimestampMs: timestampMs,
+			DurationMs:  durationMs,
+			Size:        size,
+			Keyframe:    keyframe,
+		})
+	}
+
+	switch codec {
+	case codecdetect.CodecH264:
+		raw, err := h264decoder.ExtractFrames(data)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range raw {
+			add(f.TimestampMs, f.Duration, f.SampleSize, f.IsKeyframe)
+		}
+
+	case codecdetect.CodecAV1:
+		raw, err := av1decoder.ExtractFrames(data)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range raw {
+			add(f.TimestampMs, f.Duration, len(f.Data), f.IsKeyframe)
+		}
+
+	case codecdetect.CodecMJPEG:
+		raw, err := mjpegdecoder.ExtractFrames(data)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range raw {
+			add(f.TimestampMs, f.Duration, len(f.Data), f.IsKeyframe)
+		}
+	}
+
+	return frames, nil
+}
+
+// newMetadata converts metadata read by mp4meta.
+func newMetadata(md ports.VideoMetadata) *Metadata {
+	m := &Metadata{
+		Title:     md.Title,
+		URL:       md.URL,
+		Generator: md.Generator,
+		Settings:  md.Settings,
+	}
+	if !md.RecordedAt.IsZero() {
+		t := md.RecordedAt
+		m.RecordedAt = &t
+	}
+	for _, ch := range md.Chapters {
+		m.Chapters = append(m.Chapters, Chapter{StartMs: ch.StartMs, Title: ch.Title})
+	}
+	return m
+}
+
+// sortedKeys returns the keys of a settings map in order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
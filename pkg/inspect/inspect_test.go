@@ -0,0 +1,156 @@
+package inspect
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/loadshow/pkg/adapters/mjpegencoder"
+	"github.com/user/loadshow/pkg/mocks"
+	"github.com/user/loadshow/pkg/mp4meta"
+	"github.com/user/loadshow/pkg/ports"
+)
+
+// testVideo encodes a 3-frame Motion JPEG video with metadata and subtitles.
+func testVideo(t *testing.T) []byte {
+	t.Helper()
+	enc := mjpegencoder.New()
+	if err := enc.Begin(64, 48, 10, ports.EncoderOptions{Quality: 25}); err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	for i, ts := range []int{0, 100, 300} {
+		img := image.NewRGBA(image.Rect(0, 0, 64, 48))
+		img.Set(i, i, color.White)
+		if err := enc.EncodeFrame(img, ts); err != nil {
+			t.Fatalf("EncodeFrame failed: %v", err)
+		}
+	}
+	data, err := enc.End()
+	if err != nil {
+		t.Fatalf("End failed: %v", err)
+	}
+
+	data, err = mp4meta.Embed(data, ports.VideoMetadata{
+		Title:      "Example",
+		URL:        "https://example.com/",
+		RecordedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Settings:   map[string]string{"preset": "mobile"},
+		Chapters:   []ports.Chapter{{StartMs: 100, Title: "FCP"}},
+	})
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	data, err = mp4meta.EmbedSubtitles(data, []ports.Cue{{StartMs: 0, EndMs: 100, Text: "Loading"}})
+	if err != nil {
+		t.Fatalf("EmbedSubtitles failed: %v", err)
+	}
+	return data
+}
+
+func TestInspect(t *testing.T) {
+	r, err := Inspect(testVideo(t))
+	if err != nil {
+		t.Fatalf("Inspect failed: %v", err)
+	}
+
+	if r.Codec != "mjpeg" || r.Width != 64 || r.Height != 48 {
+		t.Errorf("codec/size = %s %dx%d, want mjpeg 64x48", r.Codec, r.Width, r.Height)
+	}
+	if r.FrameCount != 3 || len(r.Frames) != 3 {
+		t.Fatalf("frame count = %d, want 3", r.FrameCount)
+	}
+	if r.Frames[2].TimestampMs != 300 {
+		t.Errorf("frame 2 at %dms, want 300ms", r.Frames[2].TimestampMs)
+	}
+	if len(r.Keyframes) != 3 {
+		t.Errorf("keyframes = %v, want every frame", r.Keyframes)
+	}
+	for _, f := range r.Frames {
+		if f.Size == 0 {
+			t.Errorf("frame %d has no size", f.Index)
+		}
+	}
+	if r.DurationMs <= 300 || r.FPS == 0 || r.BitrateKbps == 0 {
+		t.Errorf("duration/fps/bitrate = %d/%v/%v", r.DurationMs, r.FPS, r.BitrateKbps)
+	}
+
+	if r.Metadata == nil || r.Metadata.Title != "Example" || r.Metadata.Settings["preset"] != "mobile" {
+		t.Errorf("metadata = %+v", r.Metadata)
+	}
+	if len(r.Metadata.Chapters) != 1 || r.Metadata.Chapters[0].StartMs != 100 {
+		t.Errorf("chapters = %+v", r.Metadata.Chapters)
+	}
+	if len(r.Subtitles) != 1 || r.Subtitles[0].Text != "Loading" {
+		t.Errorf("subtitles = %+v", r.Subtitles)
+	}
+}
+
+func TestInspect_Invalid(t *testing.T) {
+	if _, err := Inspect([]byte("not an mp4")); err == nil {
+		t.Error("expected error for invalid data")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	r, err := Inspect(testVideo(t))
+	if err != nil {
+		t.Fatalf("Inspect failed: %v", err)
+	}
+
+	var text bytes.Buffer
+	if err := WriteText(&text, r, true); err != nil {
+		t.Fatalf("WriteText failed: %v", err)
+	}
+	for _, want := range []string{"Codec:", "64x48", "Title:", "Example", "preset:", "FCP", "Loading", "300ms"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("text output missing %q:\n%s", want, text.String())
+		}
+	}
+
+	var js bytes.Buffer
+	if err := WriteJSON(&js, r, false); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(js.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if _, ok := decoded["frames"]; ok {
+		t.Error("frames included without the frames option")
+	}
+	if decoded["frameCount"] != float64(3) {
+		t.Errorf("frameCount = %v, want 3", decoded["frameCount"])
+	}
+}
+
+func TestExtractFrames(t *testing.T) {
+	decoder := mocks.NewVideoDecoder(map[string][]ports.VideoFrame{
+		"video.mp4": {
+			{TimestampMs: 0, Duration: 100},
+			{TimestampMs: 100, Duration: 100},
+			{TimestampMs: 200, Duration: 100},
+		},
+	})
+
+	frames, err := ExtractFrames(decoder, "video.mp4", []int{250, 0, 150})
+	if err != nil {
+		t.Fatalf("ExtractFrames failed: %v", err)
+	}
+	want := []int{200, 0, 100}
+	for i, f := range frames {
+		if f.TimestampMs != want[i] {
+			t.Errorf("frame %d at %dms, want %dms", i, f.TimestampMs, want[i])
+		}
+	}
+	if !decoder.Opened["video.mp4"].Closed {
+		t.Error("iterator not closed")
+	}
+
+	if _, err := ExtractFrames(decoder, "video.mp4", []int{300}); err == nil {
+		t.Error("expected error for timestamp past the end")
+	}
+}
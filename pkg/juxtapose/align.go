@@ -0,0 +1,90 @@
+package juxtapose
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/user/loadshow/pkg/imagediff"
+	"github.com/user/loadshow/pkg/ports"
+)
+
+// Align selects how the input timelines are lined up.
+type Align string
+
+const (
+	// AlignStart starts every video at its own t=0.
+	AlignStart Align = "start"
+	// AlignFirstChange trims the leading frames that look like the first
+	// one (typically a blank page), so every video starts at its first
+	// visual change.
+	AlignFirstChange Align = "first-change"
+	// AlignMilestone starts every video at the chapter named by
+	// Options.Milestone in its embedded metadata (Input.Metadata).
+	AlignMilestone Align = "milestone"
+	// AlignOffset starts every video at its entry of Options.Offsets.
+	AlignOffset Align = "offset"
+)
+
+// startOffset returns the time in the i-th video that is shown at output t=0.
+// AlignFirstChange decodes the leading frames of the video to find it.
+func (s *Stage) startOffset(ctx context.Context, input Input, i int, frames ports.FrameIterator) (int, error) {
+	switch s.opts.Align {
+	case AlignStart, "":
+		return 0, nil
+
+	case AlignFirstChange:
+		return firstChange(ctx, frames)
+
+	case AlignMilestone:
+		if i < len(input.Metadata) {
+			for _, ch := range input.Metadata[i].Chapters {
+				if ch.Title == s.opts.Milestone {
+					return ch.StartMs, nil
+				}
+			}
+		}
+		return 0, fmt.Errorf("no %q milestone in metadata", s.opts.Milestone)
+
+	case AlignOffset:
+		if i < len(s.opts.Offsets) {
+			return s.opts.Offsets[i], nil
+		}
+		return 0, nil
+
+	default:
+		return 0, fmt.Errorf("unknown alignment: %s", s.opts.Align)
+	}
+}
+
+// firstChange returns the timestamp of the first frame that visually differs
+// from the first frame, or 0 if the video never changes.
+func firstChange(ctx context.Context, frames ports.FrameIterator) (int, error) {
+	first, err := frames.Next()
+	if err == io.EOF {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	base := imagediff.ToRGBA(first.Image)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		default:
+		}
+
+		frame, err := frames.Next()
+		if err == io.EOF {
+			return 0, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		if imagediff.Changed(base, imagediff.ToRGBA(frame.Image)) {
+			return frame.TimestampMs, nil
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package juxtapose
+
+import (
+	"context"
+	"image/color"
+	"testing"
+
+	"github.com/user/loadshow/pkg/ports"
+)
+
+var (
+	blank = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	red   = color.RGBA{R: 255, A: 255}
+	green = color.RGBA{G: 255, A: 255}
+)
+
+// leadInVideo is blank for leadMs, then red, then green 100ms later.
+func leadInVideo(leadMs int) []ports.VideoFrame {
+	return []ports.VideoFrame{
+		{Image: createTestFrame(10, 10, blank), TimestampMs: 0, Duration: leadMs},
+		{Image: createTestFrame(10, 10, red), TimestampMs: leadMs, Duration: 100},
+		{Image: createTestFrame(10, 10, green), TimestampMs: leadMs + 100, Duration: 100},
+	}
+}
+
+func alignStage(decoder ports.VideoDecoder, encoder *mockEncoder, configure func(*Options)) *Stage {
+	opts := DefaultOptions()
+	opts.Gap = 0
+	opts.FPS = 20 // 50ms frames
+	configure(&opts)
+	return New(decoder, encoder, &mockFileSystem{}, &mockLogger{}, opts)
+}
+
+func TestExecute_AlignStart(t *testing.T) {
+	decoder := &pathDecoder{frames: map[string][]ports.VideoFrame{
+		"a.mp4": leadInVideo(200),
+		"b.mp4": leadInVideo(500),
+	}}
+	encoder := &mockEncoder{}
+	stage := alignStage(decoder, encoder, func(o *Options) {})
+
+	result, err := stage.Execute(context.Background(), Input{LeftPath: "a.mp4", RightPath: "b.mp4"})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if result.DurationMs != 700 {
+		t.Errorf("duration = %d, want 700", result.DurationMs)
+	}
+	if got := encoder.frames[4].At(5, 5); got != red {
+		t.Errorf("left at 200ms = %v, want red", got)
+	}
+	if got := encoder.frames[4].At(15, 5); got != blank {
+		t.Errorf("right at 200ms = %v, want blank", got)
+	}
+}
+
+func TestExecute_AlignFirstChange(t *testing.T) {
+	decoder := &pathDecoder{frames: map[string][]ports.VideoFrame{
+		"a.mp4": leadInVideo(200),
+		"b.mp4": leadInVideo(500),
+	}}
+	encoder := &mockEncoder{}
+	stage := alignStage(decoder, encoder, func(o *Options) { o.Align = AlignFirstChange })
+
+	result, err := stage.Execute(context.Background(), Input{LeftPath: "a.mp4", RightPath: "b.mp4"})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if len(result.StartOffsetsMs) != 2 || result.StartOffsetsMs[0] != 200 || result.StartOffsetsMs[1] != 500 {
+		t.Errorf("offsets = %v, want [200 500]", result.StartOffsetsMs)
+	}
+	// Blank lead-in is trimmed: both videos show red at 0ms and green at 100ms
+	if result.DurationMs != 200 {
+		t.Errorf("duration = %d, want 200", result.DurationMs)
+	}
+	for _, tc := range []struct {
+		frame int
+		want  color.Color
+	}{{0, red}, {1, red}, {2, green}} {
+		for _, x := range []int{5, 15} {
+			if got := encoder.frames[tc.frame].At(x, 5); got != tc.want {
+				t.Errorf("frame %d at x=%d = %v, want %v", tc.frame, x, got, tc.want)
+			}
+		}
+	}
+}
+
+func TestExecute_AlignFirstChange_Static(t *testing.T) {
+	static := []ports.VideoFrame{
+		{Image: createTestFrame(10, 10, blank), TimestampMs: 0, Duration: 100},
+		{Image: createTestFrame(10, 10, blank), TimestampMs: 100, Duration: 100},
+	}
+	decoder := &pathDecoder{frames: map[string][]ports.VideoFrame{
+		"a.mp4": static,
+		"b.mp4": leadInVideo(100),
+	}}
+	stage := alignStage(decoder, &mockEncoder{}, func(o *Options) { o.Align = AlignFirstChange })
+
+	result, err := stage.Execute(context.Background(), Input{LeftPath: "a.mp4", RightPath: "b.mp4"})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	// A video without visual changes is not trimmed
+	if result.StartOffsetsMs[0] != 0 || result.StartOffsetsMs[1] != 100 {
+		t.Errorf("offsets = %v, want [0 100]", result.StartOffsetsMs)
+	}
+}
+
+func TestExecute_AlignMilestone(t *testing.T) {
+	decoder := &pathDecoder{frames: map[string][]ports.VideoFrame{
+		"a.mp4": leadInVideo(200),
+		"b.mp4": leadInVideo(500),
+	}}
+	metadata := []ports.VideoMetadata{
+		{Chapters: []ports.Chapter{{StartMs: 0, Title: "Start"}, {StartMs: 300, Title: "DOMContentLoaded"}}},
+		{Chapters: []ports.Chapter{{StartMs: 0, Title: "Start"}, {StartMs: 600, Title: "DOMContentLoaded"}}},
+	}
+	encoder := &mockEncoder{}
+	stage := alignStage(decoder, encoder, func(o *Options) {
+		o.Align = AlignMilestone
+		o.Milestone = "DOMContentLoaded"
+	})
+
+	result, err := stage.Execute(context.Background(), Input{LeftPath: "a.mp4", RightPath: "b.mp4", Metadata: metadata})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if result.StartOffsetsMs[0] != 300 || result.StartOffsetsMs[1] != 600 {
+		t.Errorf("offsets = %v, want [300 600]", result.StartOffsetsMs)
+	}
+	// 100ms after DOMContentLoaded both videos show green
+	if got := encoder.frames[0].At(5, 5); got != green {
+		t.Errorf("left at 0ms = %v, want green", got)
+	}
+	if got := encoder.frames[0].At(15, 5); got != green {
+		t.Errorf("right at 0ms = %v, want green", got)
+	}
+	if result.DurationMs != 100 {
+		t.Errorf("duration = %d, want 100", result.DurationMs)
+	}
+
+	// Inputs without the milestone are rejected
+	metadata[1].Chapters = metadata[1].Chapters[:1]
+	if _, err := stage.Execute(context.Background(), Input{LeftPath: "a.mp4", RightPath: "b.mp4", Metadata: metadata}); err == nil {
+		t.Error("expected error for a missing milestone")
+	}
+}
+
+func TestExecute_AlignOffset(t *testing.T) {
+	decoder := &pathDecoder{frames: map[string][]ports.VideoFrame{
+		"a.mp4": leadInVideo(200),
+		"b.mp4": leadInVideo(200),
+	}}
+	encoder := &mockEncoder{}
+	stage := alignStage(decoder, encoder, func(o *Options) {
+		o.Align = AlignOffset
+		o.Offsets = []int{200, -100}
+	})
+
+	result, err := stage.Execute(context.Background(), Input{LeftPath: "a.mp4", RightPath: "b.mp4"})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	// The right video is delayed by 100ms and sets the duration
+	if result.DurationMs != 500 {
+		t.Errorf("duration = %d, want 500", result.DurationMs)
+	}
+	if got := encoder.frames[0].At(5, 5); got != red {
+		t.Errorf("left at 0ms = %v, want red", got)
+	}
+	if got := encoder.frames[6].At(15, 5); got != red {
+		t.Errorf("right at 300ms = %v, want red", got)
+	}
+	if got := encoder.frames[5].At(15, 5); got != blank {
+		t.Errorf("right at 250ms = %v, want blank", got)
+	}
+}
@@ -0,0 +1,146 @@
+package juxtapose
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"path/filepath"
+	"strings"
+
+	"github.com/user/loadshow/pkg/ports"
+)
+
+// LabelPosition selects where captions are drawn relative to each video.
+type LabelPosition string
+
+const (
+	// LabelNone draws no captions.
+	LabelNone LabelPosition = ""
+	// LabelTop draws captions above the videos.
+	LabelTop LabelPosition = "top"
+	// LabelBottom draws captions below the videos.
+	LabelBottom LabelPosition = "bottom"
+)
+
+// captionPadding is the vertical padding of the caption strip in pixels.
+const captionPadding = 4
+
+// Caption labels one input video.
+type Caption struct {
+	// Text identifies the video (empty = file name without extension).
+	Text string
+	// DOMContentLoadedMs is the video's DOMContentLoaded time (0 = unknown).
+	DOMContentLoadedMs int
+	// LoadCompleteMs is the video's load event time (0 = unknown).
+	// From this time on the caption is marked as finished.
+	LoadCompleteMs int
+}
+
+// CaptionFromMetadata returns a caption with the DOMContentLoaded and Load
+// times of a loadshow recording, read from its chapter markers.
+func CaptionFromMetadata(text string, md ports.VideoMetadata) Caption {
+	caption := Caption{Text: text}
+	for _, ch := range md.Chapters {
+		switch ch.Title {
+		case "DOMContentLoaded":
+			caption.DOMContentLoadedMs = ch.StartMs
+		case "Load":
+			caption.LoadCompleteMs = ch.StartMs
+		}
+	}
+	return caption
+}
+
+// hasTimings reports whether the caption shows a timing line.
+func (c Caption) hasTimings() bool {
+	return c.DOMContentLoadedMs > 0 || c.LoadCompleteMs > 0
+}
+
+// timingText returns the timing line, e.g. "DCL 0.85s  Load 1.42s".
+func (c Caption) timingText(finished bool) string {
+	var parts []string
+	if c.DOMContentLoadedMs > 0 {
+		parts = append(parts, fmt.Sprintf("DCL %.2fs", float64(c.DOMContentLoadedMs)/1000))
+	}
+	if c.LoadCompleteMs > 0 {
+		parts = append(parts, fmt.Sprintf("Load %.2fs", float64(c.LoadCompleteMs)/1000))
+	}
+	if finished {
+		parts = append(parts, "Finished")
+	}
+	return strings.Join(parts, "  ")
+}
+
+// caption returns the caption of the i-th video, defaulting its text to the file name.
+func (in Input) caption(i int, path string) Caption {
+	var c Caption
+	if i < len(in.Captions) {
+		c = in.Captions[i]
+	}
+	if c.Text == "" {
+		c.Text = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return c
+}
+
+// captionAt returns the caption image to show at the given time.
+func (src source) captionAt(timestampMs int) image.Image {
+	if src.finishedCaption != nil && timestampMs >= src.caption.LoadCompleteMs {
+		return src.finishedCaption
+	}
+	return src.pendingCaption
+}
+
+// renderCaptions renders the caption images of every source and returns
+// the caption strip height (0 when captions are disabled). All strips share
+// one height so that videos in a row stay aligned.
+func (s *Stage) renderCaptions(sources []source) int {
+	if s.opts.LabelPosition == LabelNone {
+		return 0
+	}
+
+	fontSize := s.opts.LabelFontSize
+	if fontSize <= 0 {
+		fontSize = DefaultOptions().LabelFontSize
+	}
+	lineHeight := int(math.Ceil(fontSize * 1.5))
+
+	lines := 1
+	for _, src := range sources {
+		if src.caption.hasTimings() {
+			lines = 2
+			break
+		}
+	}
+	height := lines*lineHeight + 2*captionPadding
+
+	for i := range sources {
+		src := &sources[i]
+		src.pendingCaption = s.renderCaption(src.caption, src.Size().X, height, lineHeight, fontSize, false)
+		if src.caption.LoadCompleteMs > 0 {
+			src.finishedCaption = s.renderCaption(src.caption, src.Size().X, height, lineHeight, fontSize, true)
+		}
+	}
+	return height
+}
+
+// renderCaption draws the label and timing line centered in a strip.
+func (s *Stage) renderCaption(c Caption, width, height, lineHeight int, fontSize float64, finished bool) image.Image {
+	canvas := s.renderer.CreateCanvas(width, height, s.opts.BorderColor)
+
+	style := ports.TextStyle{
+		FontSize: fontSize,
+		Color:    s.opts.LabelColor,
+		Align:    ports.AlignCenter,
+	}
+	y := captionPadding + lineHeight/2
+	canvas.DrawText(c.Text, width/2, y, style)
+
+	if c.hasTimings() {
+		if finished {
+			style.Color = s.opts.FinishedColor
+		}
+		canvas.DrawText(c.timingText(finished), width/2, y+lineHeight, style)
+	}
+	return canvas.ToImage()
+}
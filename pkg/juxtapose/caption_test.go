@@ -0,0 +1,147 @@
+package juxtapose
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+	"testing"
+
+	"github.com/user/loadshow/pkg/mocks"
+	"github.com/user/loadshow/pkg/ports"
+)
+
+// textCanvas records drawn text. Its image is red once a "Finished" line is drawn, blue otherwise.
+type textCanvas struct {
+	mocks.Canvas
+	width, height int
+	texts         []string
+}
+
+func (c *textCanvas) DrawText(text string, x, y int, style ports.TextStyle) {
+	c.texts = append(c.texts, text)
+}
+
+func (c *textCanvas) ToImage() image.Image {
+	fill := color.RGBA{B: 255, A: 255}
+	for _, text := range c.texts {
+		if strings.HasSuffix(text, "Finished") {
+			fill = color.RGBA{R: 255, A: 255}
+		}
+	}
+	img := image.NewRGBA(image.Rect(0, 0, c.width, c.height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(fill), image.Point{}, draw.Src)
+	return img
+}
+
+func TestExecute_Captions(t *testing.T) {
+	var canvases []*textCanvas
+	renderer := &mocks.Renderer{
+		CreateCanvasFunc: func(width, height int, bg color.Color) ports.Canvas {
+			c := &textCanvas{width: width, height: height}
+			canvases = append(canvases, c)
+			return c
+		},
+	}
+	decoder := &pathDecoder{frames: map[string][]ports.VideoFrame{
+		"dir/before.mp4": {{Image: createTestFrame(100, 100, color.White), TimestampMs: 0, Duration: 2000}},
+		"dir/after.mp4":  {{Image: createTestFrame(100, 100, color.White), TimestampMs: 0, Duration: 2000}},
+	}}
+	encoder := &mockEncoder{}
+
+	opts := DefaultOptions()
+	opts.LabelPosition = LabelTop
+	opts.Gap = 0
+	opts.Renderer = renderer
+	stage := New(decoder, encoder, &mockFileSystem{}, &mockLogger{}, opts)
+
+	_, err := stage.Execute(context.Background(), Input{
+		Paths:      []string{"dir/before.mp4", "dir/after.mp4"},
+		OutputPath: "output.mp4",
+		Captions:   []Caption{{DOMContentLoadedMs: 500, LoadCompleteMs: 1000}},
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	// Two lines of 21px (14px font) plus padding above the videos
+	captionHeight := 2*21 + 2*captionPadding
+	if encoder.width != 200 || encoder.height != 100+captionHeight {
+		t.Errorf("output = %dx%d, want 200x%d", encoder.width, encoder.height, 100+captionHeight)
+	}
+
+	var texts []string
+	for _, c := range canvases {
+		texts = append(texts, strings.Join(c.texts, " | "))
+	}
+	want := []string{
+		"before | DCL 0.50s  Load 1.00s",
+		"before | DCL 0.50s  Load 1.00s  Finished",
+		"after",
+	}
+	if strings.Join(texts, "\n") != strings.Join(want, "\n") {
+		t.Errorf("captions = %q, want %q", texts, want)
+	}
+
+	// The left caption switches to the finished state at 1000ms
+	first := encoder.frames[0].(*image.RGBA)
+	last := encoder.frames[len(encoder.frames)-1].(*image.RGBA)
+	if got := first.RGBAAt(50, 10); got.B != 255 {
+		t.Errorf("caption at 0ms = %v, want pending", got)
+	}
+	if got := last.RGBAAt(50, 10); got.R != 255 {
+		t.Errorf("caption at end = %v, want finished", got)
+	}
+	// Videos are drawn below the captions
+	if got := last.RGBAAt(50, captionHeight+10); got != (color.RGBA{R: 255, G: 255, B: 255, A: 255}) {
+		t.Errorf("video pixel = %v, want white", got)
+	}
+}
+
+func TestExecute_CaptionsBottom(t *testing.T) {
+	decoder := &pathDecoder{frames: map[string][]ports.VideoFrame{
+		"a.mp4": {{Image: createTestFrame(100, 100, color.White), Duration: 100}},
+		"b.mp4": {{Image: createTestFrame(100, 100, color.White), Duration: 100}},
+	}}
+	encoder := &mockEncoder{}
+
+	opts := DefaultOptions()
+	opts.LabelPosition = LabelBottom
+	opts.LabelFontSize = 20
+	opts.Renderer = &mocks.Renderer{}
+	stage := New(decoder, encoder, &mockFileSystem{}, &mockLogger{}, opts)
+
+	if _, err := stage.Execute(context.Background(), Input{Paths: []string{"a.mp4", "b.mp4"}}); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	// A single 30px line: no input has timings
+	if encoder.height != 100+30+2*captionPadding {
+		t.Errorf("output height = %d, want %d", encoder.height, 100+30+2*captionPadding)
+	}
+	frame := encoder.frames[0].(*image.RGBA)
+	if got := frame.RGBAAt(50, 10); got != (color.RGBA{R: 255, G: 255, B: 255, A: 255}) {
+		t.Errorf("video pixel = %v, want white at the top", got)
+	}
+}
+
+func TestCaptionFromMetadata(t *testing.T) {
+	md := ports.VideoMetadata{
+		Chapters: []ports.Chapter{
+			{StartMs: 0, Title: "Start"},
+			{StartMs: 850, Title: "DOMContentLoaded"},
+			{StartMs: 1420, Title: "Load"},
+			{StartMs: 1500, Title: "LCP"},
+		},
+	}
+
+	got := CaptionFromMetadata("before", md)
+	want := Caption{Text: "before", DOMContentLoadedMs: 850, LoadCompleteMs: 1420}
+	if got != want {
+		t.Errorf("CaptionFromMetadata = %+v, want %+v", got, want)
+	}
+	if got := CaptionFromMetadata("x", ports.VideoMetadata{}); got.hasTimings() {
+		t.Errorf("expected no timings without chapters, got %+v", got)
+	}
+}
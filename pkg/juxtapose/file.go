@@ -28,6 +28,12 @@ import (
 //	    OutputPath: "output.mp4",
 //	})
 func Combine(leftPath, rightPath, outputPath string, opts Options) error {
+	return CombineAll([]string{leftPath, rightPath}, outputPath, opts)
+}
+
+// CombineAll combines any number of videos, arranged by opts.Layout.
+// Like Combine, it uses default adapters.
+func CombineAll(paths []string, outputPath string, opts Options) error {
 	// Create default adapters
 	decoder := av1decoder.NewMP4Reader()
 	defer decoder.Close()
@@ -41,8 +47,7 @@ func Combine(leftPath, rightPath, outputPath string, opts Options) error {
 
 	// Execute
 	_, err := stage.Execute(context.Background(), Input{
-		LeftPath:   leftPath,
-		RightPath:  rightPath,
+		Paths:      paths,
 		OutputPath: outputPath,
 	})
 
@@ -1,4 +1,4 @@
-// Package juxtapose provides functionality to combine two videos side by side.
+// Package juxtapose provides functionality to combine videos side by side or in a grid.
 package juxtapose
 
 import (
@@ -7,20 +7,53 @@ import (
 	"image"
 	"image/color"
 	"image/draw"
+	"math"
 
+	"github.com/user/loadshow/pkg/adapters/ggrenderer"
+	"github.com/user/loadshow/pkg/frameiter"
 	"github.com/user/loadshow/pkg/ports"
 )
 
 // Input contains the input parameters for juxtapose operation.
 type Input struct {
+	// Paths are the file paths of the videos, in layout order.
+	// When empty, LeftPath and RightPath are used.
+	Paths []string
 	// LeftPath is the file path of the left video.
 	LeftPath string
 	// RightPath is the file path of the right video.
 	RightPath string
 	// OutputPath is the file path for the output video.
 	OutputPath string
+	// Captions are the labels of the videos, in the same order as the paths.
+	// They are drawn when Options.LabelPosition is set; missing entries
+	// are labeled with the file name.
+	Captions []Caption
+	// Metadata is the embedded metadata of each video, in the same order
+	// as the paths. It is required for AlignMilestone.
+	Metadata []ports.VideoMetadata
 }
 
+// paths returns the input video paths in layout order.
+func (in Input) paths() []string {
+	if len(in.Paths) > 0 {
+		return in.Paths
+	}
+	return []string{in.LeftPath, in.RightPath}
+}
+
+// Layout selects how the input videos are arranged.
+type Layout string
+
+const (
+	// LayoutHorizontal places the videos side by side in a single row.
+	LayoutHorizontal Layout = "horizontal"
+	// LayoutVertical stacks the videos in a single column.
+	LayoutVertical Layout = "vertical"
+	// LayoutGrid places the videos in rows of Options.Columns.
+	LayoutGrid Layout = "grid"
+)
+
 // Result contains the result of juxtapose operation.
 type Result struct {
 	// OutputPath is the path where the output was written.
@@ -29,11 +62,18 @@ type Result struct {
 	FrameCount int
 	// DurationMs is the duration of the output video in milliseconds.
 	DurationMs int
+	// StartOffsetsMs is the time in each input video shown at output t=0.
+	StartOffsetsMs []int
 }
 
 // Options configures the juxtapose operation.
 type Options struct {
-	// Gap is the horizontal gap between the two videos in pixels.
+	// Layout arranges the videos (empty = LayoutHorizontal).
+	Layout Layout
+	// Columns is the number of videos per row for LayoutGrid
+	// (0 = as square as possible, e.g. 3 columns for 5-9 videos).
+	Columns int
+	// Gap is the gap between videos in pixels, horizontally and vertically.
 	Gap int
 	// BorderColor is the color of the gap between videos.
 	BorderColor color.Color
@@ -43,32 +83,68 @@ type Options struct {
 	Quality int
 	// Bitrate is the target bitrate in kbps (0 = auto).
 	Bitrate int
+	// LabelPosition draws a caption above or below each video (empty = LabelNone).
+	LabelPosition LabelPosition
+	// LabelColor is the caption text color.
+	LabelColor color.Color
+	// LabelFontSize is the caption font size in pixels.
+	LabelFontSize float64
+	// FinishedColor is the color of the timing line once a video's load completes.
+	FinishedColor color.Color
+	// Align lines up the input timelines (empty = AlignStart).
+	Align Align
+	// Milestone is the chapter title AlignMilestone aligns at, e.g. "DOMContentLoaded".
+	Milestone string
+	// Offsets are the start times in milliseconds of each input for AlignOffset.
+	// Negative offsets delay a video; missing entries are 0.
+	Offsets []int
+	// Renderer draws the captions (nil = ggrenderer.New()).
+	Renderer ports.Renderer
 }
 
 // DefaultBorderColor is the default border color (#505050, same as progress bar background).
 var DefaultBorderColor = color.RGBA{R: 80, G: 80, B: 80, A: 255}
 
+// DefaultFinishedColor is the default color of the "Finished" marker (#4CAF50 green).
+var DefaultFinishedColor = color.RGBA{R: 76, G: 175, B: 80, A: 255}
+
 // DefaultOptions returns default options.
 func DefaultOptions() Options {
 	return Options{
-		Gap:         1,
-		BorderColor: DefaultBorderColor,
-		FPS:         30.0,
-		Quality:     30,
-		Bitrate:     0,
+		Layout:        LayoutHorizontal,
+		Gap:           1,
+		BorderColor:   DefaultBorderColor,
+		FPS:           30.0,
+		Quality:       30,
+		Bitrate:       0,
+		LabelPosition: LabelNone,
+		LabelColor:    color.White,
+		LabelFontSize: 14,
+		FinishedColor: DefaultFinishedColor,
+		Align:         AlignStart,
 	}
 }
 
+// layout returns the configured layout, defaulting to LayoutHorizontal.
+func (o Options) layout() Layout {
+	if o.Layout == "" {
+		return LayoutHorizontal
+	}
+	return o.Layout
+}
+
 // Stage implements the juxtapose operation with dependency injection.
 type Stage struct {
-	decoder ports.VideoDecoder
-	encoder ports.VideoEncoder
-	fs      ports.FileSystem
-	logger  ports.Logger
-	opts    Options
+	decoder  ports.VideoDecoder
+	encoder  ports.VideoEncoder
+	renderer ports.Renderer
+	fs       ports.FileSystem
+	logger   ports.Logger
+	opts     Options
 }
 
 // New creates a new juxtapose stage with the given dependencies.
+// Captions are drawn with opts.Renderer, or ggrenderer if it is nil.
 func New(
 	decoder ports.VideoDecoder,
 	encoder ports.VideoEncoder,
@@ -76,74 +152,76 @@ func New(
 	logger ports.Logger,
 	opts Options,
 ) *Stage {
+	renderer := opts.Renderer
+	if renderer == nil {
+		renderer = ggrenderer.New()
+	}
 	return &Stage{
-		decoder: decoder,
-		encoder: encoder,
-		fs:      fs,
-		logger:  logger.WithComponent("juxtapose"),
-		opts:    opts,
+		decoder:  decoder,
+		encoder:  encoder,
+		renderer: renderer,
+		fs:       fs,
+		logger:   logger.WithComponent("juxtapose"),
+		opts:     opts,
 	}
 }
 
-// Execute combines two videos side by side.
-// The shorter video will hold its last frame until the longer one finishes.
+// Execute combines the input videos into one, arranged by Options.Layout.
+// Shorter videos hold their last frame until the longest one finishes.
 func (s *Stage) Execute(ctx context.Context, input Input) (Result, error) {
 	result := Result{
 		OutputPath: input.OutputPath,
 	}
 
-	s.logger.Debug("Reading left video: %s", input.LeftPath)
-
-	// Read left video frames
-	leftFrames, err := s.decoder.ReadFrames(input.LeftPath)
-	if err != nil {
-		return result, fmt.Errorf("read left video: %w", err)
-	}
-
-	if len(leftFrames) == 0 {
-		return result, fmt.Errorf("left video has no frames")
+	paths := input.paths()
+	if len(paths) < 2 {
+		return result, fmt.Errorf("at least two videos are required, got %d", len(paths))
 	}
 
-	s.logger.Debug("Left video: %d frames", len(leftFrames))
-
-	s.logger.Debug("Reading right video: %s", input.RightPath)
+	// Open all videos. Frames are decoded on demand while compositing,
+	// so memory use does not grow with the video length.
+	sources := make([]source, 0, len(paths))
+	defer func() {
+		for _, src := range sources {
+			src.Close()
+		}
+	}()
+	for i, path := range paths {
+		s.logger.Debug("Reading video: %s", path)
 
-	// Read right video frames
-	rightFrames, err := s.decoder.ReadFrames(input.RightPath)
-	if err != nil {
-		return result, fmt.Errorf("read right video: %w", err)
-	}
+		frames, err := s.decoder.OpenFrames(path)
+		if err != nil {
+			return result, fmt.Errorf("read video %s: %w", path, err)
+		}
+		src, err := s.openSource(ctx, input, i, frames)
+		if err != nil {
+			frames.Close()
+			return result, fmt.Errorf("read video %s: %w", path, err)
+		}
 
-	if len(rightFrames) == 0 {
-		return result, fmt.Errorf("right video has no frames")
+		s.logger.Debug("%s: %dx%d, %dms, starts at %dms", path, src.Size().X, src.Size().Y, src.DurationMs(), src.offsetMs)
+		src.caption = input.caption(i, path)
+		sources = append(sources, src)
+		result.StartOffsetsMs = append(result.StartOffsetsMs, src.offsetMs)
 	}
 
-	s.logger.Debug("Right video: %d frames", len(rightFrames))
-
-	// Get dimensions
-	leftBounds := leftFrames[0].Image.Bounds()
-	rightBounds := rightFrames[0].Image.Bounds()
-
-	leftWidth := leftBounds.Dx()
-	leftHeight := leftBounds.Dy()
-	rightWidth := rightBounds.Dx()
-	rightHeight := rightBounds.Dy()
-
-	// Calculate output dimensions
-	outputWidth := leftWidth + s.opts.Gap + rightWidth
-	outputHeight := leftHeight
-	if rightHeight > outputHeight {
-		outputHeight = rightHeight
+	// Render captions, then calculate output dimensions and positions.
+	// Each cell holds a video and its caption strip.
+	captionHeight := s.renderCaptions(sources)
+	sizes := make([]image.Point, len(sources))
+	for i, src := range sources {
+		sizes[i] = src.Size().Add(image.Pt(0, captionHeight))
 	}
+	positions, outputWidth, outputHeight := arrange(sizes, s.opts.layout(), s.opts.Columns, s.opts.Gap)
 
-	s.logger.Debug("Output dimensions: %dx%d", outputWidth, outputHeight)
+	s.logger.Debug("Output dimensions: %dx%d (%s)", outputWidth, outputHeight, s.opts.layout())
 
 	// Determine total duration
-	leftDuration := leftFrames[len(leftFrames)-1].TimestampMs + leftFrames[len(leftFrames)-1].Duration
-	rightDuration := rightFrames[len(rightFrames)-1].TimestampMs + rightFrames[len(rightFrames)-1].Duration
-	totalDuration := leftDuration
-	if rightDuration > totalDuration {
-		totalDuration = rightDuration
+	totalDuration := 0
+	for _, src := range sources {
+		if d := src.DurationMs() - src.offsetMs; d > totalDuration {
+			totalDuration = d
+		}
 	}
 
 	result.DurationMs = totalDuration
@@ -159,6 +237,7 @@ func (s *Stage) Execute(ctx context.Context, input Input) (Result, error) {
 	// Generate frames at the output FPS
 	frameDurationMs := int(1000.0 / s.opts.FPS)
 	frameCount := 0
+	bgColor := image.NewUniform(s.opts.BorderColor)
 
 	for timestampMs := 0; timestampMs <= totalDuration; timestampMs += frameDurationMs {
 		// Check for context cancellation
@@ -168,27 +247,31 @@ func (s *Stage) Execute(ctx context.Context, input Input) (Result, error) {
 		default:
 		}
 
-		// Get left frame at this timestamp
-		leftFrame := getFrameAtTime(leftFrames, timestampMs)
-		rightFrame := getFrameAtTime(rightFrames, timestampMs)
-
-		// Composite frames side by side
+		// Fill background with border color, then draw each video at its position
 		output := image.NewRGBA(image.Rect(0, 0, outputWidth, outputHeight))
-
-		// Fill background with border color
-		bgColor := image.NewUniform(s.opts.BorderColor)
 		draw.Draw(output, output.Bounds(), bgColor, image.Point{}, draw.Src)
 
-		// Draw left video (vertically centered)
-		leftY := (outputHeight - leftHeight) / 2
-		leftRect := image.Rect(0, leftY, leftWidth, leftY+leftHeight)
-		draw.Draw(output, leftRect, leftFrame.Image, leftFrame.Image.Bounds().Min, draw.Src)
-
-		// Draw right video (vertically centered)
-		rightY := (outputHeight - rightHeight) / 2
-		rightX := leftWidth + s.opts.Gap
-		rightRect := image.Rect(rightX, rightY, rightX+rightWidth, rightY+rightHeight)
-		draw.Draw(output, rightRect, rightFrame.Image, rightFrame.Image.Bounds().Min, draw.Src)
+		for i := range sources {
+			src := &sources[i]
+			videoPos, captionPos := positions[i], positions[i]
+			if s.opts.LabelPosition == LabelTop {
+				videoPos.Y += captionHeight
+			} else {
+				captionPos.Y += src.Size().Y
+			}
+
+			frame, err := src.FrameAt(timestampMs + src.offsetMs)
+			if err != nil {
+				return result, fmt.Errorf("read video %s at %dms: %w", paths[i], timestampMs, err)
+			}
+			rect := image.Rectangle{Min: videoPos, Max: videoPos.Add(src.Size())}
+			draw.Draw(output, rect, frame.Image, frame.Image.Bounds().Min, draw.Src)
+
+			if img := src.captionAt(timestampMs + src.offsetMs); img != nil {
+				rect := image.Rectangle{Min: captionPos, Max: captionPos.Add(img.Bounds().Size())}
+				draw.Draw(output, rect, img, img.Bounds().Min, draw.Src)
+			}
+		}
 
 		// Encode frame
 		if err := s.encoder.EncodeFrame(output, timestampMs); err != nil {
@@ -220,20 +303,85 @@ func (s *Stage) Execute(ctx context.Context, input Input) (Result, error) {
 	return result, nil
 }
 
-// getFrameAtTime returns the frame at or before the given timestamp.
-// If timestamp is past the last frame, returns the last frame.
-func getFrameAtTime(frames []ports.VideoFrame, timestampMs int) ports.VideoFrame {
-	if len(frames) == 0 {
-		return ports.VideoFrame{}
+// source is an input video decoded incrementally. Only the frame on screen
+// and the one after it are held in memory.
+type source struct {
+	*frameiter.Player
+	offsetMs int // Video time shown at output t=0
+
+	caption         Caption
+	pendingCaption  image.Image // Caption shown until the load completes (nil = no caption)
+	finishedCaption image.Image // Caption shown from Caption.LoadCompleteMs on
+}
+
+// openSource aligns a video and reads its first frames. Frames before the
+// start offset are skipped without being shown.
+func (s *Stage) openSource(ctx context.Context, input Input, i int, frames ports.FrameIterator) (source, error) {
+	offset, err := s.startOffset(ctx, input, i, frames)
+	if err != nil {
+		return source{}, fmt.Errorf("align: %w", err)
+	}
+	if err := frames.Seek(max(offset, 0)); err != nil {
+		return source{}, fmt.Errorf("seek to %dms: %w", offset, err)
+	}
+
+	player, err := frameiter.NewPlayer(frames)
+	if err != nil {
+		return source{}, err
 	}
+	return source{Player: player, offsetMs: offset}, nil
+}
 
-	// Find the frame that contains this timestamp
-	for i := len(frames) - 1; i >= 0; i-- {
-		if frames[i].TimestampMs <= timestampMs {
-			return frames[i]
+// gridColumns returns the number of columns used to lay out n videos.
+func gridColumns(n int, layout Layout, columns int) int {
+	switch layout {
+	case LayoutVertical:
+		return 1
+	case LayoutGrid:
+		if columns <= 0 {
+			columns = int(math.Ceil(math.Sqrt(float64(n))))
 		}
+		return min(columns, n)
+	default:
+		return n
 	}
+}
 
-	// Return first frame if timestamp is before all frames
-	return frames[0]
+// arrange places videos of the given sizes in rows and columns, filling rows
+// first. Each column is as wide as its widest video and each row as tall as
+// its tallest; videos are centered in their cell. It returns the top-left
+// position of each video and the output dimensions.
+func arrange(sizes []image.Point, layout Layout, columns, gap int) (positions []image.Point, width, height int) {
+	cols := gridColumns(len(sizes), layout, columns)
+	rows := (len(sizes) + cols - 1) / cols
+
+	colWidths := make([]int, cols)
+	rowHeights := make([]int, rows)
+	for i, size := range sizes {
+		colWidths[i%cols] = max(colWidths[i%cols], size.X)
+		rowHeights[i/cols] = max(rowHeights[i/cols], size.Y)
+	}
+
+	colX := make([]int, cols)
+	for c := range colWidths {
+		colX[c] = width
+		width += colWidths[c] + gap
+	}
+	rowY := make([]int, rows)
+	for r := range rowHeights {
+		rowY[r] = height
+		height += rowHeights[r] + gap
+	}
+	width -= gap
+	height -= gap
+
+	positions = make([]image.Point, len(sizes))
+	for i, size := range sizes {
+		c, r := i%cols, i/cols
+		positions[i] = image.Pt(
+			colX[c]+(colWidths[c]-size.X)/2,
+			rowY[r]+(rowHeights[r]-size.Y)/2,
+		)
+	}
+	return positions, width, height
 }
@@ -7,6 +7,7 @@ import (
 	"io"
 	"testing"
 
+	"github.com/user/loadshow/pkg/mocks"
 	"github.com/user/loadshow/pkg/ports"
 )
 
@@ -29,6 +30,11 @@ func (m *mockDecoder) ReadFramesFromReader(reader io.ReadSeeker) ([]ports.VideoF
 	return nil, nil
 }
 
+func (m *mockDecoder) OpenFrames(path string) (ports.FrameIterator, error) {
+	frames, _ := m.ReadFrames(path)
+	return mocks.NewFrameIterator(frames), nil
+}
+
 func (m *mockDecoder) Close() {}
 
 // mockEncoder captures encoding parameters for verification.
@@ -297,3 +303,205 @@ func TestBorderColor(t *testing.T) {
 		})
 	}
 }
+
+// pathDecoder returns frames by input path.
+type pathDecoder struct {
+	frames map[string][]ports.VideoFrame
+	opened map[string]*mocks.FrameIterator // Iterators by path (nil = not recorded)
+}
+
+func (m *pathDecoder) ReadFrames(path string) ([]ports.VideoFrame, error) {
+	return m.frames[path], nil
+}
+
+func (m *pathDecoder) ReadFramesFromReader(reader io.ReadSeeker) ([]ports.VideoFrame, error) {
+	return nil, nil
+}
+
+func (m *pathDecoder) OpenFrames(path string) (ports.FrameIterator, error) {
+	it := mocks.NewFrameIterator(m.frames[path])
+	if m.opened != nil {
+		m.opened[path] = it
+	}
+	return it, nil
+}
+
+func (m *pathDecoder) Close() {}
+
+func TestArrange(t *testing.T) {
+	square := func(n int) []image.Point {
+		sizes := make([]image.Point, n)
+		for i := range sizes {
+			sizes[i] = image.Pt(100, 100)
+		}
+		return sizes
+	}
+
+	tests := []struct {
+		name       string
+		sizes      []image.Point
+		layout     Layout
+		columns    int
+		wantWidth  int
+		wantHeight int
+		wantLast   image.Point
+	}{
+		{"horizontal", square(3), LayoutHorizontal, 0, 304, 100, image.Pt(204, 0)},
+		{"vertical", square(3), LayoutVertical, 0, 100, 304, image.Pt(0, 204)},
+		{"auto grid of 4", square(4), LayoutGrid, 0, 202, 202, image.Pt(102, 102)},
+		{"auto grid of 5", square(5), LayoutGrid, 0, 304, 202, image.Pt(102, 102)},
+		{"grid with 2 columns", square(5), LayoutGrid, 2, 202, 304, image.Pt(0, 204)},
+		{"columns capped at count", square(2), LayoutGrid, 4, 202, 100, image.Pt(102, 0)},
+		{
+			name:       "mixed sizes are centered in their cell",
+			sizes:      []image.Point{{100, 200}, {60, 100}, {80, 100}, {100, 100}},
+			layout:     LayoutGrid,
+			wantWidth:  202,
+			wantHeight: 302,
+			wantLast:   image.Pt(102, 202),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			positions, width, height := arrange(tt.sizes, tt.layout, tt.columns, 2)
+			if width != tt.wantWidth || height != tt.wantHeight {
+				t.Errorf("output = %dx%d, want %dx%d", width, height, tt.wantWidth, tt.wantHeight)
+			}
+			if got := positions[len(positions)-1]; got != tt.wantLast {
+				t.Errorf("last position = %v, want %v", got, tt.wantLast)
+			}
+		})
+	}
+
+	// Shorter videos are centered vertically in their row, narrower ones horizontally in their column
+	positions, _, _ := arrange([]image.Point{{100, 200}, {60, 100}, {60, 100}}, LayoutGrid, 2, 2)
+	if positions[1] != image.Pt(102, 50) || positions[2] != image.Pt(20, 202) {
+		t.Errorf("positions = %v", positions)
+	}
+}
+
+func TestExecute_MultipleInputs(t *testing.T) {
+	colors := []color.Color{
+		color.RGBA{R: 255, A: 255},
+		color.RGBA{G: 255, A: 255},
+		color.RGBA{B: 255, A: 255},
+	}
+	decoder := &pathDecoder{frames: map[string][]ports.VideoFrame{}}
+	paths := []string{"a.mp4", "b.mp4", "c.mp4"}
+	for i, path := range paths {
+		// Videos end at 100ms, 200ms and 300ms
+		decoder.frames[path] = []ports.VideoFrame{
+			{Image: createTestFrame(50, 50, colors[i]), TimestampMs: 0, Duration: 100 * (i + 1)},
+		}
+	}
+	encoder := &mockEncoder{}
+
+	opts := DefaultOptions()
+	opts.Layout = LayoutGrid
+	opts.Columns = 2
+	stage := New(decoder, encoder, &mockFileSystem{}, &mockLogger{}, opts)
+
+	result, err := stage.Execute(context.Background(), Input{Paths: paths, OutputPath: "grid.mp4"})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if encoder.width != 101 || encoder.height != 101 {
+		t.Errorf("output = %dx%d, want 101x101", encoder.width, encoder.height)
+	}
+	if result.DurationMs != 300 {
+		t.Errorf("duration = %d, want 300 (longest input)", result.DurationMs)
+	}
+
+	// Every frame shows all three videos; the empty fourth cell is background
+	last := encoder.frames[len(encoder.frames)-1].(*image.RGBA)
+	for i, p := range []image.Point{{25, 25}, {75, 25}, {25, 75}} {
+		if got := last.At(p.X, p.Y); got != colors[i] {
+			t.Errorf("video %d at %v = %v, want %v", i, p, got, colors[i])
+		}
+	}
+	if got := last.RGBAAt(75, 75); got != DefaultBorderColor {
+		t.Errorf("empty cell = %v, want border color", got)
+	}
+}
+
+func TestExecute_TooFewInputs(t *testing.T) {
+	stage := New(&pathDecoder{}, &mockEncoder{}, &mockFileSystem{}, &mockLogger{}, DefaultOptions())
+	if _, err := stage.Execute(context.Background(), Input{Paths: []string{"a.mp4"}}); err == nil {
+		t.Error("expected error for a single input")
+	}
+}
+
+func TestExecute_StreamsFrames(t *testing.T) {
+	red := color.RGBA{R: 255, A: 255}
+	green := color.RGBA{G: 255, A: 255}
+	blue := color.RGBA{B: 255, A: 255}
+	decoder := &pathDecoder{
+		frames: map[string][]ports.VideoFrame{
+			// Frames change at 0, 45 and 100ms; the output samples every 33ms
+			"a.mp4": {
+				{Image: createTestFrame(10, 10, red), TimestampMs: 0, Duration: 45},
+				{Image: createTestFrame(10, 10, green), TimestampMs: 45, Duration: 55},
+				{Image: createTestFrame(10, 10, blue), TimestampMs: 100, Duration: 50},
+			},
+			"b.mp4": {
+				{Image: createTestFrame(10, 10, red), TimestampMs: 0, Duration: 30},
+			},
+		},
+		opened: map[string]*mocks.FrameIterator{},
+	}
+	encoder := &mockEncoder{}
+
+	opts := DefaultOptions()
+	opts.Gap = 0
+	stage := New(decoder, encoder, &mockFileSystem{}, &mockLogger{}, opts)
+
+	if _, err := stage.Execute(context.Background(), Input{LeftPath: "a.mp4", RightPath: "b.mp4"}); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	// Output frames at 0, 33, 66, 99, 132ms
+	want := []color.Color{red, red, green, green, blue}
+	if len(encoder.frames) != len(want) {
+		t.Fatalf("encoded %d frames, want %d", len(encoder.frames), len(want))
+	}
+	for i, c := range want {
+		if got := encoder.frames[i].At(5, 5); got != c {
+			t.Errorf("frame %d = %v, want %v", i, got, c)
+		}
+		// The shorter video holds its last frame
+		if got := encoder.frames[i].At(15, 5); got != red {
+			t.Errorf("frame %d right = %v, want %v", i, got, red)
+		}
+	}
+
+	// Each input is decoded once, in a single pass, and closed
+	for path, it := range decoder.opened {
+		if it.NextCalls != len(it.Frames)+1 {
+			t.Errorf("%s: Next called %d times, want %d", path, it.NextCalls, len(it.Frames)+1)
+		}
+		if !it.Closed {
+			t.Errorf("%s: iterator not closed", path)
+		}
+	}
+}
+
+func TestExecute_EmptyVideo(t *testing.T) {
+	decoder := &pathDecoder{
+		frames: map[string][]ports.VideoFrame{
+			"a.mp4": {{Image: createTestFrame(10, 10, color.Black), Duration: 100}},
+		},
+		opened: map[string]*mocks.FrameIterator{},
+	}
+	stage := New(decoder, &mockEncoder{}, &mockFileSystem{}, &mockLogger{}, DefaultOptions())
+
+	if _, err := stage.Execute(context.Background(), Input{LeftPath: "a.mp4", RightPath: "empty.mp4"}); err == nil {
+		t.Fatal("expected error for a video without frames")
+	}
+	for path, it := range decoder.opened {
+		if !it.Closed {
+			t.Errorf("%s: iterator not closed", path)
+		}
+	}
+}
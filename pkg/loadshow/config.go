@@ -2,9 +2,14 @@
 package loadshow
 
 import (
+	"fmt"
 	"image/color"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/user/loadshow/pkg/orchestrator"
+	"github.com/user/loadshow/pkg/pipeline"
 	"github.com/user/loadshow/pkg/ports"
 )
 
@@ -44,6 +49,64 @@ func GetQualitySettings(preset QualityPreset) QualitySettings {
 	}
 }
 
+// OutputFormat represents the output file format.
+type OutputFormat string
+
+const (
+	FormatMP4  OutputFormat = "mp4"
+	FormatWebM OutputFormat = "webm"
+	FormatGIF  OutputFormat = "gif"
+	FormatWebP OutputFormat = "webp"
+	FormatAPNG OutputFormat = "apng"
+)
+
+// animatedFPS is the default frame rate for animated image formats,
+// which grow quickly with the number of frames.
+const animatedFPS = 10.0
+
+// ParseOutputFormat parses a format name (mp4, webm, gif, webp, apng).
+func ParseOutputFormat(s string) (OutputFormat, error) {
+	switch f := OutputFormat(strings.ToLower(strings.TrimSpace(s))); f {
+	case FormatMP4, FormatWebM, FormatGIF, FormatWebP, FormatAPNG:
+		return f, nil
+	case "png":
+		return FormatAPNG, nil
+	default:
+		return "", fmt.Errorf("unknown format: %s (supported: mp4, webm, gif, webp, apng)", s)
+	}
+}
+
+// FormatFromPath returns the output format implied by a file extension.
+// Unknown extensions default to MP4.
+func FormatFromPath(path string) OutputFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".webm":
+		return FormatWebM
+	case ".gif":
+		return FormatGIF
+	case ".webp":
+		return FormatWebP
+	case ".apng", ".png":
+		return FormatAPNG
+	default:
+		return FormatMP4
+	}
+}
+
+// IsAnimatedImage reports whether the format is an animated image rather than a video.
+func (f OutputFormat) IsAnimatedImage() bool {
+	return f == FormatGIF || f == FormatWebP || f == FormatAPNG
+}
+
+// Container returns the video container for the format. Animated image
+// formats have no video container and report MP4, which their encoders ignore.
+func (f OutputFormat) Container() ports.Container {
+	if f == FormatWebM {
+		return ports.ContainerWebM
+	}
+	return ports.ContainerMP4
+}
+
 // Config represents the configuration for loadshow video generation.
 type Config struct {
 	// Video size
@@ -62,15 +125,40 @@ type Config struct {
 	BackgroundColor color.Color // Canvas background color
 	BorderColor     color.Color // Column border color
 	BorderWidth     int         // Border width in pixels
+	DeviceFrame     string      // Device frame around each column: "phone", "browser", or "" for borders
+	FrameImagePath  string      // 9-slice PNG used as the device frame (overrides DeviceFrame)
+	FrameSlice      [4]int      // 9-slice insets of the PNG: top, right, bottom, left
 
 	// Encoding
-	VideoCRF          int // MP4 CRF value (0-63, lower is better)
-	ScreencastQuality int // JPEG quality for screencast (0-100)
-	OutroMs           int // Duration to continue recording after page load event in milliseconds
+	Format            OutputFormat // Output format (default: mp4)
+	FPS               float64      // Output frame rate (0 = 30 for MP4, 10 for animated images)
+	VideoCRF          int          // MP4 CRF value (0-63, lower is better)
+	ScreencastQuality int          // JPEG quality for screencast (0-100)
+	OutroMs           int          // Duration to continue recording after page load event in milliseconds
+	MaxSize           int64        // Largest output size in bytes; CRF is raised until it fits (0 = no limit)
 
 	// Banner
 	Credit string // Text shown in banner (replaces "loadshow")
 
+	// Overlay
+	HighlightChanges bool         // Highlight regions that changed between frames
+	HighlightFadeMs  int          // Fade duration for change highlights in milliseconds
+	ShowLayoutShifts bool         // Outline shifted elements and show the running CLS score
+	ShowLCP          bool         // Outline the LCP element and show the LCP badge
+	TimingMarks      []TimingMark // User-timing marks to show as badges
+
+	// Filmstrip
+	FilmstripPath       string                 // Contact sheet image path, .png or .jpg ("" = disabled)
+	FilmstripMode       pipeline.FilmstripMode // Sample at a fixed interval (default) or at visual changes
+	FilmstripIntervalMs int                    // Sampling interval in milliseconds (default: 100)
+	FilmstripColumns    int                    // Frames per row (0 = single row)
+
+	// Poster image
+	PosterPath      string               // Poster image path, .png or .jpg ("" = disabled)
+	PosterFrame     pipeline.PosterFrame // Frame used for the poster (default: final frame)
+	PosterTimeMs    int                  // Poster time in milliseconds for pipeline.PosterTime
+	ThumbnailWidths []int                // Thumbnail widths in pixels, written next to the poster
+
 	// Network throttling
 	DownloadSpeed int // Download speed in bytes/sec (0 = unlimited)
 	UploadSpeed   int // Upload speed in bytes/sec (0 = unlimited)
@@ -84,6 +172,20 @@ type Config struct {
 
 	// Timeout
 	TimeoutSec int // Recording timeout in seconds (default: 30)
+
+	// Metadata
+	Version string // loadshow version recorded in MP4 metadata ("" = omit)
+
+	// Subtitles announcing milestones
+	Subtitles     bool   // Add a soft-subtitle track (MP4 only)
+	SubtitlesPath string // WebVTT sidecar path ("" = disabled)
+}
+
+// TimingMark selects a user-timing mark or measure to show as a badge.
+type TimingMark struct {
+	Name  string      // Name passed to performance.mark() or performance.measure()
+	Label string      // Badge label (empty = Name)
+	Color color.Color // Badge color (nil = default orange)
 }
 
 // ConfigBuilder provides a fluent interface for building Config.
@@ -133,6 +235,9 @@ func desktopDefaults() Config {
 		// Banner
 		Credit: "loadshow",
 
+		// Overlay
+		HighlightFadeMs: 500,
+
 		// Network (no throttling)
 		DownloadSpeed: 0,
 		UploadSpeed:   0,
@@ -173,6 +278,9 @@ func mobileDefaults() Config {
 		// Banner
 		Credit: "loadshow",
 
+		// Overlay
+		HighlightFadeMs: 500,
+
 		// Network (10 Mbps)
 		DownloadSpeed: MbpsToBytes(10),
 		UploadSpeed:   MbpsToBytes(10),
@@ -270,12 +378,39 @@ func (b *ConfigBuilder) WithBorderWidth(width int) *ConfigBuilder {
 	return b
 }
 
+// WithDeviceFrame sets the built-in device frame drawn around each column ("phone" or "browser").
+// The frame replaces the column border.
+func (b *ConfigBuilder) WithDeviceFrame(style string) *ConfigBuilder {
+	b.config.DeviceFrame = style
+	return b
+}
+
+// WithFrameImage uses a 9-slice PNG as the device frame.
+// The insets mark the stretchable edges of the image and set the bezel thickness.
+func (b *ConfigBuilder) WithFrameImage(path string, top, right, bottom, left int) *ConfigBuilder {
+	b.config.FrameImagePath = path
+	b.config.FrameSlice = [4]int{top, right, bottom, left}
+	return b
+}
+
 // WithVideoCRF sets the MP4 CRF value (0-63, lower is better).
 func (b *ConfigBuilder) WithVideoCRF(crf int) *ConfigBuilder {
 	b.config.VideoCRF = crf
 	return b
 }
 
+// WithFormat sets the output format.
+func (b *ConfigBuilder) WithFormat(format OutputFormat) *ConfigBuilder {
+	b.config.Format = format
+	return b
+}
+
+// WithFPS sets the output frame rate (0 = format default).
+func (b *ConfigBuilder) WithFPS(fps float64) *ConfigBuilder {
+	b.config.FPS = fps
+	return b
+}
+
 // WithScreencastQuality sets the JPEG quality for screencast (0-100).
 func (b *ConfigBuilder) WithScreencastQuality(quality int) *ConfigBuilder {
 	b.config.ScreencastQuality = quality
@@ -302,6 +437,63 @@ func (b *ConfigBuilder) WithCredit(credit string) *ConfigBuilder {
 	return b
 }
 
+// WithHighlightChanges enables highlighting of regions that changed between frames.
+func (b *ConfigBuilder) WithHighlightChanges(enabled bool) *ConfigBuilder {
+	b.config.HighlightChanges = enabled
+	return b
+}
+
+// WithHighlightFadeMs sets the fade duration for change highlights in milliseconds.
+func (b *ConfigBuilder) WithHighlightFadeMs(ms int) *ConfigBuilder {
+	b.config.HighlightFadeMs = ms
+	return b
+}
+
+// WithShowLayoutShifts enables outlining of layout shifts and the running CLS badge.
+func (b *ConfigBuilder) WithShowLayoutShifts(enabled bool) *ConfigBuilder {
+	b.config.ShowLayoutShifts = enabled
+	return b
+}
+
+// WithShowLCP enables outlining of the Largest Contentful Paint element and the LCP badge.
+func (b *ConfigBuilder) WithShowLCP(enabled bool) *ConfigBuilder {
+	b.config.ShowLCP = enabled
+	return b
+}
+
+// WithTimingMark adds a user-timing mark or measure to show as a badge.
+// Measures are shown when they complete. Pass nil for the default color.
+func (b *ConfigBuilder) WithTimingMark(name, label string, c color.Color) *ConfigBuilder {
+	b.config.TimingMarks = append(b.config.TimingMarks, TimingMark{Name: name, Label: label, Color: c})
+	return b
+}
+
+// WithFilmstrip also writes a filmstrip contact sheet to path.
+// intervalMs is used with pipeline.FilmstripInterval; columns 0 lays frames out in a single row.
+func (b *ConfigBuilder) WithFilmstrip(path string, mode pipeline.FilmstripMode, intervalMs, columns int) *ConfigBuilder {
+	b.config.FilmstripPath = path
+	b.config.FilmstripMode = mode
+	b.config.FilmstripIntervalMs = intervalMs
+	b.config.FilmstripColumns = columns
+	return b
+}
+
+// WithPoster also writes a poster image to path.
+// timeMs is used with pipeline.PosterTime; LCP and Load fall back to the final frame if not recorded.
+func (b *ConfigBuilder) WithPoster(path string, frame pipeline.PosterFrame, timeMs int) *ConfigBuilder {
+	b.config.PosterPath = path
+	b.config.PosterFrame = frame
+	b.config.PosterTimeMs = timeMs
+	return b
+}
+
+// WithThumbnails also writes downscaled copies of the poster at the given widths.
+// Each is named after the poster with a width suffix (poster-320w.png).
+func (b *ConfigBuilder) WithThumbnails(widths ...int) *ConfigBuilder {
+	b.config.ThumbnailWidths = widths
+	return b
+}
+
 // WithDownloadSpeed sets the download speed limit in bytes/sec.
 // Use 0 for unlimited.
 func (b *ConfigBuilder) WithDownloadSpeed(bytesPerSec int) *ConfigBuilder {
@@ -349,6 +541,31 @@ func (b *ConfigBuilder) WithTimeoutSec(sec int) *ConfigBuilder {
 	return b
 }
 
+// WithVersion sets the loadshow version recorded in MP4 metadata.
+func (b *ConfigBuilder) WithVersion(version string) *ConfigBuilder {
+	b.config.Version = version
+	return b
+}
+
+// WithSubtitles adds a soft-subtitle track announcing milestones (MP4 only).
+func (b *ConfigBuilder) WithSubtitles(enabled bool) *ConfigBuilder {
+	b.config.Subtitles = enabled
+	return b
+}
+
+// WithSubtitlesFile also writes the milestone subtitles as a WebVTT file.
+func (b *ConfigBuilder) WithSubtitlesFile(path string) *ConfigBuilder {
+	b.config.SubtitlesPath = path
+	return b
+}
+
+// WithMaxSize limits the output size in bytes (0 = no limit).
+// The video is re-encoded at higher CRF values until it fits.
+func (b *ConfigBuilder) WithMaxSize(bytes int64) *ConfigBuilder {
+	b.config.MaxSize = bytes
+	return b
+}
+
 // MbpsToBytes converts megabits per second to bytes per second.
 // Uses 1024 as the base (1 Mbps = 1024 * 1024 / 8 bytes/sec).
 // Accepts float64 for fractional Mbps values (e.g., 1.5 Mbps).
@@ -356,6 +573,32 @@ func MbpsToBytes(mbps float64) int {
 	return int(mbps * 1024 * 1024 / 8)
 }
 
+// ParseSize parses a file size such as "5MB", "800KB", "1.5M" or "250000".
+// Units use 1024 as the base; a plain number is bytes.
+func ParseSize(s string) (int64, error) {
+	str := strings.ToUpper(strings.TrimSpace(s))
+	units := []struct {
+		suffix string
+		scale  float64
+	}{
+		{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10},
+		{"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}, {"B", 1},
+	}
+	scale := 1.0
+	for _, u := range units {
+		if strings.HasSuffix(str, u.suffix) {
+			str = strings.TrimSpace(strings.TrimSuffix(str, u.suffix))
+			scale = u.scale
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(str, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid size: %q (e.g., 5MB, 800KB)", s)
+	}
+	return int64(n * scale), nil
+}
+
 // ToOrchestratorConfig converts Config to orchestrator.Config.
 // Width/Height define the video dimensions; layout is computed from these.
 func (c Config) ToOrchestratorConfig(url, outputPath string) orchestrator.Config {
@@ -378,6 +621,16 @@ func (c Config) ToOrchestratorConfig(url, outputPath string) orchestrator.Config
 		BackgroundColor: colorToArray(c.BackgroundColor),
 		BorderColor:     colorToArray(c.BorderColor),
 
+		// Device frame
+		DeviceFrame:    c.deviceFrameStyle(),
+		FrameImagePath: c.FrameImagePath,
+		FrameSlice: pipeline.Insets{
+			Top:    c.FrameSlice[0],
+			Right:  c.FrameSlice[1],
+			Bottom: c.FrameSlice[2],
+			Left:   c.FrameSlice[3],
+		},
+
 		// Recording
 		ViewportWidth:     c.ViewportWidth,
 		ScreencastQuality: c.ScreencastQuality,
@@ -398,14 +651,90 @@ func (c Config) ToOrchestratorConfig(url, outputPath string) orchestrator.Config
 		Credit:        c.Credit,
 
 		// Composition
-		ShowProgress: true,
+		ShowProgress:     true,
+		HighlightChanges: c.HighlightChanges,
+		HighlightFadeMs:  c.HighlightFadeMs,
+		ShowLayoutShifts: c.ShowLayoutShifts,
+		ShowLCP:          c.ShowLCP,
+		TimingMarks:      toOrchestratorTimingMarks(c.TimingMarks),
 
 		// Encoding
-		VideoCRF: c.VideoCRF,
-		Bitrate:  0,
-		OutroMs:  c.OutroMs,
-		FPS:      30.0,
+		VideoCRF:  c.VideoCRF,
+		Bitrate:   0,
+		OutroMs:   c.OutroMs,
+		FPS:       c.fps(),
+		Container: c.Format.Container(),
+		MaxSize:   c.MaxSize,
+
+		// Metadata (MP4 only)
+		EmbedMetadata: c.isMP4(),
+		Generator:     c.generator(),
+
+		// Subtitles
+		Subtitles:     c.Subtitles && c.isMP4(),
+		SubtitlesPath: c.SubtitlesPath,
+
+		// Filmstrip
+		FilmstripPath:       c.FilmstripPath,
+		FilmstripMode:       c.FilmstripMode,
+		FilmstripIntervalMs: c.FilmstripIntervalMs,
+		FilmstripColumns:    c.FilmstripColumns,
+
+		// Poster
+		PosterPath:      c.PosterPath,
+		PosterFrame:     c.PosterFrame,
+		PosterTimeMs:    c.PosterTimeMs,
+		ThumbnailWidths: c.ThumbnailWidths,
+	}
+}
+
+// isMP4 reports whether the output is an MP4 video.
+func (c Config) isMP4() bool {
+	return !c.Format.IsAnimatedImage() && c.Format.Container() == ports.ContainerMP4
+}
+
+// generator returns the software name written to the video metadata.
+func (c Config) generator() string {
+	if c.Version == "" {
+		return "loadshow"
+	}
+	return "loadshow " + c.Version
+}
+
+// fps returns the output frame rate, using a lower default for animated images.
+func (c Config) fps() float64 {
+	switch {
+	case c.FPS > 0:
+		return c.FPS
+	case c.Format.IsAnimatedImage():
+		return animatedFPS
+	default:
+		return 30.0
+	}
+}
+
+// deviceFrameStyle returns the frame style, preferring a frame image when set.
+func (c Config) deviceFrameStyle() pipeline.FrameStyle {
+	if c.FrameImagePath != "" {
+		return pipeline.FrameImage
+	}
+	return pipeline.FrameStyle(c.DeviceFrame)
+}
+
+// toOrchestratorTimingMarks converts timing marks, leaving nil colors as zero (theme default).
+func toOrchestratorTimingMarks(marks []TimingMark) []orchestrator.TimingMark {
+	if len(marks) == 0 {
+		return nil
+	}
+	result := make([]orchestrator.TimingMark, 0, len(marks))
+	for _, m := range marks {
+		mark := orchestrator.TimingMark{Name: m.Name, Label: m.Label}
+		if m.Color != nil {
+			mark.Color = colorToArray(m.Color)
+		}
+		result = append(result, mark)
 	}
+	return result
 }
 
 // colorToArray converts color.Color to [4]uint8 array.
@@ -18,6 +18,10 @@ type Browser struct {
 	StopScreencastFunc       func() error
 	GetPageInfoFunc          func() (*ports.PageInfo, error)
 	GetPerformanceTimingFunc func() (*ports.PerformanceTiming, error)
+	GetLayoutShiftsFunc      func() ([]ports.LayoutShift, error)
+	GetLCPCandidatesFunc     func() ([]ports.LCPCandidate, error)
+	GetUserTimingsFunc       func() ([]ports.UserTiming, error)
+	GetResourceTimingsFunc   func() ([]ports.ResourceTiming, error)
 	CloseFunc                func() error
 }
 
@@ -86,6 +90,34 @@ func (m *Browser) GetPerformanceTiming() (*ports.PerformanceTiming, error) {
 	return &ports.PerformanceTiming{}, nil
 }
 
+func (m *Browser) GetLayoutShifts() ([]ports.LayoutShift, error) {
+	if m.GetLayoutShiftsFunc != nil {
+		return m.GetLayoutShiftsFunc()
+	}
+	return nil, nil
+}
+
+func (m *Browser) GetLCPCandidates() ([]ports.LCPCandidate, error) {
+	if m.GetLCPCandidatesFunc != nil {
+		return m.GetLCPCandidatesFunc()
+	}
+	return nil, nil
+}
+
+func (m *Browser) GetUserTimings() ([]ports.UserTiming, error) {
+	if m.GetUserTimingsFunc != nil {
+		return m.GetUserTimingsFunc()
+	}
+	return nil, nil
+}
+
+func (m *Browser) GetResourceTimings() ([]ports.ResourceTiming, error) {
+	if m.GetResourceTimingsFunc != nil {
+		return m.GetResourceTimingsFunc()
+	}
+	return nil, nil
+}
+
 func (m *Browser) Close() error {
 	if m.CloseFunc != nil {
 		return m.CloseFunc()
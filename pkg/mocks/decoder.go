@@ -0,0 +1,103 @@
+package mocks
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/user/loadshow/pkg/ports"
+)
+
+// FrameIterator is a mock implementation of ports.FrameIterator over
+// already decoded frames.
+type FrameIterator struct {
+	Frames []ports.VideoFrame
+
+	// Recorded calls for verification
+	NextCalls int
+	Closed    bool
+
+	pos int
+}
+
+// NewFrameIterator creates an iterator over frames.
+func NewFrameIterator(frames []ports.VideoFrame) *FrameIterator {
+	return &FrameIterator{Frames: frames}
+}
+
+func (m *FrameIterator) Next() (ports.VideoFrame, error) {
+	m.NextCalls++
+	if m.pos >= len(m.Frames) {
+		return ports.VideoFrame{}, io.EOF
+	}
+	frame := m.Frames[m.pos]
+	m.pos++
+	return frame, nil
+}
+
+func (m *FrameIterator) Seek(timestampMs int) error {
+	m.pos = 0
+	for i, f := range m.Frames {
+		if f.TimestampMs > timestampMs {
+			break
+		}
+		m.pos = i
+	}
+	return nil
+}
+
+func (m *FrameIterator) DurationMs() int {
+	if len(m.Frames) == 0 {
+		return 0
+	}
+	last := m.Frames[len(m.Frames)-1]
+	return last.TimestampMs + last.Duration
+}
+
+func (m *FrameIterator) Close() {
+	m.Closed = true
+}
+
+var _ ports.FrameIterator = (*FrameIterator)(nil)
+
+// VideoDecoder is a mock implementation of ports.VideoDecoder that returns
+// preset frames by path.
+type VideoDecoder struct {
+	Frames map[string][]ports.VideoFrame
+
+	// Recorded calls for verification
+	Opened map[string]*FrameIterator
+}
+
+// NewVideoDecoder creates a decoder returning frames by path.
+func NewVideoDecoder(frames map[string][]ports.VideoFrame) *VideoDecoder {
+	return &VideoDecoder{
+		Frames: frames,
+		Opened: make(map[string]*FrameIterator),
+	}
+}
+
+func (m *VideoDecoder) ReadFrames(path string) ([]ports.VideoFrame, error) {
+	frames, ok := m.Frames[path]
+	if !ok {
+		return nil, fmt.Errorf("file not found: %s", path)
+	}
+	return frames, nil
+}
+
+func (m *VideoDecoder) ReadFramesFromReader(reader io.ReadSeeker) ([]ports.VideoFrame, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func (m *VideoDecoder) OpenFrames(path string) (ports.FrameIterator, error) {
+	frames, err := m.ReadFrames(path)
+	if err != nil {
+		return nil, err
+	}
+	it := NewFrameIterator(frames)
+	m.Opened[path] = it
+	return it, nil
+}
+
+func (m *VideoDecoder) Close() {}
+
+var _ ports.VideoDecoder = (*VideoDecoder)(nil)
@@ -0,0 +1,309 @@
+package mp4meta
+
+import (
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/user/loadshow/pkg/ports"
+)
+
+// Chapter track parameters
+const (
+	chapterHandler = "text"
+	chapterName    = "Chapters"
+)
+
+var chapterKind = textTrackKind{handler: chapterHandler, name: chapterName, chapter: true}
+
+// embedChapterTrack adds chapters as a QuickTime chapter track: a text track
+// with one sample per chapter, referenced by the first track's tref/chap.
+// The last chapter lasts until the end of the first track. Files without
+// tracks are returned unchanged.
+func embedChapterTrack(data []byte, chapters []ports.Chapter) ([]byte, error) {
+	top, err := readBoxes(data, 0, len(data))
+	if err != nil {
+		return nil, err
+	}
+	moov, ok := findBox(top, "moov")
+	if !ok {
+		return nil, fmt.Errorf("no moov box")
+	}
+	children, err := readBoxes(data, moov.bodyStart(), moov.end)
+	if err != nil {
+		return nil, fmt.Errorf("moov: %w", err)
+	}
+	if _, ok := findBox(children, "trak"); !ok {
+		return data, nil
+	}
+	durationMs, err := firstTrackDurationMs(data, top, children)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := append([]ports.Chapter{}, chapters...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].StartMs < sorted[j].StartMs
+	})
+	cues := make([]ports.Cue, len(sorted))
+	for i, c := range sorted {
+		end := max(durationMs, c.StartMs+1)
+		if i+1 < len(sorted) {
+			end = sorted[i+1].StartMs
+		}
+		cues[i] = ports.Cue{StartMs: c.StartMs, EndMs: end, Text: c.Title}
+	}
+
+	samples := textSamples(cues)
+	if len(samples) == 0 {
+		return data, nil
+	}
+	return addTextTrack(data, samples, chapterKind)
+}
+
+// readChapterTrack reads the chapters from the track referenced by the
+// first track's tref/chap. It returns nil if there is none.
+func readChapterTrack(data []byte, top, moovChildren []boxInfo) ([]ports.Chapter, error) {
+	ids, err := chapterTrackIDs(data, moovChildren)
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	for _, c := range moovChildren {
+		if c.typ != "trak" {
+			continue
+		}
+		trak, err := parseTrak(data, c)
+		if err != nil {
+			return nil, err
+		}
+		if trak.id != ids[0] {
+			continue
+		}
+		samples, err := trak.allSamples(data, top)
+		if err != nil {
+			return nil, err
+		}
+		var chapters []ports.Chapter
+		for _, cue := range samplesToCues(data, samples, trak.timescale) {
+			chapters = append(chapters, ports.Chapter{StartMs: cue.StartMs, Title: cue.Text})
+		}
+		return chapters, nil
+	}
+	return nil, nil
+}
+
+// removeChapterTrack returns data without the chapter track referenced by
+// the first track, so that Embed replaces it. Fragments holding only
+// chapter samples are dropped; chapter samples in an mdat are left
+// unreferenced.
+func removeChapterTrack(data []byte) ([]byte, error) {
+	top, err := readBoxes(data, 0, len(data))
+	if err != nil {
+		return nil, err
+	}
+	moov, ok := findBox(top, "moov")
+	if !ok {
+		return nil, fmt.Errorf("no moov box")
+	}
+	children, err := readBoxes(data, moov.bodyStart(), moov.end)
+	if err != nil {
+		return nil, fmt.Errorf("moov: %w", err)
+	}
+	ids, err := chapterTrackIDs(data, children)
+	if err != nil || len(ids) == 0 {
+		return data, err
+	}
+
+	var body []byte
+	firstTrak := true
+	for _, c := range children {
+		switch c.typ {
+		case "trak":
+			if firstTrak {
+				firstTrak = false
+				trak, err := withChapterRef(data, c, 0)
+				if err != nil {
+					return nil, err
+				}
+				body = append(body, trak...)
+				continue
+			}
+			trak, err := parseTrak(data, c)
+			if err != nil {
+				return nil, err
+			}
+			if !slices.Contains(ids, trak.id) {
+				body = append(body, data[c.start:c.end]...)
+			}
+		case "mvex":
+			boxes, err := readBoxes(data, c.bodyStart(), c.end)
+			if err != nil {
+				return nil, fmt.Errorf("mvex: %w", err)
+			}
+			var mvex []byte
+			for _, b := range boxes {
+				if b.typ == "trex" && b.end-b.bodyStart() >= 8 &&
+					slices.Contains(ids, binary.BigEndian.Uint32(data[b.bodyStart()+4:b.bodyStart()+8])) {
+					continue
+				}
+				mvex = append(mvex, data[b.start:b.end]...)
+			}
+			body = append(body, makeBox("mvex", mvex)...)
+		default:
+			body = append(body, data[c.start:c.end]...)
+		}
+	}
+
+	newMoov := makeBox("moov", body)
+	if delta := int64(len(newMoov)) - int64(moov.end-moov.start); delta != 0 {
+		if err := shiftChunkOffsets(newMoov, 8, len(newMoov), int64(moov.end), delta); err != nil {
+			return nil, err
+		}
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:moov.start]...)
+	out = append(out, newMoov...)
+	dropMdat := false
+	for _, b := range top {
+		if b.start < moov.end {
+			continue
+		}
+		if b.typ == "moof" {
+			only, err := fragmentOfTracks(data, b, ids)
+			if err != nil {
+				return nil, err
+			}
+			if only {
+				dropMdat = true
+				continue
+			}
+		}
+		if b.typ == "mdat" && dropMdat {
+			dropMdat = false
+			continue
+		}
+		dropMdat = false
+		out = append(out, data[b.start:b.end]...)
+	}
+	return out, nil
+}
+
+// chapterTrackIDs returns the track IDs in the first track's tref/chap.
+func chapterTrackIDs(data []byte, moovChildren []boxInfo) ([]uint32, error) {
+	trak, ok := findBox(moovChildren, "trak")
+	if !ok {
+		return nil, nil
+	}
+	boxes, err := readBoxes(data, trak.bodyStart(), trak.end)
+	if err != nil {
+		return nil, fmt.Errorf("trak: %w", err)
+	}
+	tref, ok := findBox(boxes, "tref")
+	if !ok {
+		return nil, nil
+	}
+	refs, err := readBoxes(data, tref.bodyStart(), tref.end)
+	if err != nil {
+		return nil, fmt.Errorf("tref: %w", err)
+	}
+	chap, ok := findBox(refs, "chap")
+	if !ok {
+		return nil, nil
+	}
+	var ids []uint32
+	for pos := chap.bodyStart(); pos+4 <= chap.end; pos += 4 {
+		ids = append(ids, binary.BigEndian.Uint32(data[pos:pos+4]))
+	}
+	return ids, nil
+}
+
+// withChapterRef returns a copy of trak whose tref/chap references the
+// chapter track id, or that references no chapter track if id is 0.
+// Other track references are kept.
+func withChapterRef(data []byte, trak boxInfo, id uint32) ([]byte, error) {
+	boxes, err := readBoxes(data, trak.bodyStart(), trak.end)
+	if err != nil {
+		return nil, fmt.Errorf("trak: %w", err)
+	}
+	tkhd, ok := findBox(boxes, "tkhd")
+	if !ok {
+		return nil, fmt.Errorf("no tkhd box")
+	}
+
+	var refs, rest []byte
+	if id != 0 {
+		refs = makeBox("chap", binary.BigEndian.AppendUint32(nil, id))
+	}
+	for _, b := range boxes {
+		switch b.typ {
+		case "tkhd":
+		case "tref":
+			old, err := readBoxes(data, b.bodyStart(), b.end)
+			if err != nil {
+				return nil, fmt.Errorf("tref: %w", err)
+			}
+			for _, r := range old {
+				if r.typ != "chap" {
+					refs = append(refs, data[r.start:r.end]...)
+				}
+			}
+		default:
+			rest = append(rest, data[b.start:b.end]...)
+		}
+	}
+
+	// tref follows tkhd
+	body := append([]byte{}, data[tkhd.start:tkhd.end]...)
+	if len(refs) > 0 {
+		body = append(body, makeBox("tref", refs)...)
+	}
+	return makeBox("trak", append(body, rest...)), nil
+}
+
+// fragmentOfTracks reports whether every traf in moof belongs to one of ids.
+func fragmentOfTracks(data []byte, moof boxInfo, ids []uint32) (bool, error) {
+	boxes, err := readBoxes(data, moof.bodyStart(), moof.end)
+	if err != nil {
+		return false, fmt.Errorf("moof: %w", err)
+	}
+	found := false
+	for _, traf := range boxes {
+		if traf.typ != "traf" {
+			continue
+		}
+		parts, err := readBoxes(data, traf.bodyStart(), traf.end)
+		if err != nil {
+			return false, fmt.Errorf("traf: %w", err)
+		}
+		tfhd, ok := findBox(parts, "tfhd")
+		if !ok || tfhd.end-tfhd.bodyStart() < 8 {
+			return false, nil
+		}
+		if !slices.Contains(ids, binary.BigEndian.Uint32(data[tfhd.bodyStart()+4:tfhd.bodyStart()+8])) {
+			return false, nil
+		}
+		found = true
+	}
+	return found, nil
+}
+
+// firstTrackDurationMs returns the end time of the last sample of the first
+// track, or 0 if it has none.
+func firstTrackDurationMs(data []byte, top, moovChildren []boxInfo) (int, error) {
+	c, ok := findBox(moovChildren, "trak")
+	if !ok {
+		return 0, nil
+	}
+	trak, err := parseTrak(data, c)
+	if err != nil {
+		return 0, err
+	}
+	samples, err := trak.allSamples(data, top)
+	if err != nil || len(samples) == 0 || trak.timescale == 0 {
+		return 0, err
+	}
+	last := samples[len(samples)-1]
+	return int((last.time + uint64(last.dur)) * 1000 / uint64(trak.timescale)), nil
+}
@@ -0,0 +1,467 @@
+// Package mp4meta embeds recording metadata, chapter markers and subtitles
+// into MP4 files and reads them back.
+//
+// Metadata is written to moov/udta as iTunes-style items (meta/ilst), which
+// ffprobe, QuickTime and most taggers display. Chapters are written twice:
+// as a QuickTime chapter track, a text track the first track references
+// with tref/chap, which QuickTime, Safari and ffmpeg read, and as a Nero
+// chapter list (chpl), which VLC and mp4v2-based tools read. Subtitles are added as a 3GPP timed text (tx3g) track.
+package mp4meta
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/user/loadshow/pkg/ports"
+)
+
+// ErrNoMetadata is returned by Read when the file carries no loadshow metadata.
+var ErrNoMetadata = errors.New("mp4meta: no metadata found")
+
+// freeformMean is the namespace of loadshow's freeform (----) items.
+const freeformMean = "loadshow"
+
+// Freeform item names.
+const (
+	nameURL           = "url"
+	settingNamePrefix = "setting."
+)
+
+// iTunes item types ("\xa9" is the copyright sign used by QuickTime).
+const (
+	itemTitle     = "\xa9nam"
+	itemDate      = "\xa9day"
+	itemGenerator = "\xa9too"
+	itemFreeform  = "----"
+)
+
+// maxChapters is the most chapters a chpl box can hold (8-bit count).
+const maxChapters = 255
+
+// Embed returns a copy of an MP4 file with md written to moov/udta and
+// its chapters added as a chapter track. Any existing udta box and chapter
+// track are replaced. Chunk offsets of samples that follow moov
+// (progressive, faststart files) are shifted to match.
+func Embed(data []byte, md ports.VideoMetadata) ([]byte, error) {
+	data, err := removeChapterTrack(data)
+	if err != nil {
+		return nil, err
+	}
+	top, err := readBoxes(data, 0, len(data))
+	if err != nil {
+		return nil, err
+	}
+	moov, ok := findBox(top, "moov")
+	if !ok {
+		return nil, fmt.Errorf("no moov box")
+	}
+
+	children, err := readBoxes(data, moov.bodyStart(), moov.end)
+	if err != nil {
+		return nil, fmt.Errorf("moov: %w", err)
+	}
+
+	var body []byte
+	for _, c := range children {
+		if c.typ != "udta" {
+			body = append(body, data[c.start:c.end]...)
+		}
+	}
+	body = append(body, buildUdta(md)...)
+
+	newMoov := makeBox("moov", body)
+	if uint64(len(newMoov)) > 0xFFFFFFFF {
+		return nil, fmt.Errorf("moov too large")
+	}
+
+	// Samples stored after moov move by the size difference
+	delta := int64(len(newMoov)) - int64(moov.end-moov.start)
+	if delta != 0 {
+		if err := shiftChunkOffsets(newMoov, 8, len(newMoov), int64(moov.end), delta); err != nil {
+			return nil, err
+		}
+	}
+
+	out := make([]byte, 0, len(data)+int(delta))
+	out = append(out, data[:moov.start]...)
+	out = append(out, newMoov...)
+	out = append(out, data[moov.end:]...)
+
+	if len(md.Chapters) == 0 {
+		return out, nil
+	}
+	return embedChapterTrack(out, md.Chapters)
+}
+
+// Read extracts metadata written by Embed. Chapters come from the chpl box,
+// which keeps chapters sharing a start time, or else from the chapter track.
+func Read(data []byte) (ports.VideoMetadata, error) {
+	var md ports.VideoMetadata
+
+	top, err := readBoxes(data, 0, len(data))
+	if err != nil {
+		return md, err
+	}
+	moov, ok := findBox(top, "moov")
+	if !ok {
+		return md, fmt.Errorf("no moov box")
+	}
+	children, err := readBoxes(data, moov.bodyStart(), moov.end)
+	if err != nil {
+		return md, fmt.Errorf("moov: %w", err)
+	}
+
+	found := false
+	if udta, ok := findBox(children, "udta"); ok {
+		items, err := readBoxes(data, udta.bodyStart(), udta.end)
+		if err != nil {
+			return md, fmt.Errorf("udta: %w", err)
+		}
+		if meta, ok := findBox(items, "meta"); ok {
+			if err := readMeta(data, meta, &md); err != nil {
+				return md, err
+			}
+			found = md.Title != "" || md.URL != "" || md.Generator != "" || !md.RecordedAt.IsZero() || len(md.Settings) > 0
+		}
+		if chpl, ok := findBox(items, "chpl"); ok {
+			chapters, err := readChapters(data[chpl.bodyStart():chpl.end])
+			if err != nil {
+				return md, err
+			}
+			md.Chapters = chapters
+		}
+	}
+	if len(md.Chapters) == 0 {
+		chapters, err := readChapterTrack(data, top, children)
+		if err != nil {
+			return md, err
+		}
+		md.Chapters = chapters
+	}
+	found = found || len(md.Chapters) > 0
+
+	if !found {
+		return md, ErrNoMetadata
+	}
+	return md, nil
+}
+
+// ReadFile extracts metadata from an MP4 file on disk.
+func ReadFile(path string) (ports.VideoMetadata, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ports.VideoMetadata{}, fmt.Errorf("read file: %w", err)
+	}
+	return Read(data)
+}
+
+// buildUdta encodes the udta box holding the item list and chapters.
+func buildUdta(md ports.VideoMetadata) []byte {
+	var ilst []byte
+	if md.Title != "" {
+		ilst = append(ilst, textItem(itemTitle, md.Title)...)
+	}
+	if !md.RecordedAt.IsZero() {
+		ilst = append(ilst, textItem(itemDate, md.RecordedAt.UTC().Format(time.RFC3339))...)
+	}
+	if md.Generator != "" {
+		ilst = append(ilst, textItem(itemGenerator, md.Generator)...)
+	}
+	if md.URL != "" {
+		ilst = append(ilst, freeformItem(nameURL, md.URL)...)
+	}
+	keys := make([]string, 0, len(md.Settings))
+	for k := range md.Settings {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		ilst = append(ilst, freeformItem(settingNamePrefix+k, md.Settings[k])...)
+	}
+
+	// hdlr: pre_defined, handler type, reserved (vendor "appl" by convention), empty name
+	hdlr := []byte{0, 0, 0, 0}
+	hdlr = append(hdlr, "mdir"...)
+	hdlr = append(hdlr, "appl"...)
+	hdlr = append(hdlr, make([]byte, 8)...)
+	hdlr = append(hdlr, 0)
+
+	meta := fullBox("meta", 0, append(fullBox("hdlr", 0, hdlr), makeBox("ilst", ilst)...))
+
+	udta := meta
+	if len(md.Chapters) > 0 {
+		udta = append(udta, buildChapters(md.Chapters)...)
+	}
+	return makeBox("udta", udta)
+}
+
+// buildChapters encodes a Nero chapter list. Start times are in 100ns units.
+func buildChapters(chapters []ports.Chapter) []byte {
+	if len(chapters) > maxChapters {
+		chapters = chapters[:maxChapters]
+	}
+	body := []byte{0, 0, 0, 0, byte(len(chapters))} // reserved, count
+	for _, c := range chapters {
+		var start [8]byte
+		binary.BigEndian.PutUint64(start[:], uint64(max(c.StartMs, 0))*10000)
+		body = append(body, start[:]...)
+		title := truncateUTF8(c.Title, 255)
+		body = append(body, byte(len(title)))
+		body = append(body, title...)
+	}
+	return fullBox("chpl", 1, body)
+}
+
+func readChapters(body []byte) ([]ports.Chapter, error) {
+	if len(body) < 5 {
+		return nil, fmt.Errorf("chpl: truncated")
+	}
+	pos := 4 // version and flags
+	if body[0] == 1 {
+		pos += 4 // reserved
+	}
+	if pos >= len(body) {
+		return nil, fmt.Errorf("chpl: truncated")
+	}
+	count := int(body[pos])
+	pos++
+
+	chapters := make([]ports.Chapter, 0, count)
+	for i := 0; i < count; i++ {
+		if pos+9 > len(body) {
+			return nil, fmt.Errorf("chpl: truncated chapter %d", i)
+		}
+		start := binary.BigEndian.Uint64(body[pos : pos+8])
+		n := int(body[pos+8])
+		pos += 9
+		if pos+n > len(body) {
+			return nil, fmt.Errorf("chpl: truncated chapter %d", i)
+		}
+		chapters = append(chapters, ports.Chapter{
+			StartMs: int(start / 10000),
+			Title:   string(body[pos : pos+n]),
+		})
+		pos += n
+	}
+	return chapters, nil
+}
+
+// readMeta reads the item list from a meta box into md.
+func readMeta(data []byte, meta boxInfo, md *ports.VideoMetadata) error {
+	start := meta.bodyStart()
+	// QuickTime meta atoms have no version and flags
+	if meta.end-start >= 8 && string(data[start+4:start+8]) != "hdlr" {
+		start += 4
+	}
+	children, err := readBoxes(data, start, meta.end)
+	if err != nil {
+		return fmt.Errorf("meta: %w", err)
+	}
+	ilst, ok := findBox(children, "ilst")
+	if !ok {
+		return nil
+	}
+	items, err := readBoxes(data, ilst.bodyStart(), ilst.end)
+	if err != nil {
+		return fmt.Errorf("ilst: %w", err)
+	}
+
+	for _, item := range items {
+		parts, err := readBoxes(data, item.bodyStart(), item.end)
+		if err != nil {
+			return fmt.Errorf("ilst item %q: %w", item.typ, err)
+		}
+		var value, mean, name string
+		for _, p := range parts {
+			body := data[p.bodyStart():p.end]
+			switch p.typ {
+			case "data":
+				if len(body) >= 8 {
+					value = string(body[8:]) // skip type and locale
+				}
+			case "mean":
+				if len(body) >= 4 {
+					mean = string(body[4:])
+				}
+			case "name":
+				if len(body) >= 4 {
+					name = string(body[4:])
+				}
+			}
+		}
+
+		switch item.typ {
+		case itemTitle:
+			md.Title = value
+		case itemDate:
+			if t, err := time.Parse(time.RFC3339, value); err == nil {
+				md.RecordedAt = t
+			}
+		case itemGenerator:
+			md.Generator = value
+		case itemFreeform:
+			if mean != freeformMean {
+				continue
+			}
+			switch {
+			case name == nameURL:
+				md.URL = value
+			case strings.HasPrefix(name, settingNamePrefix):
+				if md.Settings == nil {
+					md.Settings = make(map[string]string)
+				}
+				md.Settings[strings.TrimPrefix(name, settingNamePrefix)] = value
+			}
+		}
+	}
+	return nil
+}
+
+// textItem encodes an ilst item with a UTF-8 data value.
+func textItem(typ, value string) []byte {
+	return makeBox(typ, dataBox(value))
+}
+
+// freeformItem encodes a ---- item in loadshow's namespace.
+func freeformItem(name, value string) []byte {
+	body := fullBox("mean", 0, []byte(freeformMean))
+	body = append(body, fullBox("name", 0, []byte(name))...)
+	body = append(body, dataBox(value)...)
+	return makeBox(itemFreeform, body)
+}
+
+func dataBox(value string) []byte {
+	body := []byte{0, 0, 0, 1, 0, 0, 0, 0} // type 1 (UTF-8), locale 0
+	return makeBox("data", append(body, value...))
+}
+
+// shiftChunkOffsets adds delta to stco/co64 entries at or beyond threshold,
+// walking the sample tables inside buf[start:end].
+func shiftChunkOffsets(buf []byte, start, end int, threshold, delta int64) error {
+	boxes, err := readBoxes(buf, start, end)
+	if err != nil {
+		return err
+	}
+	for _, b := range boxes {
+		switch b.typ {
+		case "trak", "mdia", "minf", "stbl":
+			if err := shiftChunkOffsets(buf, b.bodyStart(), b.end, threshold, delta); err != nil {
+				return err
+			}
+		case "stco", "co64":
+			body := buf[b.bodyStart():b.end]
+			if len(body) < 8 {
+				return fmt.Errorf("%s: truncated", b.typ)
+			}
+			count := int(binary.BigEndian.Uint32(body[4:8]))
+			size := 4
+			if b.typ == "co64" {
+				size = 8
+			}
+			if 8+count*size > len(body) {
+				return fmt.Errorf("%s: truncated", b.typ)
+			}
+			for i := 0; i < count; i++ {
+				entry := body[8+i*size : 8+(i+1)*size]
+				if size == 4 {
+					off := int64(binary.BigEndian.Uint32(entry))
+					if off >= threshold {
+						if off+delta > 0xFFFFFFFF {
+							return fmt.Errorf("stco: offset overflow")
+						}
+						binary.BigEndian.PutUint32(entry, uint32(off+delta))
+					}
+				} else {
+					off := int64(binary.BigEndian.Uint64(entry))
+					if off >= threshold {
+						binary.BigEndian.PutUint64(entry, uint64(off+delta))
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// boxInfo locates a box within a buffer.
+type boxInfo struct {
+	typ       string
+	start     int // offset of the box header
+	headerLen int
+	end       int // offset just past the box
+}
+
+func (b boxInfo) bodyStart() int {
+	return b.start + b.headerLen
+}
+
+// readBoxes lists the boxes in data[start:end].
+func readBoxes(data []byte, start, end int) ([]boxInfo, error) {
+	var boxes []boxInfo
+	pos := start
+	for pos < end {
+		if pos+8 > end {
+			return nil, fmt.Errorf("truncated box header at %d", pos)
+		}
+		size := int64(binary.BigEndian.Uint32(data[pos : pos+4]))
+		typ := string(data[pos+4 : pos+8])
+		headerLen := 8
+		switch size {
+		case 0: // extends to the end
+			size = int64(end - pos)
+		case 1: // 64-bit size follows
+			if pos+16 > end {
+				return nil, fmt.Errorf("truncated box header at %d", pos)
+			}
+			size = int64(binary.BigEndian.Uint64(data[pos+8 : pos+16]))
+			headerLen = 16
+		}
+		if size < int64(headerLen) || int64(pos)+size > int64(end) {
+			return nil, fmt.Errorf("invalid %q box size %d at %d", typ, size, pos)
+		}
+		boxes = append(boxes, boxInfo{typ: typ, start: pos, headerLen: headerLen, end: pos + int(size)})
+		pos += int(size)
+	}
+	return boxes, nil
+}
+
+func findBox(boxes []boxInfo, typ string) (boxInfo, bool) {
+	for _, b := range boxes {
+		if b.typ == typ {
+			return b, true
+		}
+	}
+	return boxInfo{}, false
+}
+
+func makeBox(typ string, body []byte) []byte {
+	out := make([]byte, 8, 8+len(body))
+	binary.BigEndian.PutUint32(out[0:4], uint32(8+len(body)))
+	copy(out[4:8], typ)
+	return append(out, body...)
+}
+
+func fullBox(typ string, version byte, body []byte) []byte {
+	return fullBoxWithFlags(typ, version, 0, body)
+}
+
+func fullBoxWithFlags(typ string, version byte, flags uint32, body []byte) []byte {
+	header := []byte{version, byte(flags >> 16), byte(flags >> 8), byte(flags)}
+	return makeBox(typ, append(header, body...))
+}
+
+// truncateUTF8 shortens s to at most n bytes without splitting a rune.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && (s[n]&0xC0) == 0x80 {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,278 @@
+package mp4meta
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/user/loadshow/pkg/adapters/mjpegdecoder"
+	"github.com/user/loadshow/pkg/adapters/mjpegencoder"
+	"github.com/user/loadshow/pkg/ports"
+)
+
+func testMetadata() ports.VideoMetadata {
+	return ports.VideoMetadata{
+		Title:      "Example Domain – ページ",
+		URL:        "https://example.com/",
+		RecordedAt: time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC),
+		Generator:  "loadshow 1.2.0",
+		Settings: map[string]string{
+			"viewport": "375",
+			"crf":      "30",
+		},
+		Chapters: []ports.Chapter{
+			{StartMs: 0, Title: "Start"},
+			{StartMs: 850, Title: "DOMContentLoaded"},
+			{StartMs: 1420, Title: "Load"},
+		},
+	}
+}
+
+func encodeMJPEG(t *testing.T) []byte {
+	t.Helper()
+	enc := mjpegencoder.New()
+	if err := enc.Begin(32, 32, 30, ports.EncoderOptions{}); err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	for _, ts := range []int{0, 100, 200} {
+		if err := enc.EncodeFrame(image.NewRGBA(image.Rect(0, 0, 32, 32)), ts); err != nil {
+			t.Fatalf("EncodeFrame failed: %v", err)
+		}
+	}
+	data, err := enc.End()
+	if err != nil {
+		t.Fatalf("End failed: %v", err)
+	}
+	return data
+}
+
+func TestEmbedRead_RoundTrip(t *testing.T) {
+	data := encodeMJPEG(t)
+	want := testMetadata()
+
+	out, err := Embed(data, want)
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+
+	got, err := Read(out)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Read = %+v, want %+v", got, want)
+	}
+
+	// The video must still decode
+	frames, err := mjpegdecoder.NewMP4Reader().ReadFramesFromReader(bytes.NewReader(out))
+	if err != nil {
+		t.Fatalf("decode after Embed failed: %v", err)
+	}
+	if len(frames) != 3 {
+		t.Errorf("decoded %d frames, want 3", len(frames))
+	}
+}
+
+func TestEmbed_ReplacesExisting(t *testing.T) {
+	data := encodeMJPEG(t)
+
+	first, err := Embed(data, testMetadata())
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	second, err := Embed(first, ports.VideoMetadata{Title: "Second"})
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+
+	got, err := Read(second)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if got.Title != "Second" || got.URL != "" || len(got.Chapters) != 0 {
+		t.Errorf("metadata not replaced: %+v", got)
+	}
+	if bytes.Count(second, []byte("udta")) != 1 {
+		t.Error("expected exactly one udta box")
+	}
+}
+
+func TestRead_NoMetadata(t *testing.T) {
+	if _, err := Read(encodeMJPEG(t)); !errors.Is(err, ErrNoMetadata) {
+		t.Errorf("expected ErrNoMetadata, got %v", err)
+	}
+	if _, err := Read([]byte("not an mp4")); err == nil {
+		t.Error("expected error for invalid data")
+	}
+}
+
+// progressiveMP4 builds ftyp + moov (with stco) + mdat, the layout of a
+// faststart file. The stco entry points at the mdat payload.
+func progressiveMP4() []byte {
+	ftyp := makeBox("ftyp", []byte("isom\x00\x00\x02\x00isom"))
+
+	stco := func(offset uint32) []byte {
+		body := make([]byte, 8)
+		binary.BigEndian.PutUint32(body[4:8], 1)
+		body = binary.BigEndian.AppendUint32(body, offset)
+		return makeBox("stco", body)
+	}
+	mvhd := make([]byte, 100)
+	binary.BigEndian.PutUint32(mvhd[12:16], 1000) // timescale
+	binary.BigEndian.PutUint32(mvhd[96:100], 2)   // next_track_ID
+	tkhd := make([]byte, 84)
+	binary.BigEndian.PutUint32(tkhd[12:16], 1) // track_ID
+
+	moovWith := func(offset uint32) []byte {
+		stbl := makeBox("stbl", stco(offset))
+		trak := append(makeBox("tkhd", tkhd), makeBox("mdia", makeBox("minf", stbl))...)
+		return makeBox("moov", append(makeBox("mvhd", mvhd), makeBox("trak", trak)...))
+	}
+
+	moovLen := len(moovWith(0))
+	payloadOffset := uint32(len(ftyp) + moovLen + 8)
+
+	out := append([]byte{}, ftyp...)
+	out = append(out, moovWith(payloadOffset)...)
+	out = append(out, makeBox("mdat", []byte("SAMPLE"))...)
+	return out
+}
+
+func TestEmbed_ShiftsChunkOffsets(t *testing.T) {
+	data := progressiveMP4()
+
+	out, err := Embed(data, testMetadata())
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+
+	i := bytes.Index(out, []byte("stco"))
+	if i < 0 {
+		t.Fatal("stco not found")
+	}
+	offset := binary.BigEndian.Uint32(out[i+12 : i+16])
+	if got := string(out[offset : offset+6]); got != "SAMPLE" {
+		t.Errorf("chunk offset %d points at %q, want SAMPLE", offset, got)
+	}
+
+	if _, err := Read(out); err != nil {
+		t.Errorf("Read failed: %v", err)
+	}
+}
+
+// chapterTrack returns the chapter track IDs and the chapters read from the track.
+func chapterTrack(t *testing.T, data []byte) ([]uint32, []ports.Chapter) {
+	t.Helper()
+	top, err := readBoxes(data, 0, len(data))
+	if err != nil {
+		t.Fatalf("readBoxes failed: %v", err)
+	}
+	moov, _ := findBox(top, "moov")
+	children, err := readBoxes(data, moov.bodyStart(), moov.end)
+	if err != nil {
+		t.Fatalf("readBoxes failed: %v", err)
+	}
+	ids, err := chapterTrackIDs(data, children)
+	if err != nil {
+		t.Fatalf("chapterTrackIDs failed: %v", err)
+	}
+	chapters, err := readChapterTrack(data, top, children)
+	if err != nil {
+		t.Fatalf("readChapterTrack failed: %v", err)
+	}
+	return ids, chapters
+}
+
+func TestEmbed_ChapterTrack(t *testing.T) {
+	want := testMetadata().Chapters
+
+	for name, data := range map[string][]byte{
+		"fragmented":  encodeMJPEG(t),
+		"progressive": progressiveMP4(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			out, err := Embed(data, testMetadata())
+			if err != nil {
+				t.Fatalf("Embed failed: %v", err)
+			}
+			ids, got := chapterTrack(t, out)
+			if len(ids) != 1 {
+				t.Fatalf("got chapter track IDs %v, want one", ids)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("chapter track = %+v, want %+v", got, want)
+			}
+
+			// The chapter track is not mistaken for subtitles
+			if _, err := ReadSubtitles(out); !errors.Is(err, ErrNoSubtitles) {
+				t.Errorf("expected ErrNoSubtitles, got %v", err)
+			}
+
+			// Embedding again replaces the track
+			again, err := Embed(out, testMetadata())
+			if err != nil {
+				t.Fatalf("Embed failed: %v", err)
+			}
+			if n := bytes.Count(again, []byte(chapterName)); n != 1 {
+				t.Errorf("found %d chapter tracks after embedding twice, want 1", n)
+			}
+			if _, got := chapterTrack(t, again); !reflect.DeepEqual(got, want) {
+				t.Errorf("chapter track after embedding twice = %+v, want %+v", got, want)
+			}
+		})
+	}
+
+	// The video must still decode
+	out, _ := Embed(encodeMJPEG(t), testMetadata())
+	out, _ = Embed(out, testMetadata())
+	frames, err := mjpegdecoder.NewMP4Reader().ReadFramesFromReader(bytes.NewReader(out))
+	if err != nil {
+		t.Fatalf("decode after Embed failed: %v", err)
+	}
+	if len(frames) != 3 {
+		t.Errorf("decoded %d frames, want 3", len(frames))
+	}
+}
+
+func TestRead_ChapterTrackOnly(t *testing.T) {
+	want := testMetadata().Chapters
+
+	out, err := embedChapterTrack(encodeMJPEG(t), want)
+	if err != nil {
+		t.Fatalf("embedChapterTrack failed: %v", err)
+	}
+	got, err := Read(out)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if !reflect.DeepEqual(got.Chapters, want) {
+		t.Errorf("Read chapters = %+v, want %+v", got.Chapters, want)
+	}
+}
+
+func TestChapters_Truncation(t *testing.T) {
+	long := string(bytes.Repeat([]byte("あ"), 100)) // 300 bytes
+	chapters := make([]ports.Chapter, 300)
+	for i := range chapters {
+		chapters[i] = ports.Chapter{StartMs: i, Title: long}
+	}
+
+	box := buildChapters(chapters)
+	got, err := readChapters(box[8:])
+	if err != nil {
+		t.Fatalf("readChapters failed: %v", err)
+	}
+	if len(got) != maxChapters {
+		t.Errorf("got %d chapters, want %d", len(got), maxChapters)
+	}
+	if len(got[0].Title) != 255 {
+		t.Errorf("title length = %d, want 255", len(got[0].Title))
+	}
+	if got[10].StartMs != 10 {
+		t.Errorf("chapter 10 start = %d, want 10", got[10].StartMs)
+	}
+}
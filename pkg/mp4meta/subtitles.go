@@ -0,0 +1,753 @@
+package mp4meta
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/user/loadshow/pkg/ports"
+)
+
+// ErrNoSubtitles is returned by ReadSubtitles when the file has no text track.
+var ErrNoSubtitles = errors.New("mp4meta: no subtitle track found")
+
+// Text track parameters
+const (
+	subtitleTimescale = 1000
+	subtitleHandler   = "sbtl"
+	subtitleName      = "Milestones"
+	subtitleFont      = "Sans-Serif"
+	subtitleFontSize  = 18
+)
+
+// textTrackKind describes what an added text track is for.
+type textTrackKind struct {
+	handler string
+	name    string
+	enabled bool // Shown by players; chapter tracks are only referenced
+	chapter bool // Referenced from the first track as its chapter list
+}
+
+var subtitleKind = textTrackKind{handler: subtitleHandler, name: subtitleName, enabled: true}
+
+// textSample is one tx3g sample: a cue, or an empty sample filling a gap.
+type textSample struct {
+	durMs int
+	data  []byte
+}
+
+// EmbedSubtitles returns a copy of an MP4 file with cues added as a 3GPP
+// timed text (tx3g) track, which players show as toggleable subtitles.
+// Both progressive files and fragmented files are supported: the samples go
+// in a new mdat (progressive) or a new fragment (fragmented) at the end.
+func EmbedSubtitles(data []byte, cues []ports.Cue) ([]byte, error) {
+	samples := textSamples(cues)
+	if len(samples) == 0 {
+		return data, nil
+	}
+	return addTextTrack(data, samples, subtitleKind)
+}
+
+// addTextTrack returns a copy of an MP4 file with a tx3g track holding samples.
+func addTextTrack(data []byte, samples []textSample, kind textTrackKind) ([]byte, error) {
+	top, err := readBoxes(data, 0, len(data))
+	if err != nil {
+		return nil, err
+	}
+	moov, ok := findBox(top, "moov")
+	if !ok {
+		return nil, fmt.Errorf("no moov box")
+	}
+	children, err := readBoxes(data, moov.bodyStart(), moov.end)
+	if err != nil {
+		return nil, fmt.Errorf("moov: %w", err)
+	}
+
+	mvhd, ok := findBox(children, "mvhd")
+	if !ok {
+		return nil, fmt.Errorf("no mvhd box")
+	}
+	movieTimescale, err := readTimescale(data[mvhd.bodyStart():mvhd.end])
+	if err != nil {
+		return nil, fmt.Errorf("mvhd: %w", err)
+	}
+	trackID := binary.BigEndian.Uint32(data[mvhd.end-4 : mvhd.end]) // next_track_ID
+	width, height, err := videoSize(data, children)
+	if err != nil {
+		return nil, err
+	}
+	_, fragmented := findBox(children, "mvex")
+
+	var totalMs int
+	for _, s := range samples {
+		totalMs += s.durMs
+	}
+	track := textTrack{
+		kind:       kind,
+		id:         trackID,
+		width:      width,
+		height:     height,
+		durationMs: totalMs,
+		samples:    samples,
+		fragmented: fragmented,
+	}
+	if !fragmented {
+		track.movieDuration = uint32(int64(totalMs) * int64(movieTimescale) / 1000)
+	}
+
+	firstTrak, lastTrak := -1, -1
+	for i, c := range children {
+		if c.typ == "trak" {
+			if firstTrak < 0 {
+				firstTrak = i
+			}
+			lastTrak = i
+		}
+	}
+	var chapterRef []byte
+	if kind.chapter {
+		if chapterRef, err = withChapterRef(data, children[firstTrak], trackID); err != nil {
+			return nil, err
+		}
+	}
+
+	buildMoov := func() []byte {
+		var body []byte
+		for i, c := range children {
+			switch {
+			case i == firstTrak && chapterRef != nil:
+				body = append(body, chapterRef...)
+			case c.typ == "mvhd":
+				// Reserve the next track ID
+				b := append([]byte{}, data[c.start:c.end]...)
+				binary.BigEndian.PutUint32(b[len(b)-4:], trackID+1)
+				body = append(body, b...)
+			case c.typ == "mvex":
+				mvex := append([]byte{}, data[c.bodyStart():c.end]...)
+				body = append(body, makeBox("mvex", append(mvex, trex(trackID)...))...)
+			default:
+				body = append(body, data[c.start:c.end]...)
+			}
+			if i == lastTrak {
+				body = append(body, track.build()...)
+			}
+		}
+		return makeBox("moov", body)
+	}
+
+	newMoov := buildMoov()
+	delta := int64(len(newMoov)) - int64(moov.end-moov.start)
+
+	var tail []byte
+	if fragmented {
+		tail = textFragment(trackID, countBoxes(top, "moof")+1, samples)
+	} else {
+		// Samples go in an mdat appended after everything else. The offset is
+		// relative to the original layout and is shifted by delta with the rest.
+		offset := int64(len(data)) + 8
+		if offset+delta > 0xFFFFFFFF {
+			return nil, fmt.Errorf("stco: offset overflow")
+		}
+		track.chunkOffset = uint32(offset)
+		newMoov = buildMoov()
+
+		var payload []byte
+		for _, s := range samples {
+			payload = append(payload, s.data...)
+		}
+		tail = makeBox("mdat", payload)
+	}
+	if uint64(len(newMoov)) > 0xFFFFFFFF {
+		return nil, fmt.Errorf("moov too large")
+	}
+
+	// Samples that follow moov move by the size difference
+	if delta != 0 {
+		if err := shiftChunkOffsets(newMoov, 8, len(newMoov), int64(moov.end), delta); err != nil {
+			return nil, err
+		}
+	}
+
+	out := make([]byte, 0, len(data)+len(newMoov)-(moov.end-moov.start)+len(tail))
+	out = append(out, data[:moov.start]...)
+	out = append(out, newMoov...)
+	out = append(out, data[moov.end:]...)
+	out = append(out, tail...)
+	return out, nil
+}
+
+// textSamples converts cues into contiguous tx3g samples. Cues are sorted by
+// start time and clipped so they do not overlap; gaps become empty samples.
+func textSamples(cues []ports.Cue) []textSample {
+	sorted := append([]ports.Cue{}, cues...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].StartMs < sorted[j].StartMs
+	})
+
+	var samples []textSample
+	t := 0
+	for i, c := range sorted {
+		start := max(c.StartMs, t)
+		end := c.EndMs
+		if i+1 < len(sorted) && sorted[i+1].StartMs < end {
+			end = sorted[i+1].StartMs
+		}
+		if end <= start || c.Text == "" {
+			continue
+		}
+		if start > t {
+			samples = append(samples, textSample{durMs: start - t, data: []byte{0, 0}})
+		}
+		text := truncateUTF8(c.Text, 0xFFFF)
+		sample := binary.BigEndian.AppendUint16(nil, uint16(len(text)))
+		samples = append(samples, textSample{durMs: end - start, data: append(sample, text...)})
+		t = end
+	}
+	return samples
+}
+
+// readTimescale returns the timescale field of an mvhd or mdhd box body.
+func readTimescale(body []byte) (uint32, error) {
+	offset := 12 // version/flags, creation and modification times
+	if len(body) > 0 && body[0] == 1 {
+		offset = 20
+	}
+	if len(body) < offset+4 {
+		return 0, fmt.Errorf("truncated")
+	}
+	return binary.BigEndian.Uint32(body[offset : offset+4]), nil
+}
+
+// videoSize returns the width and height (16.16 fixed point) of the first track.
+func videoSize(data []byte, moovChildren []boxInfo) (width, height uint32, err error) {
+	trak, ok := findBox(moovChildren, "trak")
+	if !ok {
+		return 0, 0, fmt.Errorf("no video track")
+	}
+	boxes, err := readBoxes(data, trak.bodyStart(), trak.end)
+	if err != nil {
+		return 0, 0, fmt.Errorf("trak: %w", err)
+	}
+	tkhd, ok := findBox(boxes, "tkhd")
+	if !ok || tkhd.end-tkhd.bodyStart() < 8 {
+		return 0, 0, fmt.Errorf("no tkhd box")
+	}
+	return binary.BigEndian.Uint32(data[tkhd.end-8 : tkhd.end-4]), binary.BigEndian.Uint32(data[tkhd.end-4 : tkhd.end]), nil
+}
+
+func countBoxes(boxes []boxInfo, typ string) uint32 {
+	var n uint32
+	for _, b := range boxes {
+		if b.typ == typ {
+			n++
+		}
+	}
+	return n
+}
+
+// textTrack builds the trak box of a subtitle or chapter track.
+type textTrack struct {
+	kind          textTrackKind
+	id            uint32
+	width, height uint32 // 16.16 fixed point, copied from the video track
+	durationMs    int
+	movieDuration uint32 // In the movie timescale (0 for fragmented files)
+	samples       []textSample
+	fragmented    bool   // Samples are in a fragment, sample tables stay empty
+	chunkOffset   uint32 // Offset of the samples for progressive files
+}
+
+func (t textTrack) build() []byte {
+	// tkhd flags: in movie, in preview, and enabled unless only referenced
+	flags := uint32(0x6)
+	if t.kind.enabled {
+		flags |= 0x1
+	}
+	tkhd := make([]byte, 0, 80)
+	tkhd = append(tkhd, make([]byte, 8)...) // creation, modification
+	tkhd = binary.BigEndian.AppendUint32(tkhd, t.id)
+	tkhd = append(tkhd, 0, 0, 0, 0) // reserved
+	tkhd = binary.BigEndian.AppendUint32(tkhd, t.movieDuration)
+	tkhd = append(tkhd, make([]byte, 8)...)     // reserved
+	tkhd = append(tkhd, 0, 0, 0, 0, 0, 0, 0, 0) // layer, alternate group, volume, reserved
+	for _, v := range []uint32{0x00010000, 0, 0, 0, 0x00010000, 0, 0, 0, 0x40000000} {
+		tkhd = binary.BigEndian.AppendUint32(tkhd, v)
+	}
+	tkhd = binary.BigEndian.AppendUint32(tkhd, t.width)
+	tkhd = binary.BigEndian.AppendUint32(tkhd, t.height)
+
+	mdhdDuration := uint32(t.durationMs)
+	if t.fragmented {
+		mdhdDuration = 0
+	}
+	mdhd := make([]byte, 8) // creation, modification
+	mdhd = binary.BigEndian.AppendUint32(mdhd, subtitleTimescale)
+	mdhd = binary.BigEndian.AppendUint32(mdhd, mdhdDuration)
+	mdhd = append(mdhd, 0x55, 0xC4, 0, 0) // language "und", pre_defined
+
+	hdlr := []byte{0, 0, 0, 0}
+	hdlr = append(hdlr, t.kind.handler...)
+	hdlr = append(hdlr, make([]byte, 12)...)
+	hdlr = append(hdlr, t.kind.name+"\x00"...)
+
+	dref := fullBox("dref", 0, append([]byte{0, 0, 0, 1}, fullBoxWithFlags("url ", 0, 1, nil)...))
+
+	minf := fullBox("nmhd", 0, nil)
+	minf = append(minf, makeBox("dinf", dref)...)
+	minf = append(minf, t.sampleTable()...)
+
+	mdia := fullBox("mdhd", 0, mdhd)
+	mdia = append(mdia, fullBox("hdlr", 0, hdlr)...)
+	mdia = append(mdia, makeBox("minf", minf)...)
+
+	trak := fullBoxWithFlags("tkhd", 0, flags, tkhd)
+	trak = append(trak, makeBox("mdia", mdia)...)
+	return makeBox("trak", trak)
+}
+
+func (t textTrack) sampleTable() []byte {
+	stbl := fullBox("stsd", 0, append([]byte{0, 0, 0, 1}, tx3gSampleEntry()...))
+
+	if t.fragmented {
+		stbl = append(stbl, fullBox("stts", 0, []byte{0, 0, 0, 0})...)
+		stbl = append(stbl, fullBox("stsc", 0, []byte{0, 0, 0, 0})...)
+		stbl = append(stbl, fullBox("stsz", 0, make([]byte, 8))...)
+		stbl = append(stbl, fullBox("stco", 0, []byte{0, 0, 0, 0})...)
+		return makeBox("stbl", stbl)
+	}
+
+	n := uint32(len(t.samples))
+	stts := binary.BigEndian.AppendUint32(nil, n)
+	stsz := binary.BigEndian.AppendUint32(make([]byte, 4), n) // sample_size 0, count
+	for _, s := range t.samples {
+		stts = binary.BigEndian.AppendUint32(stts, 1)
+		stts = binary.BigEndian.AppendUint32(stts, uint32(s.durMs))
+		stsz = binary.BigEndian.AppendUint32(stsz, uint32(len(s.data)))
+	}
+	// All samples in a single chunk
+	stsc := []byte{0, 0, 0, 1, 0, 0, 0, 1}
+	stsc = binary.BigEndian.AppendUint32(stsc, n)
+	stsc = append(stsc, 0, 0, 0, 1)
+	stco := binary.BigEndian.AppendUint32([]byte{0, 0, 0, 1}, t.chunkOffset)
+
+	stbl = append(stbl, fullBox("stts", 0, stts)...)
+	stbl = append(stbl, fullBox("stsc", 0, stsc)...)
+	stbl = append(stbl, fullBox("stsz", 0, stsz)...)
+	stbl = append(stbl, fullBox("stco", 0, stco)...)
+	return makeBox("stbl", stbl)
+}
+
+// tx3gSampleEntry describes white, bottom-centred text (3GPP TS 26.245).
+func tx3gSampleEntry() []byte {
+	body := make([]byte, 6)                 // reserved
+	body = append(body, 0, 1)               // data_reference_index
+	body = append(body, 0, 0, 0, 0)         // displayFlags
+	body = append(body, 1, 0xFF)            // horizontal centre, vertical bottom
+	body = append(body, 0, 0, 0, 0)         // background color
+	body = append(body, make([]byte, 8)...) // default text box
+	// Style record: startChar, endChar, font ID, face, size, color
+	body = append(body, 0, 0, 0, 0, 0, 1, 0, subtitleFontSize, 0xFF, 0xFF, 0xFF, 0xFF)
+
+	ftab := []byte{0, 1, 0, 1, byte(len(subtitleFont))}
+	ftab = append(ftab, subtitleFont...)
+	body = append(body, makeBox("ftab", ftab)...)
+	return makeBox("tx3g", body)
+}
+
+// trex sets the fragment defaults for the text track.
+func trex(trackID uint32) []byte {
+	body := binary.BigEndian.AppendUint32(nil, trackID)
+	body = append(body, 0, 0, 0, 1) // default sample description index
+	body = append(body, make([]byte, 12)...)
+	return fullBox("trex", 0, body)
+}
+
+// textFragment builds a moof+mdat holding all text samples from time zero.
+func textFragment(trackID, sequence uint32, samples []textSample) []byte {
+	const trunFlags = 0x000001 | 0x000100 | 0x000200 // data offset, durations, sizes
+
+	build := func(dataOffset uint32) []byte {
+		tfhd := binary.BigEndian.AppendUint32(nil, trackID)
+		tfdt := make([]byte, 8) // baseMediaDecodeTime
+
+		trun := binary.BigEndian.AppendUint32(nil, uint32(len(samples)))
+		trun = binary.BigEndian.AppendUint32(trun, dataOffset)
+		for _, s := range samples {
+			trun = binary.BigEndian.AppendUint32(trun, uint32(s.durMs))
+			trun = binary.BigEndian.AppendUint32(trun, uint32(len(s.data)))
+		}
+
+		traf := fullBoxWithFlags("tfhd", 0, 0x020000, tfhd) // default-base-is-moof
+		traf = append(traf, fullBox("tfdt", 1, tfdt)...)
+		traf = append(traf, fullBoxWithFlags("trun", 0, trunFlags, trun)...)
+
+		moof := fullBox("mfhd", 0, binary.BigEndian.AppendUint32(nil, sequence))
+		moof = append(moof, makeBox("traf", traf)...)
+		return makeBox("moof", moof)
+	}
+
+	moof := build(0)
+	moof = build(uint32(len(moof) + 8))
+
+	var payload []byte
+	for _, s := range samples {
+		payload = append(payload, s.data...)
+	}
+	return append(moof, makeBox("mdat", payload)...)
+}
+
+// ReadSubtitles extracts the cues of the first text track of an MP4 file.
+// Chapter tracks are skipped.
+func ReadSubtitles(data []byte) ([]ports.Cue, error) {
+	top, err := readBoxes(data, 0, len(data))
+	if err != nil {
+		return nil, err
+	}
+	moov, ok := findBox(top, "moov")
+	if !ok {
+		return nil, fmt.Errorf("no moov box")
+	}
+	children, err := readBoxes(data, moov.bodyStart(), moov.end)
+	if err != nil {
+		return nil, fmt.Errorf("moov: %w", err)
+	}
+	chapterIDs, err := chapterTrackIDs(data, children)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range children {
+		if c.typ != "trak" {
+			continue
+		}
+		trak, err := parseTrak(data, c)
+		if err != nil {
+			return nil, err
+		}
+		if trak.handler != subtitleHandler && trak.handler != "text" || slices.Contains(chapterIDs, trak.id) {
+			continue
+		}
+
+		samples, err := trak.allSamples(data, top)
+		if err != nil {
+			return nil, err
+		}
+		return samplesToCues(data, samples, trak.timescale), nil
+	}
+	return nil, ErrNoSubtitles
+}
+
+// sampleRef locates a sample in the file.
+type sampleRef struct {
+	time   uint64
+	dur    uint32
+	offset int64
+	size   uint32
+}
+
+type trakInfo struct {
+	id        uint32
+	handler   string
+	timescale uint32
+	samples   []sampleRef // From the sample tables (empty for fragmented files)
+}
+
+// allSamples returns the samples from the sample tables, or from the
+// fragments of a fragmented file.
+func (t trakInfo) allSamples(data []byte, top []boxInfo) ([]sampleRef, error) {
+	if len(t.samples) > 0 {
+		return t.samples, nil
+	}
+	return fragmentSamples(data, top, t.id)
+}
+
+func parseTrak(data []byte, trak boxInfo) (trakInfo, error) {
+	var info trakInfo
+	boxes, err := readBoxes(data, trak.bodyStart(), trak.end)
+	if err != nil {
+		return info, fmt.Errorf("trak: %w", err)
+	}
+
+	if tkhd, ok := findBox(boxes, "tkhd"); ok {
+		body := data[tkhd.bodyStart():tkhd.end]
+		offset := 12
+		if len(body) > 0 && body[0] == 1 {
+			offset = 20
+		}
+		if len(body) >= offset+4 {
+			info.id = binary.BigEndian.Uint32(body[offset : offset+4])
+		}
+	}
+
+	mdia, ok := findBox(boxes, "mdia")
+	if !ok {
+		return info, nil
+	}
+	mdiaBoxes, err := readBoxes(data, mdia.bodyStart(), mdia.end)
+	if err != nil {
+		return info, fmt.Errorf("mdia: %w", err)
+	}
+	if hdlr, ok := findBox(mdiaBoxes, "hdlr"); ok && hdlr.end-hdlr.bodyStart() >= 12 {
+		info.handler = string(data[hdlr.bodyStart()+8 : hdlr.bodyStart()+12])
+	}
+	if mdhd, ok := findBox(mdiaBoxes, "mdhd"); ok {
+		info.timescale, err = readTimescale(data[mdhd.bodyStart():mdhd.end])
+		if err != nil {
+			return info, fmt.Errorf("mdhd: %w", err)
+		}
+	}
+
+	minf, ok := findBox(mdiaBoxes, "minf")
+	if !ok {
+		return info, nil
+	}
+	minfBoxes, err := readBoxes(data, minf.bodyStart(), minf.end)
+	if err != nil {
+		return info, fmt.Errorf("minf: %w", err)
+	}
+	stbl, ok := findBox(minfBoxes, "stbl")
+	if !ok {
+		return info, nil
+	}
+	info.samples, err = tableSamples(data, stbl)
+	return info, err
+}
+
+// tableSamples resolves sample times and positions from stts/stsc/stsz/stco.
+func tableSamples(data []byte, stbl boxInfo) ([]sampleRef, error) {
+	boxes, err := readBoxes(data, stbl.bodyStart(), stbl.end)
+	if err != nil {
+		return nil, fmt.Errorf("stbl: %w", err)
+	}
+	table := func(typ string, entrySize int) ([]byte, int, error) {
+		b, ok := findBox(boxes, typ)
+		if !ok {
+			return nil, 0, nil
+		}
+		body := data[b.bodyStart():b.end]
+		header := 8
+		if typ == "stsz" {
+			header = 12
+		}
+		if len(body) < header {
+			return nil, 0, fmt.Errorf("%s: truncated", typ)
+		}
+		n := int(binary.BigEndian.Uint32(body[header-4 : header]))
+		if header+n*entrySize > len(body) {
+			return nil, 0, fmt.Errorf("%s: truncated", typ)
+		}
+		return body[header:], n, nil
+	}
+
+	stsz, count, err := table("stsz", 4)
+	if err != nil || count == 0 {
+		return nil, err
+	}
+	stts, nStts, err := table("stts", 8)
+	if err != nil {
+		return nil, err
+	}
+	stsc, nStsc, err := table("stsc", 12)
+	if err != nil {
+		return nil, err
+	}
+	chunkSize := 4
+	chunks, nChunks, err := table("stco", 4)
+	if err == nil && chunks == nil {
+		chunkSize = 8
+		chunks, nChunks, err = table("co64", 8)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]sampleRef, count)
+	for i := range samples {
+		samples[i].size = binary.BigEndian.Uint32(stsz[i*4:])
+	}
+
+	// Durations
+	i := 0
+	var t uint64
+	for e := 0; e < nStts && i < count; e++ {
+		n := int(binary.BigEndian.Uint32(stts[e*8:]))
+		dur := binary.BigEndian.Uint32(stts[e*8+4:])
+		for k := 0; k < n && i < count; k++ {
+			samples[i].time, samples[i].dur = t, dur
+			t += uint64(dur)
+			i++
+		}
+	}
+
+	// Offsets
+	i = 0
+	for e := 0; e < nStsc && i < count; e++ {
+		first := int(binary.BigEndian.Uint32(stsc[e*12:]))
+		perChunk := int(binary.BigEndian.Uint32(stsc[e*12+4:]))
+		last := nChunks + 1
+		if e+1 < nStsc {
+			last = int(binary.BigEndian.Uint32(stsc[(e+1)*12:]))
+		}
+		for chunk := first; chunk < last && chunk <= nChunks && i < count; chunk++ {
+			var offset int64
+			if chunkSize == 4 {
+				offset = int64(binary.BigEndian.Uint32(chunks[(chunk-1)*4:]))
+			} else {
+				offset = int64(binary.BigEndian.Uint64(chunks[(chunk-1)*8:]))
+			}
+			for k := 0; k < perChunk && i < count; k++ {
+				samples[i].offset = offset
+				offset += int64(samples[i].size)
+				i++
+			}
+		}
+	}
+	return samples, nil
+}
+
+// fragmentSamples collects the samples of a track from all moof boxes.
+func fragmentSamples(data []byte, top []boxInfo, trackID uint32) ([]sampleRef, error) {
+	var samples []sampleRef
+	for _, moof := range top {
+		if moof.typ != "moof" {
+			continue
+		}
+		boxes, err := readBoxes(data, moof.bodyStart(), moof.end)
+		if err != nil {
+			return nil, fmt.Errorf("moof: %w", err)
+		}
+		for _, traf := range boxes {
+			if traf.typ != "traf" {
+				continue
+			}
+			s, err := trafSamples(data, moof, traf, trackID)
+			if err != nil {
+				return nil, err
+			}
+			samples = append(samples, s...)
+		}
+	}
+	return samples, nil
+}
+
+func trafSamples(data []byte, moof, traf boxInfo, trackID uint32) ([]sampleRef, error) {
+	boxes, err := readBoxes(data, traf.bodyStart(), traf.end)
+	if err != nil {
+		return nil, fmt.Errorf("traf: %w", err)
+	}
+	tfhd, ok := findBox(boxes, "tfhd")
+	if !ok {
+		return nil, nil
+	}
+	body := data[tfhd.bodyStart():tfhd.end]
+	if len(body) < 8 || binary.BigEndian.Uint32(body[4:8]) != trackID {
+		return nil, nil
+	}
+
+	flags := binary.BigEndian.Uint32(body[0:4]) & 0xFFFFFF
+	base := int64(moof.start)
+	var defaultDur, defaultSize uint32
+	fields := body[8:]
+	read := func(n int) []byte {
+		if len(fields) < n {
+			return make([]byte, n)
+		}
+		v := fields[:n]
+		fields = fields[n:]
+		return v
+	}
+	if flags&0x01 != 0 {
+		base = int64(binary.BigEndian.Uint64(read(8)))
+	}
+	if flags&0x02 != 0 {
+		read(4)
+	}
+	if flags&0x08 != 0 {
+		defaultDur = binary.BigEndian.Uint32(read(4))
+	}
+	if flags&0x10 != 0 {
+		defaultSize = binary.BigEndian.Uint32(read(4))
+	}
+
+	var t uint64
+	if tfdt, ok := findBox(boxes, "tfdt"); ok {
+		b := data[tfdt.bodyStart():tfdt.end]
+		if len(b) >= 12 && b[0] == 1 {
+			t = binary.BigEndian.Uint64(b[4:12])
+		} else if len(b) >= 8 {
+			t = uint64(binary.BigEndian.Uint32(b[4:8]))
+		}
+	}
+
+	var samples []sampleRef
+	offset := base
+	for _, trun := range boxes {
+		if trun.typ != "trun" {
+			continue
+		}
+		b := data[trun.bodyStart():trun.end]
+		if len(b) < 8 {
+			return nil, fmt.Errorf("trun: truncated")
+		}
+		flags := binary.BigEndian.Uint32(b[0:4]) & 0xFFFFFF
+		count := int(binary.BigEndian.Uint32(b[4:8]))
+		pos := 8
+		if flags&0x001 != 0 && len(b) >= pos+4 {
+			offset = base + int64(int32(binary.BigEndian.Uint32(b[pos:])))
+			pos += 4
+		}
+		if flags&0x004 != 0 {
+			pos += 4
+		}
+		for i := 0; i < count; i++ {
+			s := sampleRef{time: t, dur: defaultDur, size: defaultSize, offset: offset}
+			for _, f := range []uint32{0x100, 0x200, 0x400, 0x800} {
+				if flags&f == 0 {
+					continue
+				}
+				if len(b) < pos+4 {
+					return nil, fmt.Errorf("trun: truncated")
+				}
+				v := binary.BigEndian.Uint32(b[pos:])
+				pos += 4
+				switch f {
+				case 0x100:
+					s.dur = v
+				case 0x200:
+					s.size = v
+				}
+			}
+			samples = append(samples, s)
+			t += uint64(s.dur)
+			offset += int64(s.size)
+		}
+	}
+	return samples, nil
+}
+
+// samplesToCues decodes tx3g samples, skipping the empty ones.
+func samplesToCues(data []byte, samples []sampleRef, timescale uint32) []ports.Cue {
+	if timescale == 0 {
+		timescale = subtitleTimescale
+	}
+	var cues []ports.Cue
+	for _, s := range samples {
+		end := s.offset + int64(s.size)
+		if s.size < 2 || s.offset < 0 || end > int64(len(data)) {
+			continue
+		}
+		sample := data[s.offset:end]
+		n := int(binary.BigEndian.Uint16(sample))
+		if n == 0 || 2+n > len(sample) {
+			continue
+		}
+		cues = append(cues, ports.Cue{
+			StartMs: int(s.time * 1000 / uint64(timescale)),
+			EndMs:   int((s.time + uint64(s.dur)) * 1000 / uint64(timescale)),
+			Text:    string(sample[2 : 2+n]),
+		})
+	}
+	return cues
+}
@@ -0,0 +1,122 @@
+package mp4meta
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/user/loadshow/pkg/adapters/mjpegdecoder"
+	"github.com/user/loadshow/pkg/ports"
+)
+
+func testCues() []ports.Cue {
+	return []ports.Cue{
+		{StartMs: 800, EndMs: 2800, Text: "DOMContentLoaded 0.80s"},
+		{StartMs: 1200, EndMs: 3200, Text: "LCP 1.20s"},
+		{StartMs: 1500, EndMs: 3500, Text: "Load 1.50s"},
+	}
+}
+
+// Overlapping cues end where the next one starts
+var wantCues = []ports.Cue{
+	{StartMs: 800, EndMs: 1200, Text: "DOMContentLoaded 0.80s"},
+	{StartMs: 1200, EndMs: 1500, Text: "LCP 1.20s"},
+	{StartMs: 1500, EndMs: 3500, Text: "Load 1.50s"},
+}
+
+func TestEmbedSubtitles_Fragmented(t *testing.T) {
+	data := encodeMJPEG(t)
+
+	out, err := EmbedSubtitles(data, testCues())
+	if err != nil {
+		t.Fatalf("EmbedSubtitles failed: %v", err)
+	}
+
+	got, err := ReadSubtitles(out)
+	if err != nil {
+		t.Fatalf("ReadSubtitles failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, wantCues) {
+		t.Errorf("ReadSubtitles = %+v, want %+v", got, wantCues)
+	}
+
+	// The video track must still decode
+	frames, err := mjpegdecoder.NewMP4Reader().ReadFramesFromReader(bytes.NewReader(out))
+	if err != nil {
+		t.Fatalf("decode after EmbedSubtitles failed: %v", err)
+	}
+	if len(frames) != 3 {
+		t.Errorf("decoded %d frames, want 3", len(frames))
+	}
+}
+
+func TestEmbedSubtitles_Progressive(t *testing.T) {
+	data := progressiveMP4()
+
+	out, err := EmbedSubtitles(data, testCues())
+	if err != nil {
+		t.Fatalf("EmbedSubtitles failed: %v", err)
+	}
+
+	got, err := ReadSubtitles(out)
+	if err != nil {
+		t.Fatalf("ReadSubtitles failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, wantCues) {
+		t.Errorf("ReadSubtitles = %+v, want %+v", got, wantCues)
+	}
+
+	// The video chunk offset follows the grown moov
+	i := bytes.Index(out, []byte("stco"))
+	offset := binary.BigEndian.Uint32(out[i+12 : i+16])
+	if got := string(out[offset : offset+6]); got != "SAMPLE" {
+		t.Errorf("chunk offset %d points at %q, want SAMPLE", offset, got)
+	}
+
+	// Metadata can still be added afterwards
+	withMeta, err := Embed(out, ports.VideoMetadata{Title: "Example"})
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if got, err := ReadSubtitles(withMeta); err != nil || len(got) != 3 {
+		t.Errorf("subtitles lost after Embed: %+v, %v", got, err)
+	}
+
+	// Chapters do not replace the subtitles
+	withChapters, err := Embed(out, testMetadata())
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if got, err := ReadSubtitles(withChapters); err != nil || !reflect.DeepEqual(got, wantCues) {
+		t.Errorf("subtitles after adding chapters = %+v, %v", got, err)
+	}
+}
+
+func TestReadSubtitles_None(t *testing.T) {
+	if _, err := ReadSubtitles(encodeMJPEG(t)); !errors.Is(err, ErrNoSubtitles) {
+		t.Errorf("expected ErrNoSubtitles, got %v", err)
+	}
+}
+
+func TestTextSamples(t *testing.T) {
+	samples := textSamples([]ports.Cue{
+		{StartMs: 500, EndMs: 1000, Text: "A"},
+		{StartMs: 2000, EndMs: 1500, Text: "invalid"},
+		{StartMs: 3000, EndMs: 4000, Text: ""},
+		{StartMs: 3000, EndMs: 3500, Text: "B"},
+	})
+
+	var durs []int
+	var texts []string
+	for _, s := range samples {
+		durs = append(durs, s.durMs)
+		texts = append(texts, string(s.data[2:]))
+	}
+	wantDurs := []int{500, 500, 2000, 500}
+	wantTexts := []string{"", "A", "", "B"}
+	if !reflect.DeepEqual(durs, wantDurs) || !reflect.DeepEqual(texts, wantTexts) {
+		t.Errorf("samples = %v %q, want %v %q", durs, texts, wantDurs, wantTexts)
+	}
+}
@@ -6,10 +6,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"image/color"
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ideamans/go-l10n"
 	"github.com/user/loadshow/pkg/pipeline"
 	"github.com/user/loadshow/pkg/ports"
+	"github.com/user/loadshow/pkg/webvtt"
 )
 
 // Config contains all configuration for the orchestrator.
@@ -51,13 +58,53 @@ type Config struct {
 	Credit        string // Banner credit text
 
 	// Composition
-	ShowProgress bool
+	ShowProgress     bool
+	HighlightChanges bool         // Highlight regions that changed between frames
+	HighlightFadeMs  int          // Fade duration for change highlights in ms
+	ShowLayoutShifts bool         // Outline shifted elements and show the running CLS badge
+	ShowLCP          bool         // Outline the LCP candidate element and show the LCP badge
+	TimingMarks      []TimingMark // User-timing marks and measures to show as badges
+
+	// Device frame
+	DeviceFrame    pipeline.FrameStyle // Frame drawn around each column (replaces the border)
+	FrameImagePath string              // 9-slice PNG path for pipeline.FrameImage
+	FrameSlice     pipeline.Insets     // 9-slice insets of the PNG, also used as the bezel thickness
 
 	// Encoding
-	VideoCRF int
-	Bitrate  int
-	OutroMs  int
-	FPS      float64
+	VideoCRF  int
+	Bitrate   int
+	OutroMs   int
+	FPS       float64
+	Container ports.Container // Video container (empty = MP4)
+	MaxSize   int64           // Largest allowed output in bytes; CRF is raised until it fits (0 = no limit)
+
+	// Metadata
+	EmbedMetadata bool   // Write title, URL, settings and chapter markers into the MP4
+	Generator     string // Software recorded in the metadata (e.g., "loadshow 1.2.0")
+
+	// Subtitles announcing milestones (DOMContentLoaded, Load, LCP, timing marks)
+	Subtitles     bool   // Add a soft-subtitle track to the MP4
+	SubtitlesPath string // WebVTT sidecar path ("" = disabled)
+
+	// Filmstrip (optional)
+	FilmstripPath       string                 // Contact sheet image path, .png or .jpg ("" = disabled)
+	FilmstripMode       pipeline.FilmstripMode // Sample at a fixed interval or at visual changes
+	FilmstripIntervalMs int                    // Sampling interval for pipeline.FilmstripInterval
+	FilmstripColumns    int                    // Frames per row (0 = single row)
+
+	// Poster image and thumbnails (optional)
+	PosterPath      string               // Poster image path, .png or .jpg ("" = disabled)
+	PosterFrame     pipeline.PosterFrame // Frame used for the poster (empty = pipeline.PosterFinal)
+	PosterTimeMs    int                  // Poster time for pipeline.PosterTime
+	ThumbnailWidths []int                // Widths of downscaled copies written next to the poster
+}
+
+// TimingMark selects a user-timing mark or measure to show as a badge.
+// Measures are shown when they complete.
+type TimingMark struct {
+	Name  string   // Mark or measure name passed to performance.mark()/measure()
+	Label string   // Badge label (empty = Name)
+	Color [4]uint8 // Badge color as RGBA (zero = theme default)
 }
 
 // DefaultConfig returns a Config with default values.
@@ -85,7 +132,8 @@ func DefaultConfig() Config {
 		BannerEnabled: false,
 		BannerHeight:  80,
 
-		ShowProgress: true,
+		ShowProgress:    true,
+		HighlightFadeMs: 500,
 
 		VideoCRF: 25,
 		Bitrate:  2000,
@@ -101,6 +149,8 @@ type Orchestrator struct {
 	bannerStage    pipeline.Stage[pipeline.BannerInput, pipeline.BannerResult]
 	compositeStage pipeline.Stage[pipeline.CompositeInput, pipeline.CompositeResult]
 	encodeStage    pipeline.Stage[pipeline.EncodeInput, pipeline.EncodeResult]
+	filmstripStage pipeline.Stage[pipeline.FilmstripInput, pipeline.FilmstripResult]
+	posterStage    pipeline.Stage[pipeline.PosterInput, pipeline.PosterResult]
 	fs             ports.FileSystem
 	sink           ports.DebugSink
 	logger         ports.Logger
@@ -113,6 +163,8 @@ func New(
 	bannerStage pipeline.Stage[pipeline.BannerInput, pipeline.BannerResult],
 	compositeStage pipeline.Stage[pipeline.CompositeInput, pipeline.CompositeResult],
 	encodeStage pipeline.Stage[pipeline.EncodeInput, pipeline.EncodeResult],
+	filmstripStage pipeline.Stage[pipeline.FilmstripInput, pipeline.FilmstripResult],
+	posterStage pipeline.Stage[pipeline.PosterInput, pipeline.PosterResult],
 	fs ports.FileSystem,
 	sink ports.DebugSink,
 	logger ports.Logger,
@@ -123,6 +175,8 @@ func New(
 		bannerStage:    bannerStage,
 		compositeStage: compositeStage,
 		encodeStage:    encodeStage,
+		filmstripStage: filmstripStage,
+		posterStage:    posterStage,
 		fs:             fs,
 		sink:           sink,
 		logger:         logger,
@@ -132,6 +186,18 @@ func New(
 // Run executes the complete pipeline.
 func (o *Orchestrator) Run(ctx context.Context, config Config) (RunResult, error) {
 	o.logger.Info(l10n.T("Starting pipeline"))
+	recordedAt := time.Now()
+
+	// Load device frame image (optional)
+	var frameImage []byte
+	if config.DeviceFrame == pipeline.FrameImage {
+		data, err := o.fs.ReadFile(config.FrameImagePath)
+		if err != nil {
+			o.logger.Error(l10n.F("Failed to read frame image: %s", err))
+			return RunResult{}, fmt.Errorf("read frame image: %w", err)
+		}
+		frameImage = data
+	}
 
 	// 1. Layout calculation
 	o.logger.Info(l10n.T("Calculating layout"))
@@ -181,7 +247,7 @@ func (o *Orchestrator) Run(ctx context.Context, config Config) (RunResult, error
 
 	// 4. Compose frames
 	o.logger.Info(l10n.F("Compositing %d frames", len(record.Frames)))
-	compositeInput := o.buildCompositeInput(config, layout, record, banner)
+	compositeInput := o.buildCompositeInput(config, layout, record, banner, frameImage)
 	composite, err := o.compositeStage.Execute(ctx, compositeInput)
 	if err != nil {
 		o.logger.Error(l10n.F("Failed to composite frames: %s", err))
@@ -191,8 +257,12 @@ func (o *Orchestrator) Run(ctx context.Context, config Config) (RunResult, error
 
 	// 5. Encode video
 	o.logger.Info(l10n.F("Encoding video with CRF %d", config.VideoCRF))
-	encodeInput := o.buildEncodeInput(config, composite)
-	encoded, err := o.encodeStage.Execute(ctx, encodeInput)
+	var cues []ports.Cue
+	if config.Subtitles || config.SubtitlesPath != "" {
+		cues = buildSubtitleCues(compositeInput, composite)
+	}
+	encodeInput := o.buildEncodeInput(config, record, composite, recordedAt, cues)
+	encoded, err := o.encodeToFit(ctx, encodeInput, config.MaxSize)
 	if err != nil {
 		o.logger.Error(l10n.F("Failed to encode video: %s", err))
 		return RunResult{}, fmt.Errorf("encode stage: %w", err)
@@ -205,40 +275,233 @@ func (o *Orchestrator) Run(ctx context.Context, config Config) (RunResult, error
 		return RunResult{}, fmt.Errorf("write output: %w", err)
 	}
 
+	// 7. Write subtitles (optional)
+	if config.SubtitlesPath != "" {
+		if err := o.fs.WriteFile(config.SubtitlesPath, webvtt.Format(cues)); err != nil {
+			o.logger.Error(l10n.F("Failed to write subtitles: %s", err))
+			return RunResult{}, fmt.Errorf("write subtitles: %w", err)
+		}
+		o.logger.Info(l10n.F("Subtitles saved to %s", config.SubtitlesPath))
+	}
+
+	// 8. Generate filmstrip (optional)
+	filmstripFrames := 0
+	if config.FilmstripPath != "" {
+		o.logger.Info(l10n.T("Generating filmstrip"))
+		filmstripInput := o.buildFilmstripInput(config, record, composite)
+		filmstrip, err := o.filmstripStage.Execute(ctx, filmstripInput)
+		if err != nil {
+			o.logger.Error(l10n.F("Failed to generate filmstrip: %s", err))
+			return RunResult{}, fmt.Errorf("filmstrip stage: %w", err)
+		}
+		if err := o.fs.WriteFile(config.FilmstripPath, filmstrip.ImageData); err != nil {
+			o.logger.Error(l10n.F("Failed to write filmstrip: %s", err))
+			return RunResult{}, fmt.Errorf("write filmstrip: %w", err)
+		}
+		filmstripFrames = filmstrip.FrameCount
+		o.logger.Info(l10n.F("Filmstrip saved to %s (%d frames)", config.FilmstripPath, filmstrip.FrameCount))
+	}
+
+	// 9. Generate poster and thumbnails (optional)
+	var poster PosterResult
+	if config.PosterPath != "" {
+		o.logger.Info(l10n.T("Generating poster image"))
+		posterInput := o.buildPosterInput(config, record, composite)
+		p, err := o.posterStage.Execute(ctx, posterInput)
+		if err != nil {
+			o.logger.Error(l10n.F("Failed to generate poster: %s", err))
+			return RunResult{}, fmt.Errorf("poster stage: %w", err)
+		}
+		if err := o.fs.WriteFile(config.PosterPath, p.ImageData); err != nil {
+			o.logger.Error(l10n.F("Failed to write poster: %s", err))
+			return RunResult{}, fmt.Errorf("write poster: %w", err)
+		}
+		o.logger.Info(l10n.F("Poster saved to %s (frame at %d ms)", config.PosterPath, p.TimestampMs))
+		poster = PosterResult{Path: config.PosterPath, TimestampMs: p.TimestampMs, Width: p.Width, Height: p.Height}
+
+		for _, thumb := range p.Thumbnails {
+			path := ThumbnailPath(config.PosterPath, thumb.Width)
+			if err := o.fs.WriteFile(path, thumb.ImageData); err != nil {
+				o.logger.Error(l10n.F("Failed to write thumbnail: %s", err))
+				return RunResult{}, fmt.Errorf("write thumbnail: %w", err)
+			}
+			o.logger.Info(l10n.F("Thumbnail saved to %s (%dx%d)", path, thumb.Width, thumb.Height))
+			poster.Thumbnails = append(poster.Thumbnails, ThumbnailResult{Path: path, Width: thumb.Width, Height: thumb.Height})
+		}
+	}
+
 	o.logger.Info(l10n.T("Pipeline completed successfully"))
 
 	// Build result for summary
 	result := RunResult{
-		DOMContentLoadedMs: record.Timing.DOMContentLoadedMs,
-		LoadCompleteMs:     record.Timing.LoadCompleteMs,
-		TotalDurationMs:    record.Timing.TotalDurationMs,
-		TimedOut:           record.Timing.TimedOut,
-		TimeoutSec:         record.Timing.TimeoutSec,
-		TotalBytes:         getTotalBytes(record.Frames),
-		PageTitle:          record.PageInfo.Title,
-		PageURL:            record.PageInfo.URL,
-		FrameCount:         len(record.Frames),
-		VideoDuration:      encoded.DurationMs,
-		VideoFileSize:      encoded.FileSize,
-		CanvasWidth:        config.CanvasWidth,
-		CanvasHeight:       config.CanvasHeight,
+		DOMContentLoadedMs:       record.Timing.DOMContentLoadedMs,
+		LoadCompleteMs:           record.Timing.LoadCompleteMs,
+		LargestContentfulPaintMs: record.Timing.LargestContentfulPaintMs,
+		CumulativeLayoutShift:    finalLayoutShift(record.LayoutShifts),
+		LayoutShiftsRecorded:     record.LayoutShiftsRecorded,
+		TotalDurationMs:          record.Timing.TotalDurationMs,
+		TimedOut:                 record.Timing.TimedOut,
+		TimeoutSec:               record.Timing.TimeoutSec,
+		TotalBytes:               getTotalBytes(record.Frames),
+		RequestCount:             getTotalResources(record.Frames),
+		PageTitle:                record.PageInfo.Title,
+		PageURL:                  record.PageInfo.URL,
+		FrameCount:               len(record.Frames),
+		VideoDuration:            encoded.DurationMs,
+		VideoFileSize:            encoded.FileSize,
+		VideoCRF:                 encoded.crf,
+		EncodePasses:             encoded.passes,
+		CanvasWidth:              config.CanvasWidth,
+		CanvasHeight:             config.CanvasHeight,
+		TimingMarks:              resolveTimingMarks(config.TimingMarks, record.UserTimings),
+		UserTimings:              userTimingTimes(record.UserTimings),
+		FilmstripFrames:          filmstripFrames,
+		Frames:                   frameResults(record.Frames),
+		Requests:                 requestResults(record.Requests),
+	}
+	if poster.Path != "" {
+		result.Poster = &poster
 	}
 
 	return result, nil
 }
 
+// maxCRF is the highest (lowest quality) CRF value.
+const maxCRF = 63
+
+// maxSizePasses bounds the number of encodes used to fit Config.MaxSize.
+const maxSizePasses = 8
+
+// fittedEncode is an encode result with the settings that produced it.
+type fittedEncode struct {
+	pipeline.EncodeResult
+	crf    int // CRF of the written video
+	passes int // Number of encodes run
+}
+
+// encodeToFit encodes the composed frames once at the requested CRF. When the
+// video is larger than maxSize, it binary-searches the lowest CRF that fits,
+// reusing the same frames, and as a last resort caps the bitrate at the
+// highest CRF. Encoders that ignore the CRF (e.g. Media Foundation) are
+// detected by an unchanged size and go straight to the bitrate cap. It fails
+// if no pass fits.
+func (o *Orchestrator) encodeToFit(ctx context.Context, input pipeline.EncodeInput, maxSize int64) (fittedEncode, error) {
+	var passes int
+	encode := func(crf, bitrate int) (fittedEncode, error) {
+		passes++
+		in := input
+		in.VideoCRF = crf
+		in.Bitrate = bitrate
+		if in.Metadata != nil && in.Metadata.Settings != nil {
+			// Record the CRF actually used
+			md := *in.Metadata
+			md.Settings = make(map[string]string, len(input.Metadata.Settings))
+			for k, v := range input.Metadata.Settings {
+				md.Settings[k] = v
+			}
+			md.Settings["crf"] = strconv.Itoa(crf)
+			in.Metadata = &md
+		}
+		encoded, err := o.encodeStage.Execute(ctx, in)
+		if err != nil {
+			return fittedEncode{}, err
+		}
+		return fittedEncode{EncodeResult: encoded, crf: crf, passes: passes}, nil
+	}
+
+	first, err := encode(input.VideoCRF, input.Bitrate)
+	if err != nil || maxSize <= 0 || first.FileSize <= maxSize {
+		return first, err
+	}
+	o.logger.Info(l10n.F("Video is %d bytes, over the %d byte limit; raising CRF", first.FileSize, maxSize))
+
+	// Lowest CRF in (low, high] that fits
+	var best *fittedEncode
+	smallest := first.FileSize
+	capCRF := maxCRF
+	low, high := input.VideoCRF+1, maxCRF
+	for low <= high && passes < maxSizePasses-1 {
+		mid := (low + high) / 2
+		encoded, err := encode(mid, input.Bitrate)
+		if err != nil {
+			return fittedEncode{}, err
+		}
+		o.logger.Info(l10n.F("Pass %d: CRF %d, %d bytes", passes, mid, encoded.FileSize))
+		if encoded.FileSize >= first.FileSize {
+			// A higher CRF did not shrink the video: the encoder ignores it
+			o.logger.Info(l10n.T("The encoder ignores the CRF; capping the bitrate"))
+			capCRF = input.VideoCRF
+			break
+		}
+		smallest = min(smallest, encoded.FileSize)
+		if encoded.FileSize <= maxSize {
+			best = &encoded
+			high = mid - 1
+		} else {
+			low = mid + 1
+		}
+	}
+	if best != nil {
+		best.passes = passes
+		return *best, nil
+	}
+
+	// Even the highest CRF tried is too large: cap the bitrate with 10% headroom
+	if durationMs := videoDurationMs(input); durationMs > 0 {
+		kbps := int(maxSize * 8 * 9 / 10 / durationMs) // bits per ms = kbit/s
+		if kbps > 0 {
+			encoded, err := encode(capCRF, kbps)
+			if err != nil {
+				return fittedEncode{}, err
+			}
+			o.logger.Info(l10n.F("Pass %d: CRF %d at %d kbps, %d bytes", passes, capCRF, kbps, encoded.FileSize))
+			if encoded.FileSize <= maxSize {
+				return encoded, nil
+			}
+			smallest = min(smallest, encoded.FileSize)
+		}
+	}
+
+	return fittedEncode{}, fmt.Errorf("video does not fit in %d bytes (smallest: %d bytes after %d passes)", maxSize, smallest, passes)
+}
+
+// videoDurationMs returns the length of the encoded video: the last frame
+// is held for one frame interval.
+func videoDurationMs(input pipeline.EncodeInput) int64 {
+	n := len(input.Frames)
+	if n == 0 {
+		return 0
+	}
+	durationMs := int64(input.Frames[n-1].TimestampMs)
+	if input.FPS > 0 {
+		durationMs += int64(math.Ceil(1000 / input.FPS))
+	}
+	return durationMs
+}
+
 func (o *Orchestrator) buildLayoutInput(config Config) pipeline.LayoutInput {
+	// A device frame replaces the column border
+	borderWidth := config.BorderWidth
+	bezel := pipeline.FrameInsets(config.DeviceFrame)
+	if config.DeviceFrame == pipeline.FrameImage {
+		bezel = config.FrameSlice
+	}
+	if config.DeviceFrame != pipeline.FrameNone {
+		borderWidth = 0
+	}
+
 	return pipeline.LayoutInput{
 		CanvasWidth:    config.CanvasWidth,
 		CanvasHeight:   config.CanvasHeight,
 		Columns:        config.Columns,
 		Gap:            config.Gap,
 		Padding:        config.Padding,
-		BorderWidth:    config.BorderWidth,
+		BorderWidth:    borderWidth,
 		Indent:         config.Indent,
 		Outdent:        config.Outdent,
 		BannerHeight:   conditionalInt(config.BannerEnabled, config.BannerHeight, 0),
 		ProgressHeight: conditionalInt(config.ShowProgress, config.ProgressHeight, 0),
+		Bezel:          bezel,
 	}
 }
 
@@ -278,6 +541,7 @@ func (o *Orchestrator) buildCompositeInput(
 	layout pipeline.LayoutResult,
 	record pipeline.RecordResult,
 	banner *pipeline.BannerResult,
+	frameImage []byte,
 ) pipeline.CompositeInput {
 	theme := pipeline.DefaultCompositeTheme()
 	// Override theme colors if specified
@@ -289,25 +553,238 @@ func (o *Orchestrator) buildCompositeInput(
 	}
 
 	return pipeline.CompositeInput{
-		RawFrames:          record.Frames,
-		Layout:             layout,
-		Banner:             banner,
-		Theme:              theme,
-		ShowProgress:       config.ShowProgress,
-		TotalTimeMs:        record.Timing.TotalDurationMs,
-		TotalBytes:         getTotalBytes(record.Frames),
-		DOMContentLoadedMs: record.Timing.DOMContentLoadedMs,
-		LoadCompleteMs:     record.Timing.LoadCompleteMs,
+		RawFrames:                record.Frames,
+		Layout:                   layout,
+		Banner:                   banner,
+		Theme:                    theme,
+		ShowProgress:             config.ShowProgress,
+		TotalTimeMs:              record.Timing.TotalDurationMs,
+		TotalBytes:               getTotalBytes(record.Frames),
+		DOMContentLoadedMs:       record.Timing.DOMContentLoadedMs,
+		LoadCompleteMs:           record.Timing.LoadCompleteMs,
+		HighlightChanges:         config.HighlightChanges,
+		HighlightFadeMs:          config.HighlightFadeMs,
+		ShowLayoutShifts:         config.ShowLayoutShifts,
+		LayoutShifts:             record.LayoutShifts,
+		ViewportWidth:            record.ViewportWidth,
+		ShowLCP:                  config.ShowLCP,
+		LCPCandidates:            record.LCPCandidates,
+		LargestContentfulPaintMs: record.Timing.LargestContentfulPaintMs,
+		TimingBadges:             buildTimingBadges(config.TimingMarks, record.UserTimings),
+		Frame:                    config.DeviceFrame,
+		FrameImage:               frameImage,
+		FrameSlice:               config.FrameSlice,
+	}
+}
+
+func (o *Orchestrator) buildEncodeInput(config Config, record pipeline.RecordResult, composite pipeline.CompositeResult, recordedAt time.Time, cues []ports.Cue) pipeline.EncodeInput {
+	input := pipeline.EncodeInput{
+		Frames:    composite.Frames,
+		VideoCRF:  config.VideoCRF,
+		Bitrate:   config.Bitrate,
+		FPS:       config.FPS,
+		Container: config.Container,
 	}
+	if config.EmbedMetadata {
+		md := buildVideoMetadata(config, record, composite.ScreenArea, recordedAt)
+		input.Metadata = &md
+	}
+	if config.Subtitles {
+		input.Subtitles = cues
+	}
+	return input
 }
 
-func (o *Orchestrator) buildEncodeInput(config Config, composite pipeline.CompositeResult) pipeline.EncodeInput {
-	return pipeline.EncodeInput{
-		Frames:   composite.Frames,
-		VideoCRF: config.VideoCRF,
-		Bitrate:  config.Bitrate,
-		FPS:      config.FPS,
+// subtitleCueMs is how long a milestone subtitle stays on screen.
+const subtitleCueMs = 3000
+
+// buildSubtitleCues announces the milestones shown as badges, e.g. "Load 2.45s".
+// Each cue lasts until the next one starts and ends with the video.
+// Milestones at the same time share a cue.
+func buildSubtitleCues(input pipeline.CompositeInput, composite pipeline.CompositeResult) []ports.Cue {
+	type milestone struct {
+		label string
+		ms    int
+	}
+	var milestones []milestone
+	if input.DOMContentLoadedMs > 0 {
+		milestones = append(milestones, milestone{"DOMContentLoaded", input.DOMContentLoadedMs})
+	}
+	if input.LoadCompleteMs > 0 {
+		milestones = append(milestones, milestone{"Load", input.LoadCompleteMs})
+	}
+	if input.LargestContentfulPaintMs > 0 {
+		milestones = append(milestones, milestone{"LCP", input.LargestContentfulPaintMs})
+	}
+	for _, badge := range input.TimingBadges {
+		milestones = append(milestones, milestone{badge.Label, badge.TimestampMs})
+	}
+	sort.SliceStable(milestones, func(i, j int) bool {
+		return milestones[i].ms < milestones[j].ms
+	})
+
+	videoEndMs := 0
+	if len(composite.Frames) > 0 {
+		videoEndMs = composite.Frames[len(composite.Frames)-1].TimestampMs
+	}
+
+	var cues []ports.Cue
+	for _, m := range milestones {
+		if m.ms >= videoEndMs {
+			break
+		}
+		text := fmt.Sprintf("%s %.2fs", m.label, float64(m.ms)/1000)
+		if n := len(cues); n > 0 && cues[n-1].StartMs == m.ms {
+			cues[n-1].Text += " / " + text
+			continue
+		}
+		cues = append(cues, ports.Cue{StartMs: m.ms, EndMs: min(m.ms+subtitleCueMs, videoEndMs), Text: text})
 	}
+	for i := 0; i+1 < len(cues); i++ {
+		cues[i].EndMs = min(cues[i].EndMs, cues[i+1].StartMs)
+	}
+	return cues
+}
+
+// buildVideoMetadata describes the recording with chapters at each captured milestone.
+func buildVideoMetadata(config Config, record pipeline.RecordResult, screen pipeline.Rectangle, recordedAt time.Time) ports.VideoMetadata {
+	url := record.PageInfo.URL
+	if url == "" {
+		url = config.URL
+	}
+
+	settings := map[string]string{
+		"viewport": strconv.Itoa(config.ViewportWidth),
+		"canvas":   fmt.Sprintf("%dx%d", config.CanvasWidth, config.CanvasHeight),
+		"columns":  strconv.Itoa(config.Columns),
+		"crf":      strconv.Itoa(config.VideoCRF),
+	}
+	if config.FPS > 0 {
+		settings["fps"] = strconv.FormatFloat(config.FPS, 'f', -1, 64)
+	}
+	if screen.Width > 0 && screen.Height > 0 {
+		settings[ScreenAreaSetting] = fmt.Sprintf("%d,%d,%d,%d", screen.X, screen.Y, screen.Width, screen.Height)
+	}
+	if config.CPUThrottling > 0 {
+		settings["cpu_throttling"] = strconv.FormatFloat(config.CPUThrottling, 'f', -1, 64)
+	}
+	if nc := config.NetworkConditions; nc.DownloadSpeed > 0 || nc.UploadSpeed > 0 || nc.LatencyMs > 0 {
+		settings["download_speed"] = strconv.Itoa(nc.DownloadSpeed)
+		settings["upload_speed"] = strconv.Itoa(nc.UploadSpeed)
+		settings["latency_ms"] = strconv.Itoa(nc.LatencyMs)
+	}
+
+	chapters := []ports.Chapter{{StartMs: 0, Title: "Start"}}
+	if record.Timing.DOMContentLoadedMs > 0 {
+		chapters = append(chapters, ports.Chapter{StartMs: record.Timing.DOMContentLoadedMs, Title: "DOMContentLoaded"})
+	}
+	if record.Timing.LoadCompleteMs > 0 {
+		chapters = append(chapters, ports.Chapter{StartMs: record.Timing.LoadCompleteMs, Title: "Load"})
+	}
+	if record.Timing.LargestContentfulPaintMs > 0 {
+		chapters = append(chapters, ports.Chapter{StartMs: record.Timing.LargestContentfulPaintMs, Title: "LCP"})
+	}
+	for _, mark := range resolveTimingMarks(config.TimingMarks, record.UserTimings) {
+		if mark.Recorded {
+			chapters = append(chapters, ports.Chapter{StartMs: mark.TimeMs, Title: mark.Label})
+		}
+	}
+	sort.SliceStable(chapters, func(i, j int) bool {
+		return chapters[i].StartMs < chapters[j].StartMs
+	})
+
+	return ports.VideoMetadata{
+		Title:      record.PageInfo.Title,
+		URL:        url,
+		RecordedAt: recordedAt,
+		Generator:  config.Generator,
+		Settings:   settings,
+		Chapters:   chapters,
+	}
+}
+
+func (o *Orchestrator) buildFilmstripInput(config Config, record pipeline.RecordResult, composite pipeline.CompositeResult) pipeline.FilmstripInput {
+	input := pipeline.DefaultFilmstripInput()
+	input.Frames = composite.Frames
+	// Detect changes on the page itself rather than on the overlays
+	if len(record.Frames) == len(composite.Frames) {
+		input.RawFrames = record.Frames
+	}
+	input.CompareArea = composite.ScreenArea
+	if config.FilmstripMode != "" {
+		input.Mode = config.FilmstripMode
+	}
+	if config.FilmstripIntervalMs > 0 {
+		input.IntervalMs = config.FilmstripIntervalMs
+	}
+	input.Columns = config.FilmstripColumns
+	input.Format = FilmstripFormat(config.FilmstripPath)
+	return input
+}
+
+// ScreenAreaSetting is the video metadata setting holding the area of the
+// frames that shows the page, as "x,y,width,height".
+const ScreenAreaSetting = "screen_area"
+
+// ScreenArea returns the area of the frames showing the page from the
+// video metadata, and false if it was not recorded.
+func ScreenArea(md ports.VideoMetadata) (pipeline.Rectangle, bool) {
+	var r pipeline.Rectangle
+	value, ok := md.Settings[ScreenAreaSetting]
+	if !ok {
+		return r, false
+	}
+	if _, err := fmt.Sscanf(value, "%d,%d,%d,%d", &r.X, &r.Y, &r.Width, &r.Height); err != nil || r.Width <= 0 || r.Height <= 0 {
+		return pipeline.Rectangle{}, false
+	}
+	return r, true
+}
+
+// FilmstripFormat returns the image format for a filmstrip path: JPEG for .jpg/.jpeg, PNG otherwise.
+func FilmstripFormat(path string) ports.ImageFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jpg", ".jpeg":
+		return ports.FormatJPEG
+	default:
+		return ports.FormatPNG
+	}
+}
+
+func (o *Orchestrator) buildPosterInput(config Config, record pipeline.RecordResult, composite pipeline.CompositeResult) pipeline.PosterInput {
+	input := pipeline.DefaultPosterInput()
+	input.Frames = composite.Frames
+	input.Frame, input.TimestampMs = o.resolvePosterFrame(config, record.Timing)
+	input.ThumbWidths = config.ThumbnailWidths
+	input.Format = FilmstripFormat(config.PosterPath)
+	return input
+}
+
+// resolvePosterFrame returns the poster frame and time. A milestone the page
+// never reached falls back to Load, then to the final frame.
+func (o *Orchestrator) resolvePosterFrame(config Config, timing pipeline.TimingInfo) (pipeline.PosterFrame, int) {
+	switch config.PosterFrame {
+	case pipeline.PosterTime:
+		return pipeline.PosterTime, config.PosterTimeMs
+	case pipeline.PosterLCP:
+		if timing.LargestContentfulPaintMs > 0 {
+			return pipeline.PosterLCP, timing.LargestContentfulPaintMs
+		}
+		o.logger.Warn(l10n.T("LCP was not recorded; using the Load frame for the poster"))
+		fallthrough
+	case pipeline.PosterLoad:
+		if timing.LoadCompleteMs > 0 {
+			return pipeline.PosterLoad, timing.LoadCompleteMs
+		}
+		o.logger.Warn(l10n.T("Load was not recorded; using the final frame for the poster"))
+	}
+	return pipeline.PosterFinal, 0
+}
+
+// ThumbnailPath returns the path of a thumbnail written next to the poster:
+// poster.png becomes poster-320w.png for a 320px wide thumbnail.
+func ThumbnailPath(posterPath string, width int) string {
+	ext := filepath.Ext(posterPath)
+	return fmt.Sprintf("%s-%dw%s", strings.TrimSuffix(posterPath, ext), width, ext)
 }
 
 func conditionalInt(condition bool, trueVal, falseVal int) int {
@@ -324,6 +801,108 @@ func getTotalBytes(frames []pipeline.RawFrame) int64 {
 	return frames[len(frames)-1].TotalBytes
 }
 
+// getTotalResources returns the recorder's count of finished requests.
+func getTotalResources(frames []pipeline.RawFrame) int {
+	if len(frames) == 0 {
+		return 0
+	}
+	return frames[len(frames)-1].TotalResources
+}
+
+// frameResults describes the recorded frames. Progress matches the progress
+// bar: the share of the final traffic, with the last frame at 100%.
+func frameResults(frames []pipeline.RawFrame) []FrameResult {
+	totalBytes := getTotalBytes(frames)
+	results := make([]FrameResult, len(frames))
+	for i, f := range frames {
+		var progress float64
+		if i == len(frames)-1 {
+			progress = 1.0
+		} else if totalBytes > 0 {
+			progress = min(float64(f.TotalBytes)/float64(totalBytes), 1.0)
+		}
+		results[i] = FrameResult{
+			TimestampMs:     f.TimestampMs,
+			Progress:        progress,
+			LoadedResources: f.LoadedResources,
+			TotalResources:  f.TotalResources,
+			TotalBytes:      f.TotalBytes,
+		}
+	}
+	return results
+}
+
+// finalLayoutShift returns the CLS score of the whole recording.
+func finalLayoutShift(shifts []pipeline.LayoutShift) float64 {
+	sorted := make([]pipeline.LayoutShift, len(shifts))
+	copy(sorted, shifts)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].TimestampMs < sorted[j].TimestampMs
+	})
+	return pipeline.CumulativeLayoutShift(sorted, math.MaxInt)
+}
+
+// userTimingTimes returns the time of each user-timing entry by name.
+func userTimingTimes(timings []pipeline.UserTiming) map[string]int {
+	times := make(map[string]int, len(timings))
+	for _, t := range timings {
+		if _, ok := times[t.Name]; !ok {
+			times[t.Name] = t.EndMs()
+		}
+	}
+	return times
+}
+
+// requestResults converts the recorded network requests for the run result.
+func requestResults(requests []pipeline.Request) []RequestResult {
+	results := make([]RequestResult, len(requests))
+	for i, r := range requests {
+		results[i] = RequestResult(r)
+	}
+	return results
+}
+
+// resolveTimingMarks looks up the recorded time of each selected mark.
+// The first entry with a matching name is used.
+func resolveTimingMarks(marks []TimingMark, timings []pipeline.UserTiming) []TimingMarkResult {
+	if len(marks) == 0 {
+		return nil
+	}
+
+	results := make([]TimingMarkResult, 0, len(marks))
+	for _, mark := range marks {
+		result := TimingMarkResult{Name: mark.Name, Label: mark.Label}
+		if result.Label == "" {
+			result.Label = mark.Name
+		}
+		for _, timing := range timings {
+			if timing.Name == mark.Name {
+				result.TimeMs = timing.EndMs()
+				result.Recorded = true
+				break
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// buildTimingBadges converts the recorded marks into composite badges, skipping marks that were not recorded.
+func buildTimingBadges(marks []TimingMark, timings []pipeline.UserTiming) []pipeline.TimingBadge {
+	var badges []pipeline.TimingBadge
+	for i, result := range resolveTimingMarks(marks, timings) {
+		if !result.Recorded {
+			continue
+		}
+		badge := pipeline.TimingBadge{Label: result.Label, TimestampMs: result.TimeMs}
+		if marks[i].Color != [4]uint8{} {
+			badge.Color = rgbaFromArray(marks[i].Color)
+		}
+		badges = append(badges, badge)
+	}
+	return badges
+}
+
 func rgbaFromArray(c [4]uint8) color.RGBA {
 	return color.RGBA{R: c[0], G: c[1], B: c[2], A: c[3]}
 }
@@ -331,14 +910,18 @@ func rgbaFromArray(c [4]uint8) color.RGBA {
 // RunResult contains the results of a pipeline run for summary generation.
 type RunResult struct {
 	// Timing information
-	DOMContentLoadedMs int
-	LoadCompleteMs     int
-	TotalDurationMs    int
-	TimedOut           bool // True if recording ended due to timeout
-	TimeoutSec         int  // Timeout value in seconds
+	DOMContentLoadedMs       int
+	LoadCompleteMs           int
+	LargestContentfulPaintMs int     // 0 = not available
+	CumulativeLayoutShift    float64 // CLS over the whole recording
+	LayoutShiftsRecorded     bool    // False if the layout shifts could not be collected (CLS is then 0)
+	TotalDurationMs          int
+	TimedOut                 bool // True if recording ended due to timeout
+	TimeoutSec               int  // Timeout value in seconds
 
 	// Traffic information
-	TotalBytes int64
+	TotalBytes   int64
+	RequestCount int // Requests the recorder saw finish (0 = no frames recorded)
 
 	// Page information
 	PageTitle string
@@ -348,8 +931,83 @@ type RunResult struct {
 	FrameCount    int
 	VideoDuration int // in ms (includes outro)
 	VideoFileSize int64
+	VideoCRF      int // CRF of the written video (raised from Config.VideoCRF to fit Config.MaxSize)
+	EncodePasses  int // Number of encodes run (more than 1 when fitting Config.MaxSize)
 
 	// Layout information
 	CanvasWidth  int
 	CanvasHeight int
+
+	// User-timing marks selected in Config.TimingMarks
+	TimingMarks []TimingMarkResult
+
+	// Time of every user-timing mark (measures: end time) by name, in ms
+	// since navigation start. The first entry with a name is used.
+	UserTimings map[string]int
+
+	// Number of frames in the filmstrip (0 = not generated)
+	FilmstripFrames int
+
+	// Poster image and thumbnails (nil = not generated)
+	Poster *PosterResult
+
+	// Recorded frames in timestamp order
+	Frames []FrameResult
+
+	// Network requests in start order, document first
+	Requests []RequestResult
+}
+
+// DOMContentLoadedRecorded reports whether DOMContentLoadedMs is a
+// measurement: the event fired, and not only after the timeout.
+func (r RunResult) DOMContentLoadedRecorded() bool {
+	return r.DOMContentLoadedMs != 0 && !(r.TimedOut && r.DOMContentLoadedMs > r.TimeoutSec*1000)
+}
+
+// LoadRecorded reports whether LoadCompleteMs is a measurement, that is
+// whether the page loaded before the timeout.
+func (r RunResult) LoadRecorded() bool {
+	return !r.TimedOut
+}
+
+// FrameResult describes one recorded frame.
+type FrameResult struct {
+	TimestampMs     int     // Time of the frame in ms since navigation start
+	Progress        float64 // Traffic-based loading progress shown on the progress bar (0-1)
+	LoadedResources int     // Number of resources loaded at this point
+	TotalResources  int     // Total resources being loaded
+	TotalBytes      int64   // Bytes transferred at this point
+}
+
+// RequestResult describes one network request made by the page.
+type RequestResult struct {
+	URL          string
+	Type         string // Initiator type ("navigation" for the document)
+	StartMs      int    // Request start in ms since navigation start
+	DurationMs   int    // Time until the response finished
+	TransferSize int64  // Bytes transferred (0 if cached or not exposed cross-origin)
+}
+
+// PosterResult describes the written poster image and its thumbnails.
+type PosterResult struct {
+	Path        string
+	TimestampMs int // Time of the frame used, in ms since navigation start
+	Width       int
+	Height      int
+	Thumbnails  []ThumbnailResult
+}
+
+// ThumbnailResult describes a written thumbnail image.
+type ThumbnailResult struct {
+	Path   string
+	Width  int
+	Height int
+}
+
+// TimingMarkResult is the recorded time of a selected user-timing mark.
+type TimingMarkResult struct {
+	Name     string
+	Label    string
+	TimeMs   int  // Mark time, or measure end time, in ms since navigation start
+	Recorded bool // False if the page never recorded the mark
 }
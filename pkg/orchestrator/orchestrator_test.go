@@ -3,12 +3,19 @@ package orchestrator
 import (
 	"context"
 	"image"
+	"image/color"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/user/loadshow/pkg/adapters/logger"
+	"github.com/user/loadshow/pkg/adapters/smartencoder"
 	"github.com/user/loadshow/pkg/mocks"
 	"github.com/user/loadshow/pkg/pipeline"
 	"github.com/user/loadshow/pkg/ports"
+	"github.com/user/loadshow/pkg/stages/encode"
 )
 
 // mockLayoutStage is a mock for the layout stage.
@@ -67,12 +74,56 @@ func (m *mockCompositeStage) Execute(ctx context.Context, input pipeline.Composi
 type mockEncodeStage struct {
 	result pipeline.EncodeResult
 	err    error
+	input  pipeline.EncodeInput
+	sizeOf func(input pipeline.EncodeInput) int64 // Optional output size per input
+	calls  int
 }
 
 func (m *mockEncodeStage) Execute(ctx context.Context, input pipeline.EncodeInput) (pipeline.EncodeResult, error) {
+	m.input = input
+	m.calls++
 	if m.err != nil {
 		return pipeline.EncodeResult{}, m.err
 	}
+	if m.sizeOf != nil {
+		result := m.result
+		result.FileSize = m.sizeOf(input)
+		return result, nil
+	}
+	return m.result, nil
+}
+
+// mockFilmstripStage is a mock for the filmstrip stage.
+type mockFilmstripStage struct {
+	result pipeline.FilmstripResult
+	err    error
+	input  pipeline.FilmstripInput
+	called bool
+}
+
+func (m *mockFilmstripStage) Execute(ctx context.Context, input pipeline.FilmstripInput) (pipeline.FilmstripResult, error) {
+	m.called = true
+	m.input = input
+	if m.err != nil {
+		return pipeline.FilmstripResult{}, m.err
+	}
+	return m.result, nil
+}
+
+// mockPosterStage is a mock for the poster stage.
+type mockPosterStage struct {
+	result pipeline.PosterResult
+	err    error
+	input  pipeline.PosterInput
+	called bool
+}
+
+func (m *mockPosterStage) Execute(ctx context.Context, input pipeline.PosterInput) (pipeline.PosterResult, error) {
+	m.called = true
+	m.input = input
+	if m.err != nil {
+		return pipeline.PosterResult{}, m.err
+	}
 	return m.result, nil
 }
 
@@ -140,6 +191,8 @@ func TestOrchestrator_Run(t *testing.T) {
 		bannerStage,
 		compositeStage,
 		encodeStage,
+		&mockFilmstripStage{},
+		&mockPosterStage{},
 		mockFS,
 		mockSink,
 		logger.NewNoop(),
@@ -224,6 +277,8 @@ func TestOrchestrator_Run_WithBanner(t *testing.T) {
 		wrappedBannerStage,
 		compositeStage,
 		encodeStage,
+		&mockFilmstripStage{},
+		&mockPosterStage{},
 		mockFS,
 		mockSink,
 		logger.NewNoop(),
@@ -281,6 +336,8 @@ func TestOrchestrator_Run_WithDebugSink(t *testing.T) {
 		bannerStage,
 		compositeStage,
 		encodeStage,
+		&mockFilmstripStage{},
+		&mockPosterStage{},
 		mockFS,
 		mockSink,
 		logger.NewNoop(),
@@ -303,3 +360,683 @@ func TestOrchestrator_Run_WithDebugSink(t *testing.T) {
 		t.Error("expected recording JSON to be saved")
 	}
 }
+
+func TestBuildTimingBadges(t *testing.T) {
+	marks := []TimingMark{
+		{Name: "hero-rendered", Label: "Hero"},
+		{Name: "app-load", Color: [4]uint8{255, 0, 0, 255}},
+		{Name: "never-marked"},
+	}
+	timings := []pipeline.UserTiming{
+		{Name: "hero-rendered", EntryType: "mark", StartMs: 800},
+		{Name: "app-load", EntryType: "measure", StartMs: 200, DurationMs: 1000},
+	}
+
+	results := resolveTimingMarks(marks, timings)
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if results[1].Label != "app-load" || results[1].TimeMs != 1200 || !results[1].Recorded {
+		t.Errorf("expected measure to resolve to its end time, got %+v", results[1])
+	}
+	if results[2].Recorded {
+		t.Errorf("expected missing mark to be unrecorded, got %+v", results[2])
+	}
+
+	badges := buildTimingBadges(marks, timings)
+	if len(badges) != 2 {
+		t.Fatalf("expected 2 badges, got %d", len(badges))
+	}
+	if badges[0].Label != "Hero" || badges[0].TimestampMs != 800 || badges[0].Color != nil {
+		t.Errorf("unexpected mark badge: %+v", badges[0])
+	}
+	if badges[1].Color != (color.RGBA{R: 255, A: 255}) {
+		t.Errorf("expected custom badge color, got %v", badges[1].Color)
+	}
+}
+
+func TestOrchestrator_Run_WithFilmstrip(t *testing.T) {
+	layoutStage := &mockLayoutStage{
+		result: pipeline.LayoutResult{Scroll: pipeline.Dimension{Width: 142, Height: 1740}},
+	}
+	recordStage := &mockRecordStage{
+		result: pipeline.RecordResult{
+			Frames: []pipeline.RawFrame{{TimestampMs: 0, ImageData: []byte{0xFF}}},
+			Timing: pipeline.TimingInfo{TotalDurationMs: 100},
+		},
+	}
+	compositeStage := &mockCompositeStage{
+		result: pipeline.CompositeResult{
+			Frames: []pipeline.ComposedFrame{
+				{TimestampMs: 0, Image: image.NewRGBA(image.Rect(0, 0, 512, 640))},
+			},
+		},
+	}
+	encodeStage := &mockEncodeStage{
+		result: pipeline.EncodeResult{VideoData: []byte{0x00}},
+	}
+	filmstripStage := &mockFilmstripStage{
+		result: pipeline.FilmstripResult{ImageData: []byte{0xFF, 0xD8}, FrameCount: 1},
+	}
+
+	mockFS := mocks.NewFileSystem()
+	orch := New(
+		layoutStage,
+		recordStage,
+		&mockBannerStage{},
+		compositeStage,
+		encodeStage,
+		filmstripStage,
+		&mockPosterStage{},
+		mockFS,
+		mocks.NewDebugSink(false),
+		logger.NewNoop(),
+	)
+
+	config := DefaultConfig()
+	config.URL = "https://example.com"
+	config.OutputPath = "output.mp4"
+	config.FilmstripPath = "filmstrip.jpg"
+	config.FilmstripMode = pipeline.FilmstripChanges
+	config.FilmstripColumns = 5
+
+	result, err := orch.Run(context.Background(), config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !filmstripStage.called {
+		t.Fatal("expected filmstrip stage to be called")
+	}
+	if filmstripStage.input.Format != ports.FormatJPEG {
+		t.Errorf("expected JPEG format for .jpg path, got %v", filmstripStage.input.Format)
+	}
+	if filmstripStage.input.Mode != pipeline.FilmstripChanges || filmstripStage.input.Columns != 5 {
+		t.Errorf("unexpected filmstrip input: mode=%s columns=%d", filmstripStage.input.Mode, filmstripStage.input.Columns)
+	}
+	if filmstripStage.input.IntervalMs != 100 {
+		t.Errorf("expected default interval 100ms, got %d", filmstripStage.input.IntervalMs)
+	}
+	if _, ok := mockFS.GetFile("filmstrip.jpg"); !ok {
+		t.Error("expected filmstrip file to be written")
+	}
+	if result.FilmstripFrames != 1 {
+		t.Errorf("expected 1 filmstrip frame, got %d", result.FilmstripFrames)
+	}
+}
+
+func TestOrchestrator_Run_WithoutFilmstrip(t *testing.T) {
+	filmstripStage := &mockFilmstripStage{}
+	orch := New(
+		&mockLayoutStage{},
+		&mockRecordStage{result: pipeline.RecordResult{Frames: []pipeline.RawFrame{{TimestampMs: 0}}}},
+		&mockBannerStage{},
+		&mockCompositeStage{},
+		&mockEncodeStage{result: pipeline.EncodeResult{VideoData: []byte{0x00}}},
+		filmstripStage,
+		&mockPosterStage{},
+		mocks.NewFileSystem(),
+		mocks.NewDebugSink(false),
+		logger.NewNoop(),
+	)
+
+	config := DefaultConfig()
+	config.OutputPath = "output.mp4"
+	if _, err := orch.Run(context.Background(), config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if filmstripStage.called {
+		t.Error("expected filmstrip stage to be skipped without FilmstripPath")
+	}
+}
+
+func TestOrchestrator_Run_WebMContainer(t *testing.T) {
+	encodeStage := &mockEncodeStage{result: pipeline.EncodeResult{VideoData: []byte{0x1A}}}
+	fs := mocks.NewFileSystem()
+	orch := New(
+		&mockLayoutStage{},
+		&mockRecordStage{result: pipeline.RecordResult{Frames: []pipeline.RawFrame{{TimestampMs: 0}}}},
+		&mockBannerStage{},
+		&mockCompositeStage{},
+		encodeStage,
+		&mockFilmstripStage{},
+		&mockPosterStage{},
+		fs,
+		mocks.NewDebugSink(false),
+		logger.NewNoop(),
+	)
+
+	config := DefaultConfig()
+	config.OutputPath = "output.webm"
+	config.Container = ports.ContainerWebM
+	if _, err := orch.Run(context.Background(), config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if encodeStage.input.Container != ports.ContainerWebM {
+		t.Errorf("expected container %q, got %q", ports.ContainerWebM, encodeStage.input.Container)
+	}
+	if _, ok := fs.GetAllFiles()["output.webm"]; !ok {
+		t.Error("expected output.webm to be written")
+	}
+}
+
+func TestOrchestrator_Run_EmbedMetadata(t *testing.T) {
+	encodeStage := &mockEncodeStage{result: pipeline.EncodeResult{VideoData: []byte{0x00}}}
+	record := pipeline.RecordResult{
+		Frames:   []pipeline.RawFrame{{TimestampMs: 0}},
+		PageInfo: ports.PageInfo{Title: "Example Domain"},
+		Timing: pipeline.TimingInfo{
+			DOMContentLoadedMs:       800,
+			LoadCompleteMs:           1500,
+			LargestContentfulPaintMs: 1200,
+		},
+		UserTimings: []pipeline.UserTiming{{Name: "hero", EntryType: "mark", StartMs: 600}},
+	}
+	orch := New(
+		&mockLayoutStage{},
+		&mockRecordStage{result: record},
+		&mockBannerStage{},
+		&mockCompositeStage{result: pipeline.CompositeResult{ScreenArea: pipeline.Rectangle{X: 16, Y: 96, Width: 480, Height: 600}}},
+		encodeStage,
+		&mockFilmstripStage{},
+		&mockPosterStage{},
+		mocks.NewFileSystem(),
+		mocks.NewDebugSink(false),
+		logger.NewNoop(),
+	)
+
+	config := DefaultConfig()
+	config.URL = "https://example.com/"
+	config.OutputPath = "output.mp4"
+	config.EmbedMetadata = true
+	config.Generator = "loadshow test"
+	config.VideoCRF = 30
+	config.TimingMarks = []TimingMark{{Name: "hero", Label: "Hero"}, {Name: "missing"}}
+	if _, err := orch.Run(context.Background(), config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	md := encodeStage.input.Metadata
+	if md == nil {
+		t.Fatal("expected metadata to be passed to the encoder")
+	}
+	if md.Title != "Example Domain" || md.URL != "https://example.com/" || md.Generator != "loadshow test" {
+		t.Errorf("unexpected metadata: %+v", md)
+	}
+	if md.RecordedAt.IsZero() {
+		t.Error("expected RecordedAt to be set")
+	}
+	if md.Settings["crf"] != "30" {
+		t.Errorf("expected crf setting 30, got %q", md.Settings["crf"])
+	}
+	if area, ok := ScreenArea(*md); !ok || area != (pipeline.Rectangle{X: 16, Y: 96, Width: 480, Height: 600}) {
+		t.Errorf("expected the screen area to round-trip, got %+v (%q)", area, md.Settings[ScreenAreaSetting])
+	}
+
+	want := []ports.Chapter{
+		{StartMs: 0, Title: "Start"},
+		{StartMs: 600, Title: "Hero"},
+		{StartMs: 800, Title: "DOMContentLoaded"},
+		{StartMs: 1200, Title: "LCP"},
+		{StartMs: 1500, Title: "Load"},
+	}
+	if len(md.Chapters) != len(want) {
+		t.Fatalf("expected %d chapters, got %+v", len(want), md.Chapters)
+	}
+	for i, c := range want {
+		if md.Chapters[i] != c {
+			t.Errorf("chapter %d: expected %+v, got %+v", i, c, md.Chapters[i])
+		}
+	}
+}
+
+func TestOrchestrator_Run_NoMetadataByDefault(t *testing.T) {
+	encodeStage := &mockEncodeStage{result: pipeline.EncodeResult{VideoData: []byte{0x00}}}
+	orch := New(
+		&mockLayoutStage{},
+		&mockRecordStage{result: pipeline.RecordResult{Frames: []pipeline.RawFrame{{TimestampMs: 0}}}},
+		&mockBannerStage{},
+		&mockCompositeStage{},
+		encodeStage,
+		&mockFilmstripStage{},
+		&mockPosterStage{},
+		mocks.NewFileSystem(),
+		mocks.NewDebugSink(false),
+		logger.NewNoop(),
+	)
+
+	config := DefaultConfig()
+	config.OutputPath = "output.mp4"
+	if _, err := orch.Run(context.Background(), config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if encodeStage.input.Metadata != nil {
+		t.Error("expected no metadata when EmbedMetadata is false")
+	}
+}
+
+func TestBuildSubtitleCues(t *testing.T) {
+	input := pipeline.CompositeInput{
+		DOMContentLoadedMs:       1230,
+		LoadCompleteMs:           2450,
+		LargestContentfulPaintMs: 1800,
+		TimingBadges: []pipeline.TimingBadge{
+			{Label: "Hero", TimestampMs: 1800},
+			{Label: "Late", TimestampMs: 9000},
+		},
+	}
+	composite := pipeline.CompositeResult{
+		Frames: []pipeline.ComposedFrame{{TimestampMs: 0}, {TimestampMs: 4000}},
+	}
+
+	cues := buildSubtitleCues(input, composite)
+
+	want := []ports.Cue{
+		{StartMs: 1230, EndMs: 1800, Text: "DOMContentLoaded 1.23s"},
+		{StartMs: 1800, EndMs: 2450, Text: "LCP 1.80s / Hero 1.80s"},
+		{StartMs: 2450, EndMs: 4000, Text: "Load 2.45s"},
+	}
+	if len(cues) != len(want) {
+		t.Fatalf("expected %d cues, got %+v", len(want), cues)
+	}
+	for i := range want {
+		if cues[i] != want[i] {
+			t.Errorf("cue %d: expected %+v, got %+v", i, want[i], cues[i])
+		}
+	}
+}
+
+func TestOrchestrator_Run_Subtitles(t *testing.T) {
+	encodeStage := &mockEncodeStage{result: pipeline.EncodeResult{VideoData: []byte{0x00}}}
+	fs := mocks.NewFileSystem()
+	record := pipeline.RecordResult{
+		Frames: []pipeline.RawFrame{{TimestampMs: 0}},
+		Timing: pipeline.TimingInfo{DOMContentLoadedMs: 500, LoadCompleteMs: 1000},
+	}
+	composite := pipeline.CompositeResult{
+		Frames: []pipeline.ComposedFrame{{TimestampMs: 0}, {TimestampMs: 5000}},
+	}
+	orch := New(
+		&mockLayoutStage{},
+		&mockRecordStage{result: record},
+		&mockBannerStage{},
+		&mockCompositeStage{result: composite},
+		encodeStage,
+		&mockFilmstripStage{},
+		&mockPosterStage{},
+		fs,
+		mocks.NewDebugSink(false),
+		logger.NewNoop(),
+	)
+
+	config := DefaultConfig()
+	config.OutputPath = "output.mp4"
+	config.Subtitles = true
+	config.SubtitlesPath = "output.vtt"
+	if _, err := orch.Run(context.Background(), config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(encodeStage.input.Subtitles) != 2 {
+		t.Errorf("expected 2 subtitle cues, got %+v", encodeStage.input.Subtitles)
+	}
+	vtt, ok := fs.GetAllFiles()["output.vtt"]
+	if !ok {
+		t.Fatal("expected output.vtt to be written")
+	}
+	if want := "00:00:00.500 --> 00:00:01.000\nDOMContentLoaded 0.50s"; !strings.Contains(string(vtt), want) {
+		t.Errorf("expected WebVTT to contain %q, got:\n%s", want, vtt)
+	}
+}
+
+func newMaxSizeOrchestrator(encodeStage *mockEncodeStage) *Orchestrator {
+	return New(
+		&mockLayoutStage{},
+		&mockRecordStage{result: pipeline.RecordResult{Frames: []pipeline.RawFrame{{TimestampMs: 0}}}},
+		&mockBannerStage{},
+		&mockCompositeStage{result: pipeline.CompositeResult{
+			Frames: []pipeline.ComposedFrame{{TimestampMs: 0}, {TimestampMs: 2000}},
+		}},
+		encodeStage,
+		&mockFilmstripStage{},
+		&mockPosterStage{},
+		mocks.NewFileSystem(),
+		mocks.NewDebugSink(false),
+		logger.NewNoop(),
+	)
+}
+
+func TestOrchestrator_Run_MaxSize(t *testing.T) {
+	tests := []struct {
+		name       string
+		crf        int
+		maxSize    int64
+		wantCRF    int
+		wantPasses int
+	}{
+		{"fits first pass", 30, 40000, 30, 1},
+		{"no limit", 30, 0, 30, 1},
+		{"raises CRF", 30, 20000, 44, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Size shrinks by 1000 bytes per CRF step
+			encodeStage := &mockEncodeStage{
+				result: pipeline.EncodeResult{VideoData: []byte{0x00}},
+				sizeOf: func(input pipeline.EncodeInput) int64 { return int64(64-input.VideoCRF) * 1000 },
+			}
+			orch := newMaxSizeOrchestrator(encodeStage)
+
+			config := DefaultConfig()
+			config.OutputPath = "output.mp4"
+			config.VideoCRF = tt.crf
+			config.MaxSize = tt.maxSize
+			config.EmbedMetadata = true
+			result, err := orch.Run(context.Background(), config)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if result.VideoCRF != tt.wantCRF {
+				t.Errorf("expected CRF %d, got %d", tt.wantCRF, result.VideoCRF)
+			}
+			if result.EncodePasses != tt.wantPasses || encodeStage.calls != tt.wantPasses {
+				t.Errorf("expected %d passes, got %d (%d calls)", tt.wantPasses, result.EncodePasses, encodeStage.calls)
+			}
+			if tt.maxSize > 0 && result.VideoFileSize > tt.maxSize {
+				t.Errorf("video size %d exceeds %d", result.VideoFileSize, tt.maxSize)
+			}
+			if got := encodeStage.input.Metadata.Settings["crf"]; got != strconv.Itoa(encodeStage.input.VideoCRF) {
+				t.Errorf("metadata crf %q does not match pass CRF %d", got, encodeStage.input.VideoCRF)
+			}
+		})
+	}
+}
+
+func TestOrchestrator_Run_MaxSizeBitrateFallback(t *testing.T) {
+	encodeStage := &mockEncodeStage{
+		result: pipeline.EncodeResult{VideoData: []byte{0x00}},
+		sizeOf: func(input pipeline.EncodeInput) int64 {
+			if input.Bitrate > 0 {
+				return int64(input.Bitrate) * 2000 / 8 // 2 seconds at the bitrate
+			}
+			return 100000 + int64(64-input.VideoCRF)*100 // Never fits by CRF alone
+		},
+	}
+	orch := newMaxSizeOrchestrator(encodeStage)
+
+	config := DefaultConfig()
+	config.OutputPath = "output.mp4"
+	config.VideoCRF = 30
+	config.Bitrate = 0
+	config.MaxSize = 50000
+	result, err := orch.Run(context.Background(), config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 90% of 50000 bytes over 2000ms plus one frame at 30 fps
+	if result.VideoCRF != maxCRF || encodeStage.input.Bitrate != 176 {
+		t.Errorf("expected CRF %d at 176 kbps, got CRF %d at %d kbps", maxCRF, result.VideoCRF, encodeStage.input.Bitrate)
+	}
+	if result.EncodePasses > maxSizePasses {
+		t.Errorf("expected at most %d passes, got %d", maxSizePasses, result.EncodePasses)
+	}
+}
+
+func TestOrchestrator_Run_MaxSizeIgnoredCRF(t *testing.T) {
+	// Like Media Foundation, the size only follows the bitrate
+	encodeStage := &mockEncodeStage{
+		result: pipeline.EncodeResult{VideoData: []byte{0x00}},
+		sizeOf: func(input pipeline.EncodeInput) int64 {
+			if input.Bitrate > 0 {
+				return int64(input.Bitrate) * 2000 / 8
+			}
+			return 100000
+		},
+	}
+	orch := newMaxSizeOrchestrator(encodeStage)
+
+	config := DefaultConfig()
+	config.OutputPath = "output.mp4"
+	config.VideoCRF = 30
+	config.Bitrate = 0
+	config.MaxSize = 50000
+	result, err := orch.Run(context.Background(), config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The first pass, one CRF pass that does not shrink, then the bitrate cap
+	if result.EncodePasses != 3 || encodeStage.calls != 3 {
+		t.Errorf("expected 3 passes, got %d (%d calls)", result.EncodePasses, encodeStage.calls)
+	}
+	if result.VideoCRF != 30 || encodeStage.input.Bitrate != 176 || result.VideoFileSize > config.MaxSize {
+		t.Errorf("expected CRF 30 at 176 kbps within the limit, got CRF %d at %d kbps, %d bytes",
+			result.VideoCRF, encodeStage.input.Bitrate, result.VideoFileSize)
+	}
+}
+
+func TestOrchestrator_Run_MaxSizeMJPEG(t *testing.T) {
+	encoder, _, err := smartencoder.New(smartencoder.CodecMJPEG, smartencoder.Options{})
+	if err != nil {
+		t.Fatalf("failed to create encoder: %v", err)
+	}
+
+	// Noise does not shrink below the limit by JPEG quality alone
+	rng := rand.New(rand.NewSource(1))
+	frames := make([]pipeline.ComposedFrame, 10)
+	for i := range frames {
+		img := image.NewRGBA(image.Rect(0, 0, 160, 120))
+		for p := range img.Pix {
+			img.Pix[p] = uint8(rng.Intn(256))
+		}
+		frames[i] = pipeline.ComposedFrame{TimestampMs: i * 100, Image: img}
+	}
+
+	orch := New(
+		&mockLayoutStage{},
+		&mockRecordStage{result: pipeline.RecordResult{Frames: []pipeline.RawFrame{{TimestampMs: 0}}}},
+		&mockBannerStage{},
+		&mockCompositeStage{result: pipeline.CompositeResult{Frames: frames}},
+		encode.NewStage(encoder, logger.NewNoop()),
+		&mockFilmstripStage{},
+		&mockPosterStage{},
+		mocks.NewFileSystem(),
+		mocks.NewDebugSink(false),
+		logger.NewNoop(),
+	)
+
+	config := DefaultConfig()
+	config.OutputPath = "output.mp4"
+	config.FPS = 10
+	config.Bitrate = 0
+	config.MaxSize = 50000
+	result, err := orch.Run(context.Background(), config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.VideoFileSize > config.MaxSize {
+		t.Errorf("video size %d exceeds %d", result.VideoFileSize, config.MaxSize)
+	}
+}
+
+func TestOrchestrator_Run_MaxSizeUnreachable(t *testing.T) {
+	encodeStage := &mockEncodeStage{
+		result: pipeline.EncodeResult{VideoData: []byte{0x00}},
+		sizeOf: func(input pipeline.EncodeInput) int64 { return 100000 },
+	}
+	orch := newMaxSizeOrchestrator(encodeStage)
+
+	config := DefaultConfig()
+	config.OutputPath = "output.mp4"
+	config.MaxSize = 1000
+	if _, err := orch.Run(context.Background(), config); err == nil {
+		t.Fatal("expected error when the video cannot fit")
+	}
+	if encodeStage.calls > maxSizePasses {
+		t.Errorf("expected at most %d passes, got %d", maxSizePasses, encodeStage.calls)
+	}
+}
+
+func TestOrchestrator_Run_Poster(t *testing.T) {
+	posterStage := &mockPosterStage{
+		result: pipeline.PosterResult{
+			TimestampMs: 1200,
+			ImageData:   []byte{0xFF, 0xD8},
+			Width:       512,
+			Height:      640,
+			Thumbnails: []pipeline.Thumbnail{
+				{ImageData: []byte{0x01}, Width: 320, Height: 400},
+				{ImageData: []byte{0x02}, Width: 160, Height: 200},
+			},
+		},
+	}
+	fs := mocks.NewFileSystem()
+	record := pipeline.RecordResult{
+		Frames: []pipeline.RawFrame{{TimestampMs: 0}},
+		Timing: pipeline.TimingInfo{LoadCompleteMs: 1250, LargestContentfulPaintMs: 1200},
+	}
+	orch := New(
+		&mockLayoutStage{},
+		&mockRecordStage{result: record},
+		&mockBannerStage{},
+		&mockCompositeStage{result: pipeline.CompositeResult{Frames: []pipeline.ComposedFrame{{TimestampMs: 0}}}},
+		&mockEncodeStage{result: pipeline.EncodeResult{VideoData: []byte{0x00}}},
+		&mockFilmstripStage{},
+		posterStage,
+		fs,
+		mocks.NewDebugSink(false),
+		logger.NewNoop(),
+	)
+
+	config := DefaultConfig()
+	config.OutputPath = "output.mp4"
+	config.PosterPath = "out/poster.jpg"
+	config.PosterFrame = pipeline.PosterLCP
+	config.ThumbnailWidths = []int{320, 160}
+	result, err := orch.Run(context.Background(), config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if posterStage.input.Frame != pipeline.PosterLCP || posterStage.input.TimestampMs != 1200 {
+		t.Errorf("expected LCP frame at 1200ms, got %s at %dms", posterStage.input.Frame, posterStage.input.TimestampMs)
+	}
+	if posterStage.input.Format != ports.FormatJPEG {
+		t.Errorf("expected JPEG format for .jpg path, got %v", posterStage.input.Format)
+	}
+
+	files := fs.GetAllFiles()
+	for _, path := range []string{"out/poster.jpg", "out/poster-320w.jpg", "out/poster-160w.jpg"} {
+		if _, ok := files[path]; !ok {
+			t.Errorf("expected %s to be written", path)
+		}
+	}
+
+	if result.Poster == nil {
+		t.Fatal("expected poster in result")
+	}
+	if result.Poster.Path != "out/poster.jpg" || result.Poster.TimestampMs != 1200 {
+		t.Errorf("unexpected poster result: %+v", result.Poster)
+	}
+	if len(result.Poster.Thumbnails) != 2 || result.Poster.Thumbnails[1].Path != "out/poster-160w.jpg" {
+		t.Errorf("unexpected thumbnails: %+v", result.Poster.Thumbnails)
+	}
+}
+
+func TestResolvePosterFrame(t *testing.T) {
+	tests := []struct {
+		name      string
+		frame     pipeline.PosterFrame
+		timing    pipeline.TimingInfo
+		wantFrame pipeline.PosterFrame
+		wantMs    int
+	}{
+		{"final", pipeline.PosterFinal, pipeline.TimingInfo{LoadCompleteMs: 900}, pipeline.PosterFinal, 0},
+		{"default", "", pipeline.TimingInfo{}, pipeline.PosterFinal, 0},
+		{"time", pipeline.PosterTime, pipeline.TimingInfo{}, pipeline.PosterTime, 750},
+		{"lcp", pipeline.PosterLCP, pipeline.TimingInfo{LoadCompleteMs: 900, LargestContentfulPaintMs: 600}, pipeline.PosterLCP, 600},
+		{"lcp falls back to load", pipeline.PosterLCP, pipeline.TimingInfo{LoadCompleteMs: 900}, pipeline.PosterLoad, 900},
+		{"load falls back to final", pipeline.PosterLoad, pipeline.TimingInfo{}, pipeline.PosterFinal, 0},
+	}
+
+	orch := New(nil, nil, nil, nil, nil, nil, nil, nil, nil, logger.NewNoop())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Config{PosterFrame: tt.frame, PosterTimeMs: 750}
+			frame, ms := orch.resolvePosterFrame(config, tt.timing)
+			if frame != tt.wantFrame || ms != tt.wantMs {
+				t.Errorf("got %s at %dms, want %s at %dms", frame, ms, tt.wantFrame, tt.wantMs)
+			}
+		})
+	}
+}
+
+func TestThumbnailPath(t *testing.T) {
+	if got := ThumbnailPath("dir/poster.png", 320); got != "dir/poster-320w.png" {
+		t.Errorf("ThumbnailPath = %q", got)
+	}
+	if got := ThumbnailPath("poster", 80); got != "poster-80w" {
+		t.Errorf("ThumbnailPath = %q", got)
+	}
+}
+
+func TestFrameResults(t *testing.T) {
+	results := frameResults([]pipeline.RawFrame{
+		{TimestampMs: 0, TotalBytes: 0, LoadedResources: 0, TotalResources: 4},
+		{TimestampMs: 100, TotalBytes: 500, LoadedResources: 2, TotalResources: 4},
+		{TimestampMs: 200, TotalBytes: 1000, LoadedResources: 4, TotalResources: 4},
+	})
+
+	wantProgress := []float64{0, 0.5, 1}
+	for i, want := range wantProgress {
+		if results[i].Progress != want {
+			t.Errorf("frame %d progress = %v, want %v", i, results[i].Progress, want)
+		}
+	}
+	if results[1].TimestampMs != 100 || results[1].LoadedResources != 2 || results[1].TotalBytes != 500 {
+		t.Errorf("unexpected frame result: %+v", results[1])
+	}
+
+	// Without traffic only the last frame is complete
+	results = frameResults([]pipeline.RawFrame{{TimestampMs: 0}, {TimestampMs: 100}})
+	if results[0].Progress != 0 || results[1].Progress != 1 {
+		t.Errorf("progress without traffic = %v, %v; want 0, 1", results[0].Progress, results[1].Progress)
+	}
+}
+
+func TestGetTotalResources(t *testing.T) {
+	frames := []pipeline.RawFrame{{TotalResources: 4}, {TotalResources: 12}}
+	if got := getTotalResources(frames); got != 12 {
+		t.Errorf("getTotalResources = %d, want 12", got)
+	}
+	if got := getTotalResources(nil); got != 0 {
+		t.Errorf("getTotalResources without frames = %d, want 0", got)
+	}
+}
+
+func TestUserTimingTimes(t *testing.T) {
+	times := userTimingTimes([]pipeline.UserTiming{
+		{Name: "hero", EntryType: "mark", StartMs: 900},
+		{Name: "hero", EntryType: "mark", StartMs: 1500},
+		{Name: "fetch", EntryType: "measure", StartMs: 200, DurationMs: 300},
+	})
+	if times["hero"] != 900 || times["fetch"] != 500 || len(times) != 2 {
+		t.Errorf("userTimingTimes = %v, want hero 900 and fetch 500", times)
+	}
+}
+
+func TestFinalLayoutShift(t *testing.T) {
+	// Unordered shifts from the browser are sorted into session windows
+	cls := finalLayoutShift([]pipeline.LayoutShift{
+		{TimestampMs: 3000, Score: 0.08},
+		{TimestampMs: 100, Score: 0.05},
+		{TimestampMs: 500, Score: 0.05},
+		{TimestampMs: 3500, Score: 0.04},
+	})
+	if math.Abs(cls-0.12) > 1e-9 {
+		t.Errorf("finalLayoutShift = %f, want 0.12", cls)
+	}
+}
@@ -0,0 +1,40 @@
+package pipeline
+
+import "math"
+
+// clsSessionGapMs and clsSessionMaxMs define CLS session windows
+// (shifts less than 1s apart, with a 5s cap per window).
+const (
+	clsSessionGapMs = 1000
+	clsSessionMaxMs = 5000
+)
+
+// CumulativeLayoutShift returns the CLS score at the given time.
+// CLS is the largest sum of shift scores within a session window, ignoring shifts after user input.
+// Shifts must be sorted by timestamp.
+func CumulativeLayoutShift(shifts []LayoutShift, untilMs int) float64 {
+	var cls, session float64
+	sessionStart, lastShift := -1, -1
+
+	for _, shift := range shifts {
+		if shift.TimestampMs > untilMs {
+			break
+		}
+		if shift.HadRecentInput {
+			continue
+		}
+
+		if sessionStart < 0 ||
+			shift.TimestampMs-lastShift >= clsSessionGapMs ||
+			shift.TimestampMs-sessionStart >= clsSessionMaxMs {
+			sessionStart = shift.TimestampMs
+			session = 0
+		}
+		session += shift.Score
+		lastShift = shift.TimestampMs
+
+		cls = math.Max(cls, session)
+	}
+
+	return cls
+}
@@ -0,0 +1,34 @@
+package pipeline
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCumulativeLayoutShift(t *testing.T) {
+	shifts := []LayoutShift{
+		{TimestampMs: 100, Score: 0.05},
+		{TimestampMs: 500, Score: 0.05},
+		{TimestampMs: 700, Score: 0.3, HadRecentInput: true}, // excluded
+		{TimestampMs: 3000, Score: 0.08},                     // new session window
+		{TimestampMs: 3500, Score: 0.04},
+	}
+
+	tests := []struct {
+		untilMs int
+		want    float64
+	}{
+		{untilMs: 0, want: 0},
+		{untilMs: 100, want: 0.05},
+		{untilMs: 1000, want: 0.10},
+		{untilMs: 3000, want: 0.10}, // second window (0.08) is smaller
+		{untilMs: 4000, want: 0.12}, // second window grows to 0.12
+	}
+
+	for _, tt := range tests {
+		got := CumulativeLayoutShift(shifts, tt.untilMs)
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("CumulativeLayoutShift(%d) = %f, want %f", tt.untilMs, got, tt.want)
+		}
+	}
+}
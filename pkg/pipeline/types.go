@@ -25,22 +25,31 @@ type Rectangle struct {
 	Height int
 }
 
+// Insets represents edge thicknesses around a rectangle.
+type Insets struct {
+	Top    int
+	Right  int
+	Bottom int
+	Left   int
+}
+
 // =============================================================================
 // Layout Stage Types
 // =============================================================================
 
 // LayoutInput contains parameters for layout calculation.
 type LayoutInput struct {
-	CanvasWidth    int // Total canvas width (default: 512)
-	CanvasHeight   int // Total canvas height (default: 640)
-	Columns        int // Number of columns (default: 3)
-	Gap            int // Gap between columns (default: 20)
-	Padding        int // Padding around the canvas (default: 20)
-	BorderWidth    int // Border width for column frames (default: 1)
-	Indent         int // Indent for non-first columns (default: 20)
-	Outdent        int // Outdent for first column (default: 20)
-	BannerHeight   int // Height of banner area (default: 0)
-	ProgressHeight int // Height of progress bar (default: 16)
+	CanvasWidth    int    // Total canvas width (default: 512)
+	CanvasHeight   int    // Total canvas height (default: 640)
+	Columns        int    // Number of columns (default: 3)
+	Gap            int    // Gap between columns (default: 20)
+	Padding        int    // Padding around the canvas (default: 20)
+	BorderWidth    int    // Border width for column frames (default: 1)
+	Indent         int    // Indent for non-first columns (default: 20)
+	Outdent        int    // Outdent for first column (default: 20)
+	BannerHeight   int    // Height of banner area (default: 0)
+	ProgressHeight int    // Height of progress bar (default: 16)
+	Bezel          Insets // Device frame thickness inside each column (default: none)
 }
 
 // DefaultLayoutInput returns LayoutInput with default values.
@@ -123,9 +132,15 @@ func DefaultRecordInput() RecordInput {
 
 // RecordResult contains the recording output.
 type RecordResult struct {
-	Frames   []RawFrame
-	PageInfo ports.PageInfo
-	Timing   TimingInfo
+	Frames               []RawFrame
+	PageInfo             ports.PageInfo
+	Timing               TimingInfo
+	LayoutShifts         []LayoutShift  // Layout shifts observed during recording
+	LayoutShiftsRecorded bool           // False if the layout shifts could not be collected
+	LCPCandidates        []LCPCandidate // Largest contentful paint candidates in paint order
+	UserTimings          []UserTiming   // User-timing marks and measures recorded by the page
+	Requests             []Request      // Network requests in start order (document first)
+	ViewportWidth        int            // Browser window width in CSS pixels used for capture
 }
 
 // RawFrame represents a single recorded frame.
@@ -137,14 +152,57 @@ type RawFrame struct {
 	TotalBytes      int64  // Total bytes transferred
 }
 
+// LayoutShift represents a layout shift observed during recording.
+// Rects are in CSS pixels relative to the viewport, which is not scrolled during recording.
+type LayoutShift struct {
+	TimestampMs    int         // Time of the shift in milliseconds since navigation start
+	Score          float64     // Layout shift score
+	HadRecentInput bool        // True if the shift followed user input (excluded from CLS)
+	PreviousRects  []Rectangle // Element rects before the shift
+	CurrentRects   []Rectangle // Element rects after the shift
+}
+
+// LCPCandidate represents a largest contentful paint candidate element.
+// Rect is in CSS pixels relative to the document.
+type LCPCandidate struct {
+	TimestampMs int       // Time the element was painted in milliseconds since navigation start
+	Size        int64     // Painted area in CSS pixels
+	Element     string    // Tag name of the element
+	URL         string    // Resource URL for image elements
+	Rect        Rectangle // Element rect (zero if the element was removed)
+}
+
+// UserTiming represents a user-timing mark or measure recorded by the page.
+type UserTiming struct {
+	Name       string // Mark or measure name
+	EntryType  string // "mark" or "measure"
+	StartMs    int    // Start time in milliseconds since navigation start
+	DurationMs int    // Duration in milliseconds (0 for marks)
+}
+
+// EndMs returns the time the entry completed (the start time for marks).
+func (u UserTiming) EndMs() int {
+	return u.StartMs + u.DurationMs
+}
+
+// Request represents a network request made while loading the page.
+type Request struct {
+	URL          string // Requested URL
+	Type         string // Initiator type ("navigation" for the document)
+	StartMs      int    // Request start in milliseconds since navigation start
+	DurationMs   int    // Time until the response finished
+	TransferSize int64  // Bytes transferred (0 if cached or not exposed cross-origin)
+}
+
 // TimingInfo contains page load timing information.
 type TimingInfo struct {
-	NavigationStartMs  int
-	DOMContentLoadedMs int
-	LoadCompleteMs     int
-	TotalDurationMs    int
-	TimedOut           bool // True if recording ended due to timeout
-	TimeoutSec         int  // Timeout value in seconds
+	NavigationStartMs        int
+	DOMContentLoadedMs       int
+	LoadCompleteMs           int
+	LargestContentfulPaintMs int // Time of the final LCP candidate (0 = not available)
+	TotalDurationMs          int
+	TimedOut                 bool // True if recording ended due to timeout
+	TimeoutSec               int  // Timeout value in seconds
 }
 
 // =============================================================================
@@ -202,33 +260,91 @@ type CompositeInput struct {
 	// Timing badges
 	DOMContentLoadedMs int // DOMContentLoaded timing in ms (0 = not available)
 	LoadCompleteMs     int // OnLoad timing in ms (0 = not available)
+	// Change highlighting
+	HighlightChanges bool // Draw translucent boxes over regions that changed since the previous frame
+	HighlightFadeMs  int  // Duration in ms for a highlight to fade out (0 = default 500ms)
+	// Layout shift visualization
+	ShowLayoutShifts bool          // Draw moved elements and the running CLS badge
+	LayoutShifts     []LayoutShift // Layout shifts observed during recording
+	ViewportWidth    int           // Browser window width in CSS pixels (for scaling page rects)
+	// Largest contentful paint
+	ShowLCP                  bool           // Outline the current LCP candidate and show the LCP badge
+	LCPCandidates            []LCPCandidate // LCP candidates in paint order
+	LargestContentfulPaintMs int            // LCP timing in ms (0 = not available)
+	// Custom badges from user-timing marks
+	TimingBadges []TimingBadge // Shown once the frame timestamp reaches each badge
+	// Device frame
+	Frame      FrameStyle // Frame drawn around each column instead of the border
+	FrameImage []byte     // PNG data for FrameImage
+	FrameSlice Insets     // 9-slice insets of FrameImage in pixels
+}
+
+// TimingBadge is a custom badge shown on the progress bar from a given time onward.
+type TimingBadge struct {
+	Label       string
+	TimestampMs int         // Time in ms since navigation start
+	Color       color.Color // Badge color (nil = theme UserTimingBadgeColor)
+}
+
+// FrameStyle selects the device frame drawn around each column.
+type FrameStyle string
+
+const (
+	FrameNone    FrameStyle = ""        // Plain column borders
+	FramePhone   FrameStyle = "phone"   // Phone bezel with speaker and home indicator
+	FrameBrowser FrameStyle = "browser" // Browser window with title bar and address bar
+	FrameImage   FrameStyle = "image"   // User-supplied 9-slice PNG
+)
+
+// FrameInsets returns the bezel thickness of a built-in frame style.
+// FrameImage uses the 9-slice insets instead.
+func FrameInsets(style FrameStyle) Insets {
+	switch style {
+	case FramePhone:
+		return Insets{Top: 20, Right: 6, Bottom: 20, Left: 6}
+	case FrameBrowser:
+		return Insets{Top: 18, Right: 2, Bottom: 2, Left: 2}
+	default:
+		return Insets{}
+	}
 }
 
 // CompositeTheme defines composition styling.
 type CompositeTheme struct {
-	BackgroundColor  color.Color
-	BorderColor      color.Color
-	ProgressBarColor color.Color
-	ProgressBgColor  color.Color
-	DCLBadgeColor    color.Color // Badge color for DOMContentLoaded
-	LoadBadgeColor   color.Color // Badge color for OnLoad
+	BackgroundColor      color.Color
+	BorderColor          color.Color
+	ProgressBarColor     color.Color
+	ProgressBgColor      color.Color
+	DCLBadgeColor        color.Color // Badge color for DOMContentLoaded
+	LoadBadgeColor       color.Color // Badge color for OnLoad
+	HighlightColor       color.Color // Color for changed-region highlights
+	LayoutShiftColor     color.Color // Outline color for shifted elements
+	CLSBadgeColor        color.Color // Badge color for the running CLS score
+	LCPBadgeColor        color.Color // Badge and outline color for LargestContentfulPaint
+	UserTimingBadgeColor color.Color // Default badge color for user-timing marks
 }
 
 // DefaultCompositeTheme returns a default composite theme.
 func DefaultCompositeTheme() CompositeTheme {
 	return CompositeTheme{
-		BackgroundColor:  color.RGBA{R: 220, G: 220, B: 220, A: 255}, // #dcdcdc 白っぽいグレー
-		BorderColor:      color.RGBA{R: 180, G: 180, B: 180, A: 255}, // #b4b4b4 少し濃いグレー
-		ProgressBarColor: color.RGBA{R: 76, G: 175, B: 80, A: 255},   // #4caf50
-		ProgressBgColor:  color.RGBA{R: 80, G: 80, B: 80, A: 255},    // #505050 濃いめのグレー
-		DCLBadgeColor:    color.RGBA{R: 66, G: 133, B: 244, A: 255},  // #4285F4 青（DevTools準拠）
-		LoadBadgeColor:   color.RGBA{R: 211, G: 75, B: 62, A: 255},   // #D34B3E 赤（DevTools準拠）
+		BackgroundColor:      color.RGBA{R: 220, G: 220, B: 220, A: 255}, // #dcdcdc 白っぽいグレー
+		BorderColor:          color.RGBA{R: 180, G: 180, B: 180, A: 255}, // #b4b4b4 少し濃いグレー
+		ProgressBarColor:     color.RGBA{R: 76, G: 175, B: 80, A: 255},   // #4caf50
+		ProgressBgColor:      color.RGBA{R: 80, G: 80, B: 80, A: 255},    // #505050 濃いめのグレー
+		DCLBadgeColor:        color.RGBA{R: 66, G: 133, B: 244, A: 255},  // #4285F4 青（DevTools準拠）
+		LoadBadgeColor:       color.RGBA{R: 211, G: 75, B: 62, A: 255},   // #D34B3E 赤（DevTools準拠）
+		HighlightColor:       color.RGBA{R: 255, G: 193, B: 7, A: 255},   // #FFC107 アンバー
+		LayoutShiftColor:     color.RGBA{R: 233, G: 30, B: 99, A: 255},   // #E91E63 ピンク
+		CLSBadgeColor:        color.RGBA{R: 142, G: 36, B: 170, A: 255},  // #8E24AA 紫
+		LCPBadgeColor:        color.RGBA{R: 15, G: 157, B: 88, A: 255},   // #0F9D58 緑
+		UserTimingBadgeColor: color.RGBA{R: 245, G: 124, B: 0, A: 255},   // #F57C00 オレンジ
 	}
 }
 
 // CompositeResult contains the composed frames.
 type CompositeResult struct {
-	Frames []ComposedFrame
+	Frames     []ComposedFrame
+	ScreenArea Rectangle // Area of the frames showing the page, below the banner and progress bar
 }
 
 // ComposedFrame represents a fully composed frame.
@@ -243,10 +359,16 @@ type ComposedFrame struct {
 
 // EncodeInput contains parameters for video encoding.
 type EncodeInput struct {
-	Frames   []ComposedFrame
-	VideoCRF int     // CRF: 0-63 (lower is higher quality)
-	Bitrate  int     // Target bitrate in kbps
-	FPS      float64 // Frames per second
+	Frames    []ComposedFrame
+	VideoCRF  int             // CRF: 0-63 (lower is higher quality)
+	Bitrate   int             // Target bitrate in kbps
+	FPS       float64         // Frames per second
+	Container ports.Container // Output container (empty means MP4)
+
+	// Metadata, when set, is embedded in the MP4 output (title, URL, chapters, ...)
+	Metadata *ports.VideoMetadata
+	// Subtitles are added to the MP4 output as a soft-subtitle track
+	Subtitles []ports.Cue
 }
 
 // DefaultEncodeInput returns EncodeInput with default values.
@@ -264,3 +386,119 @@ type EncodeResult struct {
 	DurationMs int
 	FileSize   int64
 }
+
+// =============================================================================
+// Filmstrip Stage Types
+// =============================================================================
+
+// FilmstripMode selects how frames are sampled for a filmstrip.
+type FilmstripMode string
+
+const (
+	FilmstripInterval FilmstripMode = "interval" // One frame every IntervalMs
+	FilmstripChanges  FilmstripMode = "changes"  // One frame per visual change
+)
+
+// FilmstripInput contains parameters for filmstrip generation.
+type FilmstripInput struct {
+	Frames      []ComposedFrame
+	RawFrames   []RawFrame // Recorded frames matching Frames, compared for visual changes (nil = compare Frames)
+	CompareArea Rectangle  // Area of Frames compared for visual changes when RawFrames is nil (zero = whole frame)
+	Mode        FilmstripMode
+	IntervalMs  int               // Sampling interval for FilmstripInterval
+	EndMs       int               // Last sampled time (0 = last frame)
+	Columns     int               // Frames per row (0 = single row)
+	ThumbWidth  int               // Thumbnail width in pixels (height keeps the aspect ratio)
+	Format      ports.ImageFormat // Output image format
+	Quality     int               // JPEG quality (0-100)
+	Theme       FilmstripTheme
+}
+
+// DefaultFilmstripInput returns FilmstripInput with default values.
+func DefaultFilmstripInput() FilmstripInput {
+	return FilmstripInput{
+		Mode:       FilmstripInterval,
+		IntervalMs: 100,
+		ThumbWidth: 160,
+		Format:     ports.FormatPNG,
+		Quality:    90,
+		Theme:      DefaultFilmstripTheme(),
+	}
+}
+
+// FilmstripTheme defines filmstrip styling.
+type FilmstripTheme struct {
+	BackgroundColor color.Color
+	BorderColor     color.Color // Outline of unchanged frames
+	ChangedColor    color.Color // Outline of frames where the page visually changed
+	LabelColor      color.Color
+	LabelFontSize   float64
+}
+
+// DefaultFilmstripTheme returns a default filmstrip theme.
+func DefaultFilmstripTheme() FilmstripTheme {
+	return FilmstripTheme{
+		BackgroundColor: color.White,
+		BorderColor:     color.RGBA{R: 200, G: 200, B: 200, A: 255}, // #c8c8c8
+		ChangedColor:    color.RGBA{R: 255, G: 193, B: 7, A: 255},   // #FFC107 アンバー（変化ハイライトと同色）
+		LabelColor:      color.RGBA{R: 51, G: 51, B: 51, A: 255},    // #333333
+		LabelFontSize:   12,
+	}
+}
+
+// FilmstripResult contains the encoded filmstrip image.
+type FilmstripResult struct {
+	ImageData  []byte
+	Width      int
+	Height     int
+	FrameCount int // Number of sampled frames
+}
+
+// =============================================================================
+// Poster Stage Types
+// =============================================================================
+
+// PosterFrame selects which composed frame becomes the poster image.
+type PosterFrame string
+
+const (
+	PosterFinal PosterFrame = "final" // Last frame (the fully loaded page)
+	PosterLCP   PosterFrame = "lcp"   // Frame shown at Largest Contentful Paint
+	PosterLoad  PosterFrame = "load"  // Frame shown at the load event
+	PosterTime  PosterFrame = "time"  // Frame shown at PosterInput.TimestampMs
+)
+
+// PosterInput contains parameters for poster and thumbnail generation.
+type PosterInput struct {
+	Frames      []ComposedFrame
+	Frame       PosterFrame       // Which frame to use
+	TimestampMs int               // Poster time for every mode except PosterFinal
+	ThumbWidths []int             // Thumbnail widths in pixels (height keeps the aspect ratio)
+	Format      ports.ImageFormat // Output image format
+	Quality     int               // JPEG quality (0-100)
+}
+
+// DefaultPosterInput returns PosterInput with default values.
+func DefaultPosterInput() PosterInput {
+	return PosterInput{
+		Frame:   PosterFinal,
+		Format:  ports.FormatPNG,
+		Quality: 90,
+	}
+}
+
+// PosterResult contains the encoded poster and thumbnails.
+type PosterResult struct {
+	TimestampMs int // Timestamp of the selected frame
+	ImageData   []byte
+	Width       int
+	Height      int
+	Thumbnails  []Thumbnail // In the order of PosterInput.ThumbWidths
+}
+
+// Thumbnail is a downscaled copy of the poster image.
+type Thumbnail struct {
+	ImageData []byte
+	Width     int
+	Height    int
+}
@@ -40,6 +40,18 @@ type Browser interface {
 	// GetPerformanceTiming retrieves navigation timing metrics.
 	GetPerformanceTiming() (*PerformanceTiming, error)
 
+	// GetLayoutShifts retrieves layout-shift entries observed since navigation start.
+	GetLayoutShifts() ([]LayoutShift, error)
+
+	// GetLCPCandidates retrieves largest-contentful-paint candidates observed since navigation start.
+	GetLCPCandidates() ([]LCPCandidate, error)
+
+	// GetUserTimings retrieves user-timing marks and measures recorded by the page.
+	GetUserTimings() ([]UserTiming, error)
+
+	// GetResourceTimings retrieves resource-timing entries for the document and the requests it made.
+	GetResourceTimings() ([]ResourceTiming, error)
+
 	// Close shuts down the browser.
 	Close() error
 }
@@ -93,3 +105,53 @@ type PerformanceTiming struct {
 	DOMContentLoadedEnd int64 // When DOMContentLoaded event completed
 	LoadEventEnd        int64 // When load event completed
 }
+
+// LayoutShift represents a layout-shift entry from the Layout Instability API.
+type LayoutShift struct {
+	StartTime      int64   // When the shift occurred, in ms since navigation start
+	Value          float64 // Layout shift score
+	HadRecentInput bool    // True if the shift followed user input (excluded from CLS)
+	Sources        []LayoutShiftSource
+}
+
+// LayoutShiftSource describes an element that moved during a layout shift.
+// Rects are in CSS pixels relative to the viewport.
+type LayoutShiftSource struct {
+	PreviousRect Rect
+	CurrentRect  Rect
+}
+
+// LCPCandidate represents a largest-contentful-paint entry.
+// The last candidate is the element that determines LCP.
+type LCPCandidate struct {
+	StartTime int64  // When the element was painted, in ms since navigation start
+	Size      int64  // Painted area of the element in CSS pixels
+	Element   string // Tag name of the element (empty if removed from the DOM)
+	URL       string // Resource URL for image elements
+	Rect      Rect   // Element rect in CSS pixels relative to the document
+}
+
+// UserTiming represents a performance.mark() or performance.measure() entry.
+type UserTiming struct {
+	Name      string // Mark or measure name
+	EntryType string // "mark" or "measure"
+	StartTime int64  // Start time in ms since navigation start
+	Duration  int64  // Duration in ms (0 for marks)
+}
+
+// ResourceTiming represents a navigation or resource entry from the Resource Timing API.
+type ResourceTiming struct {
+	URL           string // Requested URL
+	InitiatorType string // "navigation" for the document, otherwise e.g. "script", "img", "css", "fetch"
+	StartTime     int64  // Request start in ms since navigation start
+	Duration      int64  // Time until the response finished, in ms
+	TransferSize  int64  // Bytes transferred (0 if cached or cross-origin without Timing-Allow-Origin)
+}
+
+// Rect is a rectangle in CSS pixels.
+type Rect struct {
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+}
@@ -20,6 +20,28 @@ type VideoDecoder interface {
 	// ReadFramesFromReader reads and decodes all frames from an io.ReadSeeker.
 	ReadFramesFromReader(reader io.ReadSeeker) ([]VideoFrame, error)
 
+	// OpenFrames opens a video file for incremental decoding.
+	// Only compressed samples are kept in memory; frames are decoded on demand.
+	OpenFrames(path string) (FrameIterator, error)
+
+	// Close releases decoder resources.
+	Close()
+}
+
+// FrameIterator decodes the frames of a video one at a time.
+type FrameIterator interface {
+	// Next decodes and returns the next frame.
+	// It returns io.EOF after the last frame.
+	Next() (VideoFrame, error)
+
+	// Seek positions the iterator so that the next call to Next returns
+	// the frame shown at the given timestamp (the first frame if the
+	// timestamp is before it).
+	Seek(timestampMs int) error
+
+	// DurationMs returns the end time of the last frame in milliseconds.
+	DurationMs() int
+
 	// Close releases decoder resources.
 	Close()
 }
@@ -16,8 +16,17 @@ type VideoEncoder interface {
 	End() ([]byte, error)
 }
 
+// Container selects the file format the encoded stream is muxed into.
+type Container string
+
+const (
+	ContainerMP4  Container = "mp4"
+	ContainerWebM Container = "webm"
+)
+
 // EncoderOptions configures video encoding parameters.
 type EncoderOptions struct {
-	Bitrate int // Target bitrate in kbps
-	Quality int // CRF value: 0-63 (lower is higher quality)
+	Bitrate   int       // Target bitrate in kbps
+	Quality   int       // CRF value: 0-63 (lower is higher quality)
+	Container Container // Output container (empty means MP4)
 }
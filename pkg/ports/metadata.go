@@ -0,0 +1,26 @@
+package ports
+
+import "time"
+
+// VideoMetadata describes a recording so it survives once the video is shared.
+type VideoMetadata struct {
+	Title      string            // Page title
+	URL        string            // Recorded URL
+	RecordedAt time.Time         // When the recording was made
+	Generator  string            // Software that produced the video (e.g., "loadshow 1.2.0")
+	Settings   map[string]string // Recording settings (viewport, throttling, quality, ...)
+	Chapters   []Chapter         // Markers at page load milestones, in time order
+}
+
+// Chapter marks a point of interest in a video.
+type Chapter struct {
+	StartMs int    // Chapter start in milliseconds from the beginning of the video
+	Title   string // Chapter title (e.g., "DOMContentLoaded")
+}
+
+// Cue is a timed text annotation shown as a soft subtitle.
+type Cue struct {
+	StartMs int    // Time the text appears, in milliseconds from the beginning of the video
+	EndMs   int    // Time the text disappears
+	Text    string // Text shown (e.g., "DOMContentLoaded 1.23s")
+}
@@ -0,0 +1,255 @@
+package composite
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/user/loadshow/pkg/imagediff"
+	"github.com/user/loadshow/pkg/pipeline"
+	"github.com/user/loadshow/pkg/ports"
+)
+
+// Change detection parameters.
+// Frames are compared on a coarse grid to absorb JPEG noise from the screencast.
+const (
+	// changeCellSize is the edge length in pixels of a grid cell used for comparison.
+	changeCellSize = 8
+	// changeCellRatio is the fraction of changed pixels required to mark a cell as changed.
+	changeCellRatio = 0.1
+	// defaultHighlightFadeMs is used when HighlightFadeMs is not specified.
+	defaultHighlightFadeMs = 500
+	// highlightMaxAlpha is the fill opacity of a highlight at the moment the change appears.
+	highlightMaxAlpha = 0.35
+)
+
+// frameChange holds the regions that changed at a given frame.
+// Regions are in page coordinates at the scroll width (before column splitting).
+type frameChange struct {
+	TimestampMs int
+	Regions     []pipeline.Rectangle
+}
+
+// detectChanges compares consecutive raw frames and returns the changed regions for each frame.
+// The first frame has no regions since there is nothing to compare against.
+// Frames are decoded sequentially so that only two frames are held in memory at a time.
+func (s *Stage) detectChanges(ctx context.Context, input pipeline.CompositeInput) ([]frameChange, error) {
+	changes := make([]frameChange, len(input.RawFrames))
+
+	var prev *image.RGBA
+	for i, rawFrame := range input.RawFrames {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		changes[i].TimestampMs = rawFrame.TimestampMs
+
+		img, err := s.decodeScrollImage(rawFrame, input.Layout)
+		if err != nil {
+			return nil, fmt.Errorf("decode frame %d for change detection: %w", i, err)
+		}
+		curr := imagediff.ToRGBA(img)
+
+		if prev != nil {
+			changes[i].Regions = diffRegions(prev, curr)
+		}
+		prev = curr
+	}
+
+	return changes, nil
+}
+
+// countChangedFrames returns the number of frames that have at least one changed region.
+func countChangedFrames(changes []frameChange) int {
+	count := 0
+	for _, c := range changes {
+		if len(c.Regions) > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// diffRegions returns bounding boxes of connected changed areas between two images.
+// Only the overlapping area is compared; if the page grew, the new area is reported as changed.
+func diffRegions(prev, curr *image.RGBA) []pipeline.Rectangle {
+	width := curr.Bounds().Dx()
+	height := curr.Bounds().Dy()
+	if width == 0 || height == 0 {
+		return nil
+	}
+
+	commonW := width
+	if prev.Bounds().Dx() < commonW {
+		commonW = prev.Bounds().Dx()
+	}
+	commonH := height
+	if prev.Bounds().Dy() < commonH {
+		commonH = prev.Bounds().Dy()
+	}
+
+	cols := (width + changeCellSize - 1) / changeCellSize
+	rows := (height + changeCellSize - 1) / changeCellSize
+	changed := make([]bool, cols*rows)
+
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			x0 := col * changeCellSize
+			y0 := row * changeCellSize
+			x1 := min(x0+changeCellSize, width)
+			y1 := min(y0+changeCellSize, height)
+
+			total := (x1 - x0) * (y1 - y0)
+			diff := 0
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					if x >= commonW || y >= commonH || pixelChanged(prev, curr, x, y) {
+						diff++
+					}
+				}
+			}
+			if float64(diff) >= float64(total)*changeCellRatio {
+				changed[row*cols+col] = true
+			}
+		}
+	}
+
+	return mergeCells(changed, cols, rows, width, height)
+}
+
+// pixelChanged reports whether the pixel at (x, y) differs beyond the threshold.
+func pixelChanged(a, b *image.RGBA, x, y int) bool {
+	ia := a.PixOffset(x, y)
+	ib := b.PixOffset(x, y)
+	for c := 0; c < 3; c++ {
+		d := int(a.Pix[ia+c]) - int(b.Pix[ib+c])
+		if d < 0 {
+			d = -d
+		}
+		if d > imagediff.PixelThreshold {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeCells groups adjacent changed cells and returns their bounding boxes in pixels.
+func mergeCells(changed []bool, cols, rows, width, height int) []pipeline.Rectangle {
+	visited := make([]bool, len(changed))
+	var regions []pipeline.Rectangle
+	var stack []int
+
+	for start := range changed {
+		if !changed[start] || visited[start] {
+			continue
+		}
+
+		minCol, minRow := cols, rows
+		maxCol, maxRow := -1, -1
+
+		stack = append(stack[:0], start)
+		visited[start] = true
+		for len(stack) > 0 {
+			idx := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			col, row := idx%cols, idx/cols
+			minCol, maxCol = min(minCol, col), max(maxCol, col)
+			minRow, maxRow = min(minRow, row), max(maxRow, row)
+
+			neighbors := [4][2]int{{col - 1, row}, {col + 1, row}, {col, row - 1}, {col, row + 1}}
+			for _, n := range neighbors {
+				if n[0] < 0 || n[0] >= cols || n[1] < 0 || n[1] >= rows {
+					continue
+				}
+				nIdx := n[1]*cols + n[0]
+				if changed[nIdx] && !visited[nIdx] {
+					visited[nIdx] = true
+					stack = append(stack, nIdx)
+				}
+			}
+		}
+
+		x := minCol * changeCellSize
+		y := minRow * changeCellSize
+		regions = append(regions, pipeline.Rectangle{
+			X:      x,
+			Y:      y,
+			Width:  min((maxCol+1)*changeCellSize, width) - x,
+			Height: min((maxRow+1)*changeCellSize, height) - y,
+		})
+	}
+
+	return regions
+}
+
+// drawChangeHighlights draws fading highlight boxes for regions that changed
+// within the fade duration before the given frame.
+func (s *Stage) drawChangeHighlights(
+	canvas ports.Canvas,
+	input pipeline.CompositeInput,
+	changes []frameChange,
+	frameIndex int,
+	canvasOffset int,
+) {
+	fadeMs := input.HighlightFadeMs
+	if fadeMs <= 0 {
+		fadeMs = defaultHighlightFadeMs
+	}
+	highlightColor := input.Theme.HighlightColor
+	if highlightColor == nil {
+		highlightColor = pipeline.DefaultCompositeTheme().HighlightColor
+	}
+	currentMs := input.RawFrames[frameIndex].TimestampMs
+
+	for i := frameIndex; i >= 0; i-- {
+		change := changes[i]
+		elapsed := currentMs - change.TimestampMs
+		if elapsed >= fadeMs {
+			break
+		}
+
+		strength := 1.0 - float64(elapsed)/float64(fadeMs)
+		fill := withAlpha(highlightColor, highlightMaxAlpha*strength)
+		stroke := withAlpha(highlightColor, strength)
+
+		for _, region := range change.Regions {
+			for _, rect := range mapToWindows(region, input.Layout.Windows, canvasOffset) {
+				canvas.DrawRect(rect.X, rect.Y, rect.Width, rect.Height, fill)
+				canvas.DrawRectStroke(rect.X, rect.Y, rect.Width, rect.Height, stroke, 1)
+			}
+		}
+	}
+}
+
+// mapToWindows maps a rectangle in page coordinates to canvas coordinates.
+// A rectangle spanning a column boundary is split into one piece per window.
+func mapToWindows(region pipeline.Rectangle, windows []pipeline.Window, canvasOffset int) []pipeline.Rectangle {
+	var rects []pipeline.Rectangle
+	for _, window := range windows {
+		top := max(region.Y, window.ScrollTop)
+		bottom := min(region.Y+region.Height, window.ScrollTop+window.Height)
+		left := max(region.X, 0)
+		right := min(region.X+region.Width, window.Width)
+		if bottom <= top || right <= left {
+			continue
+		}
+		rects = append(rects, pipeline.Rectangle{
+			X:      window.X + left,
+			Y:      canvasOffset + window.Y + top - window.ScrollTop,
+			Width:  right - left,
+			Height: bottom - top,
+		})
+	}
+	return rects
+}
+
+// withAlpha returns the color with its opacity scaled by the given factor (0.0-1.0).
+func withAlpha(c color.Color, factor float64) color.Color {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	n.A = uint8(float64(n.A) * factor)
+	return n
+}
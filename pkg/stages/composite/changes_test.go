@@ -0,0 +1,185 @@
+package composite
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/user/loadshow/pkg/adapters/logger"
+	"github.com/user/loadshow/pkg/mocks"
+	"github.com/user/loadshow/pkg/pipeline"
+	"github.com/user/loadshow/pkg/ports"
+	"github.com/user/loadshow/pkg/stages/layout"
+)
+
+func TestDiffRegions_Identical(t *testing.T) {
+	a := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	b := image.NewRGBA(image.Rect(0, 0, 64, 64))
+
+	if regions := diffRegions(a, b); len(regions) != 0 {
+		t.Errorf("expected no regions, got %v", regions)
+	}
+}
+
+func TestDiffRegions_SeparateBlocks(t *testing.T) {
+	prev := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	curr := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	white := image.NewUniform(color.White)
+	draw.Draw(curr, image.Rect(0, 0, 16, 16), white, image.Point{}, draw.Src)
+	draw.Draw(curr, image.Rect(40, 40, 56, 56), white, image.Point{}, draw.Src)
+
+	regions := diffRegions(prev, curr)
+	if len(regions) != 2 {
+		t.Fatalf("expected 2 regions, got %d: %v", len(regions), regions)
+	}
+
+	want := []pipeline.Rectangle{
+		{X: 0, Y: 0, Width: 16, Height: 16},
+		{X: 40, Y: 40, Width: 16, Height: 16},
+	}
+	for i, r := range regions {
+		if r != want[i] {
+			t.Errorf("region %d: expected %v, got %v", i, want[i], r)
+		}
+	}
+}
+
+func TestDiffRegions_IgnoresSmallNoise(t *testing.T) {
+	prev := image.NewRGBA(image.Rect(0, 0, 32, 32))
+	curr := image.NewRGBA(image.Rect(0, 0, 32, 32))
+	// Slight color shift like JPEG noise, below the pixel threshold
+	draw.Draw(curr, curr.Bounds(), image.NewUniform(color.RGBA{R: 20, G: 20, B: 20, A: 255}), image.Point{}, draw.Src)
+
+	if regions := diffRegions(prev, curr); len(regions) != 0 {
+		t.Errorf("expected noise to be ignored, got %v", regions)
+	}
+}
+
+func TestDiffRegions_PageGrew(t *testing.T) {
+	prev := image.NewRGBA(image.Rect(0, 0, 16, 16))
+	curr := image.NewRGBA(image.Rect(0, 0, 16, 32))
+
+	regions := diffRegions(prev, curr)
+	want := pipeline.Rectangle{X: 0, Y: 16, Width: 16, Height: 16}
+	if len(regions) != 1 || regions[0] != want {
+		t.Errorf("expected %v, got %v", want, regions)
+	}
+}
+
+func TestMapToWindows(t *testing.T) {
+	windows := []pipeline.Window{
+		{Rectangle: pipeline.Rectangle{X: 10, Y: 5, Width: 100, Height: 200}, ScrollTop: 0},
+		{Rectangle: pipeline.Rectangle{X: 130, Y: 5, Width: 100, Height: 200}, ScrollTop: 200},
+	}
+
+	// Region spanning the column boundary is split across both windows
+	rects := mapToWindows(pipeline.Rectangle{X: 20, Y: 150, Width: 50, Height: 100}, windows, 30)
+	want := []pipeline.Rectangle{
+		{X: 30, Y: 185, Width: 50, Height: 50},
+		{X: 150, Y: 35, Width: 50, Height: 50},
+	}
+	if len(rects) != len(want) {
+		t.Fatalf("expected %d rects, got %d: %v", len(want), len(rects), rects)
+	}
+	for i, r := range rects {
+		if r != want[i] {
+			t.Errorf("rect %d: expected %v, got %v", i, want[i], r)
+		}
+	}
+
+	// Region below all windows is dropped
+	if rects := mapToWindows(pipeline.Rectangle{X: 0, Y: 500, Width: 10, Height: 10}, windows, 30); len(rects) != 0 {
+		t.Errorf("expected no rects, got %v", rects)
+	}
+}
+
+// rectRecordingCanvas records filled rectangles drawn on it.
+type rectRecordingCanvas struct {
+	mocks.Canvas
+	rects []color.NRGBA
+}
+
+func (c *rectRecordingCanvas) DrawRect(x, y, w, h int, col color.Color) {
+	c.rects = append(c.rects, color.NRGBAModel.Convert(col).(color.NRGBA))
+}
+
+func TestStage_Execute_HighlightChanges(t *testing.T) {
+	layoutResult := layout.ComputeLayout(pipeline.DefaultLayoutInput())
+	scroll := layoutResult.Scroll
+
+	// Frame 1 adds a white block at the top of the page
+	mockRenderer := &mocks.Renderer{
+		DecodeImageFunc: func(data []byte, format ports.ImageFormat) (image.Image, error) {
+			img := image.NewRGBA(image.Rect(0, 0, scroll.Width, scroll.Height))
+			if data[0] == 1 {
+				draw.Draw(img, image.Rect(0, 0, 32, 32), image.NewUniform(color.White), image.Point{}, draw.Src)
+			}
+			return img, nil
+		},
+		ResizeImageFunc: func(img image.Image, width, height int) image.Image {
+			return img
+		},
+	}
+
+	var canvases []*rectRecordingCanvas
+	mockRenderer.CreateCanvasFunc = func(width, height int, bg color.Color) ports.Canvas {
+		c := &rectRecordingCanvas{}
+		canvases = append(canvases, c)
+		return c
+	}
+
+	// Use a single worker so canvases are created in frame order
+	stage := NewStage(mockRenderer, mocks.NewDebugSink(false), logger.NewNoop(), 1)
+
+	input := pipeline.CompositeInput{
+		RawFrames: []pipeline.RawFrame{
+			{TimestampMs: 0, ImageData: []byte{0}},
+			{TimestampMs: 100, ImageData: []byte{1}},
+			{TimestampMs: 300, ImageData: []byte{1}},
+			{TimestampMs: 600, ImageData: []byte{1}},
+		},
+		Layout:           layoutResult,
+		Theme:            pipeline.DefaultCompositeTheme(),
+		HighlightChanges: true,
+		HighlightFadeMs:  400,
+	}
+
+	result, err := stage.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Frames) != 4 {
+		t.Fatalf("expected 4 frames, got %d", len(result.Frames))
+	}
+
+	highlightAlphas := func(c *rectRecordingCanvas) []uint8 {
+		var alphas []uint8
+		for _, r := range c.rects {
+			if r.R == 255 && r.G == 193 && r.B == 7 {
+				alphas = append(alphas, r.A)
+			}
+		}
+		return alphas
+	}
+
+	// Frame 0: nothing to compare against
+	if got := highlightAlphas(canvases[0]); len(got) != 0 {
+		t.Errorf("frame 0: expected no highlights, got %v", got)
+	}
+	// Frame 1: change appears at full strength
+	got1 := highlightAlphas(canvases[1])
+	if len(got1) != 1 {
+		t.Fatalf("frame 1: expected 1 highlight, got %v", got1)
+	}
+	// Frame 2: same change, 200ms later, fading
+	got2 := highlightAlphas(canvases[2])
+	if len(got2) != 1 || got2[0] >= got1[0] {
+		t.Errorf("frame 2: expected fading highlight below %d, got %v", got1[0], got2)
+	}
+	// Frame 3: 500ms after the change, fully faded
+	if got := highlightAlphas(canvases[3]); len(got) != 0 {
+		t.Errorf("frame 3: expected no highlights, got %v", got)
+	}
+}
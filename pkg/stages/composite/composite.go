@@ -43,8 +43,39 @@ func (s *Stage) Execute(ctx context.Context, input pipeline.CompositeInput) (pip
 
 	s.logger.Debug("Compositing %d frames with %d workers", len(input.RawFrames), s.numWorkers)
 
+	// Detect changed regions between consecutive frames (optional)
+	var changes []frameChange
+	if input.HighlightChanges {
+		var err error
+		changes, err = s.detectChanges(ctx, input)
+		if err != nil {
+			return pipeline.CompositeResult{}, fmt.Errorf("detect changes: %w", err)
+		}
+		s.logger.Debug("Detected changed regions in %d frames", countChangedFrames(changes))
+	}
+
+	// Order layout shifts by time for the running CLS score
+	if input.ShowLayoutShifts && len(input.LayoutShifts) > 0 {
+		shifts := make([]pipeline.LayoutShift, len(input.LayoutShifts))
+		copy(shifts, input.LayoutShifts)
+		sort.SliceStable(shifts, func(i, j int) bool {
+			return shifts[i].TimestampMs < shifts[j].TimestampMs
+		})
+		input.LayoutShifts = shifts
+	}
+
+	// Prepare the 9-slice parts for an image device frame
+	var frameParts []image.Image
+	if input.Frame == pipeline.FrameImage {
+		var err error
+		frameParts, err = s.sliceFrameImage(input.FrameImage, input.FrameSlice)
+		if err != nil {
+			return pipeline.CompositeResult{}, fmt.Errorf("load frame image: %w", err)
+		}
+	}
+
 	// Use parallel processing
-	result, err := s.executeParallel(ctx, input)
+	result, err := s.executeParallel(ctx, input, changes, frameParts)
 	if err != nil {
 		return result, err
 	}
@@ -53,6 +84,33 @@ func (s *Stage) Execute(ctx context.Context, input pipeline.CompositeInput) (pip
 	return result, nil
 }
 
+// composedBannerHeight returns the height of the banner image if a banner
+// exists, otherwise the layout value.
+func composedBannerHeight(input pipeline.CompositeInput) int {
+	if input.Banner != nil && input.Banner.Image != nil {
+		return input.Banner.Image.Bounds().Dy()
+	}
+	return input.Layout.BannerArea.Height
+}
+
+// minBadgeHeight is the timing badge height when the progress bar is thinner.
+const minBadgeHeight = 12
+
+// screenArea returns the area of the composed frames showing the page: the
+// content area below the banner, the progress bar and the timing badges.
+func screenArea(input pipeline.CompositeInput) pipeline.Rectangle {
+	bannerHeight := composedBannerHeight(input)
+	area := input.Layout.ContentArea
+	area.Y += bannerHeight + input.Layout.ProgressArea.Height
+
+	// Badges taller than the progress bar overlap the content
+	if top := bannerHeight + max(input.Layout.ProgressArea.Height, minBadgeHeight); area.Y < top {
+		area.Height = max(0, area.Height-(top-area.Y))
+		area.Y = top
+	}
+	return area
+}
+
 // indexedFrame holds a frame with its original index for sorting.
 type indexedFrame struct {
 	index int
@@ -60,7 +118,7 @@ type indexedFrame struct {
 }
 
 // executeParallel composes frames using worker pool.
-func (s *Stage) executeParallel(ctx context.Context, input pipeline.CompositeInput) (pipeline.CompositeResult, error) {
+func (s *Stage) executeParallel(ctx context.Context, input pipeline.CompositeInput, changes []frameChange, frameParts []image.Image) (pipeline.CompositeResult, error) {
 	numFrames := len(input.RawFrames)
 	jobs := make(chan int, numFrames)
 	results := make(chan indexedFrame, numFrames)
@@ -70,7 +128,7 @@ func (s *Stage) executeParallel(ctx context.Context, input pipeline.CompositeInp
 	var wg sync.WaitGroup
 	for w := 0; w < s.numWorkers; w++ {
 		wg.Add(1)
-		go s.worker(ctx, &wg, input, jobs, results, errChan)
+		go s.worker(ctx, &wg, input, changes, frameParts, jobs, results, errChan)
 	}
 
 	// Send jobs
@@ -113,7 +171,7 @@ func (s *Stage) executeParallel(ctx context.Context, input pipeline.CompositeInp
 		composedFrames[i] = f.frame
 	}
 
-	return pipeline.CompositeResult{Frames: composedFrames}, nil
+	return pipeline.CompositeResult{Frames: composedFrames, ScreenArea: screenArea(input)}, nil
 }
 
 // worker processes frames from jobs channel.
@@ -121,6 +179,8 @@ func (s *Stage) worker(
 	ctx context.Context,
 	wg *sync.WaitGroup,
 	input pipeline.CompositeInput,
+	changes []frameChange,
+	frameParts []image.Image,
 	jobs <-chan int,
 	results chan<- indexedFrame,
 	errChan chan<- error,
@@ -134,7 +194,7 @@ func (s *Stage) worker(
 		default:
 		}
 
-		frame, err := s.composeFrame(input, idx)
+		frame, err := s.composeFrame(input, changes, frameParts, idx)
 		if err != nil {
 			select {
 			case errChan <- fmt.Errorf("compose frame %d: %w", idx, err):
@@ -150,7 +210,8 @@ func (s *Stage) worker(
 // composeFrame creates a single composed frame.
 // Frame structure (from top to bottom): Banner → Progress bar → Content
 // Following TypeScript composition.ts exactly.
-func (s *Stage) composeFrame(input pipeline.CompositeInput, frameIndex int) (pipeline.ComposedFrame, error) {
+// changes is nil unless change highlighting is enabled; frameParts is nil unless an image device frame is used.
+func (s *Stage) composeFrame(input pipeline.CompositeInput, changes []frameChange, frameParts []image.Image, frameIndex int) (pipeline.ComposedFrame, error) {
 	rawFrame := input.RawFrames[frameIndex]
 	layout := input.Layout
 
@@ -158,11 +219,7 @@ func (s *Stage) composeFrame(input pipeline.CompositeInput, frameIndex int) (pip
 	// canvasHeight in TypeScript is the content area height from layout input
 	canvasWidth := layout.ContentArea.X*2 + layout.ContentArea.Width // = canvasWidth from input
 
-	// Use actual banner image height if banner exists, otherwise use layout value
-	bannerHeight := layout.BannerArea.Height
-	if input.Banner != nil && input.Banner.Image != nil {
-		bannerHeight = input.Banner.Image.Bounds().Dy()
-	}
+	bannerHeight := composedBannerHeight(input)
 
 	progressHeight := layout.ProgressArea.Height
 	contentHeight := layout.ContentArea.Height + layout.ContentArea.Y*2 // = canvasHeight from input
@@ -178,27 +235,12 @@ func (s *Stage) composeFrame(input pipeline.CompositeInput, frameIndex int) (pip
 	// Create canvas
 	canvas := s.renderer.CreateCanvas(canvasWidth, canvasHeight, input.Theme.BackgroundColor)
 
-	// Decode the raw frame image
-	frameImg, err := s.renderer.DecodeImage(rawFrame.ImageData, ports.FormatJPEG)
+	// Decode the raw frame image and resize it to scroll width
+	frameImg, err := s.decodeScrollImage(rawFrame, layout)
 	if err != nil {
 		return pipeline.ComposedFrame{}, fmt.Errorf("decode frame image: %w", err)
 	}
 
-	// Resize the frame to scroll width, maintaining aspect ratio
-	// TypeScript: Sharp.resize(input.layoutOutput.scroll.width)
-	originalBounds := frameImg.Bounds()
-	if layout.Scroll.Width > 0 && originalBounds.Dx() > 0 {
-		targetWidth := layout.Scroll.Width
-		targetHeight := originalBounds.Dy() * targetWidth / originalBounds.Dx()
-		frameImg = s.renderer.ResizeImage(frameImg, targetWidth, targetHeight)
-
-		// Crop to layout.Scroll.Height if the resized image is taller than expected
-		resizedBounds := frameImg.Bounds()
-		if resizedBounds.Dy() > layout.Scroll.Height {
-			frameImg = extractSubImage(frameImg, 0, 0, resizedBounds.Dx(), layout.Scroll.Height)
-		}
-	}
-
 	// Draw banner if present (at top: 0)
 	if input.Banner != nil && input.Banner.Image != nil && bannerHeight > 0 {
 		canvas.DrawImage(input.Banner.Image, 0, 0)
@@ -257,9 +299,13 @@ func (s *Stage) composeFrame(input pipeline.CompositeInput, frameIndex int) (pip
 	// Draw timing badges at top-right corner
 	s.drawTimingBadges(canvas, input, rawFrame, canvasWidth, bannerHeight, progressHeight)
 
-	// Draw column borders (at column.y + canvasOffset)
-	for _, col := range layout.Columns {
-		canvas.DrawRectStroke(col.X, col.Y+canvasOffset, col.Width, col.Height, input.Theme.BorderColor, 1)
+	// Draw device frames, or column borders (at column.y + canvasOffset)
+	if input.Frame != pipeline.FrameNone {
+		s.drawDeviceFrames(canvas, input, frameParts, canvasOffset)
+	} else {
+		for _, col := range layout.Columns {
+			canvas.DrawRectStroke(col.X, col.Y+canvasOffset, col.Width, col.Height, input.Theme.BorderColor, 1)
+		}
 	}
 
 	// Draw each window from the frame
@@ -288,6 +334,21 @@ func (s *Stage) composeFrame(input pipeline.CompositeInput, frameIndex int) (pip
 		}
 	}
 
+	// Draw change highlights over the windows
+	if changes != nil {
+		s.drawChangeHighlights(canvas, input, changes, frameIndex, canvasOffset)
+	}
+
+	// Draw recent layout shifts over the windows
+	if input.ShowLayoutShifts {
+		s.drawLayoutShifts(canvas, input, rawFrame, canvasOffset)
+	}
+
+	// Draw the current LCP candidate outline
+	if input.ShowLCP {
+		s.drawLCPOutline(canvas, input, rawFrame, canvasOffset)
+	}
+
 	return pipeline.ComposedFrame{
 		TimestampMs: rawFrame.TimestampMs,
 		Image:       canvas.ToImage(),
@@ -297,16 +358,16 @@ func (s *Stage) composeFrame(input pipeline.CompositeInput, frameIndex int) (pip
 // drawTimingBadges draws DCL and OnLoad badges on the progress bar area.
 // DCL badge appears at the rightmost position, OnLoad badge appears to its left.
 // Badges appear when the frame timestamp reaches the respective timing and persist after that.
+// When LCP is shown, an LCP badge appears once the final LCP candidate is painted.
+// When layout shifts are shown, a CLS badge with the running score follows once the first shift occurs.
+// Custom user-timing badges come last, each appearing once its mark is reached.
 func (s *Stage) drawTimingBadges(
 	canvas ports.Canvas,
 	input pipeline.CompositeInput,
 	rawFrame pipeline.RawFrame,
 	canvasWidth, bannerHeight, progressHeight int,
 ) {
-	badgeHeight := progressHeight
-	if badgeHeight < 12 {
-		badgeHeight = 12
-	}
+	badgeHeight := max(progressHeight, minBadgeHeight)
 	fontSize := float64(badgeHeight) * 0.7
 	hPadding := int(fontSize * 0.4)
 	badgeY := bannerHeight
@@ -330,6 +391,35 @@ func (s *Stage) drawTimingBadges(
 	if input.LoadCompleteMs > 0 && rawFrame.TimestampMs >= input.LoadCompleteMs {
 		badges = append(badges, badge{"Load", input.Theme.LoadBadgeColor})
 	}
+	if input.ShowLCP && input.LargestContentfulPaintMs > 0 && rawFrame.TimestampMs >= input.LargestContentfulPaintMs {
+		lcpColor := input.Theme.LCPBadgeColor
+		if lcpColor == nil {
+			lcpColor = pipeline.DefaultCompositeTheme().LCPBadgeColor
+		}
+		badges = append(badges, badge{"LCP", lcpColor})
+	}
+	if input.ShowLayoutShifts {
+		if cls := pipeline.CumulativeLayoutShift(input.LayoutShifts, rawFrame.TimestampMs); cls > 0 {
+			clsColor := input.Theme.CLSBadgeColor
+			if clsColor == nil {
+				clsColor = pipeline.DefaultCompositeTheme().CLSBadgeColor
+			}
+			badges = append(badges, badge{fmt.Sprintf("CLS %.3f", cls), clsColor})
+		}
+	}
+	for _, tb := range input.TimingBadges {
+		if rawFrame.TimestampMs < tb.TimestampMs {
+			continue
+		}
+		markColor := tb.Color
+		if markColor == nil {
+			markColor = input.Theme.UserTimingBadgeColor
+		}
+		if markColor == nil {
+			markColor = pipeline.DefaultCompositeTheme().UserTimingBadgeColor
+		}
+		badges = append(badges, badge{tb.Label, markColor})
+	}
 
 	// Draw badges from left to right, flush to top-left of progress bar area
 	x := 0
@@ -385,3 +475,27 @@ func extractSubImage(img image.Image, x, y, width, height int) image.Image {
 	}
 	return result
 }
+
+// decodeScrollImage decodes a raw frame and resizes it to the scroll width, maintaining aspect ratio.
+// TypeScript: Sharp.resize(input.layoutOutput.scroll.width)
+func (s *Stage) decodeScrollImage(rawFrame pipeline.RawFrame, layout pipeline.LayoutResult) (image.Image, error) {
+	frameImg, err := s.renderer.DecodeImage(rawFrame.ImageData, ports.FormatJPEG)
+	if err != nil {
+		return nil, err
+	}
+
+	originalBounds := frameImg.Bounds()
+	if layout.Scroll.Width > 0 && originalBounds.Dx() > 0 {
+		targetWidth := layout.Scroll.Width
+		targetHeight := originalBounds.Dy() * targetWidth / originalBounds.Dx()
+		frameImg = s.renderer.ResizeImage(frameImg, targetWidth, targetHeight)
+
+		// Crop to layout.Scroll.Height if the resized image is taller than expected
+		resizedBounds := frameImg.Bounds()
+		if resizedBounds.Dy() > layout.Scroll.Height {
+			frameImg = extractSubImage(frameImg, 0, 0, resizedBounds.Dx(), layout.Scroll.Height)
+		}
+	}
+
+	return frameImg, nil
+}
@@ -3,6 +3,8 @@ package composite
 import (
 	"context"
 	"image"
+	"image/color"
+	"reflect"
 	"testing"
 
 	"github.com/user/loadshow/pkg/adapters/logger"
@@ -124,6 +126,35 @@ func TestStage_Execute_WithBanner(t *testing.T) {
 	}
 }
 
+func TestScreenArea(t *testing.T) {
+	content := pipeline.Rectangle{X: 10, Y: 4, Width: 100, Height: 200}
+	tests := []struct {
+		name     string
+		progress int
+		banner   *pipeline.BannerResult
+		want     pipeline.Rectangle
+	}{
+		{"below progress bar", 16, nil, pipeline.Rectangle{X: 10, Y: 20, Width: 100, Height: 200}},
+		{"below banner image", 16, &pipeline.BannerResult{Image: image.NewRGBA(image.Rect(0, 0, 120, 80))}, pipeline.Rectangle{X: 10, Y: 100, Width: 100, Height: 200}},
+		{"clipped below badges", 0, nil, pipeline.Rectangle{X: 10, Y: 12, Width: 100, Height: 192}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := pipeline.CompositeInput{
+				Layout: pipeline.LayoutResult{
+					ContentArea:  content,
+					ProgressArea: pipeline.Rectangle{Height: tt.progress},
+				},
+				Banner: tt.banner,
+			}
+			if got := screenArea(input); got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
 func TestStage_Execute_WithDebugSink(t *testing.T) {
 	mockRenderer := &mocks.Renderer{
 		DecodeImageFunc: func(data []byte, format ports.ImageFormat) (image.Image, error) {
@@ -198,3 +229,46 @@ func TestExtractSubImage_NegativeResult(t *testing.T) {
 		t.Error("expected nil for out-of-bounds request")
 	}
 }
+
+func TestStage_Execute_TimingBadges(t *testing.T) {
+	var canvases []*badgeRecordingCanvas
+	mockRenderer := &mocks.Renderer{
+		CreateCanvasFunc: func(width, height int, bg color.Color) ports.Canvas {
+			c := &badgeRecordingCanvas{}
+			canvases = append(canvases, c)
+			return c
+		},
+	}
+
+	// Use a single worker so canvases are created in frame order
+	stage := NewStage(mockRenderer, mocks.NewDebugSink(false), logger.NewNoop(), 1)
+
+	input := pipeline.CompositeInput{
+		RawFrames: []pipeline.RawFrame{
+			{TimestampMs: 0, ImageData: []byte{0xFF}},
+			{TimestampMs: 500, ImageData: []byte{0xFF}},
+			{TimestampMs: 1500, ImageData: []byte{0xFF}},
+		},
+		Layout: layout.ComputeLayout(pipeline.DefaultLayoutInput()),
+		Theme:  pipeline.DefaultCompositeTheme(),
+		TimingBadges: []pipeline.TimingBadge{
+			{Label: "Hero", TimestampMs: 400},
+			{Label: "Interactive", TimestampMs: 1200, Color: color.RGBA{R: 255, A: 255}},
+		},
+	}
+
+	if _, err := stage.Execute(context.Background(), input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := [][]string{
+		nil,
+		{"Hero"},
+		{"Hero", "Interactive"},
+	}
+	for i, texts := range want {
+		if !reflect.DeepEqual(canvases[i].texts, texts) {
+			t.Errorf("frame %d: expected badges %v, got %v", i, texts, canvases[i].texts)
+		}
+	}
+}
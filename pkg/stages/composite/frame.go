@@ -0,0 +1,141 @@
+package composite
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/user/loadshow/pkg/pipeline"
+	"github.com/user/loadshow/pkg/ports"
+)
+
+// Device frame colors.
+var (
+	phoneBezelColor    = color.RGBA{R: 28, G: 28, B: 30, A: 255}    // #1C1C1E ほぼ黒
+	phoneDetailColor   = color.RGBA{R: 72, G: 72, B: 74, A: 255}    // #48484A スピーカー・ホームインジケーター
+	browserChromeColor = color.RGBA{R: 222, G: 225, B: 230, A: 255} // #DEE1E6 Chrome風グレー
+	screenColor        = color.RGBA{R: 255, G: 255, B: 255, A: 255} // 画面（ページ未描画部分）
+	trafficLightColors = []color.Color{
+		color.RGBA{R: 255, G: 95, B: 87, A: 255},  // #FF5F57 閉じる
+		color.RGBA{R: 254, G: 188, B: 46, A: 255}, // #FEBC2E 最小化
+		color.RGBA{R: 40, G: 200, B: 64, A: 255},  // #28C840 最大化
+	}
+)
+
+// trafficLightSize is the diameter of the browser window buttons.
+const trafficLightSize = 5
+
+// sliceFrameImage decodes a 9-slice PNG and cuts it into its nine parts,
+// ordered row by row from the top-left corner.
+func (s *Stage) sliceFrameImage(data []byte, slice pipeline.Insets) ([]image.Image, error) {
+	img, err := s.renderer.DecodeImage(data, ports.FormatPNG)
+	if err != nil {
+		return nil, err
+	}
+
+	b := img.Bounds()
+	if slice.Left+slice.Right >= b.Dx() || slice.Top+slice.Bottom >= b.Dy() {
+		return nil, fmt.Errorf("slice insets %+v do not fit %dx%d image", slice, b.Dx(), b.Dy())
+	}
+
+	xs := []int{0, slice.Left, b.Dx() - slice.Right, b.Dx()}
+	ys := []int{0, slice.Top, b.Dy() - slice.Bottom, b.Dy()}
+
+	parts := make([]image.Image, 0, 9)
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			parts = append(parts, extractSubImage(img,
+				b.Min.X+xs[col], b.Min.Y+ys[row],
+				xs[col+1]-xs[col], ys[row+1]-ys[row]))
+		}
+	}
+	return parts, nil
+}
+
+// drawDeviceFrames draws a device frame around each column in place of the column border.
+// frameParts holds the 9-slice parts for FrameImage and is nil otherwise.
+func (s *Stage) drawDeviceFrames(
+	canvas ports.Canvas,
+	input pipeline.CompositeInput,
+	frameParts []image.Image,
+	canvasOffset int,
+) {
+	for i, col := range input.Layout.Columns {
+		x, y := col.X, col.Y+canvasOffset
+
+		switch input.Frame {
+		case pipeline.FramePhone:
+			drawPhoneFrame(canvas, x, y, col.Width, col.Height)
+		case pipeline.FrameBrowser:
+			drawBrowserFrame(canvas, x, y, col.Width, col.Height)
+		case pipeline.FrameImage:
+			drawNineSlice(canvas, frameParts, input.FrameSlice, x, y, col.Width, col.Height)
+			continue
+		}
+
+		// Blank screen, visible where the page is shorter than the window
+		if i < len(input.Layout.Windows) {
+			win := input.Layout.Windows[i]
+			canvas.DrawRect(win.X, win.Y+canvasOffset, win.Width, win.Height, screenColor)
+		}
+	}
+}
+
+// drawPhoneFrame draws a phone bezel with a speaker slot and a home indicator.
+func drawPhoneFrame(canvas ports.Canvas, x, y, w, h int) {
+	insets := pipeline.FrameInsets(pipeline.FramePhone)
+
+	radius := w / 8
+	if h/8 < radius {
+		radius = h / 8
+	}
+	canvas.DrawRoundedRect(x, y, w, h, radius, phoneBezelColor)
+
+	speakerWidth := w / 4
+	canvas.DrawRoundedRect(x+(w-speakerWidth)/2, y+insets.Top/2-1, speakerWidth, 3, 1, phoneDetailColor)
+
+	indicatorWidth := w / 3
+	canvas.DrawRoundedRect(x+(w-indicatorWidth)/2, y+h-insets.Bottom/2-1, indicatorWidth, 3, 1, phoneDetailColor)
+}
+
+// drawBrowserFrame draws a browser window with window buttons and an address bar.
+func drawBrowserFrame(canvas ports.Canvas, x, y, w, h int) {
+	insets := pipeline.FrameInsets(pipeline.FrameBrowser)
+
+	canvas.DrawRoundedRect(x, y, w, h, 4, browserChromeColor)
+
+	buttonX := x + 5
+	buttonY := y + (insets.Top-trafficLightSize)/2
+	for _, c := range trafficLightColors {
+		canvas.DrawRoundedRect(buttonX, buttonY, trafficLightSize, trafficLightSize, trafficLightSize/2, c)
+		buttonX += trafficLightSize + 3
+	}
+
+	barX := buttonX + 3
+	barWidth := x + w - 4 - barX
+	if barWidth > 0 {
+		canvas.DrawRoundedRect(barX, y+4, barWidth, insets.Top-8, 3, screenColor)
+	}
+}
+
+// drawNineSlice draws a 9-slice image stretched over the rectangle.
+// Corners keep their size, edges stretch along one axis and the center stretches in both.
+func drawNineSlice(canvas ports.Canvas, parts []image.Image, slice pipeline.Insets, x, y, w, h int) {
+	if len(parts) != 9 {
+		return
+	}
+
+	xs := []int{x, x + slice.Left, x + w - slice.Right, x + w}
+	ys := []int{y, y + slice.Top, y + h - slice.Bottom, y + h}
+
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			part := parts[row*3+col]
+			dw, dh := xs[col+1]-xs[col], ys[row+1]-ys[row]
+			if part == nil || dw <= 0 || dh <= 0 {
+				continue
+			}
+			canvas.DrawImageScaled(part, xs[col], ys[row], dw, dh)
+		}
+	}
+}
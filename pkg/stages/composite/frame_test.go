@@ -0,0 +1,126 @@
+package composite
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/user/loadshow/pkg/adapters/logger"
+	"github.com/user/loadshow/pkg/mocks"
+	"github.com/user/loadshow/pkg/pipeline"
+	"github.com/user/loadshow/pkg/ports"
+	"github.com/user/loadshow/pkg/stages/layout"
+)
+
+// frameRecordingCanvas counts device frame drawing calls.
+type frameRecordingCanvas struct {
+	mocks.Canvas
+	strokes      int
+	roundedRects int
+	scaled       []image.Rectangle
+}
+
+func (c *frameRecordingCanvas) DrawRectStroke(x, y, w, h int, col color.Color, strokeWidth float64) {
+	c.strokes++
+}
+
+func (c *frameRecordingCanvas) DrawRoundedRect(x, y, w, h, radius int, col color.Color) {
+	c.roundedRects++
+}
+
+func (c *frameRecordingCanvas) DrawImageScaled(img image.Image, x, y, width, height int) {
+	c.scaled = append(c.scaled, image.Rect(x, y, x+width, y+height))
+}
+
+func framedInput(frame pipeline.FrameStyle, bezel pipeline.Insets) pipeline.CompositeInput {
+	layoutInput := pipeline.DefaultLayoutInput()
+	layoutInput.BorderWidth = 0
+	layoutInput.Bezel = bezel
+
+	return pipeline.CompositeInput{
+		RawFrames: []pipeline.RawFrame{{TimestampMs: 0, ImageData: []byte{0xFF}}},
+		Layout:    layout.ComputeLayout(layoutInput),
+		Theme:     pipeline.DefaultCompositeTheme(),
+		Frame:     frame,
+	}
+}
+
+func TestStage_Execute_PhoneFrame(t *testing.T) {
+	canvas := &frameRecordingCanvas{}
+	mockRenderer := &mocks.Renderer{
+		CreateCanvasFunc: func(width, height int, bg color.Color) ports.Canvas {
+			return canvas
+		},
+	}
+	stage := NewStage(mockRenderer, mocks.NewDebugSink(false), logger.NewNoop(), 1)
+
+	input := framedInput(pipeline.FramePhone, pipeline.FrameInsets(pipeline.FramePhone))
+	if _, err := stage.Execute(context.Background(), input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if canvas.strokes != 0 {
+		t.Errorf("expected no column borders with a device frame, got %d strokes", canvas.strokes)
+	}
+	// Bezel, speaker and home indicator per column
+	if want := 3 * len(input.Layout.Columns); canvas.roundedRects != want {
+		t.Errorf("expected %d rounded rects, got %d", want, canvas.roundedRects)
+	}
+}
+
+func TestStage_Execute_ImageFrame(t *testing.T) {
+	canvas := &frameRecordingCanvas{}
+	mockRenderer := &mocks.Renderer{
+		CreateCanvasFunc: func(width, height int, bg color.Color) ports.Canvas {
+			return canvas
+		},
+		DecodeImageFunc: func(data []byte, format ports.ImageFormat) (image.Image, error) {
+			if format == ports.FormatPNG {
+				return image.NewRGBA(image.Rect(0, 0, 60, 120)), nil
+			}
+			return image.NewRGBA(image.Rect(0, 0, 100, 100)), nil
+		},
+	}
+	stage := NewStage(mockRenderer, mocks.NewDebugSink(false), logger.NewNoop(), 1)
+
+	slice := pipeline.Insets{Top: 20, Right: 10, Bottom: 20, Left: 10}
+	input := framedInput(pipeline.FrameImage, slice)
+	input.FrameImage = []byte{0x89}
+	input.FrameSlice = slice
+
+	if _, err := stage.Execute(context.Background(), input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := 9 * len(input.Layout.Columns); len(canvas.scaled) != want {
+		t.Fatalf("expected %d 9-slice parts, got %d", want, len(canvas.scaled))
+	}
+
+	// Corners keep their size; the center fills the column interior
+	col := input.Layout.Columns[0]
+	if got := canvas.scaled[0]; got.Dx() != 10 || got.Dy() != 20 {
+		t.Errorf("expected 10x20 top-left corner, got %v", got)
+	}
+	if got := canvas.scaled[4]; got.Dx() != col.Width-20 || got.Dy() != col.Height-40 {
+		t.Errorf("expected %dx%d center, got %v", col.Width-20, col.Height-40, got)
+	}
+}
+
+func TestStage_Execute_ImageFrame_SliceTooLarge(t *testing.T) {
+	mockRenderer := &mocks.Renderer{
+		DecodeImageFunc: func(data []byte, format ports.ImageFormat) (image.Image, error) {
+			return image.NewRGBA(image.Rect(0, 0, 30, 30)), nil
+		},
+	}
+	stage := NewStage(mockRenderer, mocks.NewDebugSink(false), logger.NewNoop(), 1)
+
+	slice := pipeline.Insets{Top: 20, Right: 20, Bottom: 20, Left: 20}
+	input := framedInput(pipeline.FrameImage, slice)
+	input.FrameImage = []byte{0x89}
+	input.FrameSlice = slice
+
+	if _, err := stage.Execute(context.Background(), input); err == nil {
+		t.Error("expected error for slice insets larger than the image")
+	}
+}
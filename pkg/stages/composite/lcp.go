@@ -0,0 +1,54 @@
+package composite
+
+import (
+	"github.com/user/loadshow/pkg/pipeline"
+	"github.com/user/loadshow/pkg/ports"
+)
+
+// lcpOutlineWidth is the stroke width of the LCP candidate outline.
+const lcpOutlineWidth = 2
+
+// currentLCPCandidate returns the latest LCP candidate painted at or before the given time.
+func currentLCPCandidate(candidates []pipeline.LCPCandidate, timestampMs int) (pipeline.LCPCandidate, bool) {
+	var current pipeline.LCPCandidate
+	found := false
+	for _, c := range candidates {
+		if c.TimestampMs > timestampMs {
+			continue
+		}
+		if !found || c.TimestampMs >= current.TimestampMs {
+			current = c
+			found = true
+		}
+	}
+	return current, found
+}
+
+// drawLCPOutline outlines the current LCP candidate in each window it appears in.
+// The outline appears when the candidate is painted and moves when a larger candidate replaces it.
+func (s *Stage) drawLCPOutline(
+	canvas ports.Canvas,
+	input pipeline.CompositeInput,
+	rawFrame pipeline.RawFrame,
+	canvasOffset int,
+) {
+	candidate, ok := currentLCPCandidate(input.LCPCandidates, rawFrame.TimestampMs)
+	if !ok || candidate.Rect.Width <= 0 || candidate.Rect.Height <= 0 {
+		return
+	}
+
+	lcpColor := input.Theme.LCPBadgeColor
+	if lcpColor == nil {
+		lcpColor = pipeline.DefaultCompositeTheme().LCPBadgeColor
+	}
+
+	// Candidate rects are in CSS pixels; windows are in scroll pixels
+	scale := 1.0
+	if input.ViewportWidth > 0 && input.Layout.Scroll.Width > 0 {
+		scale = float64(input.Layout.Scroll.Width) / float64(input.ViewportWidth)
+	}
+
+	for _, rect := range mapToWindows(scaleRect(candidate.Rect, scale), input.Layout.Windows, canvasOffset) {
+		canvas.DrawRectStroke(rect.X, rect.Y, rect.Width, rect.Height, lcpColor, lcpOutlineWidth)
+	}
+}
@@ -0,0 +1,122 @@
+package composite
+
+import (
+	"context"
+	"image/color"
+	"testing"
+
+	"github.com/user/loadshow/pkg/adapters/logger"
+	"github.com/user/loadshow/pkg/mocks"
+	"github.com/user/loadshow/pkg/pipeline"
+	"github.com/user/loadshow/pkg/ports"
+	"github.com/user/loadshow/pkg/stages/layout"
+)
+
+func TestCurrentLCPCandidate(t *testing.T) {
+	candidates := []pipeline.LCPCandidate{
+		{TimestampMs: 300, Element: "h1"},
+		{TimestampMs: 900, Element: "img"},
+	}
+
+	if _, ok := currentLCPCandidate(candidates, 100); ok {
+		t.Error("expected no candidate before first paint")
+	}
+	if c, ok := currentLCPCandidate(candidates, 500); !ok || c.Element != "h1" {
+		t.Errorf("expected h1 at 500ms, got %+v", c)
+	}
+	if c, ok := currentLCPCandidate(candidates, 1000); !ok || c.Element != "img" {
+		t.Errorf("expected img at 1000ms, got %+v", c)
+	}
+}
+
+// lcpRecordingCanvas records LCP-colored outlines and badge text.
+type lcpRecordingCanvas struct {
+	mocks.Canvas
+	outlines []pipeline.Rectangle
+	texts    []string
+}
+
+func (c *lcpRecordingCanvas) DrawRectStroke(x, y, w, h int, col color.Color, strokeWidth float64) {
+	if col == pipeline.DefaultCompositeTheme().LCPBadgeColor {
+		c.outlines = append(c.outlines, pipeline.Rectangle{X: x, Y: y, Width: w, Height: h})
+	}
+}
+
+func (c *lcpRecordingCanvas) DrawText(text string, x, y int, style ports.TextStyle) {
+	c.texts = append(c.texts, text)
+}
+
+func TestStage_Execute_ShowLCP(t *testing.T) {
+	var canvases []*lcpRecordingCanvas
+	mockRenderer := &mocks.Renderer{
+		CreateCanvasFunc: func(width, height int, bg color.Color) ports.Canvas {
+			c := &lcpRecordingCanvas{}
+			canvases = append(canvases, c)
+			return c
+		},
+	}
+
+	// Use a single worker so canvases are created in frame order
+	stage := NewStage(mockRenderer, mocks.NewDebugSink(false), logger.NewNoop(), 1)
+
+	layoutResult := layout.ComputeLayout(pipeline.DefaultLayoutInput())
+	scale := float64(layoutResult.Scroll.Width) / 500
+
+	input := pipeline.CompositeInput{
+		RawFrames: []pipeline.RawFrame{
+			{TimestampMs: 0, ImageData: []byte{0xFF}},
+			{TimestampMs: 400, ImageData: []byte{0xFF}},
+			{TimestampMs: 1000, ImageData: []byte{0xFF}},
+		},
+		Layout:        layoutResult,
+		Theme:         pipeline.DefaultCompositeTheme(),
+		ShowLCP:       true,
+		ViewportWidth: 500,
+		LCPCandidates: []pipeline.LCPCandidate{
+			{TimestampMs: 300, Rect: pipeline.Rectangle{X: 0, Y: 0, Width: 500, Height: 100}},
+			{TimestampMs: 900, Rect: pipeline.Rectangle{X: 0, Y: 200, Width: 500, Height: 300}},
+		},
+		LargestContentfulPaintMs: 900,
+	}
+
+	if _, err := stage.Execute(context.Background(), input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	hasLCPBadge := func(c *lcpRecordingCanvas) bool {
+		for _, s := range c.texts {
+			if s == "LCP" {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Frame 0: nothing painted yet
+	if len(canvases[0].outlines) != 0 || hasLCPBadge(canvases[0]) {
+		t.Errorf("frame 0: expected no LCP outline or badge")
+	}
+
+	// Frame 1: first candidate outlined, final LCP not reached yet
+	if len(canvases[1].outlines) == 0 {
+		t.Fatal("frame 1: expected LCP outline")
+	}
+	window := layoutResult.Windows[0]
+	if got := canvases[1].outlines[0]; got.X != window.X || got.Height != int(100*scale+0.5) {
+		t.Errorf("frame 1: unexpected outline %+v", got)
+	}
+	if hasLCPBadge(canvases[1]) {
+		t.Error("frame 1: LCP badge should not appear before final LCP")
+	}
+
+	// Frame 2: final candidate outlined and badge shown
+	if len(canvases[2].outlines) == 0 {
+		t.Fatal("frame 2: expected LCP outline")
+	}
+	if got := canvases[2].outlines[0]; got.Y <= canvases[1].outlines[0].Y {
+		t.Errorf("frame 2: expected outline to move to the final candidate, got %+v", got)
+	}
+	if !hasLCPBadge(canvases[2]) {
+		t.Errorf("frame 2: expected LCP badge, got %v", canvases[2].texts)
+	}
+}
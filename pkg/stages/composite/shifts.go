@@ -0,0 +1,110 @@
+package composite
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/user/loadshow/pkg/pipeline"
+	"github.com/user/loadshow/pkg/ports"
+)
+
+// Layout shift visualization parameters.
+const (
+	// layoutShiftDisplayMs is how long a shift stays visible after it happens.
+	layoutShiftDisplayMs = 1000
+	// layoutShiftArrowSize is the length in pixels of the arrowhead wings.
+	layoutShiftArrowSize = 4
+)
+
+// drawLayoutShifts outlines elements that moved in recent layout shifts,
+// with an arrow from each element's previous position to its current position.
+// The outline fades out over layoutShiftDisplayMs.
+func (s *Stage) drawLayoutShifts(
+	canvas ports.Canvas,
+	input pipeline.CompositeInput,
+	rawFrame pipeline.RawFrame,
+	canvasOffset int,
+) {
+	shiftColor := input.Theme.LayoutShiftColor
+	if shiftColor == nil {
+		shiftColor = pipeline.DefaultCompositeTheme().LayoutShiftColor
+	}
+
+	// Shift rects are in CSS pixels; windows are in scroll pixels
+	scale := 1.0
+	if input.ViewportWidth > 0 && input.Layout.Scroll.Width > 0 {
+		scale = float64(input.Layout.Scroll.Width) / float64(input.ViewportWidth)
+	}
+
+	for _, shift := range input.LayoutShifts {
+		elapsed := rawFrame.TimestampMs - shift.TimestampMs
+		if elapsed < 0 || elapsed >= layoutShiftDisplayMs {
+			continue
+		}
+
+		strength := 1.0 - float64(elapsed)/float64(layoutShiftDisplayMs)
+		stroke := withAlpha(shiftColor, strength)
+		ghost := withAlpha(shiftColor, strength*0.4)
+
+		for i, curr := range shift.CurrentRects {
+			current := scaleRect(curr, scale)
+			for _, rect := range mapToWindows(current, input.Layout.Windows, canvasOffset) {
+				canvas.DrawRectStroke(rect.X, rect.Y, rect.Width, rect.Height, stroke, 2)
+			}
+
+			if i >= len(shift.PreviousRects) {
+				continue
+			}
+			previous := scaleRect(shift.PreviousRects[i], scale)
+			for _, rect := range mapToWindows(previous, input.Layout.Windows, canvasOffset) {
+				canvas.DrawRectStroke(rect.X, rect.Y, rect.Width, rect.Height, ghost, 1)
+			}
+
+			// Arrow from previous to current position (only when both are in the same window)
+			fromX, fromY := previous.X+previous.Width/2, previous.Y+previous.Height/2
+			toX, toY := current.X+current.Width/2, current.Y+current.Height/2
+			for _, window := range input.Layout.Windows {
+				x1, y1, ok1 := mapPointToWindow(fromX, fromY, window, canvasOffset)
+				x2, y2, ok2 := mapPointToWindow(toX, toY, window, canvasOffset)
+				if ok1 && ok2 {
+					drawArrow(canvas, x1, y1, x2, y2, stroke)
+					break
+				}
+			}
+		}
+	}
+}
+
+// scaleRect scales a rectangle by the given factor.
+func scaleRect(r pipeline.Rectangle, scale float64) pipeline.Rectangle {
+	return pipeline.Rectangle{
+		X:      int(math.Round(float64(r.X) * scale)),
+		Y:      int(math.Round(float64(r.Y) * scale)),
+		Width:  int(math.Round(float64(r.Width) * scale)),
+		Height: int(math.Round(float64(r.Height) * scale)),
+	}
+}
+
+// mapPointToWindow maps a point in page coordinates to canvas coordinates
+// if it falls within the window.
+func mapPointToWindow(x, y int, window pipeline.Window, canvasOffset int) (int, int, bool) {
+	if x < 0 || x >= window.Width || y < window.ScrollTop || y >= window.ScrollTop+window.Height {
+		return 0, 0, false
+	}
+	return window.X + x, canvasOffset + window.Y + y - window.ScrollTop, true
+}
+
+// drawArrow draws a line with an arrowhead at (x2, y2).
+func drawArrow(canvas ports.Canvas, x1, y1, x2, y2 int, c color.Color) {
+	if x1 == x2 && y1 == y2 {
+		return
+	}
+	canvas.DrawLine(x1, y1, x2, y2, c, 1.5)
+
+	angle := math.Atan2(float64(y2-y1), float64(x2-x1))
+	for _, wing := range []float64{angle + math.Pi*5/6, angle - math.Pi*5/6} {
+		wx := x2 + int(math.Round(math.Cos(wing)*layoutShiftArrowSize))
+		wy := y2 + int(math.Round(math.Sin(wing)*layoutShiftArrowSize))
+		canvas.DrawLine(x2, y2, wx, wy, c, 1.5)
+	}
+}
@@ -0,0 +1,115 @@
+package composite
+
+import (
+	"context"
+	"image/color"
+	"testing"
+
+	"github.com/user/loadshow/pkg/adapters/logger"
+	"github.com/user/loadshow/pkg/mocks"
+	"github.com/user/loadshow/pkg/pipeline"
+	"github.com/user/loadshow/pkg/ports"
+	"github.com/user/loadshow/pkg/stages/layout"
+)
+
+func TestMapPointToWindow(t *testing.T) {
+	window := pipeline.Window{Rectangle: pipeline.Rectangle{X: 130, Y: 5, Width: 100, Height: 200}, ScrollTop: 200}
+
+	x, y, ok := mapPointToWindow(10, 250, window, 30)
+	if !ok || x != 140 || y != 85 {
+		t.Errorf("expected (140, 85, true), got (%d, %d, %v)", x, y, ok)
+	}
+
+	if _, _, ok := mapPointToWindow(10, 100, window, 30); ok {
+		t.Error("expected point above the window to be rejected")
+	}
+}
+
+// badgeRecordingCanvas records text drawn on it.
+type badgeRecordingCanvas struct {
+	mocks.Canvas
+	texts   []string
+	strokes int
+}
+
+func (c *badgeRecordingCanvas) DrawText(text string, x, y int, style ports.TextStyle) {
+	c.texts = append(c.texts, text)
+}
+
+func (c *badgeRecordingCanvas) DrawRectStroke(x, y, w, h int, col color.Color, strokeWidth float64) {
+	c.strokes++
+}
+
+func TestStage_Execute_ShowLayoutShifts(t *testing.T) {
+	var canvases []*badgeRecordingCanvas
+	mockRenderer := &mocks.Renderer{
+		CreateCanvasFunc: func(width, height int, bg color.Color) ports.Canvas {
+			c := &badgeRecordingCanvas{}
+			canvases = append(canvases, c)
+			return c
+		},
+	}
+
+	// Use a single worker so canvases are created in frame order
+	stage := NewStage(mockRenderer, mocks.NewDebugSink(false), logger.NewNoop(), 1)
+
+	layoutResult := layout.ComputeLayout(pipeline.DefaultLayoutInput())
+	input := pipeline.CompositeInput{
+		RawFrames: []pipeline.RawFrame{
+			{TimestampMs: 0, ImageData: []byte{0xFF}},
+			{TimestampMs: 600, ImageData: []byte{0xFF}},
+			{TimestampMs: 2000, ImageData: []byte{0xFF}},
+		},
+		Layout:           layoutResult,
+		Theme:            pipeline.DefaultCompositeTheme(),
+		ShowLayoutShifts: true,
+		ViewportWidth:    500,
+		LayoutShifts: []pipeline.LayoutShift{
+			{
+				TimestampMs:   500,
+				Score:         0.125,
+				PreviousRects: []pipeline.Rectangle{{X: 0, Y: 100, Width: 500, Height: 50}},
+				CurrentRects:  []pipeline.Rectangle{{X: 0, Y: 200, Width: 500, Height: 50}},
+			},
+		},
+	}
+
+	if _, err := stage.Execute(context.Background(), input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	hasText := func(c *badgeRecordingCanvas, text string) bool {
+		for _, s := range c.texts {
+			if s == text {
+				return true
+			}
+		}
+		return false
+	}
+
+	columnBorders := len(layoutResult.Columns)
+
+	// Frame 0: before the shift
+	if hasText(canvases[0], "CLS 0.125") {
+		t.Error("frame 0: CLS badge should not be shown before the shift")
+	}
+	if canvases[0].strokes != columnBorders {
+		t.Errorf("frame 0: expected only column borders, got %d strokes", canvases[0].strokes)
+	}
+
+	// Frame 1: shift is outlined and badge appears
+	if !hasText(canvases[1], "CLS 0.125") {
+		t.Errorf("frame 1: expected CLS badge, got %v", canvases[1].texts)
+	}
+	if canvases[1].strokes <= columnBorders {
+		t.Error("frame 1: expected layout shift outlines")
+	}
+
+	// Frame 2: outline has expired but the badge persists
+	if !hasText(canvases[2], "CLS 0.125") {
+		t.Errorf("frame 2: expected CLS badge to persist, got %v", canvases[2].texts)
+	}
+	if canvases[2].strokes != columnBorders {
+		t.Errorf("frame 2: expected outlines to expire, got %d strokes", canvases[2].strokes)
+	}
+}
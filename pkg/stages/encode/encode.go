@@ -5,11 +5,12 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/user/loadshow/pkg/mp4meta"
 	"github.com/user/loadshow/pkg/pipeline"
 	"github.com/user/loadshow/pkg/ports"
 )
 
-// Stage encodes composed frames into a WebM video.
+// Stage encodes composed frames into a video file.
 type Stage struct {
 	encoder ports.VideoEncoder
 	logger  ports.Logger
@@ -41,8 +42,9 @@ func (s *Stage) Execute(ctx context.Context, input pipeline.EncodeInput) (pipeli
 
 	// Initialize encoder
 	opts := ports.EncoderOptions{
-		Bitrate: input.Bitrate,
-		Quality: input.VideoCRF,
+		Bitrate:   input.Bitrate,
+		Quality:   input.VideoCRF,
+		Container: input.Container,
 	}
 
 	if err := s.encoder.Begin(width, height, input.FPS, opts); err != nil {
@@ -68,6 +70,24 @@ func (s *Stage) Execute(ctx context.Context, input pipeline.EncodeInput) (pipeli
 		return result, fmt.Errorf("end encoding: %w", err)
 	}
 
+	// Add the subtitle track
+	if len(input.Subtitles) > 0 {
+		data, err = mp4meta.EmbedSubtitles(data, input.Subtitles)
+		if err != nil {
+			return result, fmt.Errorf("embed subtitles: %w", err)
+		}
+		s.logger.Debug("Added subtitle track with %d cues", len(input.Subtitles))
+	}
+
+	// Embed metadata and chapter markers
+	if input.Metadata != nil {
+		data, err = mp4meta.Embed(data, *input.Metadata)
+		if err != nil {
+			return result, fmt.Errorf("embed metadata: %w", err)
+		}
+		s.logger.Debug("Embedded metadata with %d chapters", len(input.Metadata.Chapters))
+	}
+
 	// Calculate duration
 	durationMs := 0
 	if len(input.Frames) > 0 {
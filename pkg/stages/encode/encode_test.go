@@ -7,7 +7,9 @@ import (
 
 	"github.com/user/loadshow/pkg/adapters/logger"
 	"github.com/user/loadshow/pkg/mocks"
+	"github.com/user/loadshow/pkg/mp4meta"
 	"github.com/user/loadshow/pkg/pipeline"
+	"github.com/user/loadshow/pkg/ports"
 )
 
 func TestStage_Execute(t *testing.T) {
@@ -159,3 +161,124 @@ func TestStage_Execute_FrameTimestamps(t *testing.T) {
 		}
 	}
 }
+
+func TestStage_Execute_Container(t *testing.T) {
+	var gotOpts ports.EncoderOptions
+	mockEncoder := &mocks.VideoEncoder{
+		BeginFunc: func(width, height int, fps float64, opts ports.EncoderOptions) error {
+			gotOpts = opts
+			return nil
+		},
+	}
+
+	stage := NewStage(mockEncoder, logger.NewNoop())
+
+	input := pipeline.EncodeInput{
+		Frames: []pipeline.ComposedFrame{
+			{TimestampMs: 0, Image: image.NewRGBA(image.Rect(0, 0, 100, 100))},
+		},
+		VideoCRF:  28,
+		Container: ports.ContainerWebM,
+	}
+
+	if _, err := stage.Execute(context.Background(), input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotOpts.Container != ports.ContainerWebM {
+		t.Errorf("expected container %q, got %q", ports.ContainerWebM, gotOpts.Container)
+	}
+	if gotOpts.Quality != 28 {
+		t.Errorf("expected quality 28, got %d", gotOpts.Quality)
+	}
+}
+
+func TestStage_Execute_Metadata(t *testing.T) {
+	// ftyp + empty moov, enough for metadata embedding
+	moov := []byte{0, 0, 0, 16, 'f', 't', 'y', 'p', 'i', 's', 'o', 'm', 0, 0, 2, 0, 0, 0, 0, 8, 'm', 'o', 'o', 'v'}
+	mockEncoder := &mocks.VideoEncoder{
+		EndFunc: func() ([]byte, error) { return moov, nil },
+	}
+
+	stage := NewStage(mockEncoder, logger.NewNoop())
+
+	input := pipeline.EncodeInput{
+		Frames: []pipeline.ComposedFrame{
+			{TimestampMs: 0, Image: image.NewRGBA(image.Rect(0, 0, 100, 100))},
+		},
+		Metadata: &ports.VideoMetadata{
+			Title:    "Example",
+			Chapters: []ports.Chapter{{StartMs: 500, Title: "Load"}},
+		},
+	}
+
+	result, err := stage.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	md, err := mp4meta.Read(result.VideoData)
+	if err != nil {
+		t.Fatalf("read metadata: %v", err)
+	}
+	if md.Title != "Example" || len(md.Chapters) != 1 {
+		t.Errorf("unexpected metadata: %+v", md)
+	}
+	if result.FileSize != int64(len(result.VideoData)) {
+		t.Errorf("FileSize = %d, want %d", result.FileSize, len(result.VideoData))
+	}
+}
+
+func TestStage_Execute_MetadataInvalidVideo(t *testing.T) {
+	stage := NewStage(&mocks.VideoEncoder{}, logger.NewNoop())
+
+	input := pipeline.EncodeInput{
+		Frames: []pipeline.ComposedFrame{
+			{TimestampMs: 0, Image: image.NewRGBA(image.Rect(0, 0, 100, 100))},
+		},
+		Metadata: &ports.VideoMetadata{Title: "Example"},
+	}
+
+	if _, err := stage.Execute(context.Background(), input); err == nil {
+		t.Error("expected error embedding metadata into non-MP4 data")
+	}
+}
+
+func TestStage_Execute_Subtitles(t *testing.T) {
+	// ftyp + moov with mvhd and a video tkhd, enough for a text track
+	mvhd := make([]byte, 108)
+	copy(mvhd, []byte{0, 0, 0, 108, 'm', 'v', 'h', 'd'})
+	mvhd[23] = 100 // timescale 100
+	mvhd[107] = 2  // next_track_ID
+	tkhd := make([]byte, 92)
+	copy(tkhd, []byte{0, 0, 0, 92, 't', 'k', 'h', 'd'})
+	trak := append([]byte{0, 0, 0, 100, 't', 'r', 'a', 'k'}, tkhd...)
+	body := append(mvhd, trak...)
+	moov := append([]byte{0, 0, 0, byte(8 + len(body)), 'm', 'o', 'o', 'v'}, body...)
+
+	mockEncoder := &mocks.VideoEncoder{
+		EndFunc: func() ([]byte, error) { return moov, nil },
+	}
+	stage := NewStage(mockEncoder, logger.NewNoop())
+
+	cues := []ports.Cue{{StartMs: 100, EndMs: 1100, Text: "Load 0.10s"}}
+	input := pipeline.EncodeInput{
+		Frames: []pipeline.ComposedFrame{
+			{TimestampMs: 0, Image: image.NewRGBA(image.Rect(0, 0, 100, 100))},
+		},
+		Subtitles: cues,
+	}
+
+	result, err := stage.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := mp4meta.ReadSubtitles(result.VideoData)
+	if err != nil {
+		t.Fatalf("read subtitles: %v", err)
+	}
+	if len(got) != 1 || got[0] != cues[0] {
+		t.Errorf("expected %+v, got %+v", cues, got)
+	}
+}
@@ -0,0 +1,309 @@
+// Package filmstrip implements the filmstrip contact sheet stage.
+package filmstrip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+
+	"github.com/user/loadshow/pkg/imagediff"
+	"github.com/user/loadshow/pkg/pipeline"
+	"github.com/user/loadshow/pkg/ports"
+)
+
+// Layout parameters in pixels.
+const (
+	cellGap       = 8
+	labelHeight   = 20
+	changedStroke = 3
+)
+
+// Stage renders sampled frames into a single contact sheet image.
+type Stage struct {
+	renderer ports.Renderer
+	logger   ports.Logger
+}
+
+// NewStage creates a new filmstrip stage.
+func NewStage(renderer ports.Renderer, logger ports.Logger) *Stage {
+	return &Stage{
+		renderer: renderer,
+		logger:   logger.WithComponent("filmstrip"),
+	}
+}
+
+// sample is a frame selected for the filmstrip.
+type sample struct {
+	index       int         // Index of the frame shown
+	image       image.Image // Image of the frame, set after sampling
+	timestampMs int         // Time shown in the label
+	changed     bool        // The page visually changed since the previous sample
+}
+
+// Execute samples the frames and encodes the filmstrip image.
+func (s *Stage) Execute(ctx context.Context, input pipeline.FilmstripInput) (pipeline.FilmstripResult, error) {
+	if len(input.Frames) == 0 {
+		return pipeline.FilmstripResult{}, fmt.Errorf("no frames for filmstrip")
+	}
+	input = withDefaults(input)
+
+	changed, err := s.detectChanges(ctx, input)
+	if err != nil {
+		return pipeline.FilmstripResult{}, err
+	}
+	samples := sampleFrames(input, changed)
+	for i := range samples {
+		samples[i].image = input.Frames[samples[i].index].Image
+	}
+
+	s.logger.Debug("Sampled %d of %d frames (%s)", len(samples), len(input.Frames), input.Mode)
+	return s.encode(samples, input)
+}
+
+// ExecuteVideo samples the frames of a saved video and encodes the
+// filmstrip image. input.Frames and input.RawFrames are ignored: the frames
+// are compared within input.CompareArea in one pass over the video, then
+// only the sampled frames are decoded again. At most two frames besides the
+// samples are held in memory.
+func (s *Stage) ExecuteVideo(ctx context.Context, frames ports.FrameIterator, input pipeline.FilmstripInput) (pipeline.FilmstripResult, error) {
+	input = withDefaults(input)
+	input.Frames = nil
+	input.RawFrames = nil
+
+	// Keep only the timestamps of the frames
+	var changed []bool
+	var prev *image.RGBA
+	for {
+		select {
+		case <-ctx.Done():
+			return pipeline.FilmstripResult{}, ctx.Err()
+		default:
+		}
+
+		frame, err := frames.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return pipeline.FilmstripResult{}, fmt.Errorf("decode frame %d: %w", len(input.Frames), err)
+		}
+
+		curr := compareImage(frame.Image, input.CompareArea)
+		changed = append(changed, prev != nil && imagediff.Changed(prev, curr))
+		prev = curr
+		input.Frames = append(input.Frames, pipeline.ComposedFrame{TimestampMs: frame.TimestampMs})
+	}
+	if len(input.Frames) == 0 {
+		return pipeline.FilmstripResult{}, fmt.Errorf("no frames for filmstrip")
+	}
+
+	samples := sampleFrames(input, changed)
+	images := make(map[int]image.Image)
+	for i := range samples {
+		index := samples[i].index
+		if _, ok := images[index]; !ok {
+			// Samples are in frame order, so seeks only move forward
+			ts := input.Frames[index].TimestampMs
+			if err := frames.Seek(ts); err != nil {
+				return pipeline.FilmstripResult{}, fmt.Errorf("seek to %dms: %w", ts, err)
+			}
+			frame, err := frames.Next()
+			if errors.Is(err, io.EOF) {
+				return pipeline.FilmstripResult{}, fmt.Errorf("no frame at %dms", ts)
+			}
+			if err != nil {
+				return pipeline.FilmstripResult{}, fmt.Errorf("decode frame at %dms: %w", ts, err)
+			}
+			images[index] = frame.Image
+		}
+		samples[i].image = images[index]
+	}
+
+	s.logger.Debug("Sampled %d of %d frames (%s), decoded %d", len(samples), len(input.Frames), input.Mode, len(images))
+	return s.encode(samples, input)
+}
+
+// withDefaults fills in the default interval and thumbnail width.
+func withDefaults(input pipeline.FilmstripInput) pipeline.FilmstripInput {
+	if input.IntervalMs <= 0 {
+		input.IntervalMs = pipeline.DefaultFilmstripInput().IntervalMs
+	}
+	if input.ThumbWidth <= 0 {
+		input.ThumbWidth = pipeline.DefaultFilmstripInput().ThumbWidth
+	}
+	return input
+}
+
+// encode renders the samples and encodes the filmstrip image.
+func (s *Stage) encode(samples []sample, input pipeline.FilmstripInput) (pipeline.FilmstripResult, error) {
+	result := pipeline.FilmstripResult{}
+
+	img := s.render(samples, input)
+	data, err := s.renderer.EncodeImage(img, input.Format, input.Quality)
+	if err != nil {
+		return result, fmt.Errorf("encode filmstrip: %w", err)
+	}
+
+	result.ImageData = data
+	result.Width = img.Bounds().Dx()
+	result.Height = img.Bounds().Dy()
+	result.FrameCount = len(samples)
+	return result, nil
+}
+
+// detectChanges reports for each frame whether it visually differs from the previous frame.
+// The recorded frames are compared when present, so that overlays of the
+// composed frames (progress, badges, highlights) do not count as changes.
+// Otherwise only the CompareArea of the composed frames is compared.
+// Only two frames are decoded at a time.
+func (s *Stage) detectChanges(ctx context.Context, input pipeline.FilmstripInput) ([]bool, error) {
+	frames := input.Frames
+	if input.RawFrames != nil && len(input.RawFrames) != len(frames) {
+		return nil, fmt.Errorf("got %d recorded frames for %d composed frames", len(input.RawFrames), len(frames))
+	}
+	changed := make([]bool, len(frames))
+
+	var prev *image.RGBA
+	for i, frame := range frames {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		var curr *image.RGBA
+		if input.RawFrames != nil {
+			img, err := s.renderer.DecodeImage(input.RawFrames[i].ImageData, ports.FormatJPEG)
+			if err != nil {
+				return nil, fmt.Errorf("decode frame %d for change detection: %w", i, err)
+			}
+			curr = imagediff.ToRGBA(img)
+		} else {
+			curr = compareImage(frame.Image, input.CompareArea)
+		}
+		if prev != nil {
+			changed[i] = imagediff.Changed(prev, curr)
+		}
+		prev = curr
+	}
+	return changed, nil
+}
+
+// compareImage returns the part of img within area, or all of img if area
+// is empty, for change detection.
+func compareImage(img image.Image, area pipeline.Rectangle) *image.RGBA {
+	if img != nil && area.Width > 0 && area.Height > 0 {
+		b := img.Bounds()
+		r := image.Rect(area.X, area.Y, area.X+area.Width, area.Y+area.Height).Add(b.Min).Intersect(b)
+		if sub, ok := img.(interface {
+			SubImage(image.Rectangle) image.Image
+		}); ok {
+			img = sub.SubImage(r)
+		}
+	}
+	return imagediff.ToRGBA(img)
+}
+
+// sampleFrames selects the frames shown in the filmstrip.
+// Images are not set: each sample holds the index of its frame.
+// Sampling ends at EndMs, or at the last visual change when EndMs is zero.
+func sampleFrames(input pipeline.FilmstripInput, changed []bool) []sample {
+	frames := input.Frames
+
+	endMs := input.EndMs
+	if endMs <= 0 {
+		endMs = frames[0].TimestampMs
+		for i := range frames {
+			if changed[i] {
+				endMs = frames[i].TimestampMs
+			}
+		}
+	}
+
+	var samples []sample
+
+	if input.Mode == pipeline.FilmstripChanges {
+		for i, frame := range frames {
+			if frame.TimestampMs > endMs {
+				break
+			}
+			if i == 0 || changed[i] {
+				samples = append(samples, sample{index: i, timestampMs: frame.TimestampMs, changed: i > 0})
+			}
+		}
+		return samples
+	}
+
+	// Interval sampling shows the latest frame at each tick, including one tick at or past endMs
+	index, prevIndex := 0, -1
+	for t := 0; ; t += input.IntervalMs {
+		for index+1 < len(frames) && frames[index+1].TimestampMs <= t {
+			index++
+		}
+
+		anyChange := false
+		for i := prevIndex + 1; prevIndex >= 0 && i <= index; i++ {
+			anyChange = anyChange || changed[i]
+		}
+		samples = append(samples, sample{index: index, timestampMs: t, changed: anyChange})
+		prevIndex = index
+
+		if t >= endMs {
+			break
+		}
+	}
+	return samples
+}
+
+// render lays the samples out in a grid with time labels.
+func (s *Stage) render(samples []sample, input pipeline.FilmstripInput) image.Image {
+	theme := input.Theme
+
+	thumbWidth := input.ThumbWidth
+	bounds := samples[0].image.Bounds()
+	thumbHeight := max(1, thumbWidth*bounds.Dy()/max(1, bounds.Dx()))
+
+	columns := input.Columns
+	if columns <= 0 || columns > len(samples) {
+		columns = len(samples)
+	}
+	rows := (len(samples) + columns - 1) / columns
+
+	cellWidth := thumbWidth + cellGap
+	cellHeight := thumbHeight + labelHeight + cellGap
+	width := cellGap + columns*cellWidth
+	height := cellGap + rows*cellHeight
+
+	canvas := s.renderer.CreateCanvas(width, height, theme.BackgroundColor)
+
+	labelStyle := ports.TextStyle{
+		FontSize: theme.LabelFontSize,
+		Color:    theme.LabelColor,
+		Align:    ports.AlignCenter,
+	}
+
+	for i, smp := range samples {
+		x := cellGap + (i%columns)*cellWidth
+		y := cellGap + (i/columns)*cellHeight
+
+		canvas.DrawImageScaled(smp.image, x, y, thumbWidth, thumbHeight)
+		if smp.changed {
+			// Stroke outside the thumbnail so the content stays visible
+			inset := changedStroke / 2
+			canvas.DrawRectStroke(x-inset-1, y-inset-1, thumbWidth+2*inset+2, thumbHeight+2*inset+2, theme.ChangedColor, changedStroke)
+		} else {
+			canvas.DrawRectStroke(x, y, thumbWidth, thumbHeight, theme.BorderColor, 1)
+		}
+
+		canvas.DrawText(formatTimestamp(smp.timestampMs), x+thumbWidth/2, y+thumbHeight+labelHeight/2, labelStyle)
+	}
+
+	return canvas.ToImage()
+}
+
+// formatTimestamp formats milliseconds as seconds with one decimal (e.g., "1.5s").
+func formatTimestamp(ms int) string {
+	return fmt.Sprintf("%.1fs", float64(ms)/1000)
+}
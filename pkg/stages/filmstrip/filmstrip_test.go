@@ -0,0 +1,259 @@
+package filmstrip
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/user/loadshow/pkg/adapters/logger"
+	"github.com/user/loadshow/pkg/mocks"
+	"github.com/user/loadshow/pkg/pipeline"
+	"github.com/user/loadshow/pkg/ports"
+)
+
+// solidFrame returns a composed frame filled with a single gray level.
+func solidFrame(ts int, level uint8) pipeline.ComposedFrame {
+	img := image.NewRGBA(image.Rect(0, 0, 100, 200))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.RGBA{R: level, G: level, B: level, A: 255}), image.Point{}, draw.Src)
+	return pipeline.ComposedFrame{TimestampMs: ts, Image: img}
+}
+
+// testFrames changes visually at 0ms, 250ms and 600ms, then holds until 1000ms.
+func testFrames() []pipeline.ComposedFrame {
+	return []pipeline.ComposedFrame{
+		solidFrame(0, 255),
+		solidFrame(100, 255),
+		solidFrame(250, 128),
+		solidFrame(400, 128),
+		solidFrame(600, 0),
+		solidFrame(1000, 0),
+	}
+}
+
+func TestSampleFrames_Interval(t *testing.T) {
+	frames := testFrames()
+	changed, err := (&Stage{}).detectChanges(context.Background(), pipeline.FilmstripInput{Frames: frames})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	input := pipeline.FilmstripInput{Frames: frames, Mode: pipeline.FilmstripInterval, IntervalMs: 200}
+	samples := sampleFrames(input, changed)
+
+	// Sampling stops at the first tick at or after the last change (600ms)
+	wantTimes := []int{0, 200, 400, 600}
+	wantChanged := []bool{false, false, true, true}
+	if len(samples) != len(wantTimes) {
+		t.Fatalf("expected %d samples, got %d", len(wantTimes), len(samples))
+	}
+	for i, s := range samples {
+		if s.timestampMs != wantTimes[i] {
+			t.Errorf("sample %d: expected %dms, got %dms", i, wantTimes[i], s.timestampMs)
+		}
+		if s.changed != wantChanged[i] {
+			t.Errorf("sample %d: expected changed=%v, got %v", i, wantChanged[i], s.changed)
+		}
+	}
+}
+
+func TestSampleFrames_Changes(t *testing.T) {
+	frames := testFrames()
+	changed, _ := (&Stage{}).detectChanges(context.Background(), pipeline.FilmstripInput{Frames: frames})
+
+	input := pipeline.FilmstripInput{Frames: frames, Mode: pipeline.FilmstripChanges}
+	samples := sampleFrames(input, changed)
+
+	wantTimes := []int{0, 250, 600}
+	if len(samples) != len(wantTimes) {
+		t.Fatalf("expected %d samples, got %d", len(wantTimes), len(samples))
+	}
+	for i, s := range samples {
+		if s.timestampMs != wantTimes[i] {
+			t.Errorf("sample %d: expected %dms, got %dms", i, wantTimes[i], s.timestampMs)
+		}
+		if s.changed != (i > 0) {
+			t.Errorf("sample %d: unexpected changed=%v", i, s.changed)
+		}
+	}
+}
+
+func TestSampleFrames_EndMs(t *testing.T) {
+	frames := testFrames()
+	changed, _ := (&Stage{}).detectChanges(context.Background(), pipeline.FilmstripInput{Frames: frames})
+
+	input := pipeline.FilmstripInput{Frames: frames, Mode: pipeline.FilmstripInterval, IntervalMs: 500, EndMs: 1000}
+	samples := sampleFrames(input, changed)
+	if len(samples) != 3 {
+		t.Errorf("expected 3 samples (0, 500, 1000ms), got %d", len(samples))
+	}
+}
+
+func TestDetectChanges_RawFrames(t *testing.T) {
+	// The composed frames change (e.g. a badge appears), the page does not
+	frames := testFrames()
+	raw := make([]pipeline.RawFrame, len(frames))
+	for i := range raw {
+		level := byte(200)
+		if i >= 3 {
+			level = 50
+		}
+		raw[i] = pipeline.RawFrame{TimestampMs: frames[i].TimestampMs, ImageData: []byte{level}}
+	}
+
+	renderer := &mocks.Renderer{
+		DecodeImageFunc: func(data []byte, format ports.ImageFormat) (image.Image, error) {
+			return solidFrame(0, data[0]).Image, nil
+		},
+	}
+	stage := NewStage(renderer, logger.NewNoop())
+
+	changed, err := stage.detectChanges(context.Background(), pipeline.FilmstripInput{Frames: frames, RawFrames: raw})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []bool{false, false, false, true, false, false}
+	for i := range want {
+		if changed[i] != want[i] {
+			t.Errorf("frame %d: expected changed=%v, got %v", i, want[i], changed[i])
+		}
+	}
+
+	if _, err := stage.detectChanges(context.Background(), pipeline.FilmstripInput{Frames: frames, RawFrames: raw[:2]}); err == nil {
+		t.Error("expected error for mismatched frame counts")
+	}
+}
+
+// withBadge draws a band of the given gray level over the top 20 rows of a
+// frame, like the progress bar and timing badges of a composed frame.
+func withBadge(frame pipeline.ComposedFrame, level uint8) pipeline.ComposedFrame {
+	img := image.NewRGBA(frame.Image.Bounds())
+	draw.Draw(img, img.Bounds(), frame.Image, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(0, 0, 100, 20), image.NewUniform(color.RGBA{R: level, G: level, B: level, A: 255}), image.Point{}, draw.Src)
+	return pipeline.ComposedFrame{TimestampMs: frame.TimestampMs, Image: img}
+}
+
+// screen is the part of the test frames below the badge band.
+var screen = pipeline.Rectangle{X: 0, Y: 20, Width: 100, Height: 180}
+
+func TestDetectChanges_CompareArea(t *testing.T) {
+	// The badge band changes on every frame, the page does not
+	frames := make([]pipeline.ComposedFrame, 4)
+	for i := range frames {
+		frames[i] = withBadge(solidFrame(i*100, 255), uint8(i*80))
+	}
+	stage := NewStage(&mocks.Renderer{}, logger.NewNoop())
+
+	changed, err := stage.detectChanges(context.Background(), pipeline.FilmstripInput{Frames: frames, CompareArea: screen})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, c := range changed {
+		if c {
+			t.Errorf("frame %d: expected no change within the compare area", i)
+		}
+	}
+
+	changed, err = stage.detectChanges(context.Background(), pipeline.FilmstripInput{Frames: frames})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !changed[1] {
+		t.Error("expected the badge band to count as a change without a compare area")
+	}
+}
+
+func TestStage_ExecuteVideo(t *testing.T) {
+	var videoFrames []ports.VideoFrame
+	for i, f := range testFrames() {
+		f = withBadge(f, uint8(i*40))
+		videoFrames = append(videoFrames, ports.VideoFrame{TimestampMs: f.TimestampMs, Image: f.Image})
+	}
+	frames := mocks.NewFrameIterator(videoFrames)
+
+	renderer := &mocks.Renderer{
+		CreateCanvasFunc: func(width, height int, bg color.Color) ports.Canvas {
+			return &mocks.Canvas{}
+		},
+		EncodeImageFunc: func(img image.Image, format ports.ImageFormat, quality int) ([]byte, error) {
+			return []byte{0x89, 'P', 'N', 'G'}, nil
+		},
+	}
+
+	stage := NewStage(renderer, logger.NewNoop())
+	input := pipeline.DefaultFilmstripInput()
+	input.Mode = pipeline.FilmstripChanges
+	input.CompareArea = screen
+
+	result, err := stage.ExecuteVideo(context.Background(), frames, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The first frame and the page changes at 250ms and 600ms
+	if result.FrameCount != 3 {
+		t.Errorf("expected 3 frames, got %d", result.FrameCount)
+	}
+	// One pass with EOF, then one decode per sample
+	if decoded := frames.NextCalls - len(videoFrames) - 1; decoded != 3 {
+		t.Errorf("expected 3 sampled frames decoded again, got %d", decoded)
+	}
+}
+
+func TestStage_ExecuteVideo_NoFrames(t *testing.T) {
+	stage := NewStage(&mocks.Renderer{}, logger.NewNoop())
+	if _, err := stage.ExecuteVideo(context.Background(), mocks.NewFrameIterator(nil), pipeline.DefaultFilmstripInput()); err == nil {
+		t.Error("expected error for a video without frames")
+	}
+}
+
+func TestStage_Execute(t *testing.T) {
+	var canvasWidth, canvasHeight int
+	var encodedFormat ports.ImageFormat
+	renderer := &mocks.Renderer{
+		CreateCanvasFunc: func(width, height int, bg color.Color) ports.Canvas {
+			canvasWidth, canvasHeight = width, height
+			return &mocks.Canvas{}
+		},
+		EncodeImageFunc: func(img image.Image, format ports.ImageFormat, quality int) ([]byte, error) {
+			encodedFormat = format
+			return []byte{0x89, 'P', 'N', 'G'}, nil
+		},
+	}
+
+	stage := NewStage(renderer, logger.NewNoop())
+	input := pipeline.DefaultFilmstripInput()
+	input.Frames = testFrames()
+	input.IntervalMs = 200
+	input.Columns = 2
+	input.ThumbWidth = 50
+
+	result, err := stage.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.FrameCount != 4 {
+		t.Errorf("expected 4 frames, got %d", result.FrameCount)
+	}
+	// 2 columns x 2 rows of 50x100 thumbnails
+	wantWidth := cellGap + 2*(50+cellGap)
+	wantHeight := cellGap + 2*(100+labelHeight+cellGap)
+	if canvasWidth != wantWidth || canvasHeight != wantHeight {
+		t.Errorf("expected %dx%d canvas, got %dx%d", wantWidth, wantHeight, canvasWidth, canvasHeight)
+	}
+	if encodedFormat != ports.FormatPNG {
+		t.Errorf("expected PNG output, got %v", encodedFormat)
+	}
+	if len(result.ImageData) == 0 {
+		t.Error("expected image data")
+	}
+}
+
+func TestStage_Execute_NoFrames(t *testing.T) {
+	stage := NewStage(&mocks.Renderer{}, logger.NewNoop())
+	if _, err := stage.Execute(context.Background(), pipeline.DefaultFilmstripInput()); err == nil {
+		t.Error("expected error for empty frames")
+	}
+}
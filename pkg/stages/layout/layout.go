@@ -29,6 +29,9 @@ func (s *Stage) Execute(ctx context.Context, input pipeline.LayoutInput) (pipeli
 // - Layout positions are RELATIVE to content area (not including banner/progress offset)
 // - column.y = padding + (isFirst ? 0 : indent)
 // - Composition will add canvasOffset (bannerHeight + progressHeight) when drawing
+//
+// When a device frame is used, each window is inset by the bezel so the frame does not cover content,
+// and the scroll width shrinks to match.
 func ComputeLayout(input pipeline.LayoutInput) pipeline.LayoutResult {
 	// Calculate column width
 	// columnWidth = (canvasWidth - padding*2 - gap*(columns-1)) / columns
@@ -78,13 +81,15 @@ func ComputeLayout(input pipeline.LayoutInput) pipeline.LayoutResult {
 		// window.y = column.y + (isFirst ? borderWidth : 0)
 		// window.width = column.width - borderWidth*2
 		// window.height = column.height - (isLast ? borderWidth : 0)
-		winX := colX + input.BorderWidth
-		winY := colY
+		// Bezel (device frame) insets apply to every column
+		bezel := input.Bezel
+		winX := colX + bezel.Left + input.BorderWidth
+		winY := colY + bezel.Top
 		if isFirst {
 			winY += input.BorderWidth
 		}
-		winWidth := columnWidth - input.BorderWidth*2
-		winHeight := colHeight
+		winWidth := columnWidth - bezel.Left - bezel.Right - input.BorderWidth*2
+		winHeight := colHeight - bezel.Top - bezel.Bottom
 		if isLast {
 			winHeight -= input.BorderWidth
 		}
@@ -104,7 +109,8 @@ func ComputeLayout(input pipeline.LayoutInput) pipeline.LayoutResult {
 
 	// Calculate scroll dimensions (viewport size for recording)
 	// TypeScript: scroll.width = columnWidth (full column width, not window width)
-	scrollWidth := columnWidth
+	// The bezel is excluded so the page is not clipped by the frame
+	scrollWidth := columnWidth - input.Bezel.Left - input.Bezel.Right
 	scrollHeight := currentScrollTop
 
 	// Banner area (at the very top, full width minus padding)
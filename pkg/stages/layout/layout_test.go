@@ -407,6 +407,44 @@ func TestComputeLayout_LargeBorderWidth(t *testing.T) {
 	}
 }
 
+// TestComputeLayout_Bezel tests that windows are inset by the device frame bezel.
+func TestComputeLayout_Bezel(t *testing.T) {
+	input := pipeline.LayoutInput{
+		CanvasWidth:  512,
+		CanvasHeight: 640,
+		Columns:      3,
+		Gap:          20,
+		Padding:      20,
+		Indent:       20,
+		Outdent:      20,
+		Bezel:        pipeline.Insets{Top: 20, Right: 6, Bottom: 20, Left: 6},
+	}
+	result := ComputeLayout(input)
+
+	// Scroll width = columnWidth - bezel.left - bezel.right = 144 - 12 = 132
+	if result.Scroll.Width != 132 {
+		t.Errorf("scroll width: expected 132, got %d", result.Scroll.Width)
+	}
+
+	for i, win := range result.Windows {
+		col := result.Columns[i]
+		if win.X != col.X+6 || win.Y != col.Y+20 {
+			t.Errorf("window[%d]: expected origin (%d,%d), got (%d,%d)", i, col.X+6, col.Y+20, win.X, win.Y)
+		}
+		if win.Width != 132 {
+			t.Errorf("window[%d].Width: expected 132, got %d", i, win.Width)
+		}
+		if win.Height != col.Height-40 {
+			t.Errorf("window[%d].Height: expected %d, got %d", i, col.Height-40, win.Height)
+		}
+	}
+
+	// Scroll positions continue across columns without gaps
+	if result.Windows[1].ScrollTop != result.Windows[0].Height {
+		t.Errorf("window[1].ScrollTop: expected %d, got %d", result.Windows[0].Height, result.Windows[1].ScrollTop)
+	}
+}
+
 // TestStage_Execute tests the stage wrapper.
 func TestStage_Execute(t *testing.T) {
 	stage := NewStage()
@@ -0,0 +1,99 @@
+// Package poster implements the poster image and thumbnail stage.
+package poster
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/user/loadshow/pkg/pipeline"
+	"github.com/user/loadshow/pkg/ports"
+)
+
+// Stage encodes one composed frame as a poster image plus resized thumbnails.
+type Stage struct {
+	renderer ports.Renderer
+	logger   ports.Logger
+}
+
+// NewStage creates a new poster stage.
+func NewStage(renderer ports.Renderer, logger ports.Logger) *Stage {
+	return &Stage{
+		renderer: renderer,
+		logger:   logger.WithComponent("poster"),
+	}
+}
+
+// Execute selects the poster frame and encodes it with its thumbnails.
+func (s *Stage) Execute(ctx context.Context, input pipeline.PosterInput) (pipeline.PosterResult, error) {
+	result := pipeline.PosterResult{}
+
+	if len(input.Frames) == 0 {
+		return result, fmt.Errorf("no frames for poster")
+	}
+	for _, w := range input.ThumbWidths {
+		if w <= 0 {
+			return result, fmt.Errorf("invalid thumbnail width: %d", w)
+		}
+	}
+
+	frame := selectFrame(input)
+	bounds := frame.Image.Bounds()
+	s.logger.Debug("Selected frame at %dms (%s)", frame.TimestampMs, input.Frame)
+
+	data, err := s.renderer.EncodeImage(frame.Image, input.Format, input.Quality)
+	if err != nil {
+		return result, fmt.Errorf("encode poster: %w", err)
+	}
+	result.TimestampMs = frame.TimestampMs
+	result.ImageData = data
+	result.Width = bounds.Dx()
+	result.Height = bounds.Dy()
+
+	for _, w := range input.ThumbWidths {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		h := thumbHeight(bounds.Dx(), bounds.Dy(), w)
+		thumb := s.renderer.ResizeImage(frame.Image, w, h)
+		data, err := s.renderer.EncodeImage(thumb, input.Format, input.Quality)
+		if err != nil {
+			return result, fmt.Errorf("encode %dpx thumbnail: %w", w, err)
+		}
+		result.Thumbnails = append(result.Thumbnails, pipeline.Thumbnail{
+			ImageData: data,
+			Width:     w,
+			Height:    h,
+		})
+	}
+
+	return result, nil
+}
+
+// selectFrame returns the frame visible at the requested time: the last frame
+// whose timestamp is not after it. PosterFinal always picks the last frame.
+func selectFrame(input pipeline.PosterInput) pipeline.ComposedFrame {
+	frames := input.Frames
+	if input.Frame == pipeline.PosterFinal || input.Frame == "" {
+		return frames[len(frames)-1]
+	}
+	selected := frames[0]
+	for _, f := range frames {
+		if f.TimestampMs > input.TimestampMs {
+			break
+		}
+		selected = f
+	}
+	return selected
+}
+
+// thumbHeight scales height to keep the aspect ratio at the given width.
+func thumbHeight(width, height, thumbWidth int) int {
+	if width <= 0 {
+		return thumbWidth
+	}
+	h := (height*thumbWidth + width/2) / width
+	if h < 1 {
+		h = 1
+	}
+	return h
+}
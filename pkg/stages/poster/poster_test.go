@@ -0,0 +1,104 @@
+package poster
+
+import (
+	"context"
+	"image"
+	"testing"
+
+	"github.com/user/loadshow/pkg/adapters/logger"
+	"github.com/user/loadshow/pkg/mocks"
+	"github.com/user/loadshow/pkg/pipeline"
+	"github.com/user/loadshow/pkg/ports"
+)
+
+func testFrames() []pipeline.ComposedFrame {
+	var frames []pipeline.ComposedFrame
+	for _, ts := range []int{0, 300, 800, 1500} {
+		frames = append(frames, pipeline.ComposedFrame{
+			TimestampMs: ts,
+			Image:       image.NewRGBA(image.Rect(0, 0, 400, 600)),
+		})
+	}
+	return frames
+}
+
+func TestSelectFrame(t *testing.T) {
+	tests := []struct {
+		name  string
+		frame pipeline.PosterFrame
+		ts    int
+		want  int
+	}{
+		{"final", pipeline.PosterFinal, 0, 1500},
+		{"default", "", 300, 1500},
+		{"exact", pipeline.PosterTime, 800, 800},
+		{"between frames", pipeline.PosterLCP, 1200, 800},
+		{"before first", pipeline.PosterTime, -10, 0},
+		{"after last", pipeline.PosterLoad, 9000, 1500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := selectFrame(pipeline.PosterInput{Frames: testFrames(), Frame: tt.frame, TimestampMs: tt.ts})
+			if got.TimestampMs != tt.want {
+				t.Errorf("selected %dms, want %dms", got.TimestampMs, tt.want)
+			}
+		})
+	}
+}
+
+func TestStage_Execute(t *testing.T) {
+	var encoded []image.Rectangle
+	renderer := &mocks.Renderer{
+		EncodeImageFunc: func(img image.Image, format ports.ImageFormat, quality int) ([]byte, error) {
+			encoded = append(encoded, img.Bounds())
+			if format != ports.FormatJPEG || quality != 85 {
+				t.Errorf("unexpected format %v quality %d", format, quality)
+			}
+			return []byte{0xFF, 0xD8}, nil
+		},
+	}
+
+	stage := NewStage(renderer, logger.NewNoop())
+	result, err := stage.Execute(context.Background(), pipeline.PosterInput{
+		Frames:      testFrames(),
+		Frame:       pipeline.PosterTime,
+		TimestampMs: 500,
+		ThumbWidths: []int{200, 101},
+		Format:      ports.FormatJPEG,
+		Quality:     85,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.TimestampMs != 300 {
+		t.Errorf("expected frame at 300ms, got %dms", result.TimestampMs)
+	}
+	if result.Width != 400 || result.Height != 600 || len(result.ImageData) == 0 {
+		t.Errorf("unexpected poster %dx%d (%d bytes)", result.Width, result.Height, len(result.ImageData))
+	}
+	if len(result.Thumbnails) != 2 {
+		t.Fatalf("expected 2 thumbnails, got %d", len(result.Thumbnails))
+	}
+	if th := result.Thumbnails[0]; th.Width != 200 || th.Height != 300 {
+		t.Errorf("expected 200x300 thumbnail, got %dx%d", th.Width, th.Height)
+	}
+	if th := result.Thumbnails[1]; th.Width != 101 || th.Height != 152 {
+		t.Errorf("expected 101x152 thumbnail, got %dx%d", th.Width, th.Height)
+	}
+	if len(encoded) != 3 || encoded[1].Dx() != 200 {
+		t.Errorf("unexpected encoded images: %v", encoded)
+	}
+}
+
+func TestStage_Execute_Errors(t *testing.T) {
+	stage := NewStage(&mocks.Renderer{}, logger.NewNoop())
+	if _, err := stage.Execute(context.Background(), pipeline.PosterInput{}); err == nil {
+		t.Error("expected error for empty frames")
+	}
+	input := pipeline.PosterInput{Frames: testFrames(), ThumbWidths: []int{0}}
+	if _, err := stage.Execute(context.Background(), input); err == nil {
+		t.Error("expected error for zero thumbnail width")
+	}
+}
@@ -4,6 +4,8 @@ package record
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
 	"time"
 
 	"github.com/user/loadshow/pkg/pipeline"
@@ -66,6 +68,7 @@ func (s *Stage) Execute(ctx context.Context, input pipeline.RecordInput) (pipeli
 	// Set window size only (no viewport override)
 	opts.WindowWidth = windowWidth
 	opts.WindowHeight = windowHeight
+	result.ViewportWidth = windowWidth
 	// Merge browser options from input
 	opts.IgnoreHTTPSErrors = input.IgnoreHTTPSErrors
 	opts.ProxyServer = input.ProxyServer
@@ -208,6 +211,43 @@ done:
 		// Continue without timing data
 	}
 
+	// Get layout shifts (may fail if timed out or unsupported)
+	layoutShifts, err := s.browser.GetLayoutShifts()
+	if err != nil {
+		s.logger.Debug("Failed to get layout shifts: %s", err)
+	} else {
+		result.LayoutShifts = convertLayoutShifts(layoutShifts)
+		result.LayoutShiftsRecorded = true
+		s.logger.Debug("Captured %d layout shifts", len(result.LayoutShifts))
+	}
+
+	// Get LCP candidates (may fail if timed out or unsupported)
+	lcpCandidates, err := s.browser.GetLCPCandidates()
+	if err != nil {
+		s.logger.Debug("Failed to get LCP candidates: %s", err)
+	} else {
+		result.LCPCandidates = convertLCPCandidates(lcpCandidates)
+		s.logger.Debug("Captured %d LCP candidates", len(result.LCPCandidates))
+	}
+
+	// Get user-timing marks and measures (may fail if timed out)
+	userTimings, err := s.browser.GetUserTimings()
+	if err != nil {
+		s.logger.Debug("Failed to get user timings: %s", err)
+	} else {
+		result.UserTimings = convertUserTimings(userTimings)
+		s.logger.Debug("Captured %d user timings", len(result.UserTimings))
+	}
+
+	// Get network requests (may fail if timed out)
+	resourceTimings, err := s.browser.GetResourceTimings()
+	if err != nil {
+		s.logger.Debug("Failed to get resource timings: %s", err)
+	} else {
+		result.Requests = convertResourceTimings(resourceTimings)
+		s.logger.Debug("Captured %d requests", len(result.Requests))
+	}
+
 	// Calculate timing
 	totalDuration := time.Since(navStart)
 	result.Timing = pipeline.TimingInfo{
@@ -222,6 +262,11 @@ done:
 		result.Timing.LoadCompleteMs = int(perfTiming.LoadEventEnd)
 	}
 
+	// LCP is the last candidate reported by the browser
+	if n := len(result.LCPCandidates); n > 0 {
+		result.Timing.LargestContentfulPaintMs = result.LCPCandidates[n-1].TimestampMs
+	}
+
 	// Fallback: Set load complete time based on last frame if not available
 	if result.Timing.LoadCompleteMs == 0 && len(result.Frames) > 0 {
 		lastFrame := result.Frames[len(result.Frames)-1]
@@ -230,3 +275,79 @@ done:
 
 	return result, nil
 }
+
+// convertLayoutShifts converts browser layout-shift entries to pipeline layout shifts.
+func convertLayoutShifts(entries []ports.LayoutShift) []pipeline.LayoutShift {
+	shifts := make([]pipeline.LayoutShift, 0, len(entries))
+	for _, e := range entries {
+		shift := pipeline.LayoutShift{
+			TimestampMs:    int(e.StartTime),
+			Score:          e.Value,
+			HadRecentInput: e.HadRecentInput,
+		}
+		for _, src := range e.Sources {
+			shift.PreviousRects = append(shift.PreviousRects, toRectangle(src.PreviousRect))
+			shift.CurrentRects = append(shift.CurrentRects, toRectangle(src.CurrentRect))
+		}
+		shifts = append(shifts, shift)
+	}
+	return shifts
+}
+
+// convertLCPCandidates converts browser LCP entries to pipeline LCP candidates.
+func convertLCPCandidates(entries []ports.LCPCandidate) []pipeline.LCPCandidate {
+	candidates := make([]pipeline.LCPCandidate, 0, len(entries))
+	for _, e := range entries {
+		candidates = append(candidates, pipeline.LCPCandidate{
+			TimestampMs: int(e.StartTime),
+			Size:        e.Size,
+			Element:     e.Element,
+			URL:         e.URL,
+			Rect:        toRectangle(e.Rect),
+		})
+	}
+	return candidates
+}
+
+// convertUserTimings converts browser user-timing entries to pipeline user timings.
+func convertUserTimings(entries []ports.UserTiming) []pipeline.UserTiming {
+	timings := make([]pipeline.UserTiming, 0, len(entries))
+	for _, e := range entries {
+		timings = append(timings, pipeline.UserTiming{
+			Name:       e.Name,
+			EntryType:  e.EntryType,
+			StartMs:    int(e.StartTime),
+			DurationMs: int(e.Duration),
+		})
+	}
+	return timings
+}
+
+// convertResourceTimings converts browser resource-timing entries to
+// pipeline requests, sorted by start time.
+func convertResourceTimings(entries []ports.ResourceTiming) []pipeline.Request {
+	requests := make([]pipeline.Request, 0, len(entries))
+	for _, e := range entries {
+		requests = append(requests, pipeline.Request{
+			URL:          e.URL,
+			Type:         e.InitiatorType,
+			StartMs:      int(e.StartTime),
+			DurationMs:   int(e.Duration),
+			TransferSize: e.TransferSize,
+		})
+	}
+	sort.SliceStable(requests, func(i, j int) bool {
+		return requests[i].StartMs < requests[j].StartMs
+	})
+	return requests
+}
+
+// toRectangle rounds a CSS pixel rect to integer coordinates.
+func toRectangle(r ports.Rect) pipeline.Rectangle {
+	return pipeline.Rectangle{
+		X:      int(math.Round(r.X)),
+		Y:      int(math.Round(r.Y)),
+		Width:  int(math.Round(r.Width)),
+		Height: int(math.Round(r.Height)),
+	}
+}
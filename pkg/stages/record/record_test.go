@@ -303,3 +303,145 @@ func TestStage_Execute_PerformanceTimingError(t *testing.T) {
 		t.Errorf("expected DOMContentLoadedMs 0 on error, got %d", result.Timing.DOMContentLoadedMs)
 	}
 }
+
+func TestStage_Execute_LayoutShifts(t *testing.T) {
+	mockBrowser := &mocks.Browser{
+		StartScreencastFunc: func(quality, maxWidth, maxHeight, postLoadDelayMs int) (<-chan ports.ScreenFrame, error) {
+			ch := make(chan ports.ScreenFrame)
+			go func() {
+				defer close(ch)
+				ch <- ports.ScreenFrame{TimestampMs: 0, Data: []byte{0xFF}}
+			}()
+			return ch, nil
+		},
+		GetLayoutShiftsFunc: func() ([]ports.LayoutShift, error) {
+			return []ports.LayoutShift{
+				{
+					StartTime: 420,
+					Value:     0.08,
+					Sources: []ports.LayoutShiftSource{
+						{
+							PreviousRect: ports.Rect{X: 0, Y: 100.4, Width: 320, Height: 49.6},
+							CurrentRect:  ports.Rect{X: 0, Y: 180, Width: 320, Height: 50},
+						},
+					},
+				},
+			}, nil
+		},
+	}
+
+	stage := New(mockBrowser, mocks.NewDebugSink(false), logger.NewNoop(), ports.BrowserOptions{Headless: true})
+
+	input := pipeline.DefaultRecordInput()
+	input.URL = "https://example.com"
+	input.ViewportWidth = 600
+	input.TimeoutMs = 1000
+
+	result, err := stage.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.ViewportWidth != 600 {
+		t.Errorf("expected ViewportWidth 600, got %d", result.ViewportWidth)
+	}
+	if len(result.LayoutShifts) != 1 || !result.LayoutShiftsRecorded {
+		t.Fatalf("expected 1 recorded layout shift, got %d (recorded %v)", len(result.LayoutShifts), result.LayoutShiftsRecorded)
+	}
+
+	shift := result.LayoutShifts[0]
+	if shift.TimestampMs != 420 || shift.Score != 0.08 {
+		t.Errorf("unexpected shift: %+v", shift)
+	}
+	wantPrev := pipeline.Rectangle{X: 0, Y: 100, Width: 320, Height: 50}
+	if len(shift.PreviousRects) != 1 || shift.PreviousRects[0] != wantPrev {
+		t.Errorf("expected previous rect %v, got %v", wantPrev, shift.PreviousRects)
+	}
+	wantCurr := pipeline.Rectangle{X: 0, Y: 180, Width: 320, Height: 50}
+	if len(shift.CurrentRects) != 1 || shift.CurrentRects[0] != wantCurr {
+		t.Errorf("expected current rect %v, got %v", wantCurr, shift.CurrentRects)
+	}
+}
+
+func TestStage_Execute_LCPCandidates(t *testing.T) {
+	mockBrowser := &mocks.Browser{
+		StartScreencastFunc: func(quality, maxWidth, maxHeight, postLoadDelayMs int) (<-chan ports.ScreenFrame, error) {
+			ch := make(chan ports.ScreenFrame)
+			go func() {
+				defer close(ch)
+				ch <- ports.ScreenFrame{TimestampMs: 0, Data: []byte{0xFF}}
+			}()
+			return ch, nil
+		},
+		GetLCPCandidatesFunc: func() ([]ports.LCPCandidate, error) {
+			return []ports.LCPCandidate{
+				{StartTime: 350, Size: 5000, Element: "h1", Rect: ports.Rect{X: 10, Y: 20, Width: 300, Height: 40}},
+				{StartTime: 1200, Size: 90000, Element: "img", URL: "https://example.com/hero.jpg", Rect: ports.Rect{X: 0, Y: 80, Width: 500, Height: 180}},
+			}, nil
+		},
+	}
+
+	stage := New(mockBrowser, mocks.NewDebugSink(false), logger.NewNoop(), ports.BrowserOptions{Headless: true})
+
+	input := pipeline.DefaultRecordInput()
+	input.URL = "https://example.com"
+	input.TimeoutMs = 1000
+
+	result, err := stage.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.LCPCandidates) != 2 {
+		t.Fatalf("expected 2 LCP candidates, got %d", len(result.LCPCandidates))
+	}
+	last := result.LCPCandidates[1]
+	if last.Element != "img" || last.Rect != (pipeline.Rectangle{X: 0, Y: 80, Width: 500, Height: 180}) {
+		t.Errorf("unexpected final candidate: %+v", last)
+	}
+	if result.Timing.LargestContentfulPaintMs != 1200 {
+		t.Errorf("expected LargestContentfulPaintMs 1200, got %d", result.Timing.LargestContentfulPaintMs)
+	}
+}
+
+func TestStage_Execute_Requests(t *testing.T) {
+	mockBrowser := &mocks.Browser{
+		StartScreencastFunc: func(quality, maxWidth, maxHeight, postLoadDelayMs int) (<-chan ports.ScreenFrame, error) {
+			ch := make(chan ports.ScreenFrame)
+			go func() {
+				defer close(ch)
+				ch <- ports.ScreenFrame{TimestampMs: 0, Data: []byte{0xFF}}
+			}()
+			return ch, nil
+		},
+		GetResourceTimingsFunc: func() ([]ports.ResourceTiming, error) {
+			return []ports.ResourceTiming{
+				{URL: "https://example.com/", InitiatorType: "navigation", StartTime: 0, Duration: 400, TransferSize: 12000},
+				{URL: "https://example.com/hero.jpg", InitiatorType: "img", StartTime: 520, Duration: 300, TransferSize: 90000},
+				{URL: "https://example.com/app.js", InitiatorType: "script", StartTime: 410, Duration: 150},
+			}, nil
+		},
+	}
+
+	stage := New(mockBrowser, mocks.NewDebugSink(false), logger.NewNoop(), ports.BrowserOptions{Headless: true})
+
+	input := pipeline.DefaultRecordInput()
+	input.URL = "https://example.com"
+	input.TimeoutMs = 1000
+
+	result, err := stage.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Requests) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(result.Requests))
+	}
+	if result.Requests[0].Type != "navigation" || result.Requests[1].URL != "https://example.com/app.js" {
+		t.Errorf("requests not in start order: %+v", result.Requests)
+	}
+	want := pipeline.Request{URL: "https://example.com/hero.jpg", Type: "img", StartMs: 520, DurationMs: 300, TransferSize: 90000}
+	if result.Requests[2] != want {
+		t.Errorf("expected %+v, got %+v", want, result.Requests[2])
+	}
+}
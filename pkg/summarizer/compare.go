@@ -0,0 +1,249 @@
+package summarizer
+
+import "math"
+
+// Thresholds decide which differences between two runs are significant.
+// A change must reach both the percentage and the absolute threshold for
+// its unit; counts only use the percentage.
+type Thresholds struct {
+	Percent float64 // Minimum relative change in percent
+	TimeMs  int     // Minimum change of timings in ms
+	Bytes   int64   // Minimum change of sizes in bytes
+	CLS     float64 // Minimum change of layout shift scores
+}
+
+// DefaultThresholds returns thresholds that ignore typical run-to-run noise.
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		Percent: 10,
+		TimeMs:  100,
+		Bytes:   10 * 1024,
+		CLS:     0.01,
+	}
+}
+
+// Exceeds reports whether the change from a to b is significant. The
+// percentage is not checked when a is 0, so any change of a count from 0
+// is significant.
+func (th Thresholds) Exceeds(unit Unit, a, b float64) bool {
+	diff := b - a
+	if diff == 0 {
+		return false
+	}
+	if a != 0 && math.Abs(diff/a*100) < th.Percent {
+		return false
+	}
+	switch unit {
+	case UnitMs:
+		return math.Abs(diff) >= float64(th.TimeMs)
+	case UnitBytes:
+		return math.Abs(diff) >= float64(th.Bytes)
+	case UnitScore:
+		return math.Abs(diff) >= th.CLS
+	}
+	return true
+}
+
+// Unit is the unit of a compared metric.
+type Unit string
+
+const (
+	UnitMs    Unit = "ms"
+	UnitBytes Unit = "bytes"
+	UnitScore Unit = "score" // Layout shift scores
+	UnitCount Unit = ""
+)
+
+// Change classifies a delta.
+type Change string
+
+const (
+	ChangeNone        Change = "unchanged"   // Within the thresholds
+	ChangeRegression  Change = "regression"  // Significantly worse
+	ChangeImprovement Change = "improvement" // Significantly better
+	ChangeChanged     Change = "changed"     // Significant, but neither better nor worse
+)
+
+// Delta is the difference of one metric between two runs.
+type Delta struct {
+	Group  string // "Timing", "Traffic" or "Video"
+	Key    string // Stable identifier, e.g. "timing.loadCompleteMs" or "timing.marks.hero"
+	Label  string // Display name
+	Unit   Unit
+	A, B   float64
+	HasA   bool // False if the metric was not recorded in A (e.g. Load after a timeout)
+	HasB   bool
+	Change Change
+
+	// Whether an increase is a regression. False for settings-like
+	// video fields where a change is only reported.
+	higherIsWorse bool
+}
+
+// Diff returns B - A. It is only meaningful if both values were recorded.
+func (d Delta) Diff() float64 {
+	return d.B - d.A
+}
+
+// Percent returns the change relative to A in percent. ok is false if
+// either value is missing or A is 0.
+func (d Delta) Percent() (pct float64, ok bool) {
+	if !d.HasA || !d.HasB || d.A == 0 {
+		return 0, false
+	}
+	return d.Diff() / d.A * 100, true
+}
+
+// Significant reports whether the change exceeds the thresholds.
+func (d Delta) Significant() bool {
+	return d.Change != ChangeNone
+}
+
+// Comparison is the result of comparing run A (baseline) with run B.
+type Comparison struct {
+	A, B       *Summary
+	Thresholds Thresholds
+	Deltas     []Delta // Timing, then traffic, then video
+}
+
+// SignificantChanges returns the number of deltas exceeding the thresholds.
+func (c *Comparison) SignificantChanges() int {
+	n := 0
+	for _, d := range c.Deltas {
+		if d.Significant() {
+			n++
+		}
+	}
+	return n
+}
+
+// Regressions returns the number of significant regressions.
+func (c *Comparison) Regressions() int {
+	n := 0
+	for _, d := range c.Deltas {
+		if d.Change == ChangeRegression {
+			n++
+		}
+	}
+	return n
+}
+
+// Compare computes the deltas of every timing, traffic and video field
+// from a to b. Marks are matched by name; a mark present in only one run
+// is reported as not recorded in the other.
+func Compare(a, b *Summary, thresholds Thresholds) *Comparison {
+	c := &Comparison{A: a, B: b, Thresholds: thresholds}
+
+	add := func(group, key, label string, unit Unit, higherIsWorse bool, va float64, hasA bool, vb float64, hasB bool) {
+		d := Delta{
+			Group:         group,
+			Key:           key,
+			Label:         label,
+			Unit:          unit,
+			A:             va,
+			B:             vb,
+			HasA:          hasA,
+			HasB:          hasB,
+			higherIsWorse: higherIsWorse,
+		}
+		d.Change = classify(d, thresholds)
+		c.Deltas = append(c.Deltas, d)
+	}
+
+	// Timing
+	dclA, dclB := a.Timing.DOMContentLoadedRecorded(), b.Timing.DOMContentLoadedRecorded()
+	add("Timing", "timing.domContentLoadedMs", "DOM Content Loaded", UnitMs, true,
+		float64(a.Timing.DOMContentLoadedMs), dclA, float64(b.Timing.DOMContentLoadedMs), dclB)
+	add("Timing", "timing.loadCompleteMs", "Load Complete", UnitMs, true,
+		float64(a.Timing.LoadCompleteMs), a.Timing.LoadRecorded(), float64(b.Timing.LoadCompleteMs), b.Timing.LoadRecorded())
+	add("Timing", "timing.largestContentfulPaintMs", "Largest Contentful Paint", UnitMs, true,
+		float64(a.Timing.LargestContentfulPaintMs), a.Timing.LargestContentfulPaintMs != 0,
+		float64(b.Timing.LargestContentfulPaintMs), b.Timing.LargestContentfulPaintMs != 0)
+	add("Timing", "timing.totalDurationMs", "Total Duration", UnitMs, true,
+		float64(a.Timing.TotalDurationMs), true, float64(b.Timing.TotalDurationMs), true)
+	for _, name := range markNames(a.Timing.Marks, b.Timing.Marks) {
+		ma, mb := findMark(a.Timing.Marks, name), findMark(b.Timing.Marks, name)
+		label := ma.Label
+		if label == "" {
+			label = mb.Label
+		}
+		if label == "" {
+			label = name
+		}
+		add("Timing", "timing.marks."+name, label, UnitMs, true,
+			float64(ma.TimeMs), ma.Recorded, float64(mb.TimeMs), mb.Recorded)
+	}
+
+	// Traffic
+	add("Traffic", "traffic.totalBytes", "Total Traffic", UnitBytes, true,
+		float64(a.Traffic.TotalBytes), true, float64(b.Traffic.TotalBytes), true)
+	add("Traffic", "traffic.requests", "Requests", UnitCount, true,
+		float64(a.Traffic.Requests), a.Traffic.Requests > 0, float64(b.Traffic.Requests), b.Traffic.Requests > 0)
+
+	// Video
+	video := func(key, label string, unit Unit, higherIsWorse bool, va, vb float64) {
+		add("Video", "video."+key, label, unit, higherIsWorse, va, true, vb, true)
+	}
+	video("frameCount", "Frame Count", UnitCount, false, float64(a.Video.FrameCount), float64(b.Video.FrameCount))
+	video("durationMs", "Video Duration", UnitMs, true, float64(a.Video.DurationMs), float64(b.Video.DurationMs))
+	video("fileSize", "Video File Size", UnitBytes, true, float64(a.Video.FileSize), float64(b.Video.FileSize))
+	video("canvasWidth", "Canvas Width", UnitCount, false, float64(a.Video.CanvasWidth), float64(b.Video.CanvasWidth))
+	video("canvasHeight", "Canvas Height", UnitCount, false, float64(a.Video.CanvasHeight), float64(b.Video.CanvasHeight))
+	video("crf", "CRF", UnitCount, false, float64(a.Video.CRF), float64(b.Video.CRF))
+	video("outroDurationMs", "Outro Duration", UnitMs, false, float64(a.Video.OutroDuration), float64(b.Video.OutroDuration))
+	video("maxSize", "Max Size", UnitBytes, false, float64(a.Video.MaxSize), float64(b.Video.MaxSize))
+	video("encodePasses", "Encode Passes", UnitCount, true, float64(a.Video.EncodePasses), float64(b.Video.EncodePasses))
+
+	return c
+}
+
+// classify applies the thresholds to a delta.
+func classify(d Delta, th Thresholds) Change {
+	worse := func(increased bool) Change {
+		if !d.higherIsWorse {
+			return ChangeChanged
+		}
+		if increased {
+			return ChangeRegression
+		}
+		return ChangeImprovement
+	}
+
+	switch {
+	case !d.HasA && !d.HasB:
+		return ChangeNone
+	case !d.HasA || !d.HasB:
+		// A metric that stopped being recorded (e.g. Load timing out) is
+		// treated like an increase
+		return worse(!d.HasB)
+	}
+
+	if !th.Exceeds(d.Unit, d.A, d.B) {
+		return ChangeNone
+	}
+	return worse(d.Diff() > 0)
+}
+
+// markNames returns the mark names of a followed by those only in b.
+func markNames(a, b []MarkTiming) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, marks := range [][]MarkTiming{a, b} {
+		for _, m := range marks {
+			if !seen[m.Name] {
+				seen[m.Name] = true
+				names = append(names, m.Name)
+			}
+		}
+	}
+	return names
+}
+
+func findMark(marks []MarkTiming, name string) MarkTiming {
+	for _, m := range marks {
+		if m.Name == name {
+			return m
+		}
+	}
+	return MarkTiming{Name: name}
+}
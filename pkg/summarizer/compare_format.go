@@ -0,0 +1,219 @@
+package summarizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// ComparisonMarkdown formats a comparison as Markdown tables, one per
+// group, suitable for pull request comments. t may be nil.
+func ComparisonMarkdown(c *Comparison, t TranslateFunc) string {
+	if t == nil {
+		t = func(key string) string { return key }
+	}
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("## %s\n\n", t("Run Comparison")))
+	for _, run := range []struct {
+		name    string
+		summary *Summary
+	}{{"A", c.A}, {"B", c.B}} {
+		sb.WriteString(fmt.Sprintf("- **%s** %s", run.name, runTitle(run.summary)))
+		if !run.summary.GeneratedAt.IsZero() {
+			sb.WriteString(fmt.Sprintf(" (%s)", run.summary.GeneratedAt.Format("2006-01-02 15:04:05")))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	group := ""
+	for i, d := range c.Deltas {
+		if d.Group != group {
+			group = d.Group
+			sb.WriteString(fmt.Sprintf("### %s\n\n", t(group)))
+			sb.WriteString(fmt.Sprintf("| %s | A | B | %s | %% | |\n", t("Metric"), t("Change")))
+			sb.WriteString("|---|---:|---:|---:|---:|---|\n")
+		}
+
+		label := t(d.Label)
+		if strings.HasPrefix(d.Key, "timing.marks.") {
+			name := strings.TrimPrefix(d.Key, "timing.marks.")
+			label = d.Label
+			if label != name {
+				label = fmt.Sprintf("%s (%s)", label, name)
+			}
+		}
+
+		diff, pct := "—", "—"
+		if d.HasA && d.HasB {
+			diff = formatDelta(d.Unit, d.Diff())
+			if p, ok := d.Percent(); ok && p == 0 {
+				pct = "0.0%"
+			} else if ok {
+				pct = fmt.Sprintf("%+.1f%%", p)
+			}
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s |\n",
+			label, formatValue(d.Unit, d.A, d.HasA), formatValue(d.Unit, d.B, d.HasB), diff, pct, changeMarker(d.Change, t)))
+
+		if i+1 == len(c.Deltas) || c.Deltas[i+1].Group != group {
+			sb.WriteString("\n")
+		}
+	}
+
+	th := c.Thresholds
+	sb.WriteString(fmt.Sprintf("%s: %d (%s: %g%%, %d ms, %s)\n",
+		t("Significant changes"), c.SignificantChanges(), t("thresholds"), th.Percent, th.TimeMs, formatBytes(th.Bytes)))
+
+	return sb.String()
+}
+
+// ComparisonJSON formats a comparison as a JSON document. Values that were
+// not recorded, and changes that cannot be computed, are null.
+func ComparisonJSON(c *Comparison) string {
+	doc := jsonComparison{
+		SchemaVersion: JSONSchemaVersion,
+		A:             jsonRun(c.A),
+		B:             jsonRun(c.B),
+		Thresholds: jsonThresholds{
+			Percent: c.Thresholds.Percent,
+			TimeMs:  c.Thresholds.TimeMs,
+			Bytes:   c.Thresholds.Bytes,
+		},
+		SignificantChanges: c.SignificantChanges(),
+		Regressions:        c.Regressions(),
+		Deltas:             []jsonDelta{},
+	}
+	for _, d := range c.Deltas {
+		jd := jsonDelta{
+			Group:       strings.ToLower(d.Group),
+			Key:         d.Key,
+			Label:       d.Label,
+			Unit:        string(d.Unit),
+			Significant: d.Significant(),
+			Change:      string(d.Change),
+		}
+		if d.HasA {
+			jd.A = floatPtr(d.A)
+		}
+		if d.HasB {
+			jd.B = floatPtr(d.B)
+		}
+		if d.HasA && d.HasB {
+			jd.Diff = floatPtr(d.Diff())
+			if p, ok := d.Percent(); ok {
+				jd.Percent = floatPtr(math.Round(p*10) / 10)
+			}
+		}
+		doc.Deltas = append(doc.Deltas, jd)
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "{}\n"
+	}
+	return string(data) + "\n"
+}
+
+type jsonComparison struct {
+	SchemaVersion      int            `json:"schemaVersion"`
+	A                  jsonRunInfo    `json:"a"`
+	B                  jsonRunInfo    `json:"b"`
+	Thresholds         jsonThresholds `json:"thresholds"`
+	SignificantChanges int            `json:"significantChanges"`
+	Regressions        int            `json:"regressions"`
+	Deltas             []jsonDelta    `json:"deltas"`
+}
+
+type jsonRunInfo struct {
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	GeneratedAt time.Time `json:"generatedAt"`
+}
+
+type jsonThresholds struct {
+	Percent float64 `json:"percent"`
+	TimeMs  int     `json:"timeMs"`
+	Bytes   int64   `json:"bytes"`
+}
+
+type jsonDelta struct {
+	Group       string   `json:"group"`
+	Key         string   `json:"key"`
+	Label       string   `json:"label"`
+	Unit        string   `json:"unit"`
+	A           *float64 `json:"a"`
+	B           *float64 `json:"b"`
+	Diff        *float64 `json:"diff"`
+	Percent     *float64 `json:"percent"`
+	Significant bool     `json:"significant"`
+	Change      string   `json:"change"`
+}
+
+func jsonRun(s *Summary) jsonRunInfo {
+	return jsonRunInfo{Title: s.Page.Title, URL: s.Page.URL, GeneratedAt: s.GeneratedAt}
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+// runTitle returns the page title and URL of a run for the header.
+func runTitle(s *Summary) string {
+	switch {
+	case s.Page.Title == "":
+		return s.Page.URL
+	case s.Page.URL == "":
+		return s.Page.Title
+	default:
+		return fmt.Sprintf("[%s](%s)", s.Page.Title, s.Page.URL)
+	}
+}
+
+func formatValue(unit Unit, v float64, recorded bool) string {
+	if !recorded {
+		return "N/A"
+	}
+	switch unit {
+	case UnitMs:
+		return fmt.Sprintf("%.0f ms", v)
+	case UnitBytes:
+		return formatBytes(int64(v))
+	default:
+		return fmt.Sprintf("%g", v)
+	}
+}
+
+func formatDelta(unit Unit, diff float64) string {
+	sign := "+"
+	switch {
+	case diff == 0:
+		sign = ""
+	case diff < 0:
+		sign = "-"
+	}
+	switch unit {
+	case UnitMs:
+		return fmt.Sprintf("%s%.0f ms", sign, math.Abs(diff))
+	case UnitBytes:
+		return sign + formatBytes(int64(math.Abs(diff)))
+	default:
+		return fmt.Sprintf("%s%g", sign, math.Abs(diff))
+	}
+}
+
+func changeMarker(c Change, t TranslateFunc) string {
+	switch c {
+	case ChangeRegression:
+		return "🔴 " + t("Regression")
+	case ChangeImprovement:
+		return "🟢 " + t("Improvement")
+	case ChangeChanged:
+		return "⚠️ " + t("Changed")
+	default:
+		return ""
+	}
+}
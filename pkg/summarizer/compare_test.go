@@ -0,0 +1,202 @@
+package summarizer
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func compareTestSummaries() (*Summary, *Summary) {
+	a := NewBuilder().
+		WithPage("Example", "https://example.com/").
+		WithTiming(800, 1500, 3000).
+		WithLargestContentfulPaint(1200).
+		WithMarks([]MarkTiming{
+			{Name: "hero", Label: "Hero", TimeMs: 900, Recorded: true},
+			{Name: "ads", Label: "Ads", TimeMs: 2000, Recorded: true},
+		}).
+		WithTraffic(100 * 1024).
+		WithRequestCount(10).
+		WithVideo(VideoInfo{FrameCount: 30, DurationMs: 5000, FileSize: 400000, CRF: 30}).
+		Build()
+	b := NewBuilder().
+		WithPage("Example", "https://example.com/").
+		WithTiming(850, 2000, 3500).
+		WithMarks([]MarkTiming{
+			{Name: "hero", Label: "Hero", TimeMs: 600, Recorded: true},
+			{Name: "cta", Label: "CTA", TimeMs: 700, Recorded: true},
+		}).
+		WithTraffic(105 * 1024).
+		WithRequestCount(12).
+		WithVideo(VideoInfo{FrameCount: 36, DurationMs: 5000, FileSize: 400000, CRF: 30}).
+		Build()
+	return a, b
+}
+
+func TestCompare(t *testing.T) {
+	a, b := compareTestSummaries()
+	c := Compare(a, b, DefaultThresholds())
+
+	deltas := make(map[string]Delta)
+	for _, d := range c.Deltas {
+		deltas[d.Key] = d
+	}
+
+	tests := []struct {
+		key  string
+		want Change
+	}{
+		{"timing.domContentLoadedMs", ChangeNone},             // +50 ms is below 100 ms
+		{"timing.loadCompleteMs", ChangeRegression},           // +500 ms, +33%
+		{"timing.largestContentfulPaintMs", ChangeRegression}, // no longer recorded in B
+		{"timing.totalDurationMs", ChangeRegression},          // +500 ms, +17%
+		{"timing.marks.hero", ChangeImprovement},              // -300 ms
+		{"timing.marks.ads", ChangeRegression},
+		{"timing.marks.cta", ChangeImprovement},
+		{"traffic.totalBytes", ChangeNone},     // +5 KB is below 10 KB
+		{"traffic.requests", ChangeRegression}, // +20%
+		{"video.frameCount", ChangeChanged},
+		{"video.fileSize", ChangeNone},
+	}
+	for _, tt := range tests {
+		d, ok := deltas[tt.key]
+		if !ok {
+			t.Errorf("%s: missing", tt.key)
+			continue
+		}
+		if d.Change != tt.want {
+			t.Errorf("%s: change = %s, want %s (%+v)", tt.key, d.Change, tt.want, d)
+		}
+	}
+
+	load := deltas["timing.loadCompleteMs"]
+	if pct, ok := load.Percent(); load.Diff() != 500 || !ok || pct < 33.3 || pct > 33.4 {
+		t.Errorf("load delta = %v (%v%%)", load.Diff(), pct)
+	}
+	if _, ok := deltas["timing.largestContentfulPaintMs"].Percent(); ok {
+		t.Error("expected no percentage when B did not record LCP")
+	}
+	if c.Regressions() != 5 || c.SignificantChanges() != 8 {
+		t.Errorf("regressions = %d, significant = %d", c.Regressions(), c.SignificantChanges())
+	}
+}
+
+func TestCompare_Thresholds(t *testing.T) {
+	a := &Summary{Timing: TimingInfo{LoadCompleteMs: 1000}}
+	b := &Summary{Timing: TimingInfo{LoadCompleteMs: 1080}}
+
+	tests := []struct {
+		name       string
+		thresholds Thresholds
+		want       Change
+	}{
+		{"default", DefaultThresholds(), ChangeNone},
+		{"percent only", Thresholds{Percent: 5}, ChangeRegression},
+		{"absolute only", Thresholds{TimeMs: 50}, ChangeRegression},
+		{"both", Thresholds{Percent: 5, TimeMs: 50}, ChangeRegression},
+		{"percent too small", Thresholds{Percent: 10, TimeMs: 50}, ChangeNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Compare(a, b, tt.thresholds)
+			if got := c.Deltas[1].Change; c.Deltas[1].Key != "timing.loadCompleteMs" || got != tt.want {
+				t.Errorf("%s = %s, want %s", c.Deltas[1].Key, got, tt.want)
+			}
+		})
+	}
+
+	// A timeout in B is a regression of Load
+	c := Compare(a, &Summary{Timing: TimingInfo{TimedOut: true, TimeoutSec: 1}}, DefaultThresholds())
+	if c.Deltas[1].Change != ChangeRegression {
+		t.Errorf("timeout change = %s, want regression", c.Deltas[1].Change)
+	}
+}
+
+func TestComparisonMarkdown(t *testing.T) {
+	a, b := compareTestSummaries()
+	output := ComparisonMarkdown(Compare(a, b, DefaultThresholds()), nil)
+
+	expected := []string{
+		"## Run Comparison",
+		"- **A** [Example](https://example.com/)",
+		"### Timing\n\n| Metric | A | B | Change | % | |",
+		"| Load Complete | 1500 ms | 2000 ms | +500 ms | +33.3% | 🔴 Regression |",
+		"| Largest Contentful Paint | 1200 ms | N/A | — | — | 🔴 Regression |",
+		"| CTA (cta) | N/A | 700 ms | — | — | 🟢 Improvement |",
+		"| Hero (hero) | 900 ms | 600 ms | -300 ms | -33.3% | 🟢 Improvement |",
+		"| DOM Content Loaded | 800 ms | 850 ms | +50 ms | +6.2% |  |",
+		"### Traffic",
+		"| Total Traffic | 100.00 KB | 105.00 KB | +5.00 KB | +5.0% |  |",
+		"| CRF | 30 | 30 | 0 | 0.0% |  |",
+		"| Frame Count | 30 | 36 | +6 | +20.0% | ⚠️ Changed |",
+		"Significant changes: 8 (thresholds: 10%, 100 ms, 10.00 KB)",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("expected output to contain %q\n%s", exp, output)
+		}
+	}
+}
+
+func TestComparisonJSON(t *testing.T) {
+	a, b := compareTestSummaries()
+	output := ComparisonJSON(Compare(a, b, DefaultThresholds()))
+
+	var doc struct {
+		SchemaVersion      int `json:"schemaVersion"`
+		SignificantChanges int `json:"significantChanges"`
+		Regressions        int `json:"regressions"`
+		Deltas             []struct {
+			Key     string   `json:"key"`
+			Group   string   `json:"group"`
+			B       *float64 `json:"b"`
+			Diff    *float64 `json:"diff"`
+			Percent *float64 `json:"percent"`
+			Change  string   `json:"change"`
+		} `json:"deltas"`
+	}
+	if err := json.Unmarshal([]byte(output), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, output)
+	}
+	if doc.SchemaVersion != JSONSchemaVersion || doc.SignificantChanges != 8 || doc.Regressions != 5 {
+		t.Errorf("unexpected totals: %+v", doc)
+	}
+	for _, d := range doc.Deltas {
+		switch d.Key {
+		case "timing.loadCompleteMs":
+			if d.Group != "timing" || *d.Diff != 500 || *d.Percent != 33.3 || d.Change != "regression" {
+				t.Errorf("load = %+v", d)
+			}
+		case "timing.largestContentfulPaintMs":
+			if d.B != nil || d.Diff != nil || d.Percent != nil {
+				t.Errorf("expected null LCP values for B: %+v", d)
+			}
+		}
+	}
+}
+
+func TestThresholds_Exceeds(t *testing.T) {
+	th := DefaultThresholds()
+	tests := []struct {
+		unit Unit
+		a, b float64
+		want bool
+	}{
+		{UnitMs, 1000, 1050, false}, // below 10%
+		{UnitMs, 500, 590, false},   // 18%, but below 100 ms
+		{UnitMs, 1000, 1200, true},
+		{UnitMs, 0, 100, true},
+		{UnitBytes, 100 * 1024, 120 * 1024, true},
+		{UnitScore, 0, 0.0001, false}, // no percentage from 0, below 0.01
+		{UnitScore, 0, 0.05, true},
+		{UnitScore, 0.2, 0.21, false}, // 5%
+		{UnitCount, 40, 41, false},
+		{UnitCount, 0, 1, true},
+		{UnitCount, 40, 30, true},
+	}
+	for _, tt := range tests {
+		if got := th.Exceeds(tt.unit, tt.a, tt.b); got != tt.want {
+			t.Errorf("Exceeds(%q, %v, %v) = %v, want %v", tt.unit, tt.a, tt.b, got, tt.want)
+		}
+	}
+}
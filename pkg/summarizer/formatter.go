@@ -1,6 +1,12 @@
 // Package summarizer provides summary generation for recording results.
 package summarizer
 
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
 // Formatter defines the interface for formatting a Summary.
 type Formatter interface {
 	// Format converts a Summary to a formatted string.
@@ -14,3 +20,42 @@ type FormatFunc func(summary *Summary) string
 func (f FormatFunc) Format(summary *Summary) string {
 	return f(summary)
 }
+
+// Format selects the file format of a summary.
+type Format string
+
+const (
+	// FormatMarkdown is a human-readable Markdown report.
+	FormatMarkdown Format = "markdown"
+	// FormatJSON is a machine-readable JSON document (see JSONFormatter).
+	FormatJSON Format = "json"
+	// FormatHTML is a single-page report with charts (see HTMLFormatter).
+	FormatHTML Format = "html"
+)
+
+// FormatForPath returns the summary format for a file path: JSON for a
+// .json extension, HTML for .html or .htm, Markdown otherwise.
+func FormatForPath(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON
+	case ".html", ".htm":
+		return FormatHTML
+	default:
+		return FormatMarkdown
+	}
+}
+
+// ParseFormat parses a summary format name.
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(s)) {
+	case FormatMarkdown, "md":
+		return FormatMarkdown, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	case FormatHTML:
+		return FormatHTML, nil
+	default:
+		return "", fmt.Errorf("unknown summary format: %s (supported: markdown, json, html)", s)
+	}
+}
@@ -0,0 +1,417 @@
+package summarizer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"path/filepath"
+	"strings"
+)
+
+// HTMLFormatter formats a Summary as a single HTML page with the video,
+// filmstrip, a timing chart, a request waterfall and the recording
+// settings. Styles and charts are inline, so with WithEmbeddedMedia the
+// page needs no other files.
+type HTMLFormatter struct {
+	translate TranslateFunc
+	version   string
+	readFile  func(path string) ([]byte, error) // nil = link media files
+}
+
+// HTMLOption is a functional option for HTMLFormatter.
+type HTMLOption func(*HTMLFormatter)
+
+// WithHTMLTranslator sets a custom translator function.
+func WithHTMLTranslator(t TranslateFunc) HTMLOption {
+	return func(f *HTMLFormatter) {
+		f.translate = t
+	}
+}
+
+// WithHTMLVersion sets the version string for the footer.
+func WithHTMLVersion(v string) HTMLOption {
+	return func(f *HTMLFormatter) {
+		f.version = v
+	}
+}
+
+// WithEmbeddedMedia inlines the video, filmstrip and poster as data URIs.
+// readFile is called with the paths in the Summary; files it cannot read
+// are linked instead.
+func WithEmbeddedMedia(readFile func(path string) ([]byte, error)) HTMLOption {
+	return func(f *HTMLFormatter) {
+		f.readFile = readFile
+	}
+}
+
+// NewHTMLFormatter creates a new HTMLFormatter.
+func NewHTMLFormatter(opts ...HTMLOption) *HTMLFormatter {
+	f := &HTMLFormatter{
+		translate: func(key string) string { return key }, // default: no translation
+		version:   "dev",
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Metric colors in the timing chart
+const (
+	colorDCL  = "#1a73e8"
+	colorLoad = "#d93025"
+	colorLCP  = "#188038"
+	colorMark = "#9334e6"
+)
+
+// Timing chart dimensions in SVG user units (see the viewBox in htmlTemplate)
+const (
+	chartWidth = 1000
+	chartTop   = 10  // Space above the progress curve
+	chartPlot  = 120 // Height of the progress curve
+)
+
+type htmlView struct {
+	Title       string
+	URL         string
+	GeneratedAt string
+	Version     string
+	Video       *htmlMedia
+	Filmstrip   *htmlMedia
+	Poster      *htmlMedia
+	Metrics     []htmlMetric
+	Chart       htmlChart
+	Requests    []htmlRequest
+	Results     []htmlRow
+	Settings    []htmlRow
+	VideoRows   []htmlRow
+}
+
+type htmlMedia struct {
+	Src     template.URL
+	Path    string
+	IsVideo bool
+}
+
+type htmlRow struct {
+	Label string
+	Value string
+}
+
+// htmlMetric is a point in time shown on the timing chart.
+type htmlMetric struct {
+	Label string
+	Value string // Formatted time, or N/A
+	Color string
+	X     float64 // Position on the chart (-1 = not shown)
+}
+
+type htmlChart struct {
+	Progress string // Polyline points of the loading progress
+	Ticks    []htmlTick
+}
+
+type htmlTick struct {
+	X     float64
+	Label string
+}
+
+// htmlRequest is a row of the request waterfall. Left and Width are
+// percentages of the chart scale.
+type htmlRequest struct {
+	URL      string
+	Type     string
+	Start    string
+	Duration string
+	Size     string
+	Left     float64
+	Width    float64
+}
+
+// Format implements the Formatter interface.
+func (f *HTMLFormatter) Format(summary *Summary) string {
+	tmpl := template.Must(template.New("report").Funcs(template.FuncMap{
+		"t": func(key string) string { return f.translate(key) },
+	}).Parse(htmlTemplate))
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, f.view(summary)); err != nil {
+		// The view contains only strings and numbers, which always render
+		return fmt.Sprintf("<!DOCTYPE html>\n<p>%s</p>\n", template.HTMLEscapeString(err.Error()))
+	}
+	return buf.String()
+}
+
+// view converts a Summary to template data.
+func (f *HTMLFormatter) view(summary *Summary) htmlView {
+	t := f.translate
+	timing := summary.Timing
+
+	title := summary.Page.Title
+	if title == "" {
+		title = summary.Page.URL
+	}
+	v := htmlView{
+		Title:       title,
+		URL:         summary.Page.URL,
+		GeneratedAt: summary.GeneratedAt.Format("2006-01-02 15:04:05"),
+		Version:     f.version,
+		Video:       f.media(summary.Video.Path),
+	}
+	if summary.Filmstrip != nil {
+		v.Filmstrip = f.media(summary.Filmstrip.Path)
+	}
+	if summary.Poster != nil {
+		v.Poster = f.media(summary.Poster.Path)
+	}
+
+	// The chart spans the latest recorded event
+	scaleMs := max(timing.DOMContentLoadedMs, timing.LoadCompleteMs, timing.LargestContentfulPaintMs)
+	for _, m := range timing.Marks {
+		scaleMs = max(scaleMs, m.TimeMs)
+	}
+	if n := len(summary.Frames); n > 0 {
+		scaleMs = max(scaleMs, summary.Frames[n-1].TimestampMs)
+	}
+	for _, r := range summary.Requests {
+		scaleMs = max(scaleMs, r.EndMs())
+	}
+	scaleMs = max(scaleMs, 1)
+	x := func(ms int) float64 {
+		return float64(ms) * chartWidth / float64(scaleMs)
+	}
+
+	metric := func(label string, ms int, available bool, color string) htmlMetric {
+		if !available {
+			return htmlMetric{Label: label, Value: "N/A", Color: color, X: -1}
+		}
+		return htmlMetric{Label: label, Value: fmt.Sprintf("%d ms", ms), Color: color, X: x(ms)}
+	}
+	v.Metrics = append(v.Metrics, metric(t("DOM Content Loaded"), timing.DOMContentLoadedMs,
+		timing.DOMContentLoadedRecorded(), colorDCL))
+	load := metric(t("Load Complete"), timing.LoadCompleteMs, timing.LoadRecorded(), colorLoad)
+	if timing.TimedOut {
+		load.Value = fmt.Sprintf("%s (%ds)", t("Timeout"), timing.TimeoutSec)
+	}
+	v.Metrics = append(v.Metrics, load)
+	if timing.LargestContentfulPaintMs > 0 {
+		v.Metrics = append(v.Metrics, metric(t("Largest Contentful Paint"), timing.LargestContentfulPaintMs, true, colorLCP))
+	}
+	for _, m := range timing.Marks {
+		label := m.Label
+		if label != m.Name {
+			label = fmt.Sprintf("%s (%s)", m.Label, m.Name)
+		}
+		v.Metrics = append(v.Metrics, metric(label, m.TimeMs, m.Recorded, colorMark))
+	}
+
+	// Loading progress as a step curve
+	var points []string
+	y := func(progress float64) float64 {
+		return chartTop + chartPlot*(1-progress)
+	}
+	prev := 0.0
+	for _, fr := range summary.Frames {
+		points = append(points,
+			fmt.Sprintf("%.1f,%.1f", x(fr.TimestampMs), y(prev)),
+			fmt.Sprintf("%.1f,%.1f", x(fr.TimestampMs), y(fr.Progress)))
+		prev = fr.Progress
+	}
+	if len(points) > 0 {
+		points = append(points, fmt.Sprintf("%d,%.1f", chartWidth, y(prev)))
+	}
+	v.Chart.Progress = strings.Join(points, " ")
+	step := tickStep(scaleMs)
+	for ms := 0; ms <= scaleMs; ms += step {
+		v.Chart.Ticks = append(v.Chart.Ticks, htmlTick{X: x(ms), Label: fmt.Sprintf("%g s", float64(ms)/1000)})
+	}
+
+	for _, r := range summary.Requests {
+		v.Requests = append(v.Requests, htmlRequest{
+			URL:      r.URL,
+			Type:     r.Type,
+			Start:    fmt.Sprintf("%d ms", r.StartMs),
+			Duration: fmt.Sprintf("%d ms", r.DurationMs),
+			Size:     formatBytes(r.TransferSize),
+			Left:     x(r.StartMs) * 100 / chartWidth,
+			Width:    max(x(r.DurationMs)*100/chartWidth, 0.2), // Keep short requests visible
+		})
+	}
+
+	v.Results = []htmlRow{
+		{t("Page Title"), summary.Page.Title},
+		{t("URL"), summary.Page.URL},
+		{t("Total Traffic"), fmt.Sprintf("%s (%d bytes)", formatBytes(summary.Traffic.TotalBytes), summary.Traffic.TotalBytes)},
+	}
+
+	s := summary.Settings
+	v.Settings = []htmlRow{
+		{t("Preset"), s.Preset},
+		{t("Quality"), s.Quality},
+		{t("Codec"), s.Codec},
+		{t("Viewport Width"), fmt.Sprintf("%d px", s.ViewportWidth)},
+		{t("Columns"), fmt.Sprintf("%d", s.Columns)},
+		{t("Download Speed"), formatSpeed(s.DownloadSpeed, t)},
+		{t("Upload Speed"), formatSpeed(s.UploadSpeed, t)},
+	}
+	if s.CPUThrottling > 1.0 {
+		v.Settings = append(v.Settings, htmlRow{t("CPU Throttling"), fmt.Sprintf("%.1fx", s.CPUThrottling)})
+	} else {
+		v.Settings = append(v.Settings, htmlRow{t("CPU Throttling"), t("None")})
+	}
+
+	video := summary.Video
+	v.VideoRows = []htmlRow{
+		{t("Frame Count"), fmt.Sprintf("%d", video.FrameCount)},
+		{t("Video Duration"), fmt.Sprintf("%d ms", video.DurationMs)},
+		{t("Video File Size"), fmt.Sprintf("%s (%d bytes)", formatBytes(video.FileSize), video.FileSize)},
+		{t("Canvas Size"), fmt.Sprintf("%dx%d px", video.CanvasWidth, video.CanvasHeight)},
+		{t("CRF"), fmt.Sprintf("%d", video.CRF)},
+		{t("Outro Duration"), fmt.Sprintf("%d ms", video.OutroDuration)},
+	}
+	if video.MaxSize > 0 {
+		v.VideoRows = append(v.VideoRows, htmlRow{t("Max Size"), fmt.Sprintf("%s (%d %s)", formatBytes(video.MaxSize), video.EncodePasses, t("passes"))})
+	}
+
+	return v
+}
+
+// media returns the source of a media file: a data URI when embedding, a
+// link otherwise. It returns nil for an empty path.
+func (f *HTMLFormatter) media(path string) *htmlMedia {
+	if path == "" {
+		return nil
+	}
+	mediaType := mediaTypes[strings.ToLower(filepath.Ext(path))]
+	m := &htmlMedia{
+		Src:     template.URL(path),
+		Path:    path,
+		IsVideo: strings.HasPrefix(mediaType, "video/"),
+	}
+	if f.readFile != nil && mediaType != "" {
+		if data, err := f.readFile(path); err == nil {
+			m.Src = template.URL("data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(data))
+		}
+	}
+	return m
+}
+
+// mediaTypes maps the extensions of files loadshow writes to MIME types.
+var mediaTypes = map[string]string{
+	".mp4":  "video/mp4",
+	".webm": "video/webm",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+	".png":  "image/png",
+	".apng": "image/apng",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+}
+
+// tickStep returns a round axis interval giving at most about ten ticks.
+func tickStep(scaleMs int) int {
+	for _, step := range []int{100, 200, 500, 1000, 2000, 5000, 10000, 20000, 30000, 60000} {
+		if scaleMs/step <= 10 {
+			return step
+		}
+	}
+	return 60000 * (scaleMs/600000 + 1)
+}
+
+// formatSpeed formats a throttled network speed in bytes per second.
+func formatSpeed(bytesPerSec int, t TranslateFunc) string {
+	if bytesPerSec <= 0 {
+		return t("Unlimited")
+	}
+	return fmt.Sprintf("%.1f Mbps", float64(bytesPerSec)*8/1000000)
+}
+
+// htmlTemplate is the report page template.
+const htmlTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="UTF-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{t "Recording Summary"}}: {{.Title}}</title>
+<style>
+  body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #202124; margin: 0 auto; max-width: 1080px; padding: 24px; }
+  h1 { font-size: 24px; margin: 0 0 4px; }
+  h2 { font-size: 18px; margin: 32px 0 12px; border-bottom: 1px solid #dadce0; padding-bottom: 4px; }
+  a { color: #1a73e8; }
+  .meta { color: #5f6368; font-size: 13px; }
+  .media { display: flex; gap: 24px; align-items: flex-start; flex-wrap: wrap; }
+  .media video, .media img { max-width: 100%; max-height: 640px; border: 1px solid #dadce0; }
+  .filmstrip { overflow-x: auto; }
+  .filmstrip img { display: block; max-width: none; border: 1px solid #dadce0; }
+  table { border-collapse: collapse; font-size: 14px; }
+  th, td { text-align: left; padding: 4px 12px 4px 0; vertical-align: top; }
+  th { color: #5f6368; font-weight: normal; }
+  .swatch { display: inline-block; width: 10px; height: 10px; margin-right: 6px; border-radius: 2px; }
+  svg { width: 100%; height: auto; font-size: 12px; }
+  .waterfall { width: 100%; table-layout: fixed; font-size: 12px; }
+  .waterfall .url { width: 40%; overflow: hidden; text-overflow: ellipsis; white-space: nowrap; }
+  .waterfall .num { width: 9%; text-align: right; white-space: nowrap; }
+  .track { position: relative; height: 12px; background: #f1f3f4; }
+  .bar { position: absolute; top: 0; height: 12px; background: #8ab4f8; }
+  .bar.navigation { background: #1a73e8; }
+  footer { margin-top: 40px; color: #5f6368; font-size: 12px; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="meta"><a href="{{.URL}}">{{.URL}}</a> &middot; {{t "Generated"}}: {{.GeneratedAt}}</div>
+
+{{if or .Video .Poster}}
+<h2>{{t "Video"}}</h2>
+<div class="media">
+{{with .Video}}{{if .IsVideo}}<video src="{{.Src}}" controls muted playsinline></video>{{else}}<img src="{{.Src}}" alt="{{.Path}}">{{end}}{{end}}
+{{with .Poster}}<img src="{{.Src}}" alt="{{t "Poster"}}">{{end}}
+</div>
+{{end}}
+
+{{with .Filmstrip}}
+<h2>{{t "Filmstrip"}}</h2>
+<div class="filmstrip"><img src="{{.Src}}" alt="{{t "Filmstrip"}}"></div>
+{{end}}
+
+<h2>{{t "Timing"}}</h2>
+<svg viewBox="0 0 1000 160" role="img">
+  {{range .Chart.Ticks}}<line x1="{{.X}}" y1="10" x2="{{.X}}" y2="130" stroke="#e8eaed"/><text x="{{.X}}" y="150" text-anchor="middle" fill="#5f6368">{{.Label}}</text>
+  {{end}}
+  {{if .Chart.Progress}}<polyline points="{{.Chart.Progress}}" fill="none" stroke="#5f6368" stroke-width="2"/>{{end}}
+  {{range .Metrics}}{{if ge .X 0.0}}<line x1="{{.X}}" y1="10" x2="{{.X}}" y2="130" stroke="{{.Color}}" stroke-width="2"><title>{{.Label}}: {{.Value}}</title></line>{{end}}
+  {{end}}
+</svg>
+<table>
+{{range .Metrics}}<tr><th><span class="swatch" style="background: {{.Color}}"></span>{{.Label}}</th><td>{{.Value}}</td></tr>
+{{end}}<tr><th><span class="swatch" style="background: #5f6368"></span>{{t "Loading Progress"}}</th><td></td></tr>
+</table>
+
+{{if .Requests}}
+<h2>{{t "Requests"}}</h2>
+<table class="waterfall">
+<tr><th class="url">{{t "URL"}}</th><th class="num">{{t "Type"}}</th><th class="num">{{t "Start"}}</th><th class="num">{{t "Duration"}}</th><th class="num">{{t "Size"}}</th><th></th></tr>
+{{range .Requests}}<tr><td class="url" title="{{.URL}}">{{.URL}}</td><td class="num">{{.Type}}</td><td class="num">{{.Start}}</td><td class="num">{{.Duration}}</td><td class="num">{{.Size}}</td><td><div class="track"><div class="bar {{.Type}}" style="left: {{printf "%.2f" .Left}}%; width: {{printf "%.2f" .Width}}%"></div></div></td></tr>
+{{end}}</table>
+{{end}}
+
+<h2>{{t "Results"}}</h2>
+<table>
+{{range .Results}}<tr><th>{{.Label}}</th><td>{{.Value}}</td></tr>
+{{end}}</table>
+
+<h2>{{t "Settings"}}</h2>
+<table>
+{{range .Settings}}<tr><th>{{.Label}}</th><td>{{.Value}}</td></tr>
+{{end}}</table>
+
+<h2>{{t "Video Details"}}</h2>
+<table>
+{{range .VideoRows}}<tr><th>{{.Label}}</th><td>{{.Value}}</td></tr>
+{{end}}</table>
+
+<footer>{{t "Generated by"}} <a href="https://github.com/user/loadshow">loadshow</a> {{.Version}}</footer>
+</body>
+</html>
+`
@@ -0,0 +1,133 @@
+package summarizer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func htmlTestSummary() *Summary {
+	summary := NewBuilder().
+		WithPage("Example <Shop>", "https://example.com/?a=1&b=2").
+		WithTiming(800, 1500, 3000).
+		WithLargestContentfulPaint(1200).
+		WithMarks([]MarkTiming{
+			{Name: "hero", Label: "Hero", TimeMs: 900, Recorded: true},
+			{Name: "ads", Label: "Ads"},
+		}).
+		WithTraffic(2048).
+		WithRequestCount(2).
+		WithSettings(Settings{Preset: "mobile", Codec: "AV1", CPUThrottling: 4}).
+		WithVideo(VideoInfo{Path: "out.mp4", FrameCount: 2, DurationMs: 5000}).
+		WithFilmstrip(FilmstripInfo{Path: "filmstrip.png", FrameCount: 4}).
+		WithFrames([]FrameInfo{
+			{TimestampMs: 0, Progress: 0},
+			{TimestampMs: 1500, Progress: 1},
+		}).
+		WithRequests([]RequestInfo{
+			{URL: "https://example.com/", Type: "navigation", StartMs: 0, DurationMs: 400, TransferSize: 12000},
+			{URL: "https://example.com/app.js", Type: "script", StartMs: 500, DurationMs: 1500},
+		}).
+		Build()
+	return summary
+}
+
+func TestHTMLFormatter_Format(t *testing.T) {
+	output := NewHTMLFormatter(WithHTMLVersion("1.2.3")).Format(htmlTestSummary())
+
+	expected := []string{
+		"<!DOCTYPE html>",
+		"<h1>Example &lt;Shop&gt;</h1>",
+		`href="https://example.com/?a=1&amp;b=2"`,
+		`<video src="out.mp4"`,
+		`<img src="filmstrip.png"`,
+		"<polyline points=",
+		"Largest Contentful Paint</th><td>1200 ms",
+		"Hero (hero)</th><td>900 ms",
+		"Ads (ads)</th><td>N/A",
+		`title="https://example.com/app.js"`,
+		"left: 25.00%; width: 75.00%",
+		"4.0x",
+		"loadshow</a> 1.2.3",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("expected output to contain %q", exp)
+		}
+	}
+	if strings.Contains(output, "ZgotmplZ") {
+		t.Error("template rejected a value as unsafe")
+	}
+}
+
+func TestHTMLFormatter_EmbeddedMedia(t *testing.T) {
+	var read []string
+	readFile := func(path string) ([]byte, error) {
+		read = append(read, path)
+		if path == "filmstrip.png" {
+			return nil, errors.New("not found")
+		}
+		return []byte("video"), nil
+	}
+
+	output := NewHTMLFormatter(WithEmbeddedMedia(readFile)).Format(htmlTestSummary())
+
+	if !strings.Contains(output, `<video src="data:video/mp4;base64,dmlkZW8="`) {
+		t.Error("expected the video to be embedded as a data URI")
+	}
+	if !strings.Contains(output, `<img src="filmstrip.png"`) {
+		t.Error("expected an unreadable filmstrip to be linked")
+	}
+	if len(read) != 2 {
+		t.Errorf("read %v, want the video and filmstrip", read)
+	}
+}
+
+func TestHTMLFormatter_Unavailable(t *testing.T) {
+	summary := &Summary{
+		Page: PageInfo{URL: "https://example.com"},
+		Timing: TimingInfo{
+			DOMContentLoadedMs: 2000,
+			TimedOut:           true,
+			TimeoutSec:         1,
+		},
+	}
+
+	output := NewHTMLFormatter(WithHTMLTranslator(func(key string) string {
+		if key == "Timeout" {
+			return "タイムアウト"
+		}
+		return key
+	})).Format(summary)
+
+	if !strings.Contains(output, "DOM Content Loaded</th><td>N/A") {
+		t.Error("expected DCL after the timeout to be N/A")
+	}
+	if !strings.Contains(output, "Load Complete</th><td>タイムアウト (1s)") {
+		t.Error("expected translated timeout for Load")
+	}
+	for _, section := range []string{"<video", "Filmstrip</h2>", "Requests</h2>", "<polyline"} {
+		if strings.Contains(output, section) {
+			t.Errorf("unexpected %q without data", section)
+		}
+	}
+}
+
+func TestTickStep(t *testing.T) {
+	tests := []struct {
+		scaleMs int
+		want    int
+	}{
+		{1, 100},
+		{1000, 100},
+		{1500, 200},
+		{8000, 1000},
+		{45000, 5000},
+		{1200000, 180000},
+	}
+	for _, tt := range tests {
+		if got := tickStep(tt.scaleMs); got != tt.want {
+			t.Errorf("tickStep(%d) = %d, want %d", tt.scaleMs, got, tt.want)
+		}
+	}
+}
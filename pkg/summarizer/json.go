@@ -0,0 +1,367 @@
+package summarizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// JSONSchemaVersion is the version of the JSON summary schema. It is
+// incremented when a field is removed or changes meaning; new fields may
+// be added without a version change.
+const JSONSchemaVersion = 1
+
+// JSONFormatter formats a Summary as a JSON document for scripts and CI.
+//
+// Times are in milliseconds since navigation start, sizes in bytes and
+// speeds in bytes per second. Measurements that were not recorded (for
+// example Load after a timeout) are null rather than 0.
+type JSONFormatter struct {
+	version string
+}
+
+// NewJSONFormatter creates a JSONFormatter. version is reported as the
+// generator version.
+func NewJSONFormatter(version string) *JSONFormatter {
+	if version == "" {
+		version = "dev"
+	}
+	return &JSONFormatter{version: version}
+}
+
+// jsonSummary is the top-level JSON summary document.
+type jsonSummary struct {
+	SchemaVersion int            `json:"schemaVersion"`
+	Generator     jsonGenerator  `json:"generator"`
+	GeneratedAt   time.Time      `json:"generatedAt"`
+	Page          jsonPage       `json:"page"`
+	Timing        jsonTiming     `json:"timing"`
+	Traffic       jsonTraffic    `json:"traffic"`
+	Settings      jsonSettings   `json:"settings"`
+	Video         jsonVideo      `json:"video"`
+	Poster        *jsonPoster    `json:"poster"`
+	Filmstrip     *jsonFilmstrip `json:"filmstrip"`
+	Frames        []jsonFrame    `json:"frames"`
+	Requests      []jsonRequest  `json:"requests"`
+}
+
+type jsonGenerator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type jsonPage struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+type jsonTiming struct {
+	DOMContentLoadedMs       *int       `json:"domContentLoadedMs"`
+	LoadCompleteMs           *int       `json:"loadCompleteMs"`
+	LargestContentfulPaintMs *int       `json:"largestContentfulPaintMs"`
+	TotalDurationMs          int        `json:"totalDurationMs"`
+	TimedOut                 bool       `json:"timedOut"`
+	TimeoutSec               int        `json:"timeoutSec"`
+	Marks                    []jsonMark `json:"marks"`
+}
+
+type jsonMark struct {
+	Name   string `json:"name"`
+	Label  string `json:"label"`
+	TimeMs *int   `json:"timeMs"`
+}
+
+type jsonTraffic struct {
+	TotalBytes int64 `json:"totalBytes"`
+	Requests   *int  `json:"requests"`
+}
+
+type jsonSettings struct {
+	Preset        string  `json:"preset"`
+	Quality       string  `json:"quality"`
+	Codec         string  `json:"codec"`
+	ViewportWidth int     `json:"viewportWidth"`
+	Columns       int     `json:"columns"`
+	DownloadSpeed int     `json:"downloadSpeed"` // 0 = unlimited
+	UploadSpeed   int     `json:"uploadSpeed"`   // 0 = unlimited
+	CPUThrottling float64 `json:"cpuThrottling"` // 1 = none
+}
+
+type jsonVideo struct {
+	Path            string `json:"path"`
+	FrameCount      int    `json:"frameCount"`
+	DurationMs      int    `json:"durationMs"`
+	FileSize        int64  `json:"fileSize"`
+	CanvasWidth     int    `json:"canvasWidth"`
+	CanvasHeight    int    `json:"canvasHeight"`
+	CRF             int    `json:"crf"`
+	OutroDurationMs int    `json:"outroDurationMs"`
+	MaxSize         int64  `json:"maxSize"` // 0 = no limit
+	EncodePasses    int    `json:"encodePasses"`
+}
+
+type jsonPoster struct {
+	Path        string          `json:"path"`
+	TimestampMs int             `json:"timestampMs"`
+	Width       int             `json:"width"`
+	Height      int             `json:"height"`
+	Thumbnails  []jsonThumbnail `json:"thumbnails"`
+}
+
+type jsonThumbnail struct {
+	Path   string `json:"path"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+type jsonFilmstrip struct {
+	Path       string `json:"path"`
+	FrameCount int    `json:"frameCount"`
+}
+
+type jsonFrame struct {
+	TimestampMs     int     `json:"timestampMs"`
+	Progress        float64 `json:"progress"`
+	LoadedResources int     `json:"loadedResources"`
+	TotalResources  int     `json:"totalResources"`
+	TotalBytes      int64   `json:"totalBytes"`
+}
+
+type jsonRequest struct {
+	URL          string `json:"url"`
+	Type         string `json:"type"`
+	StartMs      int    `json:"startMs"`
+	DurationMs   int    `json:"durationMs"`
+	TransferSize int64  `json:"transferSize"`
+}
+
+// Format implements the Formatter interface.
+func (f *JSONFormatter) Format(summary *Summary) string {
+	data, err := json.MarshalIndent(f.document(summary), "", "  ")
+	if err != nil {
+		// Only non-finite numbers fail to encode, which recordings do not produce
+		return "{}\n"
+	}
+	return string(data) + "\n"
+}
+
+// document converts a Summary to the JSON schema.
+func (f *JSONFormatter) document(summary *Summary) jsonSummary {
+	timing := summary.Timing
+	doc := jsonSummary{
+		SchemaVersion: JSONSchemaVersion,
+		Generator:     jsonGenerator{Name: "loadshow", Version: f.version},
+		GeneratedAt:   summary.GeneratedAt,
+		Page:          jsonPage{Title: summary.Page.Title, URL: summary.Page.URL},
+		Timing: jsonTiming{
+			TotalDurationMs: timing.TotalDurationMs,
+			TimedOut:        timing.TimedOut,
+			TimeoutSec:      timing.TimeoutSec,
+			Marks:           []jsonMark{},
+		},
+		Traffic: jsonTraffic{TotalBytes: summary.Traffic.TotalBytes},
+		Settings: jsonSettings{
+			Preset:        summary.Settings.Preset,
+			Quality:       summary.Settings.Quality,
+			Codec:         summary.Settings.Codec,
+			ViewportWidth: summary.Settings.ViewportWidth,
+			Columns:       summary.Settings.Columns,
+			DownloadSpeed: summary.Settings.DownloadSpeed,
+			UploadSpeed:   summary.Settings.UploadSpeed,
+			CPUThrottling: summary.Settings.CPUThrottling,
+		},
+		Video: jsonVideo{
+			Path:            summary.Video.Path,
+			FrameCount:      summary.Video.FrameCount,
+			DurationMs:      summary.Video.DurationMs,
+			FileSize:        summary.Video.FileSize,
+			CanvasWidth:     summary.Video.CanvasWidth,
+			CanvasHeight:    summary.Video.CanvasHeight,
+			CRF:             summary.Video.CRF,
+			OutroDurationMs: summary.Video.OutroDuration,
+			MaxSize:         summary.Video.MaxSize,
+			EncodePasses:    summary.Video.EncodePasses,
+		},
+		Frames:   []jsonFrame{},
+		Requests: []jsonRequest{},
+	}
+
+	if timing.DOMContentLoadedRecorded() {
+		doc.Timing.DOMContentLoadedMs = intPtr(timing.DOMContentLoadedMs)
+	}
+	if timing.LoadRecorded() {
+		doc.Timing.LoadCompleteMs = intPtr(timing.LoadCompleteMs)
+	}
+	if timing.LargestContentfulPaintMs != 0 {
+		doc.Timing.LargestContentfulPaintMs = intPtr(timing.LargestContentfulPaintMs)
+	}
+	if summary.Traffic.Requests > 0 {
+		doc.Traffic.Requests = intPtr(summary.Traffic.Requests)
+	}
+	for _, m := range timing.Marks {
+		mark := jsonMark{Name: m.Name, Label: m.Label}
+		if m.Recorded {
+			mark.TimeMs = intPtr(m.TimeMs)
+		}
+		doc.Timing.Marks = append(doc.Timing.Marks, mark)
+	}
+
+	if p := summary.Poster; p != nil {
+		poster := &jsonPoster{
+			Path:        p.Path,
+			TimestampMs: p.TimestampMs,
+			Width:       p.Width,
+			Height:      p.Height,
+			Thumbnails:  []jsonThumbnail{},
+		}
+		for _, thumb := range p.Thumbnails {
+			poster.Thumbnails = append(poster.Thumbnails, jsonThumbnail{Path: thumb.Path, Width: thumb.Width, Height: thumb.Height})
+		}
+		doc.Poster = poster
+	}
+
+	if fs := summary.Filmstrip; fs != nil {
+		doc.Filmstrip = &jsonFilmstrip{Path: fs.Path, FrameCount: fs.FrameCount}
+	}
+
+	for _, fr := range summary.Frames {
+		doc.Frames = append(doc.Frames, jsonFrame{
+			TimestampMs:     fr.TimestampMs,
+			Progress:        fr.Progress,
+			LoadedResources: fr.LoadedResources,
+			TotalResources:  fr.TotalResources,
+			TotalBytes:      fr.TotalBytes,
+		})
+	}
+
+	for _, r := range summary.Requests {
+		doc.Requests = append(doc.Requests, jsonRequest{
+			URL:          r.URL,
+			Type:         r.Type,
+			StartMs:      r.StartMs,
+			DurationMs:   r.DurationMs,
+			TransferSize: r.TransferSize,
+		})
+	}
+
+	return doc
+}
+
+// LoadJSON reads a summary written by JSONFormatter.
+func LoadJSON(path string) (*Summary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read summary: %w", err)
+	}
+	summary, err := ParseJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return summary, nil
+}
+
+// ParseJSON parses a summary written by JSONFormatter. Null measurements
+// are restored as unrecorded values, so the Summary formats the same way
+// as the one that was written.
+func ParseJSON(data []byte) (*Summary, error) {
+	var doc jsonSummary
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse summary: %w", err)
+	}
+	if doc.SchemaVersion < 1 || doc.SchemaVersion > JSONSchemaVersion {
+		return nil, fmt.Errorf("unsupported summary schema version: %d (supported: %d)", doc.SchemaVersion, JSONSchemaVersion)
+	}
+
+	summary := &Summary{
+		GeneratedAt: doc.GeneratedAt,
+		Page:        PageInfo{Title: doc.Page.Title, URL: doc.Page.URL},
+		Timing: TimingInfo{
+			TotalDurationMs: doc.Timing.TotalDurationMs,
+			TimedOut:        doc.Timing.TimedOut,
+			TimeoutSec:      doc.Timing.TimeoutSec,
+		},
+		Traffic: TrafficInfo{TotalBytes: doc.Traffic.TotalBytes},
+		Settings: Settings{
+			Preset:        doc.Settings.Preset,
+			Quality:       doc.Settings.Quality,
+			Codec:         doc.Settings.Codec,
+			ViewportWidth: doc.Settings.ViewportWidth,
+			Columns:       doc.Settings.Columns,
+			DownloadSpeed: doc.Settings.DownloadSpeed,
+			UploadSpeed:   doc.Settings.UploadSpeed,
+			CPUThrottling: doc.Settings.CPUThrottling,
+		},
+		Video: VideoInfo{
+			Path:          doc.Video.Path,
+			FrameCount:    doc.Video.FrameCount,
+			DurationMs:    doc.Video.DurationMs,
+			FileSize:      doc.Video.FileSize,
+			CanvasWidth:   doc.Video.CanvasWidth,
+			CanvasHeight:  doc.Video.CanvasHeight,
+			CRF:           doc.Video.CRF,
+			OutroDuration: doc.Video.OutroDurationMs,
+			MaxSize:       doc.Video.MaxSize,
+			EncodePasses:  doc.Video.EncodePasses,
+		},
+	}
+
+	if v := doc.Timing.DOMContentLoadedMs; v != nil {
+		summary.Timing.DOMContentLoadedMs = *v
+	}
+	if v := doc.Timing.LoadCompleteMs; v != nil {
+		summary.Timing.LoadCompleteMs = *v
+	}
+	if v := doc.Timing.LargestContentfulPaintMs; v != nil {
+		summary.Timing.LargestContentfulPaintMs = *v
+	}
+	if v := doc.Traffic.Requests; v != nil {
+		summary.Traffic.Requests = *v
+	}
+	for _, m := range doc.Timing.Marks {
+		mark := MarkTiming{Name: m.Name, Label: m.Label}
+		if m.TimeMs != nil {
+			mark.TimeMs = *m.TimeMs
+			mark.Recorded = true
+		}
+		summary.Timing.Marks = append(summary.Timing.Marks, mark)
+	}
+
+	if p := doc.Poster; p != nil {
+		poster := &PosterInfo{Path: p.Path, TimestampMs: p.TimestampMs, Width: p.Width, Height: p.Height}
+		for _, thumb := range p.Thumbnails {
+			poster.Thumbnails = append(poster.Thumbnails, ThumbnailInfo{Path: thumb.Path, Width: thumb.Width, Height: thumb.Height})
+		}
+		summary.Poster = poster
+	}
+
+	if fs := doc.Filmstrip; fs != nil {
+		summary.Filmstrip = &FilmstripInfo{Path: fs.Path, FrameCount: fs.FrameCount}
+	}
+
+	for _, fr := range doc.Frames {
+		summary.Frames = append(summary.Frames, FrameInfo{
+			TimestampMs:     fr.TimestampMs,
+			Progress:        fr.Progress,
+			LoadedResources: fr.LoadedResources,
+			TotalResources:  fr.TotalResources,
+			TotalBytes:      fr.TotalBytes,
+		})
+	}
+
+	for _, r := range doc.Requests {
+		summary.Requests = append(summary.Requests, RequestInfo{
+			URL:          r.URL,
+			Type:         r.Type,
+			StartMs:      r.StartMs,
+			DurationMs:   r.DurationMs,
+			TransferSize: r.TransferSize,
+		})
+	}
+
+	return summary, nil
+}
+
+func intPtr(v int) *int {
+	return &v
+}
@@ -0,0 +1,189 @@
+package summarizer
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONFormatter_Format(t *testing.T) {
+	summary := NewBuilder().
+		WithPage("Example", "https://example.com").
+		WithTiming(800, 1500, 3000).
+		WithMarks([]MarkTiming{
+			{Name: "hero", Label: "Hero", TimeMs: 900, Recorded: true},
+			{Name: "ads", Label: "Ads"},
+		}).
+		WithTraffic(2048).
+		WithRequestCount(3).
+		WithSettings(Settings{Preset: "mobile", Codec: "H.264", CPUThrottling: 4}).
+		WithVideo(VideoInfo{FrameCount: 2, DurationMs: 5000, OutroDuration: 2000}).
+		WithPoster(PosterInfo{Path: "poster.png", Width: 320, Height: 640}).
+		WithFrames([]FrameInfo{
+			{TimestampMs: 0, Progress: 0, TotalResources: 3},
+			{TimestampMs: 100, Progress: 1, LoadedResources: 3, TotalResources: 3, TotalBytes: 2048},
+		}).
+		Build()
+	summary.GeneratedAt = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	output := NewJSONFormatter("1.2.3").Format(summary)
+
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(output), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, output)
+	}
+
+	if doc["schemaVersion"] != float64(JSONSchemaVersion) {
+		t.Errorf("schemaVersion = %v, want %d", doc["schemaVersion"], JSONSchemaVersion)
+	}
+	if doc["generatedAt"] != "2024-05-01T12:00:00Z" {
+		t.Errorf("generatedAt = %v", doc["generatedAt"])
+	}
+	generator := doc["generator"].(map[string]any)
+	if generator["version"] != "1.2.3" {
+		t.Errorf("generator = %v", generator)
+	}
+
+	timing := doc["timing"].(map[string]any)
+	if timing["domContentLoadedMs"] != float64(800) || timing["loadCompleteMs"] != float64(1500) {
+		t.Errorf("timing = %v", timing)
+	}
+	marks := timing["marks"].([]any)
+	if len(marks) != 2 || marks[0].(map[string]any)["timeMs"] != float64(900) || marks[1].(map[string]any)["timeMs"] != nil {
+		t.Errorf("marks = %v, want hero at 900 and ads null", marks)
+	}
+
+	traffic := doc["traffic"].(map[string]any)
+	if traffic["totalBytes"] != float64(2048) || traffic["requests"] != float64(3) {
+		t.Errorf("traffic = %v", traffic)
+	}
+
+	video := doc["video"].(map[string]any)
+	if video["outroDurationMs"] != float64(2000) {
+		t.Errorf("video = %v", video)
+	}
+	poster := doc["poster"].(map[string]any)
+	if poster["path"] != "poster.png" || len(poster["thumbnails"].([]any)) != 0 {
+		t.Errorf("poster = %v", poster)
+	}
+
+	frames := doc["frames"].([]any)
+	if len(frames) != 2 {
+		t.Fatalf("got %d frames, want 2", len(frames))
+	}
+	last := frames[1].(map[string]any)
+	if last["timestampMs"] != float64(100) || last["progress"] != float64(1) || last["loadedResources"] != float64(3) {
+		t.Errorf("frame = %v", last)
+	}
+}
+
+func TestJSONFormatter_Format_Unavailable(t *testing.T) {
+	summary := &Summary{
+		Timing: TimingInfo{
+			DOMContentLoadedMs: 2000,
+			TimedOut:           true,
+			TimeoutSec:         1,
+		},
+	}
+
+	output := NewJSONFormatter("").Format(summary)
+
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(output), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	timing := doc["timing"].(map[string]any)
+	if timing["domContentLoadedMs"] != nil || timing["loadCompleteMs"] != nil {
+		t.Errorf("expected null timings after timeout, got %v", timing)
+	}
+	if traffic := doc["traffic"].(map[string]any); traffic["requests"] != nil {
+		t.Errorf("expected null requests when not recorded, got %v", traffic)
+	}
+	if doc["poster"] != nil {
+		t.Errorf("poster = %v, want null", doc["poster"])
+	}
+	if !strings.Contains(output, `"frames": []`) || !strings.Contains(output, `"marks": []`) {
+		t.Errorf("expected empty arrays instead of null:\n%s", output)
+	}
+}
+
+func TestFormatForPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want Format
+	}{
+		{"summary.json", FormatJSON},
+		{"SUMMARY.JSON", FormatJSON},
+		{"summary.md", FormatMarkdown},
+		{"report.html", FormatHTML},
+		{"report.htm", FormatHTML},
+		{"summary", FormatMarkdown},
+	}
+	for _, tt := range tests {
+		if got := FormatForPath(tt.path); got != tt.want {
+			t.Errorf("FormatForPath(%q) = %s, want %s", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for input, want := range map[string]Format{"markdown": FormatMarkdown, "md": FormatMarkdown, "JSON": FormatJSON, "html": FormatHTML} {
+		got, err := ParseFormat(input)
+		if err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %s, %v; want %s", input, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("yaml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestJSONFormatter_Format_Report(t *testing.T) {
+	output := NewJSONFormatter("").Format(htmlTestSummary())
+
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(output), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if lcp := doc["timing"].(map[string]any)["largestContentfulPaintMs"]; lcp != float64(1200) {
+		t.Errorf("largestContentfulPaintMs = %v, want 1200", lcp)
+	}
+	if path := doc["video"].(map[string]any)["path"]; path != "out.mp4" {
+		t.Errorf("video path = %v", path)
+	}
+	if filmstrip := doc["filmstrip"].(map[string]any); filmstrip["frameCount"] != float64(4) {
+		t.Errorf("filmstrip = %v", filmstrip)
+	}
+	requests := doc["requests"].([]any)
+	if len(requests) != 2 || requests[1].(map[string]any)["type"] != "script" {
+		t.Errorf("requests = %v", requests)
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	summary := htmlTestSummary()
+	summary.GeneratedAt = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	summary.Poster = &PosterInfo{Path: "poster.png", Width: 320, Height: 640, Thumbnails: []ThumbnailInfo{{Path: "thumb.png", Width: 160, Height: 320}}}
+
+	got, err := ParseJSON([]byte(NewJSONFormatter("").Format(summary)))
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, summary) {
+		t.Errorf("round trip mismatch:\ngot  %+v\nwant %+v", got, summary)
+	}
+
+	// Null timings come back as unrecorded
+	got, err = ParseJSON([]byte(NewJSONFormatter("").Format(&Summary{Timing: TimingInfo{TimedOut: true, TimeoutSec: 1}})))
+	if err != nil || got.Timing.DOMContentLoadedMs != 0 || !got.Timing.TimedOut {
+		t.Errorf("timeout round trip: %+v, %v", got, err)
+	}
+
+	for _, data := range []string{`{"schemaVersion": 2}`, `{}`, `not json`} {
+		if _, err := ParseJSON([]byte(data)); err == nil {
+			t.Errorf("expected error for %s", data)
+		}
+	}
+}
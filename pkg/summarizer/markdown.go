@@ -60,20 +60,32 @@ func (f *MarkdownFormatter) Format(summary *Summary) string {
 	sb.WriteString(fmt.Sprintf("- `%s` %s\n", t("URL"), summary.Page.URL))
 
 	// DOM Content Loaded - show N/A if not available or if timed out before DCL
-	timeoutMs := summary.Timing.TimeoutSec * 1000
-	if summary.Timing.DOMContentLoadedMs == 0 || (summary.Timing.TimedOut && summary.Timing.DOMContentLoadedMs > timeoutMs) {
+	if !summary.Timing.DOMContentLoadedRecorded() {
 		sb.WriteString(fmt.Sprintf("- `%s (DOMContentLoaded)` N/A\n", t("DOM Content Loaded")))
 	} else {
 		sb.WriteString(fmt.Sprintf("- `%s (DOMContentLoaded)` %d ms\n", t("DOM Content Loaded"), summary.Timing.DOMContentLoadedMs))
 	}
 
 	// Load Complete - show timeout if applicable
-	if summary.Timing.TimedOut {
+	if !summary.Timing.LoadRecorded() {
 		sb.WriteString(fmt.Sprintf("- `%s (Load)` %s (%ds)\n", t("Load Complete"), t("Timeout"), summary.Timing.TimeoutSec))
 	} else {
 		sb.WriteString(fmt.Sprintf("- `%s (Load)` %d ms\n", t("Load Complete"), summary.Timing.LoadCompleteMs))
 	}
 
+	// User-timing marks
+	for _, mark := range summary.Timing.Marks {
+		label := mark.Label
+		if label != mark.Name {
+			label = fmt.Sprintf("%s (%s)", mark.Label, mark.Name)
+		}
+		if mark.Recorded {
+			sb.WriteString(fmt.Sprintf("- `%s` %d ms\n", label, mark.TimeMs))
+		} else {
+			sb.WriteString(fmt.Sprintf("- `%s` N/A\n", label))
+		}
+	}
+
 	sb.WriteString(fmt.Sprintf("- `%s` %s (%d bytes)\n", t("Total Traffic"), formatBytes(summary.Traffic.TotalBytes), summary.Traffic.TotalBytes))
 	sb.WriteString("\n")
 
@@ -115,8 +127,22 @@ func (f *MarkdownFormatter) Format(summary *Summary) string {
 	sb.WriteString(fmt.Sprintf("- `%s` %dx%d px\n", t("Canvas Size"), summary.Video.CanvasWidth, summary.Video.CanvasHeight))
 	sb.WriteString(fmt.Sprintf("- `%s` %d\n", t("CRF"), summary.Video.CRF))
 	sb.WriteString(fmt.Sprintf("- `%s` %d ms\n", t("Outro Duration"), summary.Video.OutroDuration))
+	if summary.Video.MaxSize > 0 {
+		sb.WriteString(fmt.Sprintf("- `%s` %s (%d bytes, %d %s)\n", t("Max Size"), formatBytes(summary.Video.MaxSize), summary.Video.MaxSize, summary.Video.EncodePasses, t("passes")))
+	}
 	sb.WriteString("\n")
 
+	// Poster section
+	if p := summary.Poster; p != nil {
+		sb.WriteString(fmt.Sprintf("## %s\n\n", t("Poster")))
+		sb.WriteString(fmt.Sprintf("![%s](%s)\n\n", t("Poster"), p.Path))
+		sb.WriteString(fmt.Sprintf("- `%s` [%s](%s) %dx%d px, %d ms\n", t("Poster Image"), p.Path, p.Path, p.Width, p.Height, p.TimestampMs))
+		for _, thumb := range p.Thumbnails {
+			sb.WriteString(fmt.Sprintf("- `%s` [%s](%s) %dx%d px\n", t("Thumbnail"), thumb.Path, thumb.Path, thumb.Width, thumb.Height))
+		}
+		sb.WriteString("\n")
+	}
+
 	// Footer
 	sb.WriteString("---\n")
 	sb.WriteString(fmt.Sprintf("*%s [loadshow](https://github.com/user/loadshow) %s*\n", t("Generated by"), f.version))
@@ -280,3 +280,83 @@ func TestMarkdownFormatter_NoTotalDuration(t *testing.T) {
 		t.Error("output should NOT contain total duration value")
 	}
 }
+
+func TestMarkdownFormatter_Format_Marks(t *testing.T) {
+	formatter := NewMarkdownFormatter()
+	summary := &Summary{
+		GeneratedAt: time.Now(),
+		Timing: TimingInfo{
+			DOMContentLoadedMs: 500,
+			LoadCompleteMs:     1000,
+			Marks: []MarkTiming{
+				{Name: "hero-rendered", Label: "Hero", TimeMs: 800, Recorded: true},
+				{Name: "app-interactive", Label: "app-interactive"},
+			},
+		},
+	}
+
+	result := formatter.Format(summary)
+
+	if !strings.Contains(result, "- `Hero (hero-rendered)` 800 ms") {
+		t.Error("expected output to contain recorded mark")
+	}
+	if !strings.Contains(result, "- `app-interactive` N/A") {
+		t.Error("expected output to contain N/A for unrecorded mark")
+	}
+}
+
+func TestMarkdownFormatter_Format_MaxSize(t *testing.T) {
+	formatter := NewMarkdownFormatter()
+
+	summary := &Summary{
+		GeneratedAt: time.Now(),
+		Video: VideoInfo{
+			CRF:          41,
+			MaxSize:      5 * 1024 * 1024,
+			EncodePasses: 4,
+		},
+	}
+
+	result := formatter.Format(summary)
+	for _, check := range []string{"`CRF` 41", "`Max Size` 5.00 MB (5242880 bytes, 4 passes)"} {
+		if !strings.Contains(result, check) {
+			t.Errorf("expected output to contain %q", check)
+		}
+	}
+
+	summary.Video.MaxSize = 0
+	if result := formatter.Format(summary); strings.Contains(result, "Max Size") {
+		t.Error("expected no Max Size line without a limit")
+	}
+}
+
+func TestMarkdownFormatter_Format_Poster(t *testing.T) {
+	formatter := NewMarkdownFormatter()
+
+	summary := NewBuilder().
+		WithPoster(PosterInfo{
+			Path:        "poster.png",
+			TimestampMs: 1200,
+			Width:       512,
+			Height:      640,
+			Thumbnails:  []ThumbnailInfo{{Path: "poster-320w.png", Width: 320, Height: 400}},
+		}).
+		Build()
+
+	result := formatter.Format(summary)
+	for _, check := range []string{
+		"## Poster",
+		"![Poster](poster.png)",
+		"`Poster Image` [poster.png](poster.png) 512x640 px, 1200 ms",
+		"`Thumbnail` [poster-320w.png](poster-320w.png) 320x400 px",
+	} {
+		if !strings.Contains(result, check) {
+			t.Errorf("expected output to contain %q", check)
+		}
+	}
+
+	summary.Poster = nil
+	if result := formatter.Format(summary); strings.Contains(result, "## Poster") {
+		t.Error("expected no Poster section without a poster")
+	}
+}
@@ -22,6 +22,18 @@ type Summary struct {
 
 	// Video output details
 	Video VideoInfo
+
+	// Poster image and thumbnails (nil = not generated)
+	Poster *PosterInfo
+
+	// Filmstrip contact sheet (nil = not generated)
+	Filmstrip *FilmstripInfo
+
+	// Recorded frames in timestamp order
+	Frames []FrameInfo
+
+	// Network requests in start order, document first
+	Requests []RequestInfo
 }
 
 // PageInfo contains information about the recorded page.
@@ -32,16 +44,40 @@ type PageInfo struct {
 
 // TimingInfo contains timing measurements.
 type TimingInfo struct {
-	DOMContentLoadedMs int
-	LoadCompleteMs     int
-	TotalDurationMs    int
-	TimedOut           bool // True if recording ended due to timeout
-	TimeoutSec         int  // Timeout value in seconds
+	DOMContentLoadedMs       int
+	LoadCompleteMs           int
+	LargestContentfulPaintMs int // 0 = not available
+	TotalDurationMs          int
+	TimedOut                 bool // True if recording ended due to timeout
+	TimeoutSec               int  // Timeout value in seconds
+	Marks                    []MarkTiming
+}
+
+// DOMContentLoadedRecorded reports whether DOMContentLoadedMs holds a
+// measurement. It does not if the event never fired or only fired after
+// the timeout; reports show such values as N/A.
+func (t TimingInfo) DOMContentLoadedRecorded() bool {
+	return t.DOMContentLoadedMs != 0 && !(t.TimedOut && t.DOMContentLoadedMs > t.TimeoutSec*1000)
+}
+
+// LoadRecorded reports whether LoadCompleteMs holds a measurement, that is
+// whether the page loaded before the timeout.
+func (t TimingInfo) LoadRecorded() bool {
+	return !t.TimedOut
+}
+
+// MarkTiming contains the recorded time of a user-timing mark.
+type MarkTiming struct {
+	Name     string
+	Label    string
+	TimeMs   int
+	Recorded bool // False if the page never recorded the mark
 }
 
 // TrafficInfo contains network traffic information.
 type TrafficInfo struct {
 	TotalBytes int64
+	Requests   int // Requests the recorder saw finish (0 = not recorded)
 }
 
 // Settings contains the recording configuration.
@@ -62,13 +98,61 @@ type Settings struct {
 
 // VideoInfo contains information about the output video.
 type VideoInfo struct {
+	Path          string // Relative to the summary file when written by the CLI
 	FrameCount    int
 	DurationMs    int
 	FileSize      int64
 	CanvasWidth   int
 	CanvasHeight  int
-	CRF           int
-	OutroDuration int // ms
+	CRF           int   // CRF of the written video
+	OutroDuration int   // ms
+	MaxSize       int64 // Size limit in bytes (0 = none)
+	EncodePasses  int   // Encodes run to fit MaxSize
+}
+
+// PosterInfo contains the poster image written alongside the video.
+type PosterInfo struct {
+	Path        string // Relative to the summary file when written by the CLI
+	TimestampMs int    // Time of the frame used
+	Width       int
+	Height      int
+	Thumbnails  []ThumbnailInfo
+}
+
+// ThumbnailInfo contains a downscaled copy of the poster image.
+type ThumbnailInfo struct {
+	Path   string
+	Width  int
+	Height int
+}
+
+// FilmstripInfo contains the filmstrip contact sheet written alongside the video.
+type FilmstripInfo struct {
+	Path       string // Relative to the summary file when written by the CLI
+	FrameCount int
+}
+
+// RequestInfo contains the timing of one network request.
+type RequestInfo struct {
+	URL          string
+	Type         string // Initiator type ("navigation" for the document)
+	StartMs      int    // ms since navigation start
+	DurationMs   int
+	TransferSize int64 // Bytes transferred (0 if cached or not exposed cross-origin)
+}
+
+// EndMs returns the time the response finished.
+func (r RequestInfo) EndMs() int {
+	return r.StartMs + r.DurationMs
+}
+
+// FrameInfo contains the loading state at one recorded frame.
+type FrameInfo struct {
+	TimestampMs     int     // ms since navigation start
+	Progress        float64 // Traffic-based loading progress (0-1)
+	LoadedResources int
+	TotalResources  int
+	TotalBytes      int64 // Bytes transferred at this point
 }
 
 // NewSummary creates a new Summary with the current timestamp.
@@ -116,11 +200,27 @@ func (b *Builder) WithTimeout(timedOut bool, timeoutSec int) *Builder {
 	return b
 }
 
+// WithMarks sets user-timing mark information.
+func (b *Builder) WithMarks(marks []MarkTiming) *Builder {
+	b.summary.Timing.Marks = marks
+	return b
+}
+
+// WithLargestContentfulPaint sets the LCP time (0 = not available).
+func (b *Builder) WithLargestContentfulPaint(ms int) *Builder {
+	b.summary.Timing.LargestContentfulPaintMs = ms
+	return b
+}
+
 // WithTraffic sets traffic information.
 func (b *Builder) WithTraffic(totalBytes int64) *Builder {
-	b.summary.Traffic = TrafficInfo{
-		TotalBytes: totalBytes,
-	}
+	b.summary.Traffic.TotalBytes = totalBytes
+	return b
+}
+
+// WithRequestCount sets the number of finished requests (0 = not recorded).
+func (b *Builder) WithRequestCount(n int) *Builder {
+	b.summary.Traffic.Requests = n
 	return b
 }
 
@@ -136,6 +236,30 @@ func (b *Builder) WithVideo(video VideoInfo) *Builder {
 	return b
 }
 
+// WithPoster sets poster image information.
+func (b *Builder) WithPoster(poster PosterInfo) *Builder {
+	b.summary.Poster = &poster
+	return b
+}
+
+// WithFilmstrip sets filmstrip information.
+func (b *Builder) WithFilmstrip(filmstrip FilmstripInfo) *Builder {
+	b.summary.Filmstrip = &filmstrip
+	return b
+}
+
+// WithFrames sets per-frame information.
+func (b *Builder) WithFrames(frames []FrameInfo) *Builder {
+	b.summary.Frames = frames
+	return b
+}
+
+// WithRequests sets network request information.
+func (b *Builder) WithRequests(requests []RequestInfo) *Builder {
+	b.summary.Requests = requests
+	return b
+}
+
 // Build returns the constructed Summary.
 func (b *Builder) Build() *Summary {
 	return b.summary
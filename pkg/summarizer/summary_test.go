@@ -69,6 +69,40 @@ func TestBuilder_WithTraffic(t *testing.T) {
 	}
 }
 
+func TestBuilder_WithRequestCount(t *testing.T) {
+	summary := NewBuilder().
+		WithRequestCount(42).
+		WithTraffic(1024).
+		Build()
+
+	if summary.Traffic.Requests != 42 || summary.Traffic.TotalBytes != 1024 {
+		t.Errorf("unexpected traffic: %+v", summary.Traffic)
+	}
+}
+
+func TestTimingInfo_Recorded(t *testing.T) {
+	tests := []struct {
+		name        string
+		timing      TimingInfo
+		dcl, loaded bool
+	}{
+		{"loaded", TimingInfo{DOMContentLoadedMs: 800, LoadCompleteMs: 1500, TimeoutSec: 30}, true, true},
+		{"no DCL event", TimingInfo{LoadCompleteMs: 1500, TimeoutSec: 30}, false, true},
+		{"DCL before timeout", TimingInfo{DOMContentLoadedMs: 800, TimedOut: true, TimeoutSec: 1}, true, false},
+		{"DCL after timeout", TimingInfo{DOMContentLoadedMs: 2000, TimedOut: true, TimeoutSec: 1}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.timing.DOMContentLoadedRecorded(); got != tt.dcl {
+				t.Errorf("DOMContentLoadedRecorded() = %v, want %v", got, tt.dcl)
+			}
+			if got := tt.timing.LoadRecorded(); got != tt.loaded {
+				t.Errorf("LoadRecorded() = %v, want %v", got, tt.loaded)
+			}
+		})
+	}
+}
+
 func TestBuilder_WithSettings(t *testing.T) {
 	settings := Settings{
 		Preset:        "mobile",
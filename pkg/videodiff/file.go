@@ -0,0 +1,27 @@
+package videodiff
+
+import (
+	"context"
+
+	"github.com/user/loadshow/pkg/adapters/av1decoder"
+	"github.com/user/loadshow/pkg/adapters/av1encoder"
+	"github.com/user/loadshow/pkg/adapters/logger"
+	"github.com/user/loadshow/pkg/adapters/osfilesystem"
+)
+
+// Diff renders the differences between two AV1 videos and returns the
+// per-frame scores. This is a convenience function that uses default
+// adapters; use the Stage API for other codecs or a custom logger.
+func Diff(beforePath, afterPath, outputPath string, opts Options) ([]FrameScore, error) {
+	decoder := av1decoder.NewMP4Reader()
+	defer decoder.Close()
+
+	stage := New(decoder, av1encoder.New(), osfilesystem.New(), logger.NewNoop(), opts)
+
+	result, err := stage.Execute(context.Background(), Input{
+		BeforePath: beforePath,
+		AfterPath:  afterPath,
+		OutputPath: outputPath,
+	})
+	return result.Scores, err
+}
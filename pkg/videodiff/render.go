@@ -0,0 +1,80 @@
+package videodiff
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/user/loadshow/pkg/imagediff"
+)
+
+// dimFactor scales the grayscale background of ModeHeatmap (out of 256).
+const dimFactor = 80
+
+// overlayAlpha is the opacity of the ModeOverlay tint (out of 256).
+const overlayAlpha = 160
+
+// diffFrame draws the differences between two equally sized frames and
+// scores them.
+func (s *Stage) diffFrame(before, after *image.RGBA) (*image.RGBA, FrameScore) {
+	out := image.NewRGBA(after.Bounds())
+	threshold := s.opts.Threshold
+	heatmap := s.mode() == ModeHeatmap
+
+	var tint color.RGBA
+	if s.opts.Color != nil {
+		tint = color.RGBAModel.Convert(s.opts.Color).(color.RGBA)
+	}
+
+	var changed, sum int
+	for i := 0; i+3 < len(after.Pix); i += 4 {
+		d := max(
+			imagediff.AbsDiff(before.Pix[i], after.Pix[i]),
+			imagediff.AbsDiff(before.Pix[i+1], after.Pix[i+1]),
+			imagediff.AbsDiff(before.Pix[i+2], after.Pix[i+2]),
+		)
+		sum += int(d)
+
+		px := out.Pix[i : i+4 : i+4]
+		r, g, b := after.Pix[i], after.Pix[i+1], after.Pix[i+2]
+		if int(d) > threshold {
+			changed++
+			if heatmap {
+				r, g, b = heatColor(int(d), threshold)
+			} else {
+				r, g, b = blend(r, tint.R), blend(g, tint.G), blend(b, tint.B)
+			}
+		} else if heatmap {
+			luma := (int(r)*77 + int(g)*150 + int(b)*29) >> 8
+			gray := uint8(luma * dimFactor >> 8)
+			r, g, b = gray, gray, gray
+		}
+		px[0], px[1], px[2], px[3] = r, g, b, 255
+	}
+
+	pixels := after.Bounds().Dx() * after.Bounds().Dy()
+	score := FrameScore{}
+	if pixels > 0 {
+		score.ChangedRatio = float64(changed) / float64(pixels)
+		score.MeanDiff = float64(sum) / float64(pixels)
+	}
+	return out, score
+}
+
+// heatColor maps a difference above the threshold to blue (just over it),
+// red (halfway) or yellow (maximum).
+func heatColor(d, threshold int) (r, g, b uint8) {
+	span := 255 - threshold
+	if span <= 0 {
+		return 255, 255, 0
+	}
+	t := (d - threshold) * 510 / span // 0-510
+	if t <= 255 {
+		return uint8(t), 0, uint8(255 - t)
+	}
+	return 255, uint8(t - 255), 0
+}
+
+// blend mixes a channel with the overlay tint.
+func blend(c, tint uint8) uint8 {
+	return uint8((int(c)*(256-overlayAlpha) + int(tint)*overlayAlpha) >> 8)
+}
@@ -0,0 +1,73 @@
+package videodiff
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// FrameScore is the difference score of one output frame.
+type FrameScore struct {
+	// TimestampMs is the frame time in milliseconds.
+	TimestampMs int `json:"timestampMs"`
+	// ChangedRatio is the fraction of pixels above the threshold (0-1).
+	ChangedRatio float64 `json:"changedRatio"`
+	// MeanDiff is the mean per-pixel difference over all pixels (0-255),
+	// using the largest channel difference of each pixel.
+	MeanDiff float64 `json:"meanDiff"`
+}
+
+// ScoreFormat selects the file format of the per-frame scores.
+type ScoreFormat string
+
+const (
+	// ScoreCSV writes one row per frame with a header row.
+	ScoreCSV ScoreFormat = "csv"
+	// ScoreJSON writes an array of FrameScore objects.
+	ScoreJSON ScoreFormat = "json"
+)
+
+// ScoreFormatFor returns the score format for a file path: JSON for a
+// .json extension, CSV otherwise.
+func ScoreFormatFor(path string) ScoreFormat {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return ScoreJSON
+	}
+	return ScoreCSV
+}
+
+// EncodeScores serializes per-frame scores.
+func EncodeScores(scores []FrameScore, format ScoreFormat) ([]byte, error) {
+	switch format {
+	case ScoreJSON:
+		if scores == nil {
+			scores = []FrameScore{}
+		}
+		data, err := json.MarshalIndent(scores, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+
+	case ScoreCSV, "":
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		w.Write([]string{"timestamp_ms", "changed_ratio", "mean_diff"})
+		for _, s := range scores {
+			w.Write([]string{
+				strconv.Itoa(s.TimestampMs),
+				strconv.FormatFloat(s.ChangedRatio, 'f', 6, 64),
+				strconv.FormatFloat(s.MeanDiff, 'f', 3, 64),
+			})
+		}
+		w.Flush()
+		return buf.Bytes(), w.Error()
+
+	default:
+		return nil, fmt.Errorf("unknown score format: %s", format)
+	}
+}
@@ -0,0 +1,50 @@
+package videodiff
+
+import (
+	"testing"
+)
+
+func TestScoreFormatFor(t *testing.T) {
+	tests := []struct {
+		path string
+		want ScoreFormat
+	}{
+		{"scores.json", ScoreJSON},
+		{"SCORES.JSON", ScoreJSON},
+		{"scores.csv", ScoreCSV},
+		{"scores", ScoreCSV},
+	}
+	for _, tt := range tests {
+		if got := ScoreFormatFor(tt.path); got != tt.want {
+			t.Errorf("ScoreFormatFor(%q) = %s, want %s", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestEncodeScores(t *testing.T) {
+	scores := []FrameScore{
+		{TimestampMs: 0, ChangedRatio: 0, MeanDiff: 0},
+		{TimestampMs: 33, ChangedRatio: 0.125, MeanDiff: 12.5},
+	}
+
+	csv, err := EncodeScores(scores, ScoreCSV)
+	if err != nil {
+		t.Fatalf("EncodeScores(csv) failed: %v", err)
+	}
+	want := "timestamp_ms,changed_ratio,mean_diff\n0,0.000000,0.000\n33,0.125000,12.500\n"
+	if string(csv) != want {
+		t.Errorf("CSV =\n%s\nwant\n%s", csv, want)
+	}
+
+	json, err := EncodeScores(nil, ScoreJSON)
+	if err != nil {
+		t.Fatalf("EncodeScores(json) failed: %v", err)
+	}
+	if string(json) != "[]\n" {
+		t.Errorf("empty JSON = %q, want []", json)
+	}
+
+	if _, err := EncodeScores(scores, "xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
@@ -0,0 +1,234 @@
+// Package videodiff renders a video of the pixel differences between two recordings.
+package videodiff
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+
+	"github.com/user/loadshow/pkg/frameiter"
+	"github.com/user/loadshow/pkg/imagediff"
+	"github.com/user/loadshow/pkg/ports"
+)
+
+// Mode selects how differences are drawn.
+type Mode string
+
+const (
+	// ModeHeatmap colors differing pixels by magnitude (blue → red → yellow)
+	// over a dimmed grayscale copy of the second video.
+	ModeHeatmap Mode = "heatmap"
+	// ModeOverlay tints differing pixels of the second video with Options.Color.
+	ModeOverlay Mode = "overlay"
+)
+
+// Input contains the input parameters for a diff.
+type Input struct {
+	// BeforePath is the file path of the reference video.
+	BeforePath string
+	// AfterPath is the file path of the video compared against it.
+	// Differences are drawn on top of this video.
+	AfterPath string
+	// OutputPath is the file path for the difference video.
+	OutputPath string
+	// ScoresPath is the file path for the per-frame scores (empty = none).
+	// The format follows the extension: .json for JSON, anything else for CSV.
+	ScoresPath string
+}
+
+// Result contains the result of a diff.
+type Result struct {
+	// OutputPath is the path where the output was written.
+	OutputPath string
+	// FrameCount is the number of frames in the output video.
+	FrameCount int
+	// DurationMs is the duration of the output video in milliseconds.
+	DurationMs int
+	// Scores holds the difference score of each output frame.
+	Scores []FrameScore
+}
+
+// Options configures the diff.
+type Options struct {
+	// Mode draws differences as a heatmap or overlay (empty = ModeHeatmap).
+	Mode Mode
+	// Threshold is the per-channel difference (0-255) a pixel must exceed
+	// to count as changed. It hides compression noise.
+	Threshold int
+	// Color is the highlight color of ModeOverlay.
+	Color color.Color
+	// FPS is the output frame rate.
+	FPS float64
+	// Quality is the encoding quality (CRF 0-63, lower is better).
+	Quality int
+	// Bitrate is the target bitrate in kbps (0 = auto).
+	Bitrate int
+}
+
+// DefaultThreshold is the default per-channel difference threshold.
+const DefaultThreshold = 24
+
+// DefaultColor is the default overlay color (#FF1744 red).
+var DefaultColor = color.RGBA{R: 255, G: 23, B: 68, A: 255}
+
+// DefaultOptions returns default options.
+func DefaultOptions() Options {
+	return Options{
+		Mode:      ModeHeatmap,
+		Threshold: DefaultThreshold,
+		Color:     DefaultColor,
+		FPS:       30.0,
+		Quality:   30,
+		Bitrate:   0,
+	}
+}
+
+// Stage implements the diff operation with dependency injection.
+type Stage struct {
+	decoder ports.VideoDecoder
+	encoder ports.VideoEncoder
+	fs      ports.FileSystem
+	logger  ports.Logger
+	opts    Options
+}
+
+// New creates a new diff stage with the given dependencies.
+func New(
+	decoder ports.VideoDecoder,
+	encoder ports.VideoEncoder,
+	fs ports.FileSystem,
+	logger ports.Logger,
+	opts Options,
+) *Stage {
+	return &Stage{
+		decoder: decoder,
+		encoder: encoder,
+		fs:      fs,
+		logger:  logger.WithComponent("videodiff"),
+		opts:    opts,
+	}
+}
+
+// Execute compares the two videos frame by frame on a shared timeline and
+// encodes the differences. The shorter video holds its last frame until the
+// longer one finishes.
+func (s *Stage) Execute(ctx context.Context, input Input) (Result, error) {
+	result := Result{
+		OutputPath: input.OutputPath,
+	}
+
+	switch s.opts.Mode {
+	case ModeHeatmap, ModeOverlay, "":
+	default:
+		return result, fmt.Errorf("unknown diff mode: %s", s.opts.Mode)
+	}
+
+	before, err := s.open(input.BeforePath)
+	if err != nil {
+		return result, err
+	}
+	defer before.Close()
+
+	after, err := s.open(input.AfterPath)
+	if err != nil {
+		return result, err
+	}
+	defer after.Close()
+
+	size := after.Size()
+	if before.Size() != size {
+		return result, fmt.Errorf("video sizes differ: %s is %dx%d, %s is %dx%d",
+			input.BeforePath, before.Size().X, before.Size().Y, input.AfterPath, size.X, size.Y)
+	}
+
+	totalDuration := max(before.DurationMs(), after.DurationMs())
+	result.DurationMs = totalDuration
+
+	s.logger.Debug("Comparing %dx%d frames over %dms (%s, threshold %d)",
+		size.X, size.Y, totalDuration, s.mode(), s.opts.Threshold)
+
+	if err := s.encoder.Begin(size.X, size.Y, s.opts.FPS, ports.EncoderOptions{
+		Quality: s.opts.Quality,
+		Bitrate: s.opts.Bitrate,
+	}); err != nil {
+		return result, fmt.Errorf("init encoder: %w", err)
+	}
+
+	frameDurationMs := int(1000.0 / s.opts.FPS)
+	for timestampMs := 0; timestampMs <= totalDuration; timestampMs += frameDurationMs {
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		default:
+		}
+
+		a, err := before.FrameAt(timestampMs)
+		if err != nil {
+			return result, fmt.Errorf("read video %s at %dms: %w", input.BeforePath, timestampMs, err)
+		}
+		b, err := after.FrameAt(timestampMs)
+		if err != nil {
+			return result, fmt.Errorf("read video %s at %dms: %w", input.AfterPath, timestampMs, err)
+		}
+
+		output, score := s.diffFrame(imagediff.ToRGBA(a.Image), imagediff.ToRGBA(b.Image))
+		score.TimestampMs = timestampMs
+		result.Scores = append(result.Scores, score)
+
+		if err := s.encoder.EncodeFrame(output, timestampMs); err != nil {
+			return result, fmt.Errorf("encode frame at %dms: %w", timestampMs, err)
+		}
+	}
+	result.FrameCount = len(result.Scores)
+
+	s.logger.Debug("Encoded %d frames", result.FrameCount)
+
+	data, err := s.encoder.End()
+	if err != nil {
+		return result, fmt.Errorf("end encoding: %w", err)
+	}
+
+	s.logger.Debug("Writing output: %s (%d bytes)", input.OutputPath, len(data))
+
+	if err := s.fs.WriteFile(input.OutputPath, data); err != nil {
+		return result, fmt.Errorf("write output: %w", err)
+	}
+
+	if input.ScoresPath != "" {
+		scores, err := EncodeScores(result.Scores, ScoreFormatFor(input.ScoresPath))
+		if err != nil {
+			return result, fmt.Errorf("encode scores: %w", err)
+		}
+		if err := s.fs.WriteFile(input.ScoresPath, scores); err != nil {
+			return result, fmt.Errorf("write scores: %w", err)
+		}
+	}
+
+	s.logger.Info("Diff completed: %s", input.OutputPath)
+
+	return result, nil
+}
+
+// mode returns the configured mode, defaulting to ModeHeatmap.
+func (s *Stage) mode() Mode {
+	if s.opts.Mode == "" {
+		return ModeHeatmap
+	}
+	return s.opts.Mode
+}
+
+// open opens a video for incremental decoding.
+func (s *Stage) open(path string) (*frameiter.Player, error) {
+	s.logger.Debug("Reading video: %s", path)
+
+	frames, err := s.decoder.OpenFrames(path)
+	if err != nil {
+		return nil, fmt.Errorf("read video %s: %w", path, err)
+	}
+	src, err := frameiter.NewPlayer(frames)
+	if err != nil {
+		frames.Close()
+		return nil, fmt.Errorf("read video %s: %w", path, err)
+	}
+	return src, nil
+}
@@ -0,0 +1,190 @@
+package videodiff
+
+import (
+	"context"
+	"encoding/json"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/user/loadshow/pkg/adapters/logger"
+	"github.com/user/loadshow/pkg/mocks"
+	"github.com/user/loadshow/pkg/ports"
+)
+
+var (
+	white = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	black = color.RGBA{A: 255}
+)
+
+// testFrame returns a white frame whose columns left of x are filled.
+func testFrame(x int, fill color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	for py := 0; py < 10; py++ {
+		for px := 0; px < 10; px++ {
+			if px < x {
+				img.Set(px, py, fill)
+			} else {
+				img.Set(px, py, white)
+			}
+		}
+	}
+	return img
+}
+
+func newTestStage(decoder ports.VideoDecoder, encoder ports.VideoEncoder, fs ports.FileSystem, configure func(*Options)) *Stage {
+	opts := DefaultOptions()
+	opts.FPS = 10 // 100ms frames
+	configure(&opts)
+	return New(decoder, encoder, fs, logger.NewNoop(), opts)
+}
+
+func TestExecute(t *testing.T) {
+	decoder := mocks.NewVideoDecoder(map[string][]ports.VideoFrame{
+		"before.mp4": {
+			{Image: testFrame(0, black), TimestampMs: 0, Duration: 300},
+		},
+		"after.mp4": {
+			{Image: testFrame(0, black), TimestampMs: 0, Duration: 100},
+			{Image: testFrame(5, black), TimestampMs: 100, Duration: 100},
+			{Image: testFrame(10, black), TimestampMs: 200, Duration: 100},
+		},
+	})
+	encoder := &mocks.VideoEncoder{}
+	fs := mocks.NewFileSystem()
+	stage := newTestStage(decoder, encoder, fs, func(o *Options) {})
+
+	result, err := stage.Execute(context.Background(), Input{
+		BeforePath: "before.mp4",
+		AfterPath:  "after.mp4",
+		OutputPath: "diff.mp4",
+		ScoresPath: "scores.json",
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if result.FrameCount != 4 || len(encoder.EncodeFrameCalls) != 4 {
+		t.Fatalf("encoded %d frames, want 4 (0-300ms)", len(encoder.EncodeFrameCalls))
+	}
+	wantRatios := []float64{0, 0.5, 1, 1}
+	for i, want := range wantRatios {
+		if got := result.Scores[i].ChangedRatio; got != want {
+			t.Errorf("frame %d changed ratio = %v, want %v", i, got, want)
+		}
+	}
+	if got := result.Scores[2].MeanDiff; got != 255 {
+		t.Errorf("frame 2 mean diff = %v, want 255", got)
+	}
+
+	// Scores are written as JSON
+	data, err := fs.ReadFile("scores.json")
+	if err != nil {
+		t.Fatalf("scores not written: %v", err)
+	}
+	var scores []FrameScore
+	if err := json.Unmarshal(data, &scores); err != nil {
+		t.Fatalf("invalid scores JSON: %v", err)
+	}
+	if len(scores) != 4 || scores[1].TimestampMs != 100 {
+		t.Errorf("unexpected scores: %+v", scores)
+	}
+
+	for path, it := range decoder.Opened {
+		if !it.Closed {
+			t.Errorf("%s: iterator not closed", path)
+		}
+	}
+}
+
+func TestExecute_Modes(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      Mode
+		changed   color.RGBA // Output pixel where the videos differ
+		same      color.RGBA // Output pixel where they match
+		threshold int
+	}{
+		{"heatmap", ModeHeatmap, color.RGBA{R: 255, G: 255, A: 255}, color.RGBA{R: 79, G: 79, B: 79, A: 255}, DefaultThreshold},
+		{"overlay", ModeOverlay, color.RGBA{R: 159, G: 14, B: 42, A: 255}, white, DefaultThreshold},
+		{"below threshold", ModeOverlay, black, white, 255},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder := mocks.NewVideoDecoder(map[string][]ports.VideoFrame{
+				"a.mp4": {{Image: testFrame(0, black), Duration: 100}},
+				"b.mp4": {{Image: testFrame(5, black), Duration: 100}},
+			})
+			var frames []*image.RGBA
+			encoder := &mocks.VideoEncoder{
+				EncodeFrameFunc: func(img image.Image, timestampMs int) error {
+					frames = append(frames, img.(*image.RGBA))
+					return nil
+				},
+			}
+			stage := newTestStage(decoder, encoder, mocks.NewFileSystem(), func(o *Options) {
+				o.Mode = tt.mode
+				o.Threshold = tt.threshold
+			})
+
+			if _, err := stage.Execute(context.Background(), Input{BeforePath: "a.mp4", AfterPath: "b.mp4", OutputPath: "diff.mp4"}); err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+			if got := frames[0].RGBAAt(2, 2); got != tt.changed {
+				t.Errorf("changed pixel = %v, want %v", got, tt.changed)
+			}
+			if got := frames[0].RGBAAt(8, 2); got != tt.same {
+				t.Errorf("unchanged pixel = %v, want %v", got, tt.same)
+			}
+		})
+	}
+}
+
+func TestExecute_Errors(t *testing.T) {
+	decoder := mocks.NewVideoDecoder(map[string][]ports.VideoFrame{
+		"small.mp4": {{Image: image.NewRGBA(image.Rect(0, 0, 10, 10)), Duration: 100}},
+		"large.mp4": {{Image: image.NewRGBA(image.Rect(0, 0, 20, 10)), Duration: 100}},
+		"empty.mp4": {},
+	})
+
+	tests := []struct {
+		name   string
+		input  Input
+		mode   Mode
+		errMsg string
+	}{
+		{"size mismatch", Input{BeforePath: "small.mp4", AfterPath: "large.mp4"}, "", "sizes differ"},
+		{"no frames", Input{BeforePath: "small.mp4", AfterPath: "empty.mp4"}, "", "no frames"},
+		{"missing file", Input{BeforePath: "missing.mp4", AfterPath: "small.mp4"}, "", "file not found"},
+		{"unknown mode", Input{BeforePath: "small.mp4", AfterPath: "small.mp4"}, "blink", "unknown diff mode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stage := newTestStage(decoder, &mocks.VideoEncoder{}, mocks.NewFileSystem(), func(o *Options) { o.Mode = tt.mode })
+			_, err := stage.Execute(context.Background(), tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("error = %v, want %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestHeatColor(t *testing.T) {
+	tests := []struct {
+		d       int
+		r, g, b uint8
+	}{
+		{24, 0, 0, 255},    // just over the threshold: blue
+		{139, 253, 0, 2},   // about halfway: red
+		{255, 255, 255, 0}, // maximum: yellow
+	}
+	for _, tt := range tests {
+		r, g, b := heatColor(tt.d, 24)
+		if r != tt.r || g != tt.g || b != tt.b {
+			t.Errorf("heatColor(%d) = %d,%d,%d, want %d,%d,%d", tt.d, r, g, b, tt.r, tt.g, tt.b)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package videoedit
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/user/loadshow/pkg/ports"
+)
+
+// Transition selects how one clip changes into the next.
+type Transition string
+
+const (
+	// TransitionCut switches to the next clip immediately.
+	TransitionCut Transition = "cut"
+	// TransitionFade blends into the next clip over ConcatInput.TransitionMs.
+	// With gap frames, clips fade to and from the gap color instead.
+	TransitionFade Transition = "fade"
+)
+
+// ConcatInput contains the input parameters for a concatenation.
+type ConcatInput struct {
+	// Paths are the file paths of the videos, in playback order.
+	Paths []string
+	// OutputPath is the file path for the output video.
+	OutputPath string
+	// GapMs is the time of solid GapColor frames between clips (0 = none).
+	GapMs int
+	// GapColor is the color of gap frames and of the letterbox around clips
+	// smaller than the first one (nil = DefaultGapColor).
+	GapColor color.Color
+	// Transition changes between clips (empty = TransitionCut).
+	Transition Transition
+	// TransitionMs is the length of TransitionFade in milliseconds.
+	TransitionMs int
+}
+
+// clip is one item of the output timeline: an input video or gap frames.
+type clip struct {
+	path       string
+	frames     ports.FrameIterator // nil for gap frames
+	src        *source             // Opened when the clip comes on screen
+	closed     bool                // Played to the end and released
+	startMs    int                 // Output time the clip starts
+	durationMs int
+}
+
+func (c *clip) endMs() int {
+	return c.startMs + c.durationMs
+}
+
+// close releases the decoder of a clip.
+func (c *clip) close() {
+	if c.frames != nil && !c.closed {
+		c.frames.Close()
+		c.closed = true
+	}
+}
+
+// Concat plays the videos one after another. The output has the
+// dimensions of the first video; other videos are centered on GapColor.
+func (s *Stage) Concat(ctx context.Context, input ConcatInput) (Result, error) {
+	result := Result{OutputPath: input.OutputPath}
+
+	if len(input.Paths) < 2 {
+		return result, fmt.Errorf("at least two videos are required, got %d", len(input.Paths))
+	}
+	if input.GapMs < 0 {
+		return result, fmt.Errorf("invalid gap: %dms", input.GapMs)
+	}
+
+	overlap := 0
+	switch input.Transition {
+	case TransitionCut, "":
+	case TransitionFade:
+		if input.TransitionMs < 0 {
+			return result, fmt.Errorf("invalid transition length: %dms", input.TransitionMs)
+		}
+		overlap = input.TransitionMs
+	default:
+		return result, fmt.Errorf("unknown transition: %s", input.Transition)
+	}
+
+	gapColor := input.GapColor
+	if gapColor == nil {
+		gapColor = DefaultGapColor
+	}
+	gapDuration := input.GapMs
+	if gapDuration > 0 {
+		// Keep the gap fully visible for GapMs between the fades
+		gapDuration += 2 * overlap
+	}
+
+	// Lay out the timeline. Iterators are opened up front for their
+	// durations; frames are decoded only once a clip comes on screen.
+	var clips []*clip
+	defer func() {
+		for _, c := range clips {
+			c.close()
+		}
+	}()
+	var starts []int
+	startMs := 0
+	for i, path := range input.Paths {
+		if i > 0 && gapDuration > 0 {
+			clips = append(clips, &clip{startMs: startMs, durationMs: gapDuration})
+			startMs += gapDuration - overlap
+		}
+
+		s.logger.Debug("Reading video: %s", path)
+		frames, err := s.decoder.OpenFrames(path)
+		if err != nil {
+			return result, fmt.Errorf("read video %s: %w", path, err)
+		}
+		c := &clip{path: path, frames: frames, startMs: startMs, durationMs: frames.DurationMs()}
+		clips = append(clips, c)
+		if c.durationMs <= overlap {
+			return result, fmt.Errorf("video %s (%dms) is not longer than the transition (%dms)", path, c.durationMs, overlap)
+		}
+
+		starts = append(starts, startMs)
+		startMs += c.durationMs - overlap
+	}
+	last := clips[len(clips)-1]
+
+	// The first video sets the output dimensions
+	if err := s.openClip(clips[0]); err != nil {
+		return result, err
+	}
+	size := clips[0].src.Size()
+
+	s.logger.Debug("Concatenating %d videos: %dx%d, %dms", len(input.Paths), size.X, size.Y, last.endMs())
+
+	gap := image.NewUniform(gapColor)
+	result, err := s.render(ctx, input.OutputPath, size, last.endMs(), func(timestampMs int) (image.Image, error) {
+		output := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+		drawn := false
+		for _, c := range clips {
+			if timestampMs >= c.endMs() && c != last {
+				c.close()
+				continue
+			}
+			if timestampMs < c.startMs {
+				continue
+			}
+
+			var layer image.Image = gap
+			if c.frames != nil {
+				if err := s.openClip(c); err != nil {
+					return nil, err
+				}
+				img, err := c.src.frameAt(timestampMs - c.startMs)
+				if err != nil {
+					return nil, err
+				}
+				layer = letterbox(img, size, gap)
+			}
+
+			// The first clip on screen is opaque; a clip fading in is
+			// blended over it
+			if !drawn {
+				draw.Draw(output, output.Bounds(), layer, layer.Bounds().Min, draw.Src)
+				drawn = true
+				continue
+			}
+			alpha := uint8(255 * min(timestampMs-c.startMs, overlap) / max(overlap, 1))
+			mask := image.NewUniform(color.Alpha{A: alpha})
+			draw.DrawMask(output, output.Bounds(), layer, layer.Bounds().Min, mask, image.Point{}, draw.Over)
+		}
+		return output, nil
+	})
+	result.ClipStartsMs = starts
+	if err != nil {
+		return result, err
+	}
+
+	s.logger.Info("Concat completed: %s", input.OutputPath)
+
+	return result, nil
+}
+
+// openClip starts decoding a clip.
+func (s *Stage) openClip(c *clip) error {
+	if c.src != nil {
+		return nil
+	}
+	src, err := newSource(c.path, c.frames)
+	if err != nil {
+		return fmt.Errorf("read video %s: %w", c.path, err)
+	}
+	c.src = src
+	return nil
+}
+
+// letterbox centers img on a background of the given size. Images of that
+// size are returned unchanged; larger ones are cropped.
+func letterbox(img image.Image, size image.Point, background image.Image) image.Image {
+	if img.Bounds().Size() == size {
+		return img
+	}
+	canvas := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+	draw.Draw(canvas, canvas.Bounds(), background, image.Point{}, draw.Src)
+	offset := size.Sub(img.Bounds().Size()).Div(2)
+	rect := image.Rectangle{Min: offset, Max: offset.Add(img.Bounds().Size())}
+	draw.Draw(canvas, rect, img, img.Bounds().Min, draw.Src)
+	return canvas
+}
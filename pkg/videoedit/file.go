@@ -0,0 +1,55 @@
+package videoedit
+
+import (
+	"context"
+
+	"github.com/user/loadshow/pkg/adapters/av1decoder"
+	"github.com/user/loadshow/pkg/adapters/av1encoder"
+	"github.com/user/loadshow/pkg/adapters/logger"
+	"github.com/user/loadshow/pkg/adapters/osfilesystem"
+)
+
+// TrimFile keeps [startMs, endMs) of an AV1 video (endMs 0 = end of video).
+// These convenience functions use default adapters; use the Stage API for
+// other codecs or a custom logger.
+func TrimFile(path, outputPath string, startMs, endMs int, opts Options) error {
+	return withDefaultStage(opts, func(stage *Stage) error {
+		_, err := stage.Trim(context.Background(), TrimInput{
+			Path:       path,
+			OutputPath: outputPath,
+			StartMs:    startMs,
+			EndMs:      endMs,
+		})
+		return err
+	})
+}
+
+// ConcatFiles plays AV1 videos one after another with a cut between them.
+func ConcatFiles(paths []string, outputPath string, opts Options) error {
+	return withDefaultStage(opts, func(stage *Stage) error {
+		_, err := stage.Concat(context.Background(), ConcatInput{
+			Paths:      paths,
+			OutputPath: outputPath,
+		})
+		return err
+	})
+}
+
+// SpeedFile changes the playback speed of an AV1 video by factor.
+func SpeedFile(path, outputPath string, factor float64, opts Options) error {
+	return withDefaultStage(opts, func(stage *Stage) error {
+		_, err := stage.Speed(context.Background(), SpeedInput{
+			Path:       path,
+			OutputPath: outputPath,
+			Factor:     factor,
+		})
+		return err
+	})
+}
+
+func withDefaultStage(opts Options, run func(*Stage) error) error {
+	decoder := av1decoder.NewMP4Reader()
+	defer decoder.Close()
+
+	return run(New(decoder, av1encoder.New(), osfilesystem.New(), logger.NewNoop(), opts))
+}
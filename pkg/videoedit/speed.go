@@ -0,0 +1,53 @@
+package videoedit
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"math"
+)
+
+// SpeedInput contains the input parameters for a speed change.
+type SpeedInput struct {
+	// Path is the file path of the input video.
+	Path string
+	// OutputPath is the file path for the output video.
+	OutputPath string
+	// Factor is the playback speed: 2 plays twice as fast, 0.5 at half speed.
+	Factor float64
+}
+
+// Speed changes the playback speed of a video. Output time t shows the
+// frame at t × Factor in the input.
+func (s *Stage) Speed(ctx context.Context, input SpeedInput) (Result, error) {
+	result := Result{OutputPath: input.OutputPath}
+
+	if input.Factor <= 0 || math.IsInf(input.Factor, 0) || math.IsNaN(input.Factor) {
+		return result, fmt.Errorf("invalid speed factor: %v (must be greater than 0)", input.Factor)
+	}
+
+	src, err := s.open(input.Path, 0)
+	if err != nil {
+		return result, err
+	}
+	defer src.Close()
+
+	durationMs := int(math.Round(float64(src.DurationMs()) / input.Factor))
+	if durationMs < 1 {
+		durationMs = 1
+	}
+
+	s.logger.Debug("Changing speed of %s by %vx: %dms → %dms", input.Path, input.Factor, src.DurationMs(), durationMs)
+
+	result, err = s.render(ctx, input.OutputPath, src.Size(), durationMs, func(timestampMs int) (image.Image, error) {
+		return src.frameAt(int(float64(timestampMs) * input.Factor))
+	})
+	if err != nil {
+		return result, err
+	}
+	result.ClipStartsMs = []int{0}
+
+	s.logger.Info("Speed change completed: %s", input.OutputPath)
+
+	return result, nil
+}
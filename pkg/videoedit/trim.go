@@ -0,0 +1,60 @@
+package videoedit
+
+import (
+	"context"
+	"fmt"
+	"image"
+)
+
+// TrimInput contains the input parameters for a trim.
+type TrimInput struct {
+	// Path is the file path of the input video.
+	Path string
+	// OutputPath is the file path for the trimmed video.
+	OutputPath string
+	// StartMs is the first moment kept, in milliseconds.
+	StartMs int
+	// EndMs is the end of the kept range in milliseconds (0 = end of video).
+	EndMs int
+}
+
+// Trim keeps the range [StartMs, EndMs) of a video. The output starts at
+// t=0 with the frame shown at StartMs.
+func (s *Stage) Trim(ctx context.Context, input TrimInput) (Result, error) {
+	result := Result{OutputPath: input.OutputPath}
+
+	if input.StartMs < 0 {
+		return result, fmt.Errorf("invalid start: %dms", input.StartMs)
+	}
+	if input.EndMs != 0 && input.EndMs <= input.StartMs {
+		return result, fmt.Errorf("end %dms must be after start %dms", input.EndMs, input.StartMs)
+	}
+
+	src, err := s.open(input.Path, input.StartMs)
+	if err != nil {
+		return result, err
+	}
+	defer src.Close()
+
+	endMs := src.DurationMs()
+	if input.EndMs != 0 && input.EndMs < endMs {
+		endMs = input.EndMs
+	}
+	if input.StartMs >= endMs {
+		return result, fmt.Errorf("start %dms is past the end of %s (%dms)", input.StartMs, input.Path, src.DurationMs())
+	}
+
+	s.logger.Debug("Trimming %s to %dms-%dms", input.Path, input.StartMs, endMs)
+
+	result, err = s.render(ctx, input.OutputPath, src.Size(), endMs-input.StartMs, func(timestampMs int) (image.Image, error) {
+		return src.frameAt(input.StartMs + timestampMs)
+	})
+	if err != nil {
+		return result, err
+	}
+	result.ClipStartsMs = []int{0}
+
+	s.logger.Info("Trim completed: %s", input.OutputPath)
+
+	return result, nil
+}
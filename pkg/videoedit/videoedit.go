@@ -0,0 +1,169 @@
+// Package videoedit trims, concatenates and changes the speed of recorded videos.
+package videoedit
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/user/loadshow/pkg/frameiter"
+	"github.com/user/loadshow/pkg/ports"
+)
+
+// Result contains the result of an edit.
+type Result struct {
+	// OutputPath is the path where the output was written.
+	OutputPath string
+	// FrameCount is the number of frames in the output video.
+	FrameCount int
+	// DurationMs is the duration of the output video in milliseconds.
+	DurationMs int
+	// ClipStartsMs is the output time at which each input video starts.
+	ClipStartsMs []int
+}
+
+// Options configures the output of an edit.
+type Options struct {
+	// FPS is the output frame rate.
+	FPS float64
+	// Quality is the encoding quality (CRF 0-63, lower is better).
+	Quality int
+	// Bitrate is the target bitrate in kbps (0 = auto).
+	Bitrate int
+}
+
+// DefaultGapColor is the default color of gap frames and letterboxing (black).
+var DefaultGapColor color.Color = color.Black
+
+// DefaultOptions returns default options.
+func DefaultOptions() Options {
+	return Options{
+		FPS:     30.0,
+		Quality: 30,
+		Bitrate: 0,
+	}
+}
+
+// Stage implements the edit operations with dependency injection.
+type Stage struct {
+	decoder ports.VideoDecoder
+	encoder ports.VideoEncoder
+	fs      ports.FileSystem
+	logger  ports.Logger
+	opts    Options
+}
+
+// New creates a new edit stage with the given dependencies.
+func New(
+	decoder ports.VideoDecoder,
+	encoder ports.VideoEncoder,
+	fs ports.FileSystem,
+	logger ports.Logger,
+	opts Options,
+) *Stage {
+	return &Stage{
+		decoder: decoder,
+		encoder: encoder,
+		fs:      fs,
+		logger:  logger.WithComponent("videoedit"),
+		opts:    opts,
+	}
+}
+
+// render encodes frames at the output frame rate from t=0 up to, but not
+// including, durationMs, so edited videos keep their exact length when they
+// are concatenated later. frameAt returns the image shown at each time.
+func (s *Stage) render(ctx context.Context, outputPath string, size image.Point, durationMs int, frameAt func(timestampMs int) (image.Image, error)) (Result, error) {
+	result := Result{
+		OutputPath: outputPath,
+		DurationMs: durationMs,
+	}
+
+	if err := s.encoder.Begin(size.X, size.Y, s.opts.FPS, ports.EncoderOptions{
+		Quality: s.opts.Quality,
+		Bitrate: s.opts.Bitrate,
+	}); err != nil {
+		return result, fmt.Errorf("init encoder: %w", err)
+	}
+
+	frameDurationMs := int(1000.0 / s.opts.FPS)
+	for timestampMs := 0; timestampMs < durationMs || result.FrameCount == 0; timestampMs += frameDurationMs {
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		default:
+		}
+
+		img, err := frameAt(timestampMs)
+		if err != nil {
+			return result, err
+		}
+		if err := s.encoder.EncodeFrame(img, timestampMs); err != nil {
+			return result, fmt.Errorf("encode frame at %dms: %w", timestampMs, err)
+		}
+		result.FrameCount++
+	}
+
+	s.logger.Debug("Encoded %d frames", result.FrameCount)
+
+	data, err := s.encoder.End()
+	if err != nil {
+		return result, fmt.Errorf("end encoding: %w", err)
+	}
+
+	s.logger.Debug("Writing output: %s (%d bytes)", outputPath, len(data))
+
+	if err := s.fs.WriteFile(outputPath, data); err != nil {
+		return result, fmt.Errorf("write output: %w", err)
+	}
+
+	return result, nil
+}
+
+// open opens a video for incremental decoding, starting at the frame shown
+// at startMs.
+func (s *Stage) open(path string, startMs int) (*source, error) {
+	s.logger.Debug("Reading video: %s", path)
+
+	frames, err := s.decoder.OpenFrames(path)
+	if err != nil {
+		return nil, fmt.Errorf("read video %s: %w", path, err)
+	}
+	if startMs > 0 {
+		if err := frames.Seek(startMs); err != nil {
+			frames.Close()
+			return nil, fmt.Errorf("read video %s: seek to %dms: %w", path, startMs, err)
+		}
+	}
+	src, err := newSource(path, frames)
+	if err != nil {
+		frames.Close()
+		return nil, fmt.Errorf("read video %s: %w", path, err)
+	}
+	return src, nil
+}
+
+// source is an input video decoded incrementally.
+type source struct {
+	*frameiter.Player
+	path string
+}
+
+func newSource(path string, frames ports.FrameIterator) (*source, error) {
+	player, err := frameiter.NewPlayer(frames)
+	if err != nil {
+		return nil, err
+	}
+	return &source{Player: player, path: path}, nil
+}
+
+// frameAt returns the image shown at the given timestamp (see
+// frameiter.Player.FrameAt).
+func (src *source) frameAt(timestampMs int) (image.Image, error) {
+	frame, err := src.FrameAt(timestampMs)
+	if err != nil {
+		return nil, fmt.Errorf("read video %s at %dms: %w", src.path, timestampMs, err)
+	}
+	return frame.Image, nil
+}
@@ -0,0 +1,248 @@
+package videoedit
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/user/loadshow/pkg/adapters/logger"
+	"github.com/user/loadshow/pkg/mocks"
+	"github.com/user/loadshow/pkg/ports"
+)
+
+var (
+	red   = color.RGBA{R: 255, A: 255}
+	green = color.RGBA{G: 255, A: 255}
+	blue  = color.RGBA{B: 255, A: 255}
+	white = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	black = color.RGBA{A: 255}
+)
+
+func solid(size int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func testDecoder() *mocks.VideoDecoder {
+	return mocks.NewVideoDecoder(map[string][]ports.VideoFrame{
+		"rgb.mp4": {
+			{Image: solid(4, red), TimestampMs: 0, Duration: 100},
+			{Image: solid(4, green), TimestampMs: 100, Duration: 100},
+			{Image: solid(4, blue), TimestampMs: 200, Duration: 100},
+		},
+		"red.mp4":   {{Image: solid(4, red), TimestampMs: 0, Duration: 200}},
+		"blue.mp4":  {{Image: solid(4, blue), TimestampMs: 0, Duration: 200}},
+		"small.mp4": {{Image: solid(2, blue), TimestampMs: 0, Duration: 100}},
+	})
+}
+
+// recordingEncoder returns an encoder that keeps the top-left and center
+// pixel of each encoded frame.
+func recordingEncoder(corners, centers *[]color.RGBA) *mocks.VideoEncoder {
+	return &mocks.VideoEncoder{
+		EncodeFrameFunc: func(img image.Image, timestampMs int) error {
+			rgba := img.(*image.RGBA)
+			*corners = append(*corners, rgba.RGBAAt(0, 0))
+			*centers = append(*centers, rgba.RGBAAt(2, 2))
+			return nil
+		},
+	}
+}
+
+func newTestStage(decoder ports.VideoDecoder, encoder ports.VideoEncoder, fps float64) *Stage {
+	opts := DefaultOptions()
+	opts.FPS = fps
+	return New(decoder, encoder, mocks.NewFileSystem(), logger.NewNoop(), opts)
+}
+
+func assertColors(t *testing.T, got, want []color.RGBA) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d frames, want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("frame %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestTrim(t *testing.T) {
+	var corners, centers []color.RGBA
+	decoder := testDecoder()
+	stage := newTestStage(decoder, recordingEncoder(&corners, &centers), 10)
+
+	result, err := stage.Trim(context.Background(), TrimInput{Path: "rgb.mp4", OutputPath: "out.mp4", StartMs: 100})
+	if err != nil {
+		t.Fatalf("Trim failed: %v", err)
+	}
+	if result.DurationMs != 200 {
+		t.Errorf("duration = %dms, want 200ms", result.DurationMs)
+	}
+	assertColors(t, corners, []color.RGBA{green, blue})
+	if !decoder.Opened["rgb.mp4"].Closed {
+		t.Error("iterator not closed")
+	}
+
+	corners = nil
+	if _, err := stage.Trim(context.Background(), TrimInput{Path: "rgb.mp4", OutputPath: "out.mp4", StartMs: 50, EndMs: 150}); err != nil {
+		t.Fatalf("Trim failed: %v", err)
+	}
+	assertColors(t, corners, []color.RGBA{red})
+}
+
+func TestSpeed(t *testing.T) {
+	tests := []struct {
+		factor float64
+		want   []color.RGBA
+	}{
+		{2, []color.RGBA{red, blue}},
+		{0.5, []color.RGBA{red, red, green, green, blue, blue}},
+	}
+	for _, tt := range tests {
+		var corners, centers []color.RGBA
+		stage := newTestStage(testDecoder(), recordingEncoder(&corners, &centers), 10)
+
+		if _, err := stage.Speed(context.Background(), SpeedInput{Path: "rgb.mp4", OutputPath: "out.mp4", Factor: tt.factor}); err != nil {
+			t.Fatalf("Speed(%v) failed: %v", tt.factor, err)
+		}
+		assertColors(t, corners, tt.want)
+	}
+}
+
+func TestConcat(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      ConcatInput
+		wantStarts []int
+		corners    []color.RGBA
+		centers    []color.RGBA
+	}{
+		{
+			name:       "cut",
+			input:      ConcatInput{Paths: []string{"red.mp4", "rgb.mp4"}},
+			wantStarts: []int{0, 200},
+			corners:    []color.RGBA{red, red, red, green, blue},
+		},
+		{
+			name:       "gap",
+			input:      ConcatInput{Paths: []string{"red.mp4", "blue.mp4"}, GapMs: 100, GapColor: white},
+			wantStarts: []int{0, 300},
+			corners:    []color.RGBA{red, red, white, blue, blue},
+		},
+		{
+			name:       "letterbox",
+			input:      ConcatInput{Paths: []string{"red.mp4", "small.mp4"}},
+			wantStarts: []int{0, 200},
+			corners:    []color.RGBA{red, red, black},
+			centers:    []color.RGBA{red, red, blue},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var corners, centers []color.RGBA
+			decoder := testDecoder()
+			stage := newTestStage(decoder, recordingEncoder(&corners, &centers), 10)
+
+			result, err := stage.Concat(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Concat failed: %v", err)
+			}
+			if len(result.ClipStartsMs) != len(tt.wantStarts) || result.ClipStartsMs[1] != tt.wantStarts[1] {
+				t.Errorf("clip starts = %v, want %v", result.ClipStartsMs, tt.wantStarts)
+			}
+			assertColors(t, corners, tt.corners)
+			if tt.centers != nil {
+				assertColors(t, centers, tt.centers)
+			}
+			for path, it := range decoder.Opened {
+				if !it.Closed {
+					t.Errorf("%s: iterator not closed", path)
+				}
+			}
+		})
+	}
+}
+
+func TestConcat_Fade(t *testing.T) {
+	var corners, centers []color.RGBA
+	stage := newTestStage(testDecoder(), recordingEncoder(&corners, &centers), 20)
+
+	result, err := stage.Concat(context.Background(), ConcatInput{
+		Paths:        []string{"red.mp4", "blue.mp4"},
+		Transition:   TransitionFade,
+		TransitionMs: 100,
+	})
+	if err != nil {
+		t.Fatalf("Concat failed: %v", err)
+	}
+	if result.DurationMs != 300 || result.ClipStartsMs[1] != 100 {
+		t.Fatalf("duration %dms, starts %v; want 300ms with blue.mp4 at 100ms", result.DurationMs, result.ClipStartsMs)
+	}
+
+	// Halfway through the fade both clips are visible
+	mid := corners[3] // 150ms
+	if mid.R < 120 || mid.R > 135 || mid.B < 120 || mid.B > 135 {
+		t.Errorf("frame at 150ms = %v, want an even red/blue blend", mid)
+	}
+	if corners[0] != red || corners[len(corners)-1] != blue {
+		t.Errorf("fade should start red and end blue, got %v and %v", corners[0], corners[len(corners)-1])
+	}
+}
+
+func TestErrors(t *testing.T) {
+	stage := newTestStage(testDecoder(), &mocks.VideoEncoder{}, 10)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		run    func() error
+		errMsg string
+	}{
+		{"trim end before start", func() error {
+			_, err := stage.Trim(ctx, TrimInput{Path: "rgb.mp4", StartMs: 200, EndMs: 100})
+			return err
+		}, "must be after start"},
+		{"trim past end", func() error {
+			_, err := stage.Trim(ctx, TrimInput{Path: "rgb.mp4", StartMs: 300})
+			return err
+		}, "past the end"},
+		{"speed zero", func() error {
+			_, err := stage.Speed(ctx, SpeedInput{Path: "rgb.mp4"})
+			return err
+		}, "invalid speed factor"},
+		{"concat one video", func() error {
+			_, err := stage.Concat(ctx, ConcatInput{Paths: []string{"rgb.mp4"}})
+			return err
+		}, "at least two videos"},
+		{"concat unknown transition", func() error {
+			_, err := stage.Concat(ctx, ConcatInput{Paths: []string{"rgb.mp4", "red.mp4"}, Transition: "wipe"})
+			return err
+		}, "unknown transition"},
+		{"concat long transition", func() error {
+			_, err := stage.Concat(ctx, ConcatInput{Paths: []string{"rgb.mp4", "small.mp4"}, Transition: TransitionFade, TransitionMs: 100})
+			return err
+		}, "not longer than the transition"},
+		{"missing file", func() error {
+			_, err := stage.Concat(ctx, ConcatInput{Paths: []string{"rgb.mp4", "missing.mp4"}})
+			return err
+		}, "file not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("error = %v, want %q", err, tt.errMsg)
+			}
+		})
+	}
+}
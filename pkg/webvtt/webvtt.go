@@ -0,0 +1,35 @@
+// Package webvtt writes cues as a WebVTT subtitle file.
+package webvtt
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/user/loadshow/pkg/ports"
+)
+
+// Format returns cues as a WebVTT document.
+func Format(cues []ports.Cue) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("WEBVTT\n")
+	for i, c := range cues {
+		fmt.Fprintf(&buf, "\n%d\n%s --> %s\n%s\n", i+1, timestamp(c.StartMs), timestamp(c.EndMs), escape(c.Text))
+	}
+	return buf.Bytes()
+}
+
+// timestamp formats milliseconds as hh:mm:ss.ttt.
+func timestamp(ms int) string {
+	if ms < 0 {
+		ms = 0
+	}
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+var escaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\n", " ")
+
+// escape makes text safe for a cue payload, which cannot contain markup or line breaks.
+func escape(text string) string {
+	return escaper.Replace(text)
+}
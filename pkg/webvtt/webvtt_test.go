@@ -0,0 +1,27 @@
+package webvtt
+
+import (
+	"testing"
+
+	"github.com/user/loadshow/pkg/ports"
+)
+
+func TestFormat(t *testing.T) {
+	got := string(Format([]ports.Cue{
+		{StartMs: 1230, EndMs: 3230, Text: "DOMContentLoaded 1.23s"},
+		{StartMs: 3723450, EndMs: 3725450, Text: "A <b> & C\nD"},
+	}))
+
+	want := "WEBVTT\n" +
+		"\n1\n00:00:01.230 --> 00:00:03.230\nDOMContentLoaded 1.23s\n" +
+		"\n2\n01:02:03.450 --> 01:02:05.450\nA &lt;b&gt; &amp; C D\n"
+	if got != want {
+		t.Errorf("Format() =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestFormat_Empty(t *testing.T) {
+	if got := string(Format(nil)); got != "WEBVTT\n" {
+		t.Errorf("Format(nil) = %q", got)
+	}
+}
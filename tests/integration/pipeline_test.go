@@ -27,7 +27,9 @@ import (
 	"github.com/user/loadshow/pkg/stages/banner"
 	"github.com/user/loadshow/pkg/stages/composite"
 	"github.com/user/loadshow/pkg/stages/encode"
+	"github.com/user/loadshow/pkg/stages/filmstrip"
 	"github.com/user/loadshow/pkg/stages/layout"
+	"github.com/user/loadshow/pkg/stages/poster"
 	"github.com/user/loadshow/pkg/stages/record"
 )
 
@@ -485,6 +487,8 @@ func TestOrchestratorWithDebugSink(t *testing.T) {
 	bannerStage := banner.NewStage(htmlCapturer, sink, logger.NewNoop())
 	compositeStage := composite.NewStage(renderer, sink, logger.NewNoop(), 2)
 	encodeStage := encode.NewStage(encoder, logger.NewNoop())
+	filmstripStage := filmstrip.NewStage(renderer, logger.NewNoop())
+	posterStage := poster.NewStage(renderer, logger.NewNoop())
 
 	// Create orchestrator
 	orch := orchestrator.New(
@@ -493,6 +497,8 @@ func TestOrchestratorWithDebugSink(t *testing.T) {
 		bannerStage,
 		compositeStage,
 		encodeStage,
+		filmstripStage,
+		posterStage,
 		fs,
 		sink,
 		logger.NewNoop(),